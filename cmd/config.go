@@ -30,6 +30,8 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/encoding"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/secrets"
 	"github.com/pulumi/pulumi/pkg/tokens"
@@ -41,13 +43,25 @@ func newConfigCmd() *cobra.Command {
 	var stack string
 	var showSecrets bool
 	var jsonOut bool
+	var filter string
+	var outputFormat string
 
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage configuration",
 		Long: "Lists all configuration values for a specific stack. To add a new configuration value, run\n" +
 			"'pulumi config set'. To remove and existing value run 'pulumi config rm'. To get the value of\n" +
-			"for a specific configuration key, use 'pulumi config get <key-name>'.",
+			"for a specific configuration key, use 'pulumi config get <key-name>'. To list only the keys\n" +
+			"that start with a given prefix, such as those for a single package, use '--filter <prefix>'.\n" +
+			"\n" +
+			"If '--config-file' is passed more than once, the values listed are those of all of the files\n" +
+			"merged together, with values in later files overriding those in earlier ones. Changes made by\n" +
+			"'pulumi config set' and 'pulumi config rm' are saved to the last file.\n" +
+			"\n" +
+			"The values are listed as a table by default. Pass '--output-format json' or '--output-format yaml'\n" +
+			"to emit a map from each fully qualified key to its value instead, for use by scripts. Secret\n" +
+			"values are omitted from these forms unless '--show-secrets' is passed. Either form may be read\n" +
+			"by 'pulumi config import' to set the same values in another stack.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
@@ -59,7 +73,17 @@ func newConfigCmd() *cobra.Command {
 				return err
 			}
 
-			return listConfig(stack, showSecrets, jsonOut)
+			if jsonOut {
+				if outputFormat != "" && outputFormat != configFormatJSON {
+					return errors.Errorf("--json cannot be combined with --output-format %s", outputFormat)
+				}
+				outputFormat = configFormatJSON
+			}
+			if outputFormat == "" {
+				outputFormat = configFormatTable
+			}
+
+			return listConfig(stack, showSecrets, outputFormat, filter)
 		}),
 	}
 
@@ -68,22 +92,164 @@ func newConfigCmd() *cobra.Command {
 		"Show secret values when listing config instead of displaying blinded values")
 	cmd.Flags().BoolVarP(
 		&jsonOut, "json", "j", false,
-		"Emit output as JSON")
+		"Emit output as JSON; the same as '--output-format json'")
+	cmd.Flags().StringVar(
+		&outputFormat, "output-format", "",
+		"The format in which to list the values: one of 'table' (the default), 'json', or 'yaml'")
+	cmd.Flags().StringVar(
+		&filter, "filter", "",
+		"Only list configuration keys that start with the given prefix, e.g. 'aws:'")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	cmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 
+	cmd.AddCommand(newConfigCopyCmd(&stack))
 	cmd.AddCommand(newConfigGetCmd(&stack))
+	cmd.AddCommand(newConfigImportCmd(&stack))
 	cmd.AddCommand(newConfigRmCmd(&stack))
 	cmd.AddCommand(newConfigSetCmd(&stack))
 	cmd.AddCommand(newConfigRefreshCmd(&stack))
+	cmd.AddCommand(newConfigRenderCmd(&stack))
+	cmd.AddCommand(newConfigRotateKeyCmd(&stack))
+	cmd.AddCommand(newConfigValidateCmd(&stack))
 
 	return cmd
 }
 
+func newConfigCopyCmd(stack *string) *cobra.Command {
+	var force bool
+	var destStack string
+
+	copyCmd := &cobra.Command{
+		Use:   "copy <src-key> <dst-key>",
+		Short: "Copy a configuration value to another key",
+		Long: "Copy a configuration value to another key.\n" +
+			"\n" +
+			"The value is copied as-is, so a secret value remains a secret. The copy fails if the destination\n" +
+			"key is already set, unless '--force' is passed.\n" +
+			"\n" +
+			"Pass '--dest-stack' to copy the value into another stack's configuration instead. A secret value\n" +
+			"is then decrypted with the source stack's key and re-encrypted with the destination stack's, so\n" +
+			"both keys must be available.",
+		Args: cmdutil.SpecificArgs([]string{"src-key", "dst-key"}),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stack, true, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			src, err := parseConfigKey(args[0])
+			if err != nil {
+				return errors.Wrap(err, "invalid source configuration key")
+			}
+			dst, err := parseConfigKey(args[1])
+			if err != nil {
+				return errors.Wrap(err, "invalid destination configuration key")
+			}
+
+			ps, err := loadProjectStack(s)
+			if err != nil {
+				return err
+			}
+
+			if ps.Config == nil {
+				ps.Config = config.Map{}
+			}
+			if destStack == "" {
+				if err = copyConfigValue(ps.Config, src, dst, force); err != nil {
+					return err
+				}
+				return saveProjectStack(s, ps)
+			}
+
+			d, err := requireStack(destStack, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			if d.Ref().Name() == s.Ref().Name() {
+				return errors.New("the destination stack must differ from the source stack")
+			}
+			dps, err := loadProjectStack(d)
+			if err != nil {
+				return err
+			}
+			if dps.Config == nil {
+				dps.Config = config.Map{}
+			}
+
+			var dec config.Decrypter
+			var enc config.Encrypter
+			if v, has := ps.Config[src]; has && v.Secure() {
+				if dec, err = getStackDencrypter(s); err != nil {
+					return errors.Wrapf(err, "could not decrypt secrets of stack '%s'", s.Ref())
+				}
+				if enc, err = getStackEncrypter(d); err != nil {
+					return errors.Wrapf(err, "could not re-encrypt secrets for stack '%s'", d.Ref())
+				}
+			}
+			if err = copyConfigValueToStack(ps.Config, dps.Config, src, dst, force, dec, enc); err != nil {
+				return err
+			}
+			return saveProjectStack(d, dps)
+		}),
+	}
+
+	copyCmd.PersistentFlags().BoolVarP(
+		&force, "force", "f", false,
+		"Overwrite the destination key if it is already set")
+	copyCmd.PersistentFlags().StringVar(
+		&destStack, "dest-stack", "",
+		"Copy the value into the configuration of the given stack, rather than that of the source stack")
+
+	return copyCmd
+}
+
+// copyConfigValue copies the value of the source key in the given configuration to the destination key. It fails if the
+// source key is not set, or if the destination key is set and force is false.
+func copyConfigValue(c config.Map, src, dst config.Key, force bool) error {
+	if src == dst {
+		if _, has := c[src]; has {
+			return errors.Errorf("cannot copy configuration key '%s' to itself", prettyKey(src))
+		}
+	}
+	return copyConfigValueToStack(c, c, src, dst, force, config.NopDecrypter, config.NopEncrypter)
+}
+
+// copyConfigValueToStack copies the value of the source key in one stack's configuration to the destination key in
+// another's. A secret value is decrypted using the decrypter and re-encrypted using the encrypter, which are only used
+// for secret values. It fails if the source key is not set, if the secret fails to decrypt, or if the destination key
+// is set and force is false; the destination configuration is only changed if the copy succeeds.
+func copyConfigValueToStack(from, to config.Map, src, dst config.Key, force bool,
+	dec config.Decrypter, enc config.Encrypter) error {
+
+	v, has := from[src]
+	if !has {
+		return errors.Errorf("configuration key '%s' not found", prettyKey(src))
+	}
+	if _, has := to[dst]; has && !force {
+		return errors.Errorf("configuration key '%s' is already set; pass --force to overwrite it", prettyKey(dst))
+	}
+	if v.Secure() {
+		plaintext, err := v.Value(dec)
+		if err != nil {
+			return errors.Wrapf(err, "could not decrypt configuration value '%s'", prettyKey(src))
+		}
+		if v, err = v.Reencrypt(plaintext, enc); err != nil {
+			return errors.Wrapf(err, "could not encrypt configuration value '%s'", prettyKey(dst))
+		}
+	}
+	to[dst] = v
+	return nil
+}
+
 func newConfigGetCmd(stack *string) *cobra.Command {
 	var jsonOut bool
 
@@ -117,10 +283,16 @@ func newConfigGetCmd(stack *string) *cobra.Command {
 }
 
 func newConfigRmCmd(stack *string) *cobra.Command {
+	var dryRun bool
+
 	rmCmd := &cobra.Command{
 		Use:   "rm <key>",
 		Short: "Remove configuration value",
-		Args:  cmdutil.SpecificArgs([]string{"key"}),
+		Long: "Remove configuration value.\n" +
+			"\n" +
+			"Pass '--dry-run' to show the value that would be removed and the resulting configuration file\n" +
+			"without saving it.",
+		Args: cmdutil.SpecificArgs([]string{"key"}),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
@@ -141,14 +313,26 @@ func newConfigRmCmd(stack *string) *cobra.Command {
 				return err
 			}
 
+			old, had := ps.Config[key]
 			if ps.Config != nil {
 				delete(ps.Config, key)
 			}
 
+			if dryRun {
+				var before *config.Value
+				if had {
+					before = &old
+				}
+				return printConfigDryRun(s, ps, key, before, nil, had)
+			}
 			return saveProjectStack(s, ps)
 		}),
 	}
 
+	rmCmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"Show the change that would be made to the configuration file without saving it")
+
 	return rmCmd
 }
 
@@ -224,17 +408,38 @@ func newConfigRefreshCmd(stack *string) *cobra.Command {
 }
 
 func newConfigSetCmd(stack *string) *cobra.Command {
+	var dryRun bool
 	var plaintext bool
 	var secret bool
+	var path bool
+	var resolve bool
 
 	setCmd := &cobra.Command{
 		Use:   "set <key> [value]",
 		Short: "Set configuration value",
 		Long: "Configuration values can be accessed when a stack is being deployed and used to configure behavior. \n" +
 			"If a value is not present on the command line, pulumi will prompt for the value. Multi-line values\n" +
-			"may be set by piping a file to standard in.",
+			"may be set by piping a file to standard in.\n" +
+			"\n" +
+			"Pass '--dry-run' to show the value before and after the change and the resulting configuration\n" +
+			"file without saving it.\n" +
+			"\n" +
+			"Pass '--path' to set a single property of a configuration object, whose path follows the key after a\n" +
+			"dot: for example, 'pulumi config set --path db.host example.com' sets the 'host' property of the object\n" +
+			"'db', creating it if necessary. Combined with '--secret', only that property is encrypted, and only its\n" +
+			"value is hidden in the output of Pulumi commands; the rest of the object is stored in plaintext.\n" +
+			"\n" +
+			"Pass '--resolve' to set the key to a reference to a secret that is never saved, even encrypted, but is\n" +
+			"fetched each time the stack is deployed: the value is then the secret's URI, either env://<NAME>, which\n" +
+			"refers to an environment variable, or file://<PATH>, which refers to the contents of a file. The secret\n" +
+			"is hidden in the output of Pulumi commands, and an operation fails before it changes anything if the\n" +
+			"secret cannot be fetched.",
 		Args: cmdutil.RangeArgs(1, 2),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if resolve && (secret || path) {
+				return errors.New("--resolve may not be combined with --secret or --path")
+			}
+
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
@@ -245,7 +450,16 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
-			key, err := parseConfigKey(args[0])
+			keyText, objectPath := args[0], ""
+			if path {
+				dot := strings.Index(keyText, ".")
+				if dot == -1 {
+					return errors.Errorf("'%s' has no path; with --path, the key must be of the form <key>.<path>",
+						keyText)
+				}
+				keyText, objectPath = keyText[:dot], keyText[dot+1:]
+			}
+			key, err := parseConfigKey(keyText)
 			if err != nil {
 				return errors.Wrap(err, "invalid configuration key")
 			}
@@ -274,7 +488,11 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 
 			// Encrypt the config value if needed.
 			var v config.Value
-			if secret {
+			if resolve {
+				if v, err = config.NewReference(value); err != nil {
+					return err
+				}
+			} else if secret {
 				c, cerr := getStackEncrypter(s)
 				if cerr != nil {
 					return cerr
@@ -287,8 +505,13 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 			} else {
 				v = config.NewValue(value)
 
-				// If we saved a plaintext configuration value, and --plaintext was not passed, warn the user.
-				if !plaintext && looksLikeSecret(key, value) {
+				// If we saved a plaintext configuration value, and --plaintext was not passed, warn the user. The
+				// name of a property of an object is what says whether it is a secret.
+				nameKey := key
+				if objectPath != "" {
+					nameKey = config.MustMakeKey(key.Namespace(), objectPath[strings.LastIndex(objectPath, ".")+1:])
+				}
+				if !plaintext && looksLikeSecret(nameKey, value) {
 					return errors.Errorf(
 						"config value '%s' looks like a secret; "+
 							"rerun with --secret to encrypt it, or --plaintext if you meant to store in plaintext",
@@ -301,43 +524,241 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
+			old, had := ps.Config[key]
+			if objectPath != "" {
+				if v, err = old.SetPath(objectPath, v); err != nil {
+					return errors.Wrapf(err, "could not set '%s' in configuration value '%s'", objectPath, prettyKey(key))
+				}
+			}
 			ps.Config[key] = v
 
+			if dryRun {
+				changed := !had || old != v
+				if had && old.Secure() && v.Secure() {
+					// Encrypting a secret yields a different ciphertext each time, so compare the secrets themselves.
+					d, derr := getStackDencrypter(s)
+					if derr != nil {
+						return derr
+					}
+					oldValue, derr := old.Value(d)
+					if derr != nil {
+						return derr
+					}
+					newValue, derr := v.Value(d)
+					if derr != nil {
+						return derr
+					}
+					changed = oldValue != newValue
+				}
+
+				var before *config.Value
+				if had {
+					before = &old
+				}
+				return printConfigDryRun(s, ps, key, before, &v, changed)
+			}
 			return saveProjectStack(s, ps)
 		}),
 	}
 
+	setCmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"Show the change that would be made to the configuration file without saving it")
 	setCmd.PersistentFlags().BoolVar(
 		&plaintext, "plaintext", false,
 		"Save the value as plaintext (unencrypted)")
 	setCmd.PersistentFlags().BoolVar(
 		&secret, "secret", false,
 		"Encrypt the value instead of storing it in plaintext")
+	setCmd.PersistentFlags().BoolVar(
+		&path, "path", false,
+		"Set a property of a configuration object, at the path that follows the key after a dot")
+	setCmd.PersistentFlags().BoolVar(
+		&resolve, "resolve", false,
+		"Set the key to a reference to a secret that is fetched each time the stack is deployed, and never saved")
 
 	return setCmd
 }
 
-var stackConfigFile string
+// stackConfigFiles are the configuration files passed with --config-file, in increasing order of precedence. If there
+// are none, the stack's default configuration file is used.
+var stackConfigFiles []string
+
+// stackConfigPath returns the path of the configuration file that changes to a stack's configuration are saved to, or
+// the empty string if the stack's default configuration file should be used. If several configuration files were
+// passed, this is the last of them, whose values take precedence over those of the others.
+func stackConfigPath() string {
+	if len(stackConfigFiles) == 0 {
+		return ""
+	}
+	return stackConfigFiles[len(stackConfigFiles)-1]
+}
 
 func getProjectStackPath(stack backend.Stack) (string, error) {
-	if stackConfigFile == "" {
-		return workspace.DetectProjectStackPath(stack.Ref().Name())
+	if path := stackConfigPath(); path != "" {
+		return path, nil
 	}
-	return stackConfigFile, nil
+	return workspace.DetectProjectStackPath(stack.Ref().Name())
 }
 
 func loadProjectStack(stack backend.Stack) (*workspace.ProjectStack, error) {
-	if stackConfigFile == "" {
-		return workspace.DetectProjectStack(stack.Ref().Name())
+	if path := stackConfigPath(); path != "" {
+		return workspace.LoadProjectStack(path)
 	}
-	return workspace.LoadProjectStack(stackConfigFile)
+	return workspace.DetectProjectStack(stack.Ref().Name())
 }
 
 func saveProjectStack(stack backend.Stack, ps *workspace.ProjectStack) error {
-	if stackConfigFile == "" {
-		return workspace.SaveProjectStack(stack.Ref().Name(), ps)
+	if path := stackConfigPath(); path != "" {
+		return ps.Save(path)
 	}
-	return ps.Save(stackConfigFile)
+	return workspace.SaveProjectStack(stack.Ref().Name(), ps)
+}
+
+// printConfigDryRun prints the change to the given key that a configuration mutation would make, and the contents
+// that the stack's configuration file would then have, without saving the file. The given project stack must already
+// reflect the change. Before and after are the key's values before and after the change, or nil if the key is not set.
+func printConfigDryRun(stack backend.Stack, ps *workspace.ProjectStack, key config.Key,
+	before, after *config.Value, changed bool) error {
+
+	if !changed {
+		if after == nil {
+			fmt.Printf("no change: %s is not set\n", prettyKey(key))
+		} else {
+			fmt.Printf("no change: %s is already set to %s\n", prettyKey(key), describeConfigValue(after))
+		}
+		return nil
+	}
+
+	fmt.Printf("%s:\n", prettyKey(key))
+	fmt.Printf("    before: %s\n", describeConfigValue(before))
+	fmt.Printf("    after:  %s\n", describeConfigValue(after))
+	return printConfigFileDryRun(stack, ps)
+}
+
+// describeConfigValue returns the text that a dry run shows for the given configuration value, which is nil if the
+// key is not set. Secrets are not shown.
+func describeConfigValue(v *config.Value) string {
+	switch {
+	case v == nil:
+		return "(not set)"
+	default:
+		return v.Masked()
+	}
+}
+
+// printConfigFileDryRun prints the contents that the stack's configuration file would have if the given project stack
+// were saved, without saving it.
+func printConfigFileDryRun(stack backend.Stack, ps *workspace.ProjectStack) error {
+	path, err := getProjectStackPath(stack)
+	if err != nil {
+		return err
+	}
+	m, _ := encoding.Detect(path)
+	if m == nil {
+		return errors.Errorf("can not save configuration file %s: unrecognized extension", path)
+	}
+	b, err := m.Marshal(ps)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nThe configuration file %s would contain:\n\n%s", path, b)
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		fmt.Println()
+	}
+	fmt.Println("\nThis was a dry run; the configuration file was not changed.")
+	return nil
+}
+
+// loadStackConfig loads the effective configuration of the given stack. If several configuration files were passed,
+// their values are merged, with the values in later files overriding those in earlier ones. Unlike loadProjectStack,
+// which may be used to create a configuration file, it is an error for any of the files to be missing.
+func loadStackConfig(stack backend.Stack) (config.Map, error) {
+	if len(stackConfigFiles) == 0 {
+		ps, err := loadProjectStack(stack)
+		if err != nil {
+			return nil, err
+		}
+		return ps.Config, nil
+	}
+
+	files := make([]*workspace.ProjectStack, len(stackConfigFiles))
+	for i, path := range stackConfigFiles {
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return nil, errors.Errorf("configuration file '%s' does not exist", path)
+			}
+			return nil, err
+		}
+		ps, err := workspace.LoadProjectStack(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading configuration file '%s'", path)
+		}
+		files[i] = ps
+	}
+
+	return mergeConfigFiles(stackConfigFiles, files, func(path string) (config.Decrypter, error) {
+		sm, err := getConfigFileSecretsManager(stack, path)
+		if err != nil {
+			return nil, err
+		}
+		return sm.Decrypter()
+	}, func() (config.Encrypter, error) {
+		return getStackEncrypter(stack)
+	})
+}
+
+// mergeConfigFiles merges the configuration in the given files, which were loaded from the given paths, with the values
+// in later files overriding those in earlier ones. The merged configuration is decrypted with the secrets manager of
+// the last file, to which changes are saved. A secret from an earlier file that uses a different secrets manager is
+// therefore decrypted with the decrypter that getDecrypter returns for its file, and re-encrypted with the encrypter
+// that getEncrypter returns. Neither is called if no such secret is merged.
+func mergeConfigFiles(paths []string, files []*workspace.ProjectStack,
+	getDecrypter func(path string) (config.Decrypter, error),
+	getEncrypter func() (config.Encrypter, error)) (config.Map, error) {
+
+	cfg := make(config.Map)
+	origins := make(map[config.Key]int)
+	for i, ps := range files {
+		for k, v := range ps.Config {
+			cfg[k], origins[k] = v, i
+		}
+	}
+
+	last := files[len(files)-1]
+	decrypters := make(map[int]config.Decrypter)
+	var enc config.Encrypter
+	for k, v := range cfg {
+		i := origins[k]
+		sameManager := files[i].SecretsProvider == last.SecretsProvider && files[i].EncryptionSalt == last.EncryptionSalt
+		if !v.Secure() || sameManager {
+			continue
+		}
+
+		var err error
+		dec, has := decrypters[i]
+		if !has {
+			if dec, err = getDecrypter(paths[i]); err != nil {
+				return nil, errors.Wrapf(err, "could not decrypt the secrets in configuration file '%s'", paths[i])
+			}
+			decrypters[i] = dec
+		}
+		if enc == nil {
+			if enc, err = getEncrypter(); err != nil {
+				return nil, errors.Wrap(err, "could not create an encrypter")
+			}
+		}
+
+		plaintext, err := v.Value(dec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt configuration value '%s' in '%s'", prettyKey(k), paths[i])
+		}
+		if cfg[k], err = v.Reencrypt(plaintext, enc); err != nil {
+			return nil, errors.Wrapf(err, "could not encrypt configuration value '%s'", prettyKey(k))
+		}
+	}
+	return cfg, nil
 }
 
 func parseConfigKey(key string) (config.Key, error) {
@@ -372,22 +793,47 @@ func prettyKeyForProject(k config.Key, proj *workspace.Project) string {
 	return fmt.Sprintf("%s:%s", k.Namespace(), k.Name())
 }
 
+// The formats in which listConfig can list configuration values.
+const (
+	configFormatTable = "table"
+	configFormatJSON  = "json"
+	configFormatYAML  = "yaml"
+)
+
 // configValueJSON is the shape of the --json output for a configuration value.  While we can add fields to this
-// structure in the future, we should not change existing fields.
+// structure in the future, we should not change existing fields. The YAML output has the same shape.
 type configValueJSON struct {
 	// When the value is encrypted and --show-secrets was not passed, the value will not be set.
-	Value  *string `json:"value,omitempty"`
-	Secret bool    `json:"secret"`
+	Value  *string `json:"value,omitempty" yaml:"value,omitempty"`
+	Secret bool    `json:"secret" yaml:"secret"`
+
+	// Object is true if the value is an object, in which case Value is its JSON text. Only the object's secure leaves
+	// are secret; they are "[secret]" unless --show-secrets was passed.
+	Object bool `json:"object,omitempty" yaml:"object,omitempty"`
+
+	// Reference is the URI of the secret to which the value refers, if it is a reference. The secret itself is only
+	// fetched when the stack is deployed, so the value is never set.
+	Reference string `json:"reference,omitempty" yaml:"reference,omitempty"`
 }
 
-func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
-	ps, err := loadProjectStack(stack)
+func listConfig(stack backend.Stack, showSecrets bool, format string, filter string) error {
+	var marshal func(v interface{}) ([]byte, error)
+	switch format {
+	case configFormatTable:
+	case configFormatJSON:
+		marshal = func(v interface{}) ([]byte, error) { return json.MarshalIndent(v, "", "  ") }
+	case configFormatYAML:
+		marshal = encoding.YAML.Marshal
+	default:
+		return errors.Errorf("unknown output format %q; expected one of %q, %q, or %q",
+			format, configFormatTable, configFormatJSON, configFormatYAML)
+	}
+
+	cfg, err := loadStackConfig(stack)
 	if err != nil {
 		return err
 	}
 
-	cfg := ps.Config
-
 	// By default, we will use a blinding decrypter to show "[secret]". If requested, display secrets in plaintext.
 	decrypter := config.NewBlindingDecrypter()
 	if cfg.HasSecureValue() && showSecrets {
@@ -406,11 +852,27 @@ func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
 	}
 	sort.Sort(keys)
 
-	if jsonOut {
+	if filter != "" {
+		keys = filterConfigKeys(keys, filter)
+		if len(keys) == 0 && marshal == nil {
+			fmt.Printf("No configuration keys start with '%s'.\n", filter)
+			return nil
+		}
+	}
+
+	if marshal != nil {
 		configValues := make(map[string]configValueJSON)
 		for _, key := range keys {
 			entry := configValueJSON{
-				Secret: cfg[key].Secure(),
+				Secret:    cfg[key].Secure() || cfg[key].Reference(),
+				Object:    cfg[key].Object(),
+				Reference: cfg[key].ReferenceURI(),
+			}
+
+			// The secret to which a reference refers is only fetched when the stack is deployed.
+			if cfg[key].Reference() {
+				configValues[key.String()] = entry
+				continue
 			}
 
 			decrypted, err := cfg[key].Value(decrypter)
@@ -421,21 +883,25 @@ func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
 
 			// If the value was a secret value and we aren't showing secrets, then the above would have set value
 			// to "[secret]" which is reasonable when printing for human display, but for our JSON output, we'd rather
-			// just elide the value.
-			if cfg[key].Secure() && !showSecrets {
+			// just elide the value. The value of an object is kept, since only its secure leaves are "[secret]".
+			if cfg[key].Secure() && !cfg[key].Object() && !showSecrets {
 				entry.Value = nil
 			}
 
 			configValues[key.String()] = entry
 		}
-		out, err := json.MarshalIndent(configValues, "", "  ")
+		out, err := marshal(configValues)
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(out))
+		fmt.Println(strings.TrimSuffix(string(out), "\n"))
 	} else {
 		rows := []cmdutil.TableRow{}
 		for _, key := range keys {
+			if cfg[key].Reference() {
+				rows = append(rows, cmdutil.TableRow{Columns: []string{prettyKey(key), cfg[key].Masked()}})
+				continue
+			}
 			decrypted, err := cfg[key].Value(decrypter)
 			if err != nil {
 				return errors.Wrap(err, "could not decrypt configuration value")
@@ -453,15 +919,30 @@ func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
 	return nil
 }
 
+// filterConfigKeys returns the keys that start with the given prefix, either in their fully qualified form or in the
+// form in which they are displayed for the current project.
+func filterConfigKeys(keys config.KeyArray, prefix string) config.KeyArray {
+	var filtered config.KeyArray
+	for _, key := range keys {
+		if strings.HasPrefix(key.String(), prefix) || strings.HasPrefix(prettyKey(key), prefix) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
 func getConfig(stack backend.Stack, key config.Key, jsonOut bool) error {
-	ps, err := loadProjectStack(stack)
+	cfg, err := loadStackConfig(stack)
 	if err != nil {
 		return err
 	}
 
-	cfg := ps.Config
-
 	if v, ok := cfg[key]; ok {
+		if v.Reference() {
+			return errors.Errorf("configuration key '%s' refers to a secret that is only fetched when the stack is "+
+				"deployed", prettyKey(key))
+		}
+
 		var d config.Decrypter
 		if v.Secure() {
 			var err error
@@ -480,6 +961,7 @@ func getConfig(stack backend.Stack, key config.Key, jsonOut bool) error {
 			value := configValueJSON{
 				Value:  &raw,
 				Secret: v.Secure(),
+				Object: v.Object(),
 			}
 
 			out, err := json.MarshalIndent(value, "", "  ")
@@ -532,20 +1014,63 @@ func looksLikeSecret(k config.Key, v string) bool {
 		(info.Entropy >= (entropyThreshold/2) && entropyPerChar >= entropyPerCharThreshold))
 }
 
-// getStackConfiguration loads configuration information for a given stack. If stackConfigFile is non empty,
-// it is uses instead of the default configuration file for the stack
+// checkStackConfig validates the stack's configuration against the project's config schema. It prints each problem
+// that is only a warning, and returns an error that lists the others, if there are any.
+func checkStackConfig(stack backend.Stack, proj *workspace.Project, cfg backend.StackConfiguration) error {
+	problems, err := proj.ValidateConfig(cfg.Config, cfg.Decrypter)
+	if err != nil {
+		return errors.Wrap(err, "validating stack configuration")
+	}
+
+	var invalid []string
+	for _, p := range problems {
+		msg := fmt.Sprintf("%s %s", prettyKeyForProject(p.Key, proj), p.Message)
+		if p.Warning {
+			cmdutil.Diag().Warningf(diag.Message("", msg))
+		} else {
+			invalid = append(invalid, msg)
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.Errorf("the configuration of stack '%s' is invalid:\n    %s",
+			stack.Ref(), strings.Join(invalid, "\n    "))
+	}
+	return nil
+}
+
+// getStackConfiguration loads configuration information for a given stack. If any configuration files were passed
+// with --config-file, their merged values are used instead of those in the default configuration file for the stack.
 func getStackConfiguration(stack backend.Stack, sm secrets.Manager) (backend.StackConfiguration, error) {
-	workspaceStack, err := loadProjectStack(stack)
+	cfg, err := loadStackConfig(stack)
 	if err != nil {
 		return backend.StackConfiguration{}, errors.Wrap(err, "loading stack configuration")
 	}
 
+	// Expand the references to built-in variables, such as ${stack}, in the configuration values.
+	proj, err := workspace.DetectProject()
+	if err != nil {
+		return backend.StackConfiguration{}, err
+	}
+	cfg, err = cfg.Interpolate(config.Builtins{
+		Project: string(proj.Name),
+		Stack:   string(stack.Ref().Name()),
+	})
+	if err != nil {
+		return backend.StackConfiguration{}, err
+	}
+
+	// Fetch the secrets to which references refer, such as env://DB_PASSWORD, before anything is changed. They are
+	// held in memory for this operation only, and are never saved.
+	if cfg, err = cfg.Resolve(config.DefaultResolvers()); err != nil {
+		return backend.StackConfiguration{}, err
+	}
+
 	// If there are no secrets in the configuration, we should never use the decrypter, so it is safe to return
 	// one which panics if it is used. This provides for some nice UX in the common case (since, for example, building
 	// the correct decrypter for the local backend would involve prompting for a passphrase)
-	if !workspaceStack.Config.HasSecureValue() {
+	if !cfg.HasSecureValue() {
 		return backend.StackConfiguration{
-			Config:    workspaceStack.Config,
+			Config:    cfg,
 			Decrypter: config.NewPanicCrypter(),
 		}, nil
 	}
@@ -556,7 +1081,7 @@ func getStackConfiguration(stack backend.Stack, sm secrets.Manager) (backend.Sta
 	}
 
 	return backend.StackConfiguration{
-		Config:    workspaceStack.Config,
+		Config:    cfg,
 		Decrypter: crypter,
 	}, nil
 }
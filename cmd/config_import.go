@@ -0,0 +1,426 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newConfigImportCmd(stack *string) *cobra.Command {
+	var dryRun bool
+	var plaintext bool
+	var secretKeys []string
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Set configuration values from a JSON or YAML file",
+		Long: "Set configuration values from a JSON or YAML file.\n" +
+			"\n" +
+			"The file must contain a map from each key to its value. A value may be a string, a number, or a\n" +
+			"boolean, or an entry of the form that 'pulumi config --output-format json' and '--output-format\n" +
+			"yaml' emit, so the configuration of one stack may be copied to another: an object with a 'value'\n" +
+			"property and optional 'secret' and 'object' properties, or with a 'reference' property that holds\n" +
+			"the URI of a secret that is fetched each time the stack is deployed. Pass '--show-secrets' when\n" +
+			"listing the configuration, or its secrets are omitted, and cannot be imported.\n" +
+			"\n" +
+			"The values of the keys passed to '--secret-keys', and of those that are marked as secrets in the\n" +
+			"file, are encrypted. Every string leaf of a secret object is encrypted. As with 'pulumi config set',\n" +
+			"a plaintext value that looks like a secret is an error unless '--plaintext' is passed.\n" +
+			"\n" +
+			"Either every value in the file is set or, if any of them cannot be, none are. Keys that already\n" +
+			"have the values in the file are left as they are. Pass '--dry-run' to show the changes that would\n" +
+			"be made and the resulting configuration file without saving it.",
+		Args: cmdutil.SpecificArgs([]string{"file"}),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stack, true, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			imports, err := readConfigImports(args[0], secretKeys)
+			if err != nil {
+				return err
+			}
+			if !plaintext {
+				for _, imp := range imports {
+					if !imp.Secret && !imp.Object && !imp.Reference && looksLikeSecret(imp.Key, imp.Value) {
+						return errors.Errorf(
+							"the value of '%s' looks like a secret; "+
+								"rerun with --secret-keys %s to encrypt it, or --plaintext if you meant to store in plaintext",
+							prettyKey(imp.Key), prettyKey(imp.Key))
+					}
+				}
+			}
+
+			ps, err := loadProjectStack(s)
+			if err != nil {
+				return err
+			}
+			if ps.Config == nil {
+				ps.Config = make(config.Map)
+			}
+
+			// Only ask for the stack's keys if there are secrets to encrypt, or to compare with.
+			var enc config.Encrypter = config.NopEncrypter
+			for _, imp := range imports {
+				if imp.Secret {
+					if enc, err = getStackEncrypter(s); err != nil {
+						return err
+					}
+					break
+				}
+			}
+			var dec config.Decrypter = config.NopDecrypter
+			if ps.Config.HasSecureValue() {
+				if dec, err = getStackDencrypter(s); err != nil {
+					return err
+				}
+			}
+
+			before := make(config.Map)
+			for key, v := range ps.Config {
+				before[key] = v
+			}
+			added, changed, err := importConfig(ps.Config, imports, enc, dec)
+			if err != nil {
+				return err
+			}
+			summary := fmt.Sprintf("%d set, %d changed, %d unchanged",
+				len(added), len(changed), len(imports)-len(added)-len(changed))
+
+			if dryRun {
+				if len(added)+len(changed) == 0 {
+					fmt.Printf("no change: every key in %s already has its value (%s)\n", args[0], summary)
+					return nil
+				}
+				keys := append(append(config.KeyArray{}, added...), changed...)
+				sort.Sort(keys)
+				for _, key := range keys {
+					var old *config.Value
+					if v, had := before[key]; had {
+						old = &v
+					}
+					v := ps.Config[key]
+					fmt.Printf("%s:\n", prettyKey(key))
+					fmt.Printf("    before: %s\n", describeConfigValue(old))
+					fmt.Printf("    after:  %s\n", describeConfigValue(&v))
+				}
+				fmt.Printf("\nImporting %s would change the configuration of stack '%s': %s\n",
+					args[0], s.Ref(), summary)
+				return printConfigFileDryRun(s, ps)
+			}
+
+			if len(added)+len(changed) > 0 {
+				if err = saveProjectStack(s, ps); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Imported %s into the configuration of stack '%s': %s\n", args[0], s.Ref(), summary)
+			return nil
+		}),
+	}
+
+	importCmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"Show the changes that would be made to the configuration file without saving it")
+	importCmd.PersistentFlags().BoolVar(
+		&plaintext, "plaintext", false,
+		"Save values that look like secrets as plaintext (unencrypted)")
+	importCmd.PersistentFlags().StringSliceVar(
+		&secretKeys, "secret-keys", nil,
+		"Encrypt the values of the given keys instead of storing them in plaintext")
+
+	return importCmd
+}
+
+// configImport is a configuration value that is read from a file by 'pulumi config import'.
+type configImport struct {
+	Key       config.Key
+	Value     string // the text of a plain value, the JSON text of an object, or the URI of a reference.
+	Secret    bool
+	Object    bool
+	Reference bool
+}
+
+// readConfigImports reads the configuration values in the given JSON or YAML file, sorted by key, and marks the values
+// of the given keys as secrets. It is an error for a secret key not to be in the file.
+func readConfigImports(path string, secretKeys []string) ([]configImport, error) {
+	m, _ := encoding.Detect(path)
+	if m == nil {
+		return nil, errors.Errorf("can not read configuration file %s: unrecognized extension", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err = m.Unmarshal(b, &values); err != nil {
+		return nil, errors.Wrapf(err, "could not read configuration file %s", path)
+	}
+	return parseConfigImports(values, secretKeys)
+}
+
+// parseConfigImports returns the given configuration values, sorted by key, with the values of the given keys marked
+// as secrets.
+func parseConfigImports(values map[string]interface{}, secretKeys []string) ([]configImport, error) {
+	secrets := make(map[config.Key]bool)
+	for _, k := range secretKeys {
+		key, err := parseConfigKey(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration key '%s' in --secret-keys", k)
+		}
+		secrets[key] = true
+	}
+
+	var imports []configImport
+	seen := make(map[config.Key]bool)
+	for k, v := range values {
+		key, err := parseConfigKey(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration key '%s'", k)
+		}
+		imp, err := parseConfigImportValue(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for configuration key '%s'", k)
+		}
+		if seen[key] {
+			return nil, errors.Errorf("configuration key '%s' is set more than once", prettyKey(key))
+		}
+		seen[key] = true
+		if secrets[key] && imp.Reference {
+			return nil, errors.Errorf("secret key '%s' is a reference, whose secret is never saved", prettyKey(key))
+		}
+		imp.Key, imp.Secret = key, imp.Secret || secrets[key]
+		imports = append(imports, imp)
+		delete(secrets, key)
+	}
+	if len(secrets) > 0 {
+		var missing config.KeyArray
+		for key := range secrets {
+			missing = append(missing, key)
+		}
+		sort.Sort(missing)
+		return nil, errors.Errorf("secret key '%s' is not set by the file", prettyKey(missing[0]))
+	}
+
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Key.String() < imports[j].Key.String() })
+	return imports, nil
+}
+
+// parseConfigImportValue returns the given configuration value, without its key. The value is either a scalar, or an
+// object of the shape of configValueJSON.
+func parseConfigImportValue(v interface{}) (configImport, error) {
+	switch v := v.(type) {
+	case string:
+		return configImport{Value: v}, nil
+	case bool:
+		return configImport{Value: strconv.FormatBool(v)}, nil
+	case int:
+		return configImport{Value: strconv.Itoa(v)}, nil
+	case uint64:
+		return configImport{Value: strconv.FormatUint(v, 10)}, nil
+	case float64:
+		return configImport{Value: strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case map[string]interface{}:
+		return parseConfigImportEntry(v)
+	case map[interface{}]interface{}:
+		entry := make(map[string]interface{})
+		for k, e := range v {
+			name, ok := k.(string)
+			if !ok {
+				return configImport{}, errors.Errorf("unexpected property %v", k)
+			}
+			entry[name] = e
+		}
+		return parseConfigImportEntry(entry)
+	case nil:
+		return configImport{}, errors.New("the value is null")
+	default:
+		return configImport{}, errors.Errorf("expected a string, number, boolean, or object, but got %v", v)
+	}
+}
+
+// parseConfigImportEntry returns the configuration value, without its key, of an entry of the shape of
+// configValueJSON.
+func parseConfigImportEntry(entry map[string]interface{}) (configImport, error) {
+	for name := range entry {
+		switch name {
+		case "value", "secret", "object", "reference":
+		default:
+			return configImport{}, errors.Errorf(
+				"unexpected property '%s'; expected only 'value', 'secret', 'object', and 'reference'", name)
+		}
+	}
+
+	var imp configImport
+	for name, flag := range map[string]*bool{"secret": &imp.Secret, "object": &imp.Object} {
+		if f, has := entry[name]; has {
+			b, ok := f.(bool)
+			if !ok {
+				return configImport{}, errors.Errorf("'%s' must be a boolean, but is %v", name, f)
+			}
+			*flag = b
+		}
+	}
+
+	// A reference is listed by its URI; the secret to which it refers is only fetched when the stack is deployed.
+	if r, has := entry["reference"]; has {
+		uri, ok := r.(string)
+		if !ok {
+			return configImport{}, errors.Errorf("'reference' must be a string, but is %v", r)
+		}
+		if _, has := entry["value"]; has || imp.Object {
+			return configImport{}, errors.New("a reference can not also have a 'value' or be an object")
+		}
+		if _, err := config.NewReference(uri); err != nil {
+			return configImport{}, err
+		}
+		return configImport{Value: uri, Reference: true}, nil
+	}
+
+	v, has := entry["value"]
+	if !has {
+		if imp.Secret {
+			return configImport{}, errors.New("the secret has no value; " +
+				"pass --show-secrets to 'pulumi config' to list the values of secrets")
+		}
+		return configImport{}, errors.New("the object has no 'value' property")
+	}
+	value, ok := v.(string)
+	if !ok {
+		return configImport{}, errors.Errorf("'value' must be a string, but is %v", v)
+	}
+	imp.Value = value
+
+	if imp.Object {
+		object, err := config.NewObjectValue(value)
+		if err != nil {
+			return configImport{}, err
+		}
+		if object.Secure() {
+			return configImport{}, errors.New("the object has encrypted leaves, which can not be imported")
+		}
+		if strings.Contains(value, `"[secret]"`) {
+			return configImport{}, errors.New("the object's secrets are masked; " +
+				"pass --show-secrets to 'pulumi config' to list the values of secrets")
+		}
+	}
+	return imp, nil
+}
+
+// importConfig sets the given values in the given configuration, encrypting the secrets with the given encrypter, and
+// returns the keys that it set and the keys whose values it changed. The configuration's existing secrets are
+// decrypted with the given decrypter to compare them with the imported values. Keys that already have their imported
+// values are left as they are, so an existing secret is not re-encrypted. If any value cannot be set, the
+// configuration is not changed at all.
+func importConfig(cfg config.Map, imports []configImport, enc config.Encrypter,
+	dec config.Decrypter) (added, changed []config.Key, err error) {
+
+	values := make(config.Map)
+	for _, imp := range imports {
+		old, had := cfg[imp.Key]
+		if had {
+			same, err := hasConfigImport(old, imp, dec)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "could not decrypt configuration value '%s'", prettyKey(imp.Key))
+			}
+			if same {
+				continue
+			}
+		}
+
+		v, err := newConfigImportValue(imp, enc)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not encrypt configuration value '%s'", prettyKey(imp.Key))
+		}
+		values[imp.Key] = v
+
+		if had {
+			changed = append(changed, imp.Key)
+		} else {
+			added = append(added, imp.Key)
+		}
+	}
+
+	for key, v := range values {
+		cfg[key] = v
+	}
+	return added, changed, nil
+}
+
+// hasConfigImport returns true if the given existing configuration value already has the imported value. Secrets are
+// decrypted with the given decrypter to compare them.
+func hasConfigImport(old config.Value, imp configImport, dec config.Decrypter) (bool, error) {
+	switch {
+	case imp.Reference || old.Reference():
+		return old.ReferenceURI() == imp.Value && imp.Reference, nil
+	case old.Object() != imp.Object || old.Secure() != imp.Secret:
+		return false, nil
+	}
+
+	current, err := old.Value(dec)
+	if err != nil {
+		return false, err
+	}
+	value := imp.Value
+	if imp.Object {
+		// Compare the objects' JSON texts in the same, canonical form.
+		object, err := config.NewObjectValue(imp.Value)
+		if err != nil {
+			return false, err
+		}
+		if value, err = object.Value(nil); err != nil {
+			return false, err
+		}
+	}
+	return current == value, nil
+}
+
+// newConfigImportValue returns the configuration value to which an imported value is set, encrypting it with the given
+// encrypter if it is a secret.
+func newConfigImportValue(imp configImport, enc config.Encrypter) (config.Value, error) {
+	switch {
+	case imp.Reference:
+		return config.NewReference(imp.Value)
+	case imp.Object && imp.Secret:
+		return config.NewSecureObjectValue(imp.Value, enc)
+	case imp.Object:
+		return config.NewObjectValue(imp.Value)
+	case imp.Secret:
+		encrypted, err := enc.EncryptValue(imp.Value)
+		if err != nil {
+			return config.Value{}, err
+		}
+		return config.NewSecureValue(encrypted), nil
+	default:
+		return config.NewValue(imp.Value), nil
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestReadConfigImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-config-import-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The values that 'pulumi config --output-format json' and '--output-format yaml' emit can be imported.
+	name, password := "web", "hunter2"
+	listed := map[string]configValueJSON{
+		"test:name":     {Value: &name},
+		"test:password": {Value: &password, Secret: true},
+	}
+	b, err := json.Marshal(listed)
+	assert.NoError(t, err)
+	jsonPath := filepath.Join(dir, "listed.json")
+	assert.NoError(t, ioutil.WriteFile(jsonPath, b, 0600))
+	b, err = encoding.YAML.Marshal(listed)
+	assert.NoError(t, err)
+	yamlPath := filepath.Join(dir, "listed.yaml")
+	assert.NoError(t, ioutil.WriteFile(yamlPath, b, 0600))
+
+	expected := []configImport{
+		{Key: config.MustMakeKey("test", "name"), Value: "web"},
+		{Key: config.MustMakeKey("test", "password"), Value: "hunter2", Secret: true},
+	}
+	for _, path := range []string{jsonPath, yamlPath} {
+		imports, err := readConfigImports(path, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, imports)
+	}
+
+	// Scalars of any type are read as strings, and --secret-keys marks values as secrets.
+	scalarPath := filepath.Join(dir, "scalars.yaml")
+	assert.NoError(t, ioutil.WriteFile(scalarPath,
+		[]byte("test:port: 8080\ntest:ratio: 0.5\ntest:enabled: true\ntest:token: abc\n"), 0600))
+	imports, err := readConfigImports(scalarPath, []string{"test:token"})
+	assert.NoError(t, err)
+	assert.Equal(t, []configImport{
+		{Key: config.MustMakeKey("test", "enabled"), Value: "true"},
+		{Key: config.MustMakeKey("test", "port"), Value: "8080"},
+		{Key: config.MustMakeKey("test", "ratio"), Value: "0.5"},
+		{Key: config.MustMakeKey("test", "token"), Value: "abc", Secret: true},
+	}, imports)
+
+	// Objects and references are listed in a form from which they are imported as they were.
+	object := `{"host":"db.example.com","port":5432}`
+	objectPath := filepath.Join(dir, "objects.json")
+	assert.NoError(t, ioutil.WriteFile(objectPath, []byte(`{
+		"test:db": {"value": "{\"port\":5432,\"host\":\"db.example.com\"}", "secret": false, "object": true},
+		"test:password": {"secret": true, "reference": "env://DB_PASSWORD"}
+	}`), 0600))
+	imports, err = readConfigImports(objectPath, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []configImport{
+		{Key: config.MustMakeKey("test", "db"), Value: `{"port":5432,"host":"db.example.com"}`, Object: true},
+		{Key: config.MustMakeKey("test", "password"), Value: "env://DB_PASSWORD", Reference: true},
+	}, imports)
+	crypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	cfg := config.Map{}
+	_, _, err = importConfig(cfg, imports, crypter, crypter)
+	assert.NoError(t, err)
+	assert.True(t, cfg[config.MustMakeKey("test", "db")].Object())
+	v, err := cfg[config.MustMakeKey("test", "db")].Value(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, object, v)
+	assert.Equal(t, "env://DB_PASSWORD", cfg[config.MustMakeKey("test", "password")].ReferenceURI())
+
+	// Malformed files are rejected.
+	for name, contents := range map[string]string{
+		"list.json":     `["test:name"]`,
+		"syntax.json":   `{"test:name": `,
+		"null.json":     `{"test:name": null}`,
+		"array.json":    `{"test:name": ["web"]}`,
+		"unknown.json":  `{"test:name": {"value": "web", "other": true}}`,
+		"omitted.json":  `{"test:password": {"secret": true}}`,
+		"masked.json":   `{"test:db": {"value": "{\"password\":\"[secret]\"}", "secret": true, "object": true}}`,
+		"object.json":   `{"test:db": {"value": "db.example.com", "object": true}}`,
+		"badref.json":   `{"test:password": {"reference": "DB_PASSWORD"}}`,
+		"refvalue.json": `{"test:password": {"value": "hunter2", "reference": "env://DB_PASSWORD"}}`,
+		"badkey.json":   `{"a:b:c": "web"}`,
+		"unknown.ext":   `{"test:name": "web"}`,
+		"badvalue.yaml": "test:name:\n  value: [web]\n",
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+		_, err = readConfigImports(path, nil)
+		assert.Error(t, err, name)
+	}
+
+	// A secret key that the file does not set is probably misspelled.
+	_, err = readConfigImports(jsonPath, []string{"test:passwrd"})
+	assert.EqualError(t, err, "secret key 'test:passwrd' is not set by the file")
+
+	// A reference is never encrypted.
+	_, err = readConfigImports(objectPath, []string{"test:password"})
+	assert.Error(t, err)
+}
+
+func TestImportConfig(t *testing.T) {
+	crypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	secret, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	key := func(name string) config.Key {
+		return config.MustMakeKey("test", name)
+	}
+	cfg := config.Map{
+		key("name"):     config.NewValue("web"),
+		key("port"):     config.NewValue("80"),
+		key("password"): config.NewSecureValue(secret),
+	}
+
+	// Keys that already have their values, including secrets, are left as they are.
+	added, changed, err := importConfig(cfg, []configImport{
+		{Key: key("name"), Value: "web"},
+		{Key: key("password"), Value: "hunter2", Secret: true},
+		{Key: key("port"), Value: "8080"},
+		{Key: key("token"), Value: "abc", Secret: true},
+	}, crypter, crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.Key{key("token")}, added)
+	assert.Equal(t, []config.Key{key("port")}, changed)
+	assert.Equal(t, config.NewSecureValue(secret), cfg[key("password")])
+	assert.Equal(t, config.NewValue("8080"), cfg[key("port")])
+	assert.True(t, cfg[key("token")].Secure())
+	v, err := cfg[key("token")].Value(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", v)
+
+	// Making a plaintext value a secret changes it, even if the value is the same.
+	_, changed, err = importConfig(cfg, []configImport{{Key: key("name"), Value: "web", Secret: true}}, crypter, crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.Key{key("name")}, changed)
+	assert.True(t, cfg[key("name")].Secure())
+
+	// If any value cannot be set, none are.
+	otherKey := make([]byte, config.SymmetricCrypterKeyBytes)
+	otherKey[0] = 1
+	other := config.NewSymmetricCrypter(otherKey)
+	before := config.Map{}
+	for k, v := range cfg {
+		before[k] = v
+	}
+	_, _, err = importConfig(cfg, []configImport{
+		{Key: key("new"), Value: "value"},
+		{Key: key("password"), Value: "swordfish", Secret: true},
+	}, other, other)
+	assert.Error(t, err)
+	assert.Equal(t, before, cfg)
+}
+
+func TestImportConfigObjects(t *testing.T) {
+	crypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	key := func(name string) config.Key {
+		return config.MustMakeKey("test", name)
+	}
+	db, err := config.Value{}.SetPath("host", config.NewValue("db.example.com"))
+	assert.NoError(t, err)
+	secret, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	db, err = db.SetPath("password", config.NewSecureValue(secret))
+	assert.NoError(t, err)
+	ref, err := config.NewReference("env://DB_PASSWORD")
+	assert.NoError(t, err)
+	cfg := config.Map{key("db"): db, key("password"): ref}
+
+	// Objects and references that are the same as those in the configuration, whatever the order of the objects'
+	// keys, are left as they are.
+	added, changed, err := importConfig(cfg, []configImport{
+		{Key: key("db"), Value: `{"password": "hunter2", "host": "db.example.com"}`, Secret: true, Object: true},
+		{Key: key("password"), Value: "env://DB_PASSWORD", Reference: true},
+	}, crypter, crypter)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Empty(t, changed)
+	assert.Equal(t, db, cfg[key("db")])
+
+	// Every string leaf of a secret object is encrypted, and a plain object is not encrypted at all.
+	added, changed, err = importConfig(cfg, []configImport{
+		{Key: key("db"), Value: `{"host": "db.example.com", "password": "swordfish"}`, Secret: true, Object: true},
+		{Key: key("password"), Value: "env://PASSWORD", Reference: true},
+		{Key: key("web"), Value: `{"port": 80}`, Object: true},
+	}, crypter, crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.Key{key("web")}, added)
+	assert.Equal(t, []config.Key{key("db"), key("password")}, changed)
+	assert.Equal(t, `{"host":"[secret]","password":"[secret]"}`, cfg[key("db")].Masked())
+	v, err := cfg[key("db")].Value(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"db.example.com","password":"swordfish"}`, v)
+	assert.Equal(t, "env://PASSWORD", cfg[key("password")].ReferenceURI())
+	assert.False(t, cfg[key("web")].Secure())
+	v, err = cfg[key("web")].Value(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"port":80}`, v)
+}
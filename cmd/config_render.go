@@ -0,0 +1,268 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newConfigRenderCmd(stack *string) *cobra.Command {
+	var templateFile string
+	var dryRun bool
+
+	renderCmd := &cobra.Command{
+		Use:   "render",
+		Short: "Set configuration values from a template",
+		Long: "Set configuration values from a template.\n" +
+			"\n" +
+			"The template is a Go text/template (https://golang.org/pkg/text/template/) that renders a YAML\n" +
+			"mapping from configuration keys to their values. A key without a namespace is in the project's\n" +
+			"namespace. A value may be a string, number, or boolean, or a mapping with a single 'secret'\n" +
+			"property whose value is to be encrypted. For example:\n" +
+			"\n" +
+			"    region: {{ quote (env \"AWS_REGION\") }}\n" +
+			"    bucketName: {{ .Project }}-{{ .Stack }}-assets\n" +
+			"    dbPassword: {secret: {{ quote (config \"dbPasswordSeed\") }}}\n" +
+			"\n" +
+			"The template may refer to the name of the stack as '.Stack' and to the name of the project as\n" +
+			"'.Project'. The stack's existing configuration is the map '.Config', whose keys include their\n" +
+			"namespaces; the 'config' function returns the value of a key, which is in the project's\n" +
+			"namespace if it has no namespace of its own, and fails if the key is not set. The 'env' function\n" +
+			"returns the value of an environment variable, and 'quote' quotes a string for use in YAML.\n" +
+			"\n" +
+			"The rendered values are saved to the stack's configuration. Keys that the template does not render\n" +
+			"are left alone. Pass '--dry-run' to show the changes that would be made without saving them.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if templateFile == "" {
+				return errors.New("missing required flag --template")
+			}
+			text, err := ioutil.ReadFile(templateFile)
+			if err != nil {
+				return errors.Wrap(err, "reading the template")
+			}
+
+			s, err := requireStack(*stack, true, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			proj, _, err := readProject(pulumiAppProj)
+			if err != nil {
+				return err
+			}
+			ps, err := loadProjectStack(s)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadStackConfig(s)
+			if err != nil {
+				return err
+			}
+
+			// The template may refer to the values of secrets, and the values of changed secrets are compared with
+			// their new values, so every secret must be decrypted.
+			var dec config.Decrypter = config.NopDecrypter
+			if cfg.HasSecureValue() || ps.Config.HasSecureValue() {
+				if dec, err = getStackDencrypter(s); err != nil {
+					return err
+				}
+			}
+			existing, err := cfg.Decrypt(dec)
+			if err != nil {
+				return errors.Wrap(err, "decrypting the stack's configuration")
+			}
+			old, err := ps.Config.Decrypt(dec)
+			if err != nil {
+				return errors.Wrap(err, "decrypting the stack's configuration")
+			}
+
+			data := configTemplateData{
+				Stack:   string(s.Ref().Name()),
+				Project: string(proj.Name),
+				Config:  make(map[string]string),
+			}
+			for k, v := range existing {
+				data.Config[k.String()] = v
+			}
+			values, err := renderConfigTemplate(filepath.Base(templateFile), string(text), data)
+			if err != nil {
+				return err
+			}
+
+			var enc config.Encrypter
+			var changed []config.Key
+			for _, key := range sortedRenderedKeys(values) {
+				rendered := values[key]
+				before, had := ps.Config[key]
+				if had && before.Secure() == rendered.Secret && old[key] == rendered.Value {
+					continue
+				}
+
+				v := config.NewValue(rendered.Value)
+				if rendered.Secret {
+					if enc == nil {
+						if enc, err = getStackEncrypter(s); err != nil {
+							return err
+						}
+					}
+					ciphertext, encerr := enc.EncryptValue(rendered.Value)
+					if encerr != nil {
+						return encerr
+					}
+					v = config.NewSecureValue(ciphertext)
+				}
+				if dryRun {
+					var beforep *config.Value
+					if had {
+						beforep = &before
+					}
+					fmt.Printf("%s:\n", prettyKey(key))
+					fmt.Printf("    before: %s\n", describeConfigValue(beforep))
+					fmt.Printf("    after:  %s\n", describeConfigValue(&v))
+				}
+				ps.Config[key] = v
+				changed = append(changed, key)
+			}
+
+			if len(changed) == 0 {
+				fmt.Printf("no change: the template's %d value(s) are already set\n", len(values))
+				return nil
+			}
+			if dryRun {
+				return printConfigFileDryRun(s, ps)
+			}
+			if err = saveProjectStack(s, ps); err != nil {
+				return err
+			}
+			fmt.Printf("Set %d of the template's %d configuration value(s) for stack '%s'.\n",
+				len(changed), len(values), s.Ref())
+			return nil
+		}),
+	}
+
+	renderCmd.PersistentFlags().StringVarP(
+		&templateFile, "template", "t", "",
+		"The template that renders the configuration values")
+	renderCmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"Show the changes that would be made to the configuration file without saving it")
+
+	return renderCmd
+}
+
+// configTemplateData is the data that a configuration template is executed with.
+type configTemplateData struct {
+	Stack   string            // the name of the stack whose configuration is rendered.
+	Project string            // the name of the stack's project.
+	Config  map[string]string // the stack's existing configuration, by key and namespace.
+}
+
+// renderedConfigValue is a configuration value that a template rendered.
+type renderedConfigValue struct {
+	Value  string
+	Secret bool
+}
+
+// renderConfigTemplate executes the configuration template with the given name and text, and returns the values
+// that it rendered by key. Errors in the template, and in the YAML that it renders, are reported along with the lines
+// on which they occur.
+func renderConfigTemplate(name, text string, data configTemplateData) (map[config.Key]renderedConfigValue, error) {
+	qualify := func(key string) string {
+		if !strings.Contains(key, tokens.TokenDelimiter) {
+			return data.Project + tokens.TokenDelimiter + key
+		}
+		return key
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"config": func(key string) (string, error) {
+			v, has := data.Config[qualify(key)]
+			if !has {
+				return "", errors.Errorf("configuration key '%s' is not set", key)
+			}
+			return v, nil
+		},
+		"env":   os.Getenv,
+		"quote": strconv.Quote,
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+
+	var rendered map[string]interface{}
+	if err = yaml.Unmarshal(out.Bytes(), &rendered); err != nil {
+		return nil, errors.Wrapf(err, "template: %s rendered invalid YAML (lines are those of the rendered output)",
+			name)
+	}
+
+	values := make(map[config.Key]renderedConfigValue)
+	for k, v := range rendered {
+		key, err := config.ParseKey(qualify(k))
+		if err != nil {
+			return nil, errors.Wrapf(err, "template: %s rendered an invalid key '%s'", name, k)
+		}
+
+		var value renderedConfigValue
+		if m, ok := v.(map[interface{}]interface{}); ok && len(m) == 1 && m["secret"] != nil {
+			value.Secret, v = true, m["secret"]
+		}
+		switch v := v.(type) {
+		case string:
+			value.Value = v
+		case int, float64, bool:
+			value.Value = fmt.Sprint(v)
+		default:
+			return nil, errors.Errorf("template: %s rendered an invalid value for '%s': expected a string, number, "+
+				"boolean, or {secret: <value>}", name, k)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// sortedRenderedKeys returns the keys of the given rendered values in order.
+func sortedRenderedKeys(values map[config.Key]renderedConfigValue) []config.Key {
+	keys := make(config.KeyArray, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Sort(keys)
+	return keys
+}
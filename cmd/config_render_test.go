@@ -0,0 +1,64 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestRenderConfigTemplate(t *testing.T) {
+	data := configTemplateData{
+		Stack:   "dev",
+		Project: "proj",
+		Config:  map[string]string{"proj:seed": "s3cr3t", "aws:region": "us-west-2"},
+	}
+
+	values, err := renderConfigTemplate("config.tmpl", `
+name: {{ .Project }}-{{ .Stack }}
+region: {{ index .Config "aws:region" }}
+aws:profile: {{ quote "dev profile" }}
+port: 8080
+enabled: true
+password: {secret: {{ quote (config "seed") }}}
+`, data)
+	assert.NoError(t, err)
+	assert.Equal(t, map[config.Key]renderedConfigValue{
+		config.MustMakeKey("proj", "name"):     {Value: "proj-dev"},
+		config.MustMakeKey("proj", "region"):   {Value: "us-west-2"},
+		config.MustMakeKey("aws", "profile"):   {Value: "dev profile"},
+		config.MustMakeKey("proj", "port"):     {Value: "8080"},
+		config.MustMakeKey("proj", "enabled"):  {Value: "true"},
+		config.MustMakeKey("proj", "password"): {Value: "s3cr3t", Secret: true},
+	}, values)
+
+	// Errors are reported with the lines on which they occur.
+	for text, message := range map[string]string{
+		"a: 1\nb: {{ .Nope }}\n":           "config.tmpl:2:",
+		"a: 1\n\nb: {{ config \"nope\" }}": "config.tmpl:3:",
+		"a: 1\nb: {{ if }}\n":              "config.tmpl:2:",
+		"a: 1\nb: c: d\n":                  "line 2",
+		"a: [1, 2]\n":                      "invalid value for 'a'",
+		"a:b:c: 1\n":                       "invalid key 'a:b:c'",
+	} {
+		_, err = renderConfigTemplate("config.tmpl", text, data)
+		if assert.Error(t, err, text) {
+			assert.Contains(t, err.Error(), message, text)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/secrets"
+	"github.com/pulumi/pulumi/pkg/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newConfigRotateKeyCmd(stackName *string) *cobra.Command {
+	var oldPassphraseFile string
+	var newPassphraseFile string
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Re-encrypt all of a stack's secrets with a new passphrase",
+		Long: "Re-encrypt all of a stack's secrets with a new passphrase.\n" +
+			"\n" +
+			"This command decrypts every secret configuration value and every secret in the stack's\n" +
+			"checkpoint using the current passphrase, and then re-encrypts them using a new passphrase.\n" +
+			"Every secret is verified to decrypt before anything is written; if any secret cannot be\n" +
+			"decrypted, nothing is changed.\n" +
+			"\n" +
+			"The current passphrase is read from --old-passphrase-file, PULUMI_CONFIG_PASSPHRASE, or\n" +
+			"the console, in that order. The new passphrase is read from --new-passphrase-file or the\n" +
+			"console.\n" +
+			"\n" +
+			"This command is only supported for stacks that use the passphrase secrets provider.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			ps, err := loadProjectStack(s)
+			if err != nil {
+				return err
+			}
+			if ps.EncryptionSalt == "" ||
+				(ps.SecretsProvider != "" && ps.SecretsProvider != "default" && ps.SecretsProvider != "passphrase") {
+				return errors.Errorf("stack '%s' does not use the passphrase secrets provider", s.Ref())
+			}
+
+			oldPhrase, err := readRotationPassphrase(oldPassphraseFile, false /*confirm*/)
+			if err != nil {
+				return errors.Wrap(err, "reading current passphrase")
+			}
+			oldSM, err := passphrase.NewPassphaseSecretsManager(oldPhrase, ps.EncryptionSalt)
+			if err != nil {
+				return err
+			}
+
+			newPhrase, err := readRotationPassphrase(newPassphraseFile, true /*confirm*/)
+			if err != nil {
+				return errors.Wrap(err, "reading new passphrase")
+			}
+			newSalt := newPassphraseEncryptionSalt(newPhrase)
+			newSM, err := passphrase.NewPassphaseSecretsManager(newPhrase, newSalt)
+			contract.AssertNoError(err)
+
+			oldDecrypter, err := oldSM.Decrypter()
+			if err != nil {
+				return err
+			}
+			newEncrypter, err := newSM.Encrypter()
+			if err != nil {
+				return err
+			}
+			newConfig, err := rotateConfigSecrets(ps.Config, oldDecrypter, newEncrypter)
+			if err != nil {
+				return err
+			}
+
+			// The checkpoint's secrets are decrypted by the secrets provider recorded in the deployment, which reads
+			// the passphrase from the environment.
+			restore := setConfigPassphraseEnv(oldPhrase)
+			dep, err := rotateDeploymentSecrets(s, newSM)
+			restore()
+			if err != nil {
+				return err
+			}
+
+			// Everything decrypted successfully, so we can now write the re-encrypted configuration and checkpoint.
+			// The configuration is written first, as it is the easier of the two to restore if the other fails to be
+			// written: the stack must never be left with a checkpoint and a configuration that need different
+			// passphrases.
+			oldConfig, oldSalt := ps.Config, ps.EncryptionSalt
+			ps.Config = newConfig
+			ps.EncryptionSalt = newSalt
+			if err = saveProjectStack(s, ps); err != nil {
+				return errors.Wrap(err, "saving re-encrypted configuration")
+			}
+			if err = s.ImportDeployment(commandContext(), dep); err != nil {
+				ps.Config = oldConfig
+				ps.EncryptionSalt = oldSalt
+				if restoreErr := saveProjectStack(s, ps); restoreErr != nil {
+					return errors.Wrapf(err, "saving re-encrypted checkpoint (the stack's configuration now uses "+
+						"the new passphrase, as restoring it failed: %v)", restoreErr)
+				}
+				return errors.Wrap(err, "saving re-encrypted checkpoint")
+			}
+
+			fmt.Printf("Rotated the secrets key for stack '%s'.\n", s.Ref())
+			return nil
+		}),
+	}
+
+	rotateCmd.PersistentFlags().StringVar(
+		&oldPassphraseFile, "old-passphrase-file", "",
+		"A file containing the passphrase which currently protects the stack's secrets")
+	rotateCmd.PersistentFlags().StringVar(
+		&newPassphraseFile, "new-passphrase-file", "",
+		"A file containing the new passphrase with which to protect the stack's secrets")
+
+	return rotateCmd
+}
+
+// readRotationPassphrase reads a passphrase from the given file, if any. Otherwise, the passphrase is read from
+// PULUMI_CONFIG_PASSPHRASE or the console if this is the current passphrase, or from the console (with confirmation)
+// if this is the new passphrase.
+func readRotationPassphrase(file string, confirm bool) (string, error) {
+	if file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return cmdutil.RemoveTralingNewline(string(b)), nil
+	}
+
+	if !confirm {
+		return readPassphrase("Enter your current passphrase to unlock config/secrets")
+	}
+
+	for {
+		first, err := cmdutil.ReadConsoleNoEcho("Enter your new passphrase to protect config/secrets")
+		if err != nil {
+			return "", err
+		}
+		second, err := cmdutil.ReadConsoleNoEcho("Re-enter your new passphrase to confirm")
+		if err != nil {
+			return "", err
+		}
+		if first == second {
+			return first, nil
+		}
+		cmdutil.Diag().Errorf(diag.Message("", "passphrases do not match"))
+	}
+}
+
+// setConfigPassphraseEnv sets PULUMI_CONFIG_PASSPHRASE to the given passphrase, returning a function that restores
+// its previous value.
+func setConfigPassphraseEnv(phrase string) func() {
+	old, hadOld := os.LookupEnv("PULUMI_CONFIG_PASSPHRASE")
+	contract.IgnoreError(os.Setenv("PULUMI_CONFIG_PASSPHRASE", phrase))
+	return func() {
+		if hadOld {
+			contract.IgnoreError(os.Setenv("PULUMI_CONFIG_PASSPHRASE", old))
+		} else {
+			contract.IgnoreError(os.Unsetenv("PULUMI_CONFIG_PASSPHRASE"))
+		}
+	}
+}
+
+// rotateConfigSecrets returns a copy of the given configuration with each secure value decrypted using the decrypter
+// and re-encrypted using the encrypter. If any value fails to decrypt, an error naming the key is returned.
+func rotateConfigSecrets(cfg config.Map, dec config.Decrypter, enc config.Encrypter) (config.Map, error) {
+	plaintexts, err := decryptConfigSecrets(cfg, dec)
+	if err != nil {
+		return nil, err
+	}
+	return encryptConfigSecrets(cfg, plaintexts, enc)
+}
+
+// decryptConfigSecrets decrypts each secure value of the given configuration using the decrypter, and returns the
+// plaintexts by key. If any value fails to decrypt, an error naming the key is returned.
+func decryptConfigSecrets(cfg config.Map, dec config.Decrypter) (map[config.Key]string, error) {
+	plaintexts := make(map[config.Key]string)
+	for key, value := range cfg {
+		if !value.Secure() {
+			continue
+		}
+		plaintext, err := value.Value(dec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt configuration value '%s'", prettyKey(key))
+		}
+		plaintexts[key] = plaintext
+	}
+	return plaintexts, nil
+}
+
+// encryptConfigSecrets returns a copy of the given configuration in which each secure value is replaced by its
+// plaintext, as returned by decryptConfigSecrets, encrypted using the encrypter. Only the secure leaves of an object
+// are encrypted.
+func encryptConfigSecrets(cfg config.Map, plaintexts map[config.Key]string,
+	enc config.Encrypter) (config.Map, error) {
+
+	encrypted := make(config.Map)
+	for key, value := range cfg {
+		plaintext, has := plaintexts[key]
+		if !value.Secure() || !has {
+			encrypted[key] = value
+			continue
+		}
+		reencrypted, err := value.Reencrypt(plaintext, enc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not encrypt configuration value '%s'", prettyKey(key))
+		}
+		encrypted[key] = reencrypted
+	}
+	return encrypted, nil
+}
+
+// rotateDeploymentSecrets loads the stack's current deployment and re-serializes it using the given secrets manager.
+func rotateDeploymentSecrets(s backend.Stack, sm secrets.Manager) (*apitype.UntypedDeployment, error) {
+	untyped, err := s.ExportDeployment(commandContext())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt the stack's checkpoint")
+	}
+
+	snap, err := stack.DeserializeUntypedDeployment(untyped, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt the stack's checkpoint")
+	}
+
+	sdep, err := stack.SerializeDeployment(snap, sm)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not re-encrypt the stack's checkpoint")
+	}
+	bytes, err := json.Marshal(sdep)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}, nil
+}
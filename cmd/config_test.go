@@ -15,8 +15,12 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pulumi/pulumi/pkg/resource/config"
@@ -41,3 +45,209 @@ func TestSecretDetection(t *testing.T) {
 	// The key name does not match the, so even though this "looks like" a secret, we say it is not.
 	assert.False(t, looksLikeSecret(config.MustMakeKey("test", "okay"), "1415fc1f4eaeb5e096ee58c1480016638fff29bf"))
 }
+
+func TestRotateConfigSecrets(t *testing.T) {
+	oldCrypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	newKey := make([]byte, config.SymmetricCrypterKeyBytes)
+	newKey[0] = 1
+	newCrypter := config.NewSymmetricCrypter(newKey)
+
+	secret, err := oldCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	cfg := config.Map{
+		config.MustMakeKey("test", "plain"):  config.NewValue("value"),
+		config.MustMakeKey("test", "secret"): config.NewSecureValue(secret),
+	}
+
+	rotated, err := rotateConfigSecrets(cfg, oldCrypter, newCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg[config.MustMakeKey("test", "plain")], rotated[config.MustMakeKey("test", "plain")])
+
+	v, err := rotated[config.MustMakeKey("test", "secret")].Value(newCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+	_, err = rotated[config.MustMakeKey("test", "secret")].Value(oldCrypter)
+	assert.Error(t, err)
+
+	// A value that cannot be decrypted with the old key fails the rotation as a whole.
+	_, err = rotateConfigSecrets(rotated, oldCrypter, newCrypter)
+	assert.Error(t, err)
+}
+
+func TestFilterConfigKeys(t *testing.T) {
+	keys := config.KeyArray{
+		config.MustMakeKey("aws", "region"),
+		config.MustMakeKey("aws", "profile"),
+		config.MustMakeKey("test", "dbHost"),
+	}
+
+	assert.Equal(t, keys[:2], filterConfigKeys(keys, "aws:"))
+	assert.Equal(t, config.KeyArray{keys[1]}, filterConfigKeys(keys, "aws:pro"))
+	assert.Equal(t, config.KeyArray{keys[2]}, filterConfigKeys(keys, "test:db"))
+	assert.Len(t, filterConfigKeys(keys, "gcp:"), 0)
+}
+
+func TestLoadStackConfigMergesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-config-files-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	base, local := filepath.Join(dir, "Pulumi.base.yaml"), filepath.Join(dir, "Pulumi.local.yaml")
+	assert.NoError(t, ioutil.WriteFile(base, []byte("config:\n  test:a: base\n  test:b: base\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(local, []byte("config:\n  test:b: local\n  test:c: local\n"), 0600))
+
+	defer func() { stackConfigFiles = nil }()
+	stackConfigFiles = []string{base, local}
+
+	// Values in later files override those in earlier ones, and changes are saved to the last file.
+	cfg, err := loadStackConfig(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, config.Map{
+		config.MustMakeKey("test", "a"): config.NewValue("base"),
+		config.MustMakeKey("test", "b"): config.NewValue("local"),
+		config.MustMakeKey("test", "c"): config.NewValue("local"),
+	}, cfg)
+	assert.Equal(t, local, stackConfigPath())
+
+	// Missing files are an error.
+	missing := filepath.Join(dir, "Pulumi.missing.yaml")
+	stackConfigFiles = []string{base, missing}
+	_, err = loadStackConfig(nil)
+	assert.EqualError(t, err, "configuration file '"+missing+"' does not exist")
+}
+
+func TestMergeConfigFilesSecrets(t *testing.T) {
+	baseCrypter := config.NewSymmetricCrypter(make([]byte, 32))
+	localCrypter := config.NewSymmetricCrypter([]byte("0123456789abcdef0123456789abcdef"))
+	encrypt := func(crypter config.Crypter, plaintext string) config.Value {
+		ciphertext, err := crypter.EncryptValue(plaintext)
+		assert.NoError(t, err)
+		return config.NewSecureValue(ciphertext)
+	}
+
+	a, b, c := config.MustMakeKey("test", "a"), config.MustMakeKey("test", "b"), config.MustMakeKey("test", "c")
+	base := &workspace.ProjectStack{EncryptionSalt: "base", Config: config.Map{
+		a: encrypt(baseCrypter, "base-a"),
+		b: encrypt(baseCrypter, "base-b"),
+	}}
+	local := &workspace.ProjectStack{EncryptionSalt: "local", Config: config.Map{
+		b: encrypt(localCrypter, "local-b"),
+		c: encrypt(localCrypter, "local-c"),
+	}}
+	getDecrypter := func(path string) (config.Decrypter, error) {
+		assert.Equal(t, "base.yaml", path)
+		return baseCrypter, nil
+	}
+	getEncrypter := func() (config.Encrypter, error) { return localCrypter, nil }
+
+	// The secrets of each file are decrypted with that file's secrets manager, and those from earlier files are
+	// re-encrypted with that of the last file.
+	cfg, err := mergeConfigFiles([]string{"base.yaml", "local.yaml"}, []*workspace.ProjectStack{base, local},
+		getDecrypter, getEncrypter)
+	assert.NoError(t, err)
+	plaintext, err := cfg.Decrypt(localCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, map[config.Key]string{a: "base-a", b: "local-b", c: "local-c"}, plaintext)
+
+	// Files that share a secrets manager are merged as they are.
+	base.EncryptionSalt = "local"
+	cfg, err = mergeConfigFiles([]string{"base.yaml", "local.yaml"}, []*workspace.ProjectStack{base, local},
+		func(string) (config.Decrypter, error) { return nil, errors.New("unexpected decrypter") },
+		func() (config.Encrypter, error) { return nil, errors.New("unexpected encrypter") })
+	assert.NoError(t, err)
+	assert.Equal(t, base.Config[a], cfg[a])
+}
+
+func TestCopyConfigValue(t *testing.T) {
+	src, dst := config.MustMakeKey("test", "old"), config.MustMakeKey("test", "new")
+	cfg := config.Map{src: config.NewSecureValue("ciphertext")}
+
+	// Secret values remain secret.
+	assert.NoError(t, copyConfigValue(cfg, src, dst, false))
+	assert.Equal(t, config.NewSecureValue("ciphertext"), cfg[dst])
+	assert.Equal(t, config.NewSecureValue("ciphertext"), cfg[src])
+
+	// An existing destination is only overwritten when forced.
+	cfg[src] = config.NewValue("plain")
+	assert.Error(t, copyConfigValue(cfg, src, dst, false))
+	assert.Equal(t, config.NewSecureValue("ciphertext"), cfg[dst])
+	assert.NoError(t, copyConfigValue(cfg, src, dst, true))
+	assert.Equal(t, config.NewValue("plain"), cfg[dst])
+
+	// The source must exist.
+	err := copyConfigValue(cfg, config.MustMakeKey("test", "missing"), dst, true)
+	assert.EqualError(t, err, "configuration key 'test:missing' not found")
+}
+
+func TestCopyConfigValueToStack(t *testing.T) {
+	srcCrypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	dstKey := make([]byte, config.SymmetricCrypterKeyBytes)
+	dstKey[0] = 1
+	dstCrypter := config.NewSymmetricCrypter(dstKey)
+
+	secret, err := srcCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	key := config.MustMakeKey("test", "secret")
+	from, to := config.Map{key: config.NewSecureValue(secret)}, config.Map{}
+
+	// Secret values are re-encrypted with the destination stack's key.
+	assert.NoError(t, copyConfigValueToStack(from, to, key, key, false, srcCrypter, dstCrypter))
+	assert.True(t, to[key].Secure())
+	v, err := to[key].Value(dstCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+
+	// A secret that cannot be decrypted leaves the destination unchanged.
+	other := config.MustMakeKey("test", "other")
+	err = copyConfigValueToStack(from, to, key, other, false, dstCrypter, dstCrypter)
+	assert.Error(t, err)
+	_, has := to[other]
+	assert.False(t, has)
+
+	// Only the secure leaves of an object are re-encrypted, and only they are hidden.
+	object, err := config.Value{}.SetPath("host", config.NewValue("db.example.com"))
+	assert.NoError(t, err)
+	object, err = object.SetPath("password", config.NewSecureValue(secret))
+	assert.NoError(t, err)
+	db := config.MustMakeKey("test", "db")
+	from[db] = object
+	assert.NoError(t, copyConfigValueToStack(from, to, db, db, false, srcCrypter, dstCrypter))
+	assert.Equal(t, `{"host":"db.example.com","password":"[secret]"}`, describeConfigValue(&object))
+	copied := to[db]
+	assert.Equal(t, `{"host":"db.example.com","password":"[secret]"}`, describeConfigValue(&copied))
+	assert.NotEqual(t, object, copied)
+	v, err = to[db].Value(dstCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"db.example.com","password":"hunter2"}`, v)
+}
+
+func TestEncryptConfigSecrets(t *testing.T) {
+	srcCrypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	dstKey := make([]byte, config.SymmetricCrypterKeyBytes)
+	dstKey[0] = 1
+	dstCrypter := config.NewSymmetricCrypter(dstKey)
+
+	secret, err := srcCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	cfg := config.Map{
+		config.MustMakeKey("test", "plain"):  config.NewValue("value"),
+		config.MustMakeKey("test", "secret"): config.NewSecureValue(secret),
+	}
+
+	// Decryption fails as a whole if any secret was not encrypted with the given key, before anything is encrypted.
+	_, err = decryptConfigSecrets(cfg, dstCrypter)
+	assert.EqualError(t, err, "could not decrypt configuration value 'test:secret': "+
+		"cipher: message authentication failed")
+
+	plaintexts, err := decryptConfigSecrets(cfg, srcCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, map[config.Key]string{config.MustMakeKey("test", "secret"): "hunter2"}, plaintexts)
+
+	encrypted, err := encryptConfigSecrets(cfg, plaintexts, dstCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, config.NewValue("value"), encrypted[config.MustMakeKey("test", "plain")])
+	v, err := encrypted[config.MustMakeKey("test", "secret")].Value(dstCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+}
@@ -0,0 +1,82 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newConfigValidateCmd(stack *string) *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check a stack's configuration against the project's config schema",
+		Long: "Check a stack's configuration against the project's config schema.\n" +
+			"\n" +
+			"The project may declare the configuration keys that its program reads in the 'configSchema'\n" +
+			"section of Pulumi.yaml, mapping each key to its 'type' ('string', 'integer', 'number', or\n" +
+			"'boolean'), and whether it is 'required' or must be a 'secret'. A key may also declare rules\n" +
+			"that its value must obey: a 'minimum' and 'maximum' for numbers, a regular expression 'pattern'\n" +
+			"that the whole of a string must match, and an 'enum' list of the values that it may have.\n" +
+			"\n" +
+			"This command reports the required keys that the stack does not set, the secret keys whose\n" +
+			"values are not encrypted, and the values that are not of their declared types or that break\n" +
+			"their keys' rules, and exits with a non-zero status if there are any. It also warns about\n" +
+			"the keys in the project's namespace that the schema does not declare, which may be misspelled.\n" +
+			"\n" +
+			"The same checks are made before 'pulumi preview', 'pulumi up', and 'pulumi validate' run the\n" +
+			"program, but this command does not run the program at all, so it is a fast check to make\n" +
+			"before a deployment.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stack, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			proj, _, err := readProject(pulumiAppProj)
+			if err != nil {
+				return err
+			}
+
+			sm, err := getStackSecretsManager(s)
+			if err != nil {
+				return errors.Wrap(err, "getting secrets manager")
+			}
+
+			cfg, err := getStackConfiguration(s, sm)
+			if err != nil {
+				return errors.Wrap(err, "getting stack configuration")
+			}
+			if err = checkStackConfig(s, proj, cfg); err != nil {
+				return err
+			}
+
+			fmt.Printf("The configuration of stack '%s' is valid.\n", s.Ref())
+			return nil
+		}),
+	}
+
+	return validateCmd
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend/httpstate"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/secrets"
+	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
 func getStackEncrypter(s backend.Stack) (config.Encrypter, error) {
@@ -44,24 +45,36 @@ func getStackDencrypter(s backend.Stack) (config.Decrypter, error) {
 }
 
 func getStackSecretsManager(s backend.Stack) (secrets.Manager, error) {
-	ps, err := loadProjectStack(s)
+	return getConfigFileSecretsManager(s, stackConfigPath())
+}
+
+// getConfigFileSecretsManager returns the secrets manager for the values in the given configuration file of the stack,
+// or in the stack's default configuration file if the path is empty.
+func getConfigFileSecretsManager(s backend.Stack, path string) (secrets.Manager, error) {
+	var ps *workspace.ProjectStack
+	var err error
+	if path != "" {
+		ps, err = workspace.LoadProjectStack(path)
+	} else {
+		ps, err = workspace.DetectProjectStack(s.Ref().Name())
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	if ps.SecretsProvider != "default" && ps.SecretsProvider != "passphrase" && ps.SecretsProvider != "" {
-		return newCloudSecretsManager(s.Ref().Name(), stackConfigFile, ps.SecretsProvider)
+		return newCloudSecretsManager(s.Ref().Name(), path, ps.SecretsProvider)
 	}
 
 	if ps.EncryptionSalt != "" {
-		return newPassphraseSecretsManager(s.Ref().Name(), stackConfigFile)
+		return newPassphraseSecretsManager(s.Ref().Name(), path)
 	}
 
 	switch stack := s.(type) {
 	case httpstate.Stack:
 		return newServiceSecretsManager(stack)
 	case filestate.Stack:
-		return newPassphraseSecretsManager(s.Ref().Name(), stackConfigFile)
+		return newPassphraseSecretsManager(s.Ref().Name(), path)
 	}
 
 	return nil, errors.Errorf("unknown stack type %s", reflect.TypeOf(s))
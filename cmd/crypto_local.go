@@ -96,9 +96,21 @@ func newPassphraseSecretsManager(stackName tokens.QName, configFile string) (sec
 		cmdutil.Diag().Errorf(diag.Message("", "passphrases do not match"))
 	}
 
-	// Produce a new salt.
+	// Produce a new salt and store it.
+	info.EncryptionSalt = newPassphraseEncryptionSalt(phrase)
+	if err = info.Save(configFile); err != nil {
+		return nil, err
+	}
+
+	// Finally, build the full secrets manager from the state we just saved
+	return passphrase.NewPassphaseSecretsManager(phrase, info.EncryptionSalt)
+}
+
+// newPassphraseEncryptionSalt produces a fresh salt for the given passphrase, returning it in the form that is stored
+// in a stack's configuration file.
+func newPassphraseEncryptionSalt(phrase string) string {
 	salt := make([]byte, 8)
-	_, err = cryptorand.Read(salt)
+	_, err := cryptorand.Read(salt)
 	contract.Assertf(err == nil, "could not read from system random")
 
 	// Encrypt a message and store it with the salt so we can test if the password is correct later.
@@ -106,12 +118,5 @@ func newPassphraseSecretsManager(stackName tokens.QName, configFile string) (sec
 	msg, err := crypter.EncryptValue("pulumi")
 	contract.AssertNoError(err)
 
-	// Now store the result and save it.
-	info.EncryptionSalt = fmt.Sprintf("v1:%s:%s", base64.StdEncoding.EncodeToString(salt), msg)
-	if err = info.Save(configFile); err != nil {
-		return nil, err
-	}
-
-	// Finally, build the full secrets manager from the state we just saved
-	return passphrase.NewPassphaseSecretsManager(phrase, info.EncryptionSalt)
+	return fmt.Sprintf("v1:%s:%s", base64.StdEncoding.EncodeToString(salt), msg)
 }
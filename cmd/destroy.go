@@ -16,13 +16,16 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/result"
 )
@@ -35,9 +38,17 @@ func newDestroyCmd() *cobra.Command {
 
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
+	var continueOnError bool
 	var diffDisplay bool
+	var failOnProtected bool
 	var parallel int
+	var readParallel int
+	var sliceNames []string
+	var providerParallel []string
 	var refresh bool
+	var resourceTimeout time.Duration
+	var resourceTimeouts []string
+	var retryAttempts int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
@@ -55,6 +66,14 @@ func newDestroyCmd() *cobra.Command {
 			"loaded from the associated state file in the workspace.  After running to completion,\n" +
 			"all of this stack's resources and associated state will be gone.\n" +
 			"\n" +
+			"Protected resources, and any resources that they depend on, are retained and reported at the end\n" +
+			"of the operation. Pass `--fail-on-protected` to fail the destroy instead.\n" +
+			"\n" +
+			"By default, the destroy stops at the first resource that fails to be deleted. Pass `--continue-on-error`\n" +
+			"to keep deleting the other resources instead. Resources that fail to be deleted, and any resources that\n" +
+			"they depend on, remain in the stack's state, and are reported at the end of the operation, so that the\n" +
+			"destroy may be retried.\n" +
+			"\n" +
 			"Warning: this command is generally irreversible and should be used with great care.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
@@ -92,6 +111,10 @@ func newDestroyCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			slices, err := getSlices(proj, sliceNames)
+			if err != nil {
+				return result.FromError(err)
+			}
 
 			m, err := getUpdateMetadata(message, root)
 			if err != nil {
@@ -109,11 +132,19 @@ func newDestroyCmd() *cobra.Command {
 			}
 
 			opts.Engine = engine.UpdateOptions{
-				Analyzers:     analyzers,
-				Parallel:      parallel,
-				Debug:         debug,
-				Refresh:       refresh,
-				UseLegacyDiff: useLegacyDiff(),
+				Analyzers:        analyzers,
+				Parallel:         parallel,
+				ProviderParallel: providerParallel,
+				ReadParallel:     readParallel,
+				Slices:           slices,
+				Debug:            debug,
+				Refresh:          refresh,
+				UseLegacyDiff:    useLegacyDiff(),
+				FailOnProtected:  failOnProtected,
+				ContinueOnError:  continueOnError,
+				ResourceTimeout:  resourceTimeout,
+				ResourceTimeouts: resourceTimeouts,
+				RetryPolicy:      resource.RetryPolicy{Attempts: retryAttempts},
 			}
 
 			_, res := s.Destroy(commandContext(), backend.UpdateOperation{
@@ -125,6 +156,7 @@ func newDestroyCmd() *cobra.Command {
 				SecretsManager:     sm,
 				Scopes:             cancellationScopes,
 			})
+			res = auditUpdate(s, apitype.DestroyUpdate, res)
 			if res != nil && res.Error() == context.Canceled {
 				return result.FromError(errors.New("destroy cancelled"))
 			}
@@ -138,9 +170,10 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	cmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 	cmd.PersistentFlags().StringVarP(
 		&message, "message", "m", "",
 		"Optional message to associate with the destroy operation")
@@ -149,15 +182,40 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().StringSliceVar(
 		&analyzers, "analyzer", []string{},
 		"Run one or more analyzers as part of this update")
+	cmd.PersistentFlags().BoolVar(
+		&continueOnError, "continue-on-error", false,
+		"Continue deleting resources after a resource fails to be deleted, retaining it and the resources it depends on")
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVar(
+		&failOnProtected, "fail-on-protected", false,
+		"Fail the destroy if any protected resources are encountered, rather than retaining them")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().IntVar(
+		&readParallel, "parallel-read", 0,
+		"Allow N resource reads to run in parallel at once when refreshing the stack's resources. Defaults to --parallel")
+	cmd.PersistentFlags().StringArrayVar(
+		&sliceNames, "slice", []string{}, sliceFlagUsage)
+	cmd.PersistentFlags().StringArrayVar(
+		&providerParallel, "provider-parallel", []string{},
+		"Allow at most N resource operations to run in parallel against the provider for package PKG (PKG=N)")
 	cmd.PersistentFlags().BoolVarP(
 		&refresh, "refresh", "r", false,
 		"Refresh the state of the stack's resources before this update")
+	cmd.PersistentFlags().DurationVar(
+		&resourceTimeout, "resource-timeout", 0,
+		"The time limit for each resource delete that does not declare a custom timeout (0 for no limit)")
+	cmd.PersistentFlags().StringArrayVar(
+		&resourceTimeouts, "resource-timeouts", []string{},
+		"Limit an operation on the resources whose URNs match a pattern, overriding their declared timeouts and "+
+			"the stack's pulumi:resourceTimeouts configuration (PATTERN:OPERATION=DURATION, e.g. '*:delete=20m')")
+	cmd.PersistentFlags().IntVar(
+		&retryAttempts, "retry-attempts", 0,
+		"The number of times to attempt each resource delete that fails transiently, for resources that do not "+
+			"declare a retry policy (0 for the default of 1)")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -56,7 +56,11 @@ waiting for changes to these resources to complete. You should confirm whether o
 operations listed completed successfully by checking the state of the appropriate provider.
 For example, if you are using AWS, you can confirm using the AWS Console.
 
-Once you have confirmed the status of the interrupted operations, you can repair your stack
+If the operations were interrupted during an update, you can continue the update using
+'pulumi up --continue', which refreshes the resources whose updates or deletes were
+interrupted before proceeding.
+
+Otherwise, once you have confirmed the status of the interrupted operations, you can repair your stack
 using 'pulumi stack export' to export your stack to a file. For each operation that succeeded,
 remove that operation from the "pending_operations" section of the file. Once this is complete,
 use 'pulumi stack import' to import the repaired stack.
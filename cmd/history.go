@@ -88,6 +88,7 @@ This command lists data about previous updates for a stack.`,
 // updateInfoJSON is the shape of the --json output for a configuration value.  While we can add fields to this
 // structure in the future, we should not change existing fields.
 type updateInfoJSON struct {
+	ID          string                     `json:"id,omitempty"`
 	Kind        string                     `json:"kind"`
 	StartTime   string                     `json:"startTime"`
 	Message     string                     `json:"message"`
@@ -108,6 +109,7 @@ func displayUpdatesJSON(updates []backend.UpdateInfo, decrypter config.Decrypter
 	updatesJSON := make([]updateInfoJSON, len(updates))
 	for idx, update := range updates {
 		info := updateInfoJSON{
+			ID:          update.ID,
 			Kind:        string(update.Kind),
 			StartTime:   time.Unix(update.StartTime, 0).UTC().Format(timeFormat),
 			Message:     update.Message,
@@ -117,12 +119,18 @@ func displayUpdatesJSON(updates []backend.UpdateInfo, decrypter config.Decrypter
 		info.Config = make(map[string]configValueJSON)
 		for k, v := range update.Config {
 			configValue := configValueJSON{
-				Secret: v.Secure(),
+				Secret:    v.Secure() || v.Reference(),
+				Object:    v.Object(),
+				Reference: v.ReferenceURI(),
 			}
-			if !v.Secure() || (v.Secure() && decrypter != nil) {
+			// The secret to which a reference refers is never saved, so there is no value to show.
+			if !v.Reference() && (!v.Secure() || (v.Secure() && decrypter != nil)) {
 				value, err := v.Value(decrypter)
 				contract.AssertNoError(err)
 				configValue.Value = makeStringRef(value)
+			} else if v.Object() {
+				// Only the secure leaves of an object are secret, so the rest of it may be shown.
+				configValue.Value = makeStringRef(v.Masked())
 			}
 
 			info.Config[k.String()] = configValue
@@ -155,6 +163,9 @@ func displayUpdatesConsole(updates []backend.UpdateInfo, opts display.Options) e
 
 	for _, update := range updates {
 
+		if update.ID != "" {
+			fmt.Printf("UpdateID: %v\n", update.ID)
+		}
 		fmt.Printf("UpdateKind: %v\n", update.Kind)
 		if update.Result == "succeeded" {
 			fmt.Print(opts.Color.Colorize(fmt.Sprintf("%sStatus: %v%s\n", colors.Green, update.Result, colors.Reset)))
@@ -0,0 +1,232 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+// importManifest lists the existing resources that `pulumi import` imports into a stack.
+type importManifest struct {
+	Resources []importManifestEntry `json:"resources" yaml:"resources"`
+}
+
+type importManifestEntry struct {
+	Type string `json:"type" yaml:"type"` // the type token of the resource, e.g. aws:s3/bucket:Bucket.
+	Name string `json:"name" yaml:"name"` // the name of the resource in the stack.
+	ID   string `json:"id" yaml:"id"`     // the provider's ID of the existing resource.
+}
+
+func newImportCmd() *cobra.Command {
+	var from string
+	var message string
+	var stack string
+
+	// Flags for engine.UpdateOptions.
+	var diffDisplay bool
+	var parallel int
+	var skipPreview bool
+	var yes bool
+
+	var cmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import existing resources into a stack",
+		Long: "Import existing resources into a stack.\n" +
+			"\n" +
+			"This command reads a manifest of existing resources and imports all of them into the stack's\n" +
+			"state in a single update, without running the stack's program. The manifest is a JSON or YAML\n" +
+			"file of the form:\n" +
+			"\n" +
+			"    {\"resources\": [{\"type\": \"aws:s3/bucket:Bucket\", \"name\": \"logs\", \"id\": \"my-logs\"}]}\n" +
+			"\n" +
+			"The current state of each resource is read using its package's default provider, and becomes\n" +
+			"the resource's inputs in the stack. A resource that cannot be imported does not stop the import\n" +
+			"of the others; each failure is reported, followed by the number of resources that were imported.\n" +
+			"Resources whose names the stack already uses are skipped with a warning.\n" +
+			"\n" +
+			"The project is loaded from the current directory. Use the `-C` or `--cwd` flag to use a\n" +
+			"different directory.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			if from == "" {
+				return result.Errorf("the manifest of resources to import must be specified using --from")
+			}
+			imports, err := readImportManifest(from)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			interactive := cmdutil.Interactive()
+			if !interactive {
+				yes = true // auto-approve changes, since we cannot prompt.
+			}
+
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			var displayType = display.DisplayProgress
+			if diffDisplay {
+				displayType = display.DisplayDiff
+			}
+
+			opts.Display = display.Options{
+				Color:         cmdutil.GetGlobalColorization(),
+				IsInteractive: interactive,
+				Type:          displayType,
+			}
+
+			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			proj, root, err := readProject(pulumiAppProj)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			m, err := getUpdateMetadata(message, root)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "gathering environment metadata"))
+			}
+
+			sm, err := getStackSecretsManager(s)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting secrets manager"))
+			}
+
+			cfg, err := getStackConfiguration(s, sm)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting stack configuration"))
+			}
+
+			opts.Engine = engine.UpdateOptions{
+				Parallel:      parallel,
+				UseLegacyDiff: useLegacyDiff(),
+				Imports:       imports,
+			}
+
+			changes, res := s.Update(commandContext(), backend.UpdateOperation{
+				Proj:               proj,
+				Root:               root,
+				M:                  m,
+				Opts:               opts,
+				StackConfiguration: cfg,
+				SecretsManager:     sm,
+				Scopes:             cancellationScopes,
+			})
+			res = auditUpdate(s, apitype.ImportUpdate, res)
+			if res != nil && res.Error() == context.Canceled {
+				return result.FromError(errors.New("import cancelled"))
+			}
+			if res == nil || changes != nil {
+				fmt.Printf("Imported %d of %d resource(s)\n", changes[deploy.OpImport], len(imports))
+			}
+			return PrintEngineResult(res)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&from, "from", "",
+		"The JSON or YAML manifest that lists the type, name, and ID of each resource to import")
+	cmd.PersistentFlags().StringVarP(
+		&message, "message", "m", "",
+		"Optional message to associate with the update operation")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not perform a preview before performing the import")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform the import after previewing it")
+
+	return cmd
+}
+
+// readImportManifest reads the resources to import from the manifest at the given path, which is decoded as JSON or
+// YAML according to its extension.
+func readImportManifest(path string) ([]deploy.Import, error) {
+	m, _ := encoding.Detect(path)
+	if m == nil {
+		return nil, errors.Errorf("could not read import manifest %s: expected a .json or .yaml file", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading import manifest")
+	}
+	var manifest importManifest
+	if err = m.Unmarshal(b, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "could not read import manifest %s", path)
+	}
+	if len(manifest.Resources) == 0 {
+		return nil, errors.Errorf("import manifest %s does not list any resources", path)
+	}
+
+	imports := make([]deploy.Import, len(manifest.Resources))
+	seen := make(map[string]bool)
+	for i, r := range manifest.Resources {
+		if r.Type == "" || r.Name == "" || r.ID == "" {
+			return nil, errors.Errorf("resource %d of import manifest %s must have a type, a name, and an ID", i, path)
+		}
+		t, err := tokens.ParseTypeToken(r.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resource %d of import manifest %s", i, path)
+		}
+		if !tokens.IsQName(r.Name) {
+			return nil, errors.Errorf("resource %d of import manifest %s has an invalid name %q", i, path, r.Name)
+		}
+		key := r.Type + "::" + r.Name
+		if seen[key] {
+			return nil, errors.Errorf("import manifest %s lists the %s resource named %s more than once",
+				path, r.Type, r.Name)
+		}
+		seen[key] = true
+
+		imports[i] = deploy.Import{Type: t, Name: tokens.QName(r.Name), ID: resource.ID(r.ID)}
+	}
+	return imports, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func writeImportManifest(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadImportManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expected := []deploy.Import{
+		{Type: "aws:s3/bucket:Bucket", Name: "logs", ID: "my-logs"},
+		{Type: "aws:s3/bucket:Bucket", Name: "assets", ID: "my-assets"},
+	}
+
+	imports, err := readImportManifest(writeImportManifest(t, dir, "manifest.json", `{"resources": [
+		{"type": "aws:s3/bucket:Bucket", "name": "logs", "id": "my-logs"},
+		{"type": "aws:s3/bucket:Bucket", "name": "assets", "id": "my-assets"}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, imports)
+
+	imports, err = readImportManifest(writeImportManifest(t, dir, "manifest.yaml", `resources:
+- type: aws:s3/bucket:Bucket
+  name: logs
+  id: my-logs
+- type: aws:s3/bucket:Bucket
+  name: assets
+  id: my-assets
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, imports)
+
+	// Duplicate, incomplete, and empty manifests are rejected.
+	_, err = readImportManifest(writeImportManifest(t, dir, "dup.json", `{"resources": [
+		{"type": "aws:s3/bucket:Bucket", "name": "logs", "id": "a"},
+		{"type": "aws:s3/bucket:Bucket", "name": "logs", "id": "b"}
+	]}`))
+	assert.Error(t, err)
+	_, err = readImportManifest(writeImportManifest(t, dir, "noid.json",
+		`{"resources": [{"type": "aws:s3/bucket:Bucket", "name": "logs"}]}`))
+	assert.Error(t, err)
+	_, err = readImportManifest(writeImportManifest(t, dir, "badtype.json",
+		`{"resources": [{"type": "bucket", "name": "logs", "id": "a"}]}`))
+	assert.Error(t, err)
+	_, err = readImportManifest(writeImportManifest(t, dir, "empty.json", `{"resources": []}`))
+	assert.Error(t, err)
+}
@@ -154,9 +154,10 @@ func newLogsCmd() *cobra.Command {
 	logsCmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	logsCmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	logsCmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 	logsCmd.PersistentFlags().BoolVarP(
 		&jsonOut, "json", "j", false, "Emit output as JSON")
 	logsCmd.PersistentFlags().BoolVarP(
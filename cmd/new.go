@@ -680,7 +680,7 @@ func promptForConfig(
 		var secret bool
 		if stackConfig != nil {
 			// Use the stack's existing value as the default.
-			if val, ok := stackConfig[k]; ok {
+			if val, ok := stackConfig[k]; ok && !val.Reference() {
 				// It's OK to pass a nil or non-nil crypter for non-secret values.
 				value, err := val.Value(decrypter)
 				if err != nil {
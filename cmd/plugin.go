@@ -44,8 +44,11 @@ func newPluginCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newPluginInstallCmd())
+	cmd.AddCommand(newPluginLockCmd())
 	cmd.AddCommand(newPluginLsCmd())
+	cmd.AddCommand(newPluginPublishCmd())
 	cmd.AddCommand(newPluginRmCmd())
+	cmd.AddCommand(newPluginUnpackCmd())
 
 	return cmd
 }
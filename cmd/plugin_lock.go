@@ -0,0 +1,70 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newPluginLockCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "lock",
+		Args:  cmdutil.NoArgs,
+		Short: "Record the checksums of the plugins used by the current project",
+		Long: "Record the checksums of the plugins used by the current project.\n" +
+			"\n" +
+			"This command computes the set of plugins required by the current project, which\n" +
+			"must already be installed, and writes the checksum of each one to " + workspace.PluginLockFile + "\n" +
+			"next to the project file, replacing any checksums that were recorded before.\n" +
+			"\n" +
+			"Each time the project is previewed or updated, the plugins it uses are checked\n" +
+			"against this file, and the operation fails if any of them have changed.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			_, root, err := readProject(pulumiAppProj)
+			if err != nil {
+				return err
+			}
+
+			plugins, err := getProjectPlugins()
+			if err != nil {
+				return err
+			}
+			lock, err := workspace.LockPlugins(plugins)
+			if err != nil {
+				return errors.Wrap(err, "locking plugins (run `pulumi plugin install` to install missing plugins)")
+			}
+
+			path := filepath.Join(root, workspace.PluginLockFile)
+			if err = lock.Save(path); err != nil {
+				return errors.Wrapf(err, "writing %s", path)
+			}
+
+			n := len(lock.Plugins)
+			fmt.Printf("Recorded the %s of %d %s in %s\n",
+				english.PluralWord(n, "checksum", ""), n, english.PluralWord(n, "plugin", ""), path)
+			return nil
+		}),
+	}
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/util/archive"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newPluginPublishCmd() *cobra.Command {
+	var force bool
+	var serverURL string
+
+	var cmd = &cobra.Command{
+		Use:   "publish KIND NAME VERSION DIR",
+		Args:  cmdutil.ExactArgs(4),
+		Short: "Publish a plugin to a plugin server",
+		Long: "Publish a plugin to a plugin server.\n" +
+			"\n" +
+			"This command packages the contents of DIR, which must contain the plugin's built\n" +
+			"executable, into a tarball and uploads it, along with a manifest that records its\n" +
+			"checksum, to the plugin server given by --server.  The server must accept PUT\n" +
+			"requests; once published, the plugin may be installed with\n" +
+			"`pulumi plugin install KIND NAME VERSION --server URL`.\n" +
+			"\n" +
+			"The plugin is published for the current OS and architecture.  Publishing a version\n" +
+			"that the server already has is an error unless --force is passed.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if serverURL == "" {
+				return errors.New("missing required flag --server")
+			}
+			if !workspace.IsPluginKind(args[0]) {
+				return errors.Errorf("unrecognized plugin kind: %s", args[0])
+			}
+			version, err := semver.ParseTolerant(args[2])
+			if err != nil {
+				return errors.Wrap(err, "invalid plugin semver")
+			}
+			info := workspace.PluginInfo{
+				Kind:      workspace.PluginKind(args[0]),
+				Name:      args[1],
+				Version:   &version,
+				ServerURL: serverURL,
+			}
+
+			// Make sure that the plugin has been built before packaging it, so that a broken plugin is never
+			// published.
+			dir := args[3]
+			exe := filepath.Join(dir, info.File())
+			fi, err := os.Stat(exe)
+			if err != nil {
+				return errors.Wrapf(err, "%s does not contain the plugin's executable %s; has it been built?",
+					dir, info.File())
+			}
+			if !fi.Mode().IsRegular() || (runtime.GOOS != "windows" && fi.Mode()&0111 == 0) {
+				return errors.Errorf("%s is not an executable file", exe)
+			}
+
+			tarball, err := archive.Tgz(dir)
+			if err != nil {
+				return errors.Wrapf(err, "packaging %s", dir)
+			}
+			manifest, err := info.Publish(tarball, force)
+			if _, exists := err.(workspace.PluginVersionExistsError); exists {
+				return errors.Wrap(err, "pass --force to replace it")
+			} else if err != nil {
+				return errors.Wrapf(err, "publishing %s plugin %s", info.Kind, info)
+			}
+
+			fmt.Printf("Published %s plugin %s (%s-%s) to %s\n", info.Kind, info, manifest.OS, manifest.Arch, serverURL)
+			fmt.Printf("SHA-256 checksum: %s\n", manifest.Checksum)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVar(&force,
+		"force", false, "Replace the plugin if this version has already been published")
+	cmd.PersistentFlags().StringVar(&serverURL,
+		"server", "", "The URL of the plugin server to publish to")
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newPluginUnpackCmd() *cobra.Command {
+	var checksum string
+	var force bool
+
+	var cmd = &cobra.Command{
+		Use:   "unpack TARBALL DIR",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Extract the contents of a published plugin tarball",
+		Long: "Extract the contents of a published plugin tarball.\n" +
+			"\n" +
+			"This command verifies the checksum of TARBALL, a plugin tarball such as one that\n" +
+			"`pulumi plugin publish` uploads, and then extracts its files into DIR, so that they\n" +
+			"may be inspected or edited.  The expected checksum is read from the manifest that is\n" +
+			"published alongside the tarball, in TARBALL.json, unless it is given by --checksum.\n" +
+			"Nothing is extracted if the checksum does not match or the tarball is corrupt.\n" +
+			"\n" +
+			"DIR is created if it does not exist.  Unpacking into a directory that is not empty\n" +
+			"is an error unless --force is passed.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			file, dir := args[0], args[1]
+			tarball, err := ioutil.ReadFile(file)
+			if err != nil {
+				return errors.Wrapf(err, "reading %s", file)
+			}
+
+			var manifest *workspace.PluginManifest
+			if checksum == "" {
+				manifestFile := file + ".json"
+				if manifest, err = workspace.ReadPluginManifest(manifestFile); err != nil {
+					if os.IsNotExist(errors.Cause(err)) {
+						return errors.Errorf("%s does not exist; pass --checksum to give the tarball's checksum",
+							manifestFile)
+					}
+					return err
+				}
+				checksum = manifest.Checksum
+			}
+			if err = workspace.VerifyPluginTarball(tarball, checksum); err != nil {
+				return errors.Wrapf(err, "verifying %s", file)
+			}
+			if err = workspace.UnpackPluginTarball(tarball, dir, force); err != nil {
+				return errors.Wrapf(err, "unpacking %s", file)
+			}
+
+			if manifest != nil {
+				fmt.Printf("Unpacked %s plugin %s-%s (%s-%s) to %s\n",
+					manifest.Kind, manifest.Name, manifest.Version, manifest.OS, manifest.Arch, dir)
+			} else {
+				fmt.Printf("Unpacked %s to %s\n", file, dir)
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVar(&checksum,
+		"checksum", "", "The expected SHA-256 checksum of the tarball, as a hex string, instead of its manifest's")
+	cmd.PersistentFlags().BoolVar(&force,
+		"force", false, "Unpack into DIR even if it is not empty, overwriting any files that the tarball contains")
+
+	return cmd
+}
@@ -15,31 +15,56 @@
 package cmd
 
 import (
+	"strconv"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/result"
 )
 
 func newPreviewCmd() *cobra.Command {
+	var compareTo string
 	var debug bool
 	var expectNop bool
 	var message string
+	var refresh bool
 	var stack string
 
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
+	var costEstimator string
+	var deprecationErrors bool
 	var diffDisplay bool
+	var diffFormat string
+	var features []string
 	var jsonDisplay bool
+	var jsonFile string
+	var logResources []string
+	var maxErrors int
+	var mockFixtures string
 	var parallel int
+	var readParallel int
+	var sliceNames []string
+	var sliceCreateMissing bool
+	var targetReplaces []string
+	var targetDependents bool
+	var providerParallel []string
+	var saveDiffPath string
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var showURNs bool
+	var showSamesReason bool
 	var suppressOutputs bool
+	var useMocks bool
+	var providerDryRun bool
 
 	var cmd = &cobra.Command{
 		Use:        "preview",
@@ -55,33 +80,104 @@ func newPreviewCmd() *cobra.Command {
 			"operations must take place to achieve the desired state. No changes to the stack will\n" +
 			"actually take place.\n" +
 			"\n" +
+			"Pass --json to display the preview as JSON rather than in human-readable form. To capture the JSON\n" +
+			"while still displaying the preview, pass --json-file with the path of a file to write the JSON to,\n" +
+			"or '-' to write it to stdout and display the preview on stderr instead.\n" +
+			"\n" +
+			"Pass --compare-to with a git branch, tag, or commit to review how a change to the program alters\n" +
+			"the plan: the program at that revision is previewed first, then the program in the working tree,\n" +
+			"both against the stack's current state and configuration, and the differences between the two\n" +
+			"plans are shown as a unified diff. Files in the project directory that the revision does not have,\n" +
+			"such as installed dependencies, are linked into its copy of the project.\n" +
+			"\n" +
+			"Pass --expect-no-changes to fail if the preview proposes any changes, exiting with code " +
+			strconv.Itoa(driftExitCode) + "\n" +
+			"rather than the code used for other failures. Together with --refresh, which first brings the\n" +
+			"stack's state up to date with its resources, this detects drift, e.g. in a scheduled CI job.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			if jsonFile != "" {
+				jsonDisplay = true
+			}
+
 			var displayType = display.DisplayProgress
 			if diffDisplay {
 				displayType = display.DisplayDiff
 			}
+			switch diffFormat {
+			case "", "pretty":
+			case "unified":
+				if jsonDisplay && jsonFile == "" {
+					return result.Errorf("--diff-format unified may not be used with --json")
+				}
+				displayType = display.DisplayUnifiedDiff
+			default:
+				return result.Errorf("unsupported diff format '%s': expected 'pretty' or 'unified'", diffFormat)
+			}
+
+			if compareTo != "" && jsonDisplay {
+				return result.Errorf("--compare-to may not be used with --json or --json-file")
+			}
 
+			mocks, err := getMocks(useMocks, mockFixtures)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if err = checkProviderDryRun(providerDryRun, mocks); err != nil {
+				return result.FromError(err)
+			}
+
+			logPatterns, err := resource.ParseURNPatterns(logResources)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "invalid --log-resource"))
+			}
+			replaceTargets, err := getReplaceTargets(targetReplaces, targetDependents)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			// The display cannot fail the preview itself, so it hands back any error writing the --json-file.
+			var jsonErr error
 			opts := backend.UpdateOptions{
 				Engine: engine.UpdateOptions{
-					Analyzers:     analyzers,
-					Parallel:      parallel,
-					Debug:         debug,
-					UseLegacyDiff: useLegacyDiff(),
+					Analyzers:         analyzers,
+					Parallel:          parallel,
+					ProviderParallel:  providerParallel,
+					ReadParallel:      readParallel,
+					Debug:             debug,
+					UseLegacyDiff:     useLegacyDiff(),
+					ExplainSames:      showSamesReason,
+					LogResources:      logPatterns,
+					Features:          features,
+					CostEstimator:     costEstimator,
+					DeprecationErrors: deprecationErrors,
+					MaxErrors:         maxErrors,
+					Refresh:           refresh,
+					ReplaceTargets:    replaceTargets,
+					TargetDependents:  targetDependents,
+					Mocks:             mocks,
+					ProviderDryRun:    providerDryRun,
 				},
 				Display: display.Options{
 					Color:                cmdutil.GetGlobalColorization(),
 					ShowConfig:           showConfig,
 					ShowReplacementSteps: showReplacementSteps,
 					ShowSameResources:    showSames,
+					ShowURNs:             showURNs,
 					SuppressOutputs:      suppressOutputs,
 					IsInteractive:        cmdutil.Interactive(),
 					Type:                 displayType,
 					JSONDisplay:          jsonDisplay,
+					JSONPath:             jsonFile,
+					JSONError:            &jsonErr,
 					Debug:                debug,
+					LogResources:         logPatterns,
+					SaveDiffPath:         saveDiffPath,
 				},
+				Metrics: metricsSink,
 			}
 
 			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
@@ -93,11 +189,19 @@ func newPreviewCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			if opts.Engine.Slices, err = getSlices(proj, sliceNames); err != nil {
+				return result.FromError(err)
+			}
+			if err = checkSliceCreateMissing(sliceNames, sliceCreateMissing); err != nil {
+				return result.FromError(err)
+			}
+			opts.Engine.SliceCreateMissing = sliceCreateMissing
 
 			m, err := getUpdateMetadata("", root)
 			if err != nil {
 				return result.FromError(errors.Wrap(err, "gathering environment metadata"))
 			}
+			addFeatureMetadata(m, features)
 
 			sm, err := getStackSecretsManager(s)
 			if err != nil {
@@ -108,8 +212,11 @@ func newPreviewCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(errors.Wrap(err, "getting stack configuration"))
 			}
+			if err = checkStackConfig(s, proj, cfg); err != nil {
+				return result.FromError(err)
+			}
 
-			changes, res := s.Preview(commandContext(), backend.UpdateOperation{
+			op := backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
 				M:                  m,
@@ -117,31 +224,45 @@ func newPreviewCmd() *cobra.Command {
 				StackConfiguration: cfg,
 				SecretsManager:     sm,
 				Scopes:             cancellationScopes,
-			})
+			}
+			var changes engine.ResourceChanges
+			var res result.Result
+			if compareTo != "" {
+				changes, res = previewComparedTo(compareTo, s, op)
+			} else {
+				changes, res = s.Preview(commandContext(), op)
+			}
 
 			switch {
 			case res != nil:
 				return PrintEngineResult(res)
+			case jsonErr != nil:
+				return result.FromError(jsonErr)
 			case expectNop && changes != nil && changes.HasChanges():
-				return result.FromError(errors.New("error: no changes were expected but changes were proposed"))
+				return result.FromError(driftError(changes))
 			default:
 				return nil
 			}
 		}),
 	}
 
+	cmd.PersistentFlags().StringVar(
+		&compareTo, "compare-to", "",
+		"Compare the plan of the program in the working tree with that of the program at this git revision")
 	cmd.PersistentFlags().BoolVarP(
 		&debug, "debug", "d", false,
 		"Print detailed debugging output during resource operations")
 	cmd.PersistentFlags().BoolVar(
 		&expectNop, "expect-no-changes", false,
-		"Return an error if any changes are proposed by this preview")
+		"Return an error, and exit with code "+strconv.Itoa(driftExitCode)+", if any changes are proposed by "+
+			"this preview")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	cmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 
 	cmd.PersistentFlags().StringVarP(
 		&message, "message", "m", "",
@@ -151,15 +272,68 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().StringSliceVar(
 		&analyzers, "analyzer", []string{},
 		"Run one or more analyzers as part of this update")
+	cmd.PersistentFlags().StringArrayVar(
+		&features, "feature", []string{},
+		"Enable the named feature flag for this preview; may be specified multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&deprecationErrors, "werror", false,
+		"Fail the preview if any deprecated resource types or properties are used, rather than warning")
+	cmd.PersistentFlags().StringVar(
+		&costEstimator, "cost", "",
+		"Estimate the change in cost of the previewed changes using the named cost estimator plugin")
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&diffFormat, "diff-format", "pretty",
+		"The format of the diff display: 'pretty', or 'unified' to show each resource's changes as a unified diff")
 	cmd.Flags().BoolVarP(
 		&jsonDisplay, "json", "j", false,
 		"Serialize the preview diffs, operations, and overall output as JSON")
+	cmd.PersistentFlags().StringVar(
+		&jsonFile, "json-file", "",
+		"Write the preview serialized as JSON to this file, or to stdout if '-', in addition to displaying it")
+	cmd.PersistentFlags().StringArrayVar(
+		&logResources, "log-resource", []string{},
+		"Log the operations on the resources whose URNs match this pattern in detail, as --debug does for every "+
+			"resource; '*' matches any characters and '?' a single character. May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&maxErrors, "max-errors", 0,
+		"Stop the preview after N errors have been reported, suppressing any further errors (0 for no limit)")
+	cmd.PersistentFlags().BoolVar(
+		&useMocks, "mock", false,
+		"Satisfy every resource provider operation with a mock rather than the provider, so that no cloud is contacted")
+	cmd.PersistentFlags().StringVar(
+		&mockFixtures, "mock-fixtures", "",
+		"Mock resource providers, as --mock does, returning the canned outputs and function results in this JSON or "+
+			"YAML file")
+	cmd.PersistentFlags().BoolVar(
+		&providerDryRun, "provider-dry-run", false,
+		"Ask resource providers to run their logic without calling cloud APIs, so that a provider can be tested "+
+			"without changing real resources")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().IntVar(
+		&readParallel, "parallel-read", 0,
+		"Allow N resource reads to run in parallel at once when refreshing the stack's resources. Defaults to --parallel")
+	cmd.PersistentFlags().StringArrayVar(
+		&sliceNames, "slice", []string{}, sliceFlagUsage)
+	cmd.PersistentFlags().BoolVar(
+		&sliceCreateMissing, "slice-create-missing", false, sliceCreateMissingFlagUsage)
+	cmd.PersistentFlags().StringArrayVar(
+		&targetReplaces, "target-replace", []string{}, targetReplaceFlagUsage)
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false, targetDependentsFlagUsage)
+	cmd.PersistentFlags().StringArrayVar(
+		&providerParallel, "provider-parallel", []string{},
+		"Allow at most N resource operations to run in parallel against the provider for package PKG (PKG=N)")
+	cmd.PersistentFlags().BoolVarP(
+		&refresh, "refresh", "r", false,
+		"Refresh the state of the stack's resources before this preview")
+	cmd.PersistentFlags().StringVar(
+		&saveDiffPath, "save-diff", "",
+		"Save the preview, rendered as a diff without colors, to the given file in addition to displaying it")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -169,9 +343,35 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that needn't be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&showURNs, "show-urns", false,
+		"Show the full URN of each resource in place of its name, e.g. to pass it to --target-replace or 'pulumi state'")
+	cmd.PersistentFlags().BoolVar(
+		&showSamesReason, "show-sames-reason", false,
+		"Explain why each unchanged resource is unchanged: which of its properties were compared, which were "+
+			"normalized by its provider, and which were ignored")
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
 
 	return cmd
 }
+
+// driftExitCode is the code with which a preview that was passed --expect-no-changes exits if it proposes changes.
+const driftExitCode = 2
+
+// driftError returns the error that a preview that was expected to propose no changes reports when it proposes the
+// given changes.
+func driftError(changes engine.ResourceChanges) error {
+	count := 0
+	for op, c := range changes {
+		if op != deploy.OpSame {
+			count += c
+		}
+	}
+	return cmdutil.ExitCodeError{
+		Code: driftExitCode,
+		Err: errors.Errorf("drift detected: no changes were expected, but the preview proposed %d change(s), "+
+			"shown above; the stack's program, configuration, or resources no longer match its state", count),
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/gitutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// previewComparedTo previews the given operation on a stack twice, first with the program at the given revision of
+// the git repository that holds the project, and then with the program in the working tree, and reports how the plan
+// of the working tree differs from that of the revision. Both previews are made against the stack's current state and
+// configuration. It returns the changes that the preview of the working tree proposes.
+func previewComparedTo(revision string, s backend.Stack, op backend.UpdateOperation) (engine.ResourceChanges,
+	result.Result) {
+
+	repo, err := gitutil.GetGitRepository(op.Root)
+	if err != nil {
+		return nil, result.FromError(err)
+	} else if repo == nil {
+		return nil, result.Errorf("--compare-to requires the project to be in a git repository")
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, result.FromError(errors.Wrap(err, "reading git repository"))
+	}
+	rel, err := filepath.Rel(worktree.Filesystem.Root(), op.Root)
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+
+	dir, err := ioutil.TempDir("", "pulumi-compare-")
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	// Write the revision's sources to one directory, and the renderings of both plans to another, so that the plans
+	// cannot collide with any of the revision's files.
+	sources := filepath.Join(dir, "sources")
+	hash, err := gitutil.GitExtractRevision(repo, revision, sources)
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+	baseRoot := filepath.Join(sources, rel)
+	projPath, err := workspace.DetectProjectPathFrom(baseRoot)
+	if err != nil || projPath == "" || filepath.Dir(projPath) != baseRoot {
+		return nil, result.Errorf("there is no Pulumi.yaml project file in %s at revision '%s'", rel, revision)
+	}
+	baseProj, err := workspace.LoadProject(projPath)
+	if err != nil {
+		return nil, result.FromError(errors.Wrapf(err, "loading the project at revision '%s'", revision))
+	}
+	if err = linkUntrackedFiles(op.Root, baseRoot); err != nil {
+		return nil, result.FromError(err)
+	}
+
+	plans := filepath.Join(dir, "plans")
+	if err = os.Mkdir(plans, 0700); err != nil {
+		return nil, result.FromError(err)
+	}
+	baseOp := op
+	baseOp.Proj, baseOp.Root = baseProj, baseRoot
+	baseOp.Opts.Display.SaveDiffPath = filepath.Join(plans, "base.txt")
+	if op.Opts.Display.SaveDiffPath == "" {
+		op.Opts.Display.SaveDiffPath = filepath.Join(plans, "current.txt")
+	}
+
+	colorize := op.Opts.Display.Color.Colorize
+	fmt.Println(colorize(fmt.Sprintf("%sPreviewing the program at %s (%s):%s", colors.SpecHeadline, revision,
+		hash.String()[:7], colors.Reset)))
+	if _, res := s.Preview(commandContext(), baseOp); res != nil {
+		return nil, res
+	}
+	fmt.Println(colorize(fmt.Sprintf("\n%sPreviewing the program in the working tree:%s", colors.SpecHeadline,
+		colors.Reset)))
+	changes, res := s.Preview(commandContext(), op)
+	if res != nil {
+		return changes, res
+	}
+
+	base, err := ioutil.ReadFile(baseOp.Opts.Display.SaveDiffPath)
+	if err != nil {
+		return changes, result.FromError(errors.Wrap(err, "reading the plan of the revision"))
+	}
+	current, err := ioutil.ReadFile(op.Opts.Display.SaveDiffPath)
+	if err != nil {
+		return changes, result.FromError(errors.Wrap(err, "reading the plan of the working tree"))
+	}
+	comparison := display.RenderPlanComparison(string(base), string(current), revision, "working tree")
+	if comparison == "" {
+		fmt.Println(colorize(fmt.Sprintf("\n%sThe working tree proposes the same plan as %s%s", colors.SpecInfo,
+			revision, colors.Reset)))
+		return changes, nil
+	}
+	fmt.Println(colorize(fmt.Sprintf("\n%sThe plan of the working tree differs from that of %s:%s", colors.SpecHeadline,
+		revision, colors.Reset)))
+	fmt.Println(comparison)
+	return changes, nil
+}
+
+// linkUntrackedFiles links each file or directory in the project directory at root that does not exist in the copy
+// of the project at copyRoot into the copy. This makes the dependencies that are installed in the project directory
+// but not committed, such as node_modules, available to the copied program.
+func linkUntrackedFiles(root, copyRoot string) error {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		dest := filepath.Join(copyRoot, entry.Name())
+		if _, err = os.Lstat(dest); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if err = os.Symlink(filepath.Join(root, entry.Name()), dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
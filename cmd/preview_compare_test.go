@@ -0,0 +1,53 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkUntrackedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-compare")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	root, copyRoot := filepath.Join(dir, "root"), filepath.Join(dir, "copy")
+	for _, path := range []string{
+		filepath.Join(root, ".git", "HEAD"),
+		filepath.Join(root, "index.js"),
+		filepath.Join(root, "node_modules", "dep", "index.js"),
+		filepath.Join(copyRoot, "index.js"),
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(path), 0600))
+	}
+
+	assert.NoError(t, linkUntrackedFiles(root, copyRoot))
+
+	// The copy keeps its own files, and gains links to the others, but not to the repository.
+	b, err := ioutil.ReadFile(filepath.Join(copyRoot, "index.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(copyRoot, "index.js"), string(b))
+	b, err = ioutil.ReadFile(filepath.Join(copyRoot, "node_modules", "dep", "index.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "node_modules", "dep", "index.js"), string(b))
+	_, err = os.Lstat(filepath.Join(copyRoot, ".git"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -41,16 +41,25 @@ import (
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
 	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
 	"github.com/pulumi/pulumi/pkg/version"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
 // NewPulumiCmd creates a new Pulumi Cmd instance.
 func NewPulumiCmd() *cobra.Command {
+	var caCert string
+	var clientCert string
+	var clientKey string
 	var cwd string
+	var home string
+	var logDir string
 	var logFlow bool
 	var logToStderr bool
+	var metricsAddr string
+	var pluginPaths []string
 	var tracing string
 	var tracingHeaderFlag string
 	var profiling string
@@ -93,12 +102,62 @@ func NewPulumiCmd() *cobra.Command {
 			}
 
 			if cwd != "" {
-				if err := os.Chdir(cwd); err != nil {
+				if err := changeWorkingDirectory(cwd); err != nil {
 					return err
 				}
 			}
 
-			logging.InitLogging(logToStderr, verbose, logFlow)
+			// Relocate the home directory by setting its environment variable, so that plugins see it too.
+			if home != "" {
+				dir, err := filepath.Abs(home)
+				if err != nil {
+					return errors.Wrap(err, "resolving --home")
+				}
+				if err = os.Setenv(workspace.PulumiHomeEnvVar, dir); err != nil {
+					return err
+				}
+			}
+
+			if err := httputil.ConfigureTransport(httputil.TransportOptions{
+				CACertFile:     caCert,
+				ClientCertFile: clientCert,
+				ClientKeyFile:  clientKey,
+			}); err != nil {
+				return errors.Wrap(err, "configuring HTTP transport")
+			}
+
+			for _, override := range pluginPaths {
+				name, path, err := workspace.ParsePluginPathOverride(override)
+				if err != nil {
+					return err
+				}
+				workspace.SetPluginPathOverride(name, path)
+			}
+
+			if metricsAddr != "" {
+				sink, err := metrics.New(metricsAddr)
+				if err != nil {
+					return errors.Wrap(err, "configuring metrics")
+				}
+				metricsSink = sink
+			}
+
+			if logDir != "" {
+				dir, err := createLogDirectory(logDir)
+				if err != nil {
+					return err
+				}
+				logDir = dir
+			}
+
+			logging.InitLoggingToDir(logToStderr, verbose, logFlow, logDir)
+			if verbose > 0 && !logToStderr {
+				dir := logDir
+				if dir == "" {
+					dir = os.TempDir()
+				}
+				fmt.Fprintf(os.Stderr, "Writing logs to %s\n", dir)
+			}
 			cmdutil.InitTracing("pulumi-cli", "pulumi", tracing)
 			if tracingHeaderFlag != "" {
 				tracingHeader = tracingHeaderFlag
@@ -131,20 +190,39 @@ func NewPulumiCmd() *cobra.Command {
 		},
 	}
 
+	cmd.PersistentFlags().StringVar(&caCert, "ca-cert", "",
+		"Trust the CA certificates in the given PEM file, in addition to the system's, when making HTTPS requests")
+	cmd.PersistentFlags().StringVar(&clientCert, "client-cert", "",
+		"Present the certificate in the given PEM file to servers that request a client certificate")
+	cmd.PersistentFlags().StringVar(&clientKey, "client-key", "",
+		"The PEM file holding the private key of the certificate given by --client-cert")
 	cmd.PersistentFlags().StringVarP(&cwd, "cwd", "C", "",
-		"Run pulumi as if it had been started in another directory")
+		"Run pulumi as if it had been started in another directory; relative paths in other arguments are "+
+			"resolved against this directory")
 	cmd.PersistentFlags().BoolVarP(&cmdutil.Emoji, "emoji", "e", runtime.GOOS == "darwin",
 		"Enable emojis in the output")
 	cmd.PersistentFlags().BoolVar(&filestate.DisableIntegrityChecking, "disable-integrity-checking", false,
 		"Disable integrity checking of checkpoint files")
+	cmd.PersistentFlags().StringVar(&home, "home", "",
+		"Store credentials, plugins, templates, and local backend state under the given directory, creating it if "+
+			"necessary, instead of ~/.pulumi; the same as setting "+workspace.PulumiHomeEnvVar)
+	cmd.PersistentFlags().StringVar(&logDir, "log-dir", "",
+		"Write log files to the given directory, creating it if necessary, instead of the system's temporary directory")
 	cmd.PersistentFlags().BoolVar(&logFlow, "logflow", false,
 		"Flow log settings to child processes (like plugins)")
 	cmd.PersistentFlags().BoolVar(&logToStderr, "logtostderr", false,
 		"Log to stderr instead of to files")
+	cmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", "",
+		"Emit timing and resource-count metrics for each update to a StatsD server (statsd://HOST:PORT) or to the "+
+			"given Prometheus pushgateway URL")
 	cmd.PersistentFlags().BoolVar(&cmdutil.DisableInteractive, "non-interactive", false,
 		"Disable interactive mode for all commands")
+	cmd.PersistentFlags().StringArrayVar(&pluginPaths, "plugin-path", nil,
+		"Launch the binary at PATH in place of any installed version of the resource provider NAME (NAME=PATH); "+
+			"may be specified multiple times. Overrides may also be set using "+workspace.PluginPathEnvVar)
 	cmd.PersistentFlags().StringVar(&tracing, "tracing", "",
-		"Emit tracing to a Zipkin-compatible tracing endpoint")
+		"Emit tracing to a Zipkin-compatible tracing endpoint, or to a file in the Zipkin JSON format if the "+
+			"endpoint is of the form file:<path>")
 	cmd.PersistentFlags().StringVar(&profiling, "profiling", "",
 		"Emit CPU and memory profiles and an execution trace to '[filename].[pid].{cpu,mem,trace}', respectively")
 	cmd.PersistentFlags().IntVarP(&verbose, "verbose", "v", 0,
@@ -158,6 +236,7 @@ func NewPulumiCmd() *cobra.Command {
 	//     - Deploy Commands
 	cmd.AddCommand(newUpCmd())
 	cmd.AddCommand(newPreviewCmd())
+	cmd.AddCommand(newValidateCmd())
 	cmd.AddCommand(newDestroyCmd())
 	//     - Stack Management Commands:
 	cmd.AddCommand(newStackCmd())
@@ -169,6 +248,7 @@ func NewPulumiCmd() *cobra.Command {
 	//     - Advanced Commands:
 	cmd.AddCommand(newCancelCmd())
 	cmd.AddCommand(newRefreshCmd())
+	cmd.AddCommand(newImportCmd())
 	cmd.AddCommand(newStateCmd())
 	//     - Other Commands:
 	cmd.AddCommand(newLogsCmd())
@@ -36,7 +36,10 @@ func newRefreshCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var jsonDisplay bool
 	var parallel int
+	var readParallel int
+	var providerParallel []string
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
@@ -54,6 +57,11 @@ func newRefreshCmd() *cobra.Command {
 			"the program text isn't updated accordingly, subsequent updates may still appear to be out of\n" +
 			"synch with respect to the cloud provider's source of truth.\n" +
 			"\n" +
+			"Each resource that drifted is shown with the properties whose values changed. Properties that\n" +
+			"a resource's provider expects to change on their own, such as timestamps, are not compared.\n" +
+			"Pass --json to emit this drift report as JSON; the refresh is then only previewed, and the\n" +
+			"stack's state is left unchanged.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.NoArgs,
@@ -67,6 +75,12 @@ func newRefreshCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			if jsonDisplay {
+				if skipPreview {
+					return result.Errorf("--json may not be used with --skip-preview")
+				}
+				opts.PreviewOnly = true
+			}
 
 			var displayType = display.DisplayProgress
 			if diffDisplay {
@@ -81,6 +95,7 @@ func newRefreshCmd() *cobra.Command {
 				SuppressOutputs:      suppressOutputs,
 				IsInteractive:        interactive,
 				Type:                 displayType,
+				JSONDisplay:          jsonDisplay,
 				Debug:                debug,
 			}
 
@@ -110,10 +125,12 @@ func newRefreshCmd() *cobra.Command {
 			}
 
 			opts.Engine = engine.UpdateOptions{
-				Analyzers:     analyzers,
-				Parallel:      parallel,
-				Debug:         debug,
-				UseLegacyDiff: useLegacyDiff(),
+				Analyzers:        analyzers,
+				Parallel:         parallel,
+				ProviderParallel: providerParallel,
+				ReadParallel:     readParallel,
+				Debug:            debug,
+				UseLegacyDiff:    useLegacyDiff(),
 			}
 
 			changes, res := s.Refresh(commandContext(), backend.UpdateOperation{
@@ -148,9 +165,10 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	cmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 
 	cmd.PersistentFlags().StringVarP(
 		&message, "message", "m", "",
@@ -163,9 +181,18 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Serialize the drift found by previewing the refresh as JSON, without changing the stack's state")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().IntVar(
+		&readParallel, "parallel-read", 0,
+		"Allow N resource reads to run in parallel at once when refreshing the stack's resources. Defaults to --parallel")
+	cmd.PersistentFlags().StringArrayVar(
+		&providerParallel, "provider-parallel", []string{},
+		"Allow at most N resource operations to run in parallel against the provider for package PKG (PKG=N)")
 	cmd.PersistentFlags().BoolVar(
 		&showReplacementSteps, "show-replacement-steps", false,
 		"Show detailed resource replacement creates and deletes instead of a single step")
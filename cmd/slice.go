@@ -0,0 +1,79 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// sliceFlagUsage describes the --slice flag of the commands that deploy or destroy a stack's resources.
+const sliceFlagUsage = "Restrict the operation to the resources in this slice, which the project's 'slices' section " +
+	"declares; may be specified multiple times to include the resources in any of the slices"
+
+const sliceCreateMissingFlagUsage = "Also create the resources outside of the slices of --slice that do not exist, " +
+	"so that the resources in the slices that depend on them may be deployed"
+
+// checkSliceCreateMissing fails if --slice-create-missing was passed without any slices.
+func checkSliceCreateMissing(names []string, createMissing bool) error {
+	if createMissing && len(names) == 0 {
+		return errors.New("--slice-create-missing may only be passed with --slice")
+	}
+	return nil
+}
+
+// getSlices returns the project's slices with the given names.
+func getSlices(proj *workspace.Project, names []string) ([]deploy.Slice, error) {
+	var slices []deploy.Slice
+	for _, name := range names {
+		def, has := proj.Slices[name]
+		if !has {
+			var known []string
+			for n := range proj.Slices {
+				known = append(known, n)
+			}
+			sort.Strings(known)
+			if len(known) == 0 {
+				return nil, errors.Errorf("there is no slice named '%s'; the project does not declare any slices", name)
+			}
+			return nil, errors.Errorf("there is no slice named '%s'; the project declares %s", name,
+				strings.Join(known, ", "))
+		}
+
+		patterns, err := resource.ParseURNPatterns(def.Resources)
+		if err != nil {
+			return nil, errors.Wrapf(err, "slice '%s'", name)
+		}
+		slice := deploy.Slice{Name: name, Resources: patterns, Tags: def.Tags}
+		for _, m := range def.Modules {
+			if strings.Count(m, ":") != 1 {
+				return nil, errors.Errorf("slice '%s': invalid module '%s'; expected a module such as 'aws:s3'", name, m)
+			}
+			slice.Modules = append(slice.Modules, tokens.Module(m))
+		}
+		if len(slice.Resources) == 0 && len(slice.Modules) == 0 && len(slice.Tags) == 0 {
+			return nil, errors.Errorf("slice '%s' does not declare any resources, modules, or tags", name)
+		}
+		slices = append(slices, slice)
+	}
+	return slices, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func TestGetSlices(t *testing.T) {
+	proj := &workspace.Project{
+		Name: "proj",
+		Slices: map[string]workspace.ProjectSlice{
+			"frontend": {Resources: []string{"*::web-*"}, Modules: []string{"aws:s3"}},
+			"data":     {Tags: map[string]string{"team": "data"}},
+			"empty":    {Description: "nothing"},
+			"invalid":  {Modules: []string{"aws"}},
+		},
+	}
+
+	slices, err := getSlices(proj, []string{"frontend", "data"})
+	assert.NoError(t, err)
+	if assert.Len(t, slices, 2) {
+		assert.Equal(t, "frontend", slices[0].Name)
+		assert.Len(t, slices[0].Resources, 1)
+		assert.Equal(t, []tokens.Module{"aws:s3"}, slices[0].Modules)
+		assert.Equal(t, map[string]string{"team": "data"}, slices[1].Tags)
+	}
+
+	_, err = getSlices(proj, []string{"backend"})
+	assert.EqualError(t, err, "there is no slice named 'backend'; the project declares data, empty, frontend, invalid")
+	_, err = getSlices(proj, []string{"empty"})
+	assert.Error(t, err)
+	_, err = getSlices(proj, []string{"invalid"})
+	assert.Error(t, err)
+}
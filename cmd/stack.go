@@ -161,6 +161,7 @@ func newStackCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSecrets, "show-secrets", false, "Display stack outputs which are marked as secret in plaintext")
 
+	cmd.AddCommand(newStackCloneCmd())
 	cmd.AddCommand(newStackExportCmd())
 	cmd.AddCommand(newStackGraphCmd())
 	cmd.AddCommand(newStackImportCmd())
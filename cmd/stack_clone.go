@@ -0,0 +1,218 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackCloneCmd() *cobra.Command {
+	var force bool
+	var secretsProvider string
+	var withState bool
+
+	cmd := &cobra.Command{
+		Use:   "clone <source-stack-name> <destination-stack-name>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Create a new stack with the same configuration as an existing stack",
+		Long: "Create a new stack with the same configuration as an existing stack.\n" +
+			"\n" +
+			"This command creates the destination stack and copies the source stack's configuration into it.\n" +
+			"The source stack's resources are not copied, since each stack manages its own resources, so the\n" +
+			"first `pulumi up` of the new stack creates a new copy of each of them.\n" +
+			"\n" +
+			"Secret configuration values are decrypted with the source stack's key and re-encrypted with the\n" +
+			"destination stack's, so both keys must be available. If any secret cannot be decrypted, the clone\n" +
+			"fails before the destination stack is created or changed.\n" +
+			"\n" +
+			"Pass --with-state to also copy the source stack's state, e.g. to make a backup of the stack or to\n" +
+			"test changes to it. The two stacks then record the same cloud resources, so updating or destroying\n" +
+			"either of them changes the resources of both.\n" +
+			"\n" +
+			"If the destination stack already exists, its configuration (and, with --with-state, its state)\n" +
+			"is only overwritten if --force is passed.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if err := validateSecretsProvider(secretsProvider); err != nil {
+				return err
+			}
+
+			b, err := currentBackend(opts)
+			if err != nil {
+				return err
+			}
+
+			srcRef, err := b.ParseStackReference(args[0])
+			if err != nil {
+				return err
+			}
+			src, err := b.GetStack(commandContext(), srcRef)
+			if err != nil {
+				return err
+			} else if src == nil {
+				return errors.Errorf("no stack named '%s' found", srcRef)
+			}
+
+			dstRef, err := b.ParseStackReference(args[1])
+			if err != nil {
+				return err
+			}
+			if dstRef.Name() == srcRef.Name() {
+				return errors.New("a stack cannot be cloned into itself")
+			}
+			dst, err := b.GetStack(commandContext(), dstRef)
+			if err != nil {
+				return err
+			}
+			if dst != nil && !force {
+				return errors.Errorf("stack '%s' already exists; pass --force to overwrite it", dstRef)
+			}
+
+			// Read everything from the source stack before changing anything.
+			srcStack, err := loadProjectStack(src)
+			if err != nil {
+				return err
+			}
+			secrets, err := decryptStackSecrets(src, srcStack.Config)
+			if err != nil {
+				return err
+			}
+			var deployment *apitype.UntypedDeployment
+			if withState {
+				if deployment, err = exportDeploymentForStack(src, dstRef.Name()); err != nil {
+					return err
+				}
+			}
+
+			if dst == nil {
+				var createOpts interface{} // Backend-specific config options, none currently.
+				if dst, err = createStack(b, dstRef, createOpts, false /*setCurrent*/, secretsProvider); err != nil {
+					return err
+				}
+			}
+
+			// Copy the configuration into the destination stack's configuration file, which may already hold the
+			// settings of its secrets provider. Secret values are re-encrypted with the destination stack's key.
+			dstStack, err := loadProjectStack(dst)
+			if err != nil {
+				return err
+			}
+			dstStack.Config = srcStack.Config
+			if len(secrets) > 0 {
+				enc, encErr := getStackEncrypter(dst)
+				if encErr != nil {
+					return errors.Wrapf(encErr, "could not re-encrypt secrets for stack '%s'", dstRef)
+				}
+				if dstStack.Config, err = encryptConfigSecrets(srcStack.Config, secrets, enc); err != nil {
+					return err
+				}
+			}
+			if err = saveProjectStack(dst, dstStack); err != nil {
+				return errors.Wrap(err, "saving configuration")
+			}
+
+			if deployment != nil {
+				if err = dst.ImportDeployment(commandContext(), deployment); err != nil {
+					return errors.Wrap(err, "could not import deployment")
+				}
+				cmdutil.Diag().Warningf(diag.Message("", "stacks '%s' and '%s' now record the same resources; "+
+					"updating or destroying either stack will change the resources of both"), srcRef, dstRef)
+			}
+
+			fmt.Printf("Cloned %s into %s\n", srcRef, dstRef)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&force, "force", "f", false,
+		"Overwrite the destination stack if it already exists")
+	cmd.PersistentFlags().StringVar(
+		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
+			"decrypt the destination stack's secrets (possible choices: default, passphrase)")
+	cmd.PersistentFlags().BoolVar(
+		&withState, "with-state", false,
+		"Also copy the source stack's state, so that both stacks record the same resources (not recommended)")
+
+	return cmd
+}
+
+// decryptStackSecrets decrypts the secure values of the given configuration of the given stack using the stack's
+// secrets provider, and returns the plaintexts by key. The stack's key is only requested if there are secure values.
+func decryptStackSecrets(s backend.Stack, cfg config.Map) (map[config.Key]string, error) {
+	hasSecrets := false
+	for _, v := range cfg {
+		hasSecrets = hasSecrets || v.Secure()
+	}
+	if !hasSecrets {
+		return nil, nil
+	}
+
+	dec, err := getStackDencrypter(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decrypt secrets of stack '%s'", s.Ref())
+	}
+	return decryptConfigSecrets(cfg, dec)
+}
+
+// exportDeploymentForStack exports the deployment of the given stack and rewrites the URNs of its resources so that it
+// may be imported into the stack with the given name.
+func exportDeploymentForStack(s backend.Stack, name tokens.QName) (*apitype.UntypedDeployment, error) {
+	exported, err := s.ExportDeployment(commandContext())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not export deployment")
+	}
+	snapshot, err := stack.DeserializeUntypedDeployment(exported, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not deserialize deployment")
+	}
+	if snapshot == nil {
+		// The stack has never been updated, so there is no state to copy.
+		return nil, nil
+	}
+	if err = edit.RenameStack(snapshot, name); err != nil {
+		return nil, err
+	}
+
+	sdp, err := stack.SerializeDeployment(snapshot, snapshot.SecretsManager)
+	if err != nil {
+		return nil, errors.Wrap(err, "constructing deployment")
+	}
+	bytes, err := json.Marshal(sdp)
+	if err != nil {
+		return nil, err
+	}
+	return &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}, nil
+}
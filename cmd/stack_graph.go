@@ -16,6 +16,9 @@ package cmd
 
 import (
 	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/graph"
@@ -35,11 +38,15 @@ var ignoreDependencyEdges bool
 // The color of dependency edges in the graph. Defaults to #246C60, a blush-green.
 var dependencyEdgeColor string
 
+// The color of dependency edges that providers reported in the graph. Defaults to #7A4BA0, a purple.
+var providerDependencyEdgeColor string
+
 // The color of parent edges in the graph. Defaults to #AA6639, an orange.
 var parentEdgeColor string
 
 func newStackGraphCmd() *cobra.Command {
 	var stackName string
+	var format string
 
 	cmd := &cobra.Command{
 		Use:   "graph",
@@ -49,8 +56,22 @@ func newStackGraphCmd() *cobra.Command {
 			"\n" +
 			"This command can be used to view the dependency graph that a Pulumi program\n" +
 			"admitted when it was ran. This graph is output in the DOT format. This command operates\n" +
-			"on your stack's most recent deployment.",
+			"on your stack's most recent deployment.\n" +
+			"\n" +
+			"Pass --format json to output the graph as JSON instead, for tools that analyze it. The JSON\n" +
+			"is an object with a 'version', currently " + strconv.Itoa(graphJSONVersion) + ", a list of 'nodes',\n" +
+			"each of which has the 'urn', 'type', and 'name' of a resource and whether it is 'custom',\n" +
+			"and a list of 'edges'.\n" +
+			"Each edge runs 'from' the URN of a resource 'to' that of a resource it depends on or that is\n" +
+			"its parent, and has a 'kind': 'property' if some of the resource's input 'properties', which\n" +
+			"the edge lists, depend on the other; 'explicit' if the program declared the dependency but\n" +
+			"none of the properties have it; 'provider' if the resource's provider reported it; or\n" +
+			"'parent'. Cycles of dependencies are output as they are, and their edges have 'cycle' set.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if format != "dot" && format != "json" {
+				return errors.Errorf("unsupported graph format '%s': expected 'dot' or 'json'", format)
+			}
+
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
@@ -64,13 +85,17 @@ func newStackGraphCmd() *cobra.Command {
 				return err
 			}
 
-			dg := makeDependencyGraph(snap)
 			file, err := os.Create(args[0])
 			if err != nil {
 				return err
 			}
 
-			if err := dotconv.Print(dg, file); err != nil {
+			if format == "json" {
+				err = printGraphJSON(snap, file)
+			} else {
+				err = dotconv.Print(makeDependencyGraph(snap), file)
+			}
+			if err != nil {
 				_ = file.Close()
 				return err
 			}
@@ -82,12 +107,16 @@ func newStackGraphCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVarP(
 		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(&format, "format", "dot",
+		"The format in which to output the graph: 'dot' or 'json'")
 	cmd.PersistentFlags().BoolVar(&ignoreParentEdges, "ignore-parent-edges", false,
 		"Ignores edges introduced by parent/child resource relationships")
 	cmd.PersistentFlags().BoolVar(&ignoreDependencyEdges, "ignore-dependency-edges", false,
 		"Ignores edges introduced by dependency resource relationships")
 	cmd.PersistentFlags().StringVar(&dependencyEdgeColor, "dependency-edge-color", "#246C60",
 		"Sets the color of dependency edges in the graph")
+	cmd.PersistentFlags().StringVar(&providerDependencyEdgeColor, "provider-dependency-edge-color", "#7A4BA0",
+		"Sets the color of the edges of dependencies that providers reported, rather than programs declared")
 	cmd.PersistentFlags().StringVar(&parentEdgeColor, "parent-edge-color", "#AA6639",
 		"Sets the color of parent edges in the graph")
 	return cmd
@@ -100,8 +129,9 @@ func newStackGraphCmd() *cobra.Command {
 // `dependencyEdge` implements graph.Edge, `dependencyVertex` implements graph.Vertex, and
 // `dependencyGraph` implements `graph.Graph`.
 type dependencyEdge struct {
-	to   *dependencyVertex
-	from *dependencyVertex
+	to       *dependencyVertex
+	from     *dependencyVertex
+	provider bool // true if the resource's provider reported the dependency, rather than its program.
 }
 
 // In this simple case, edges have no data.
@@ -123,6 +153,9 @@ func (edge *dependencyEdge) From() graph.Vertex {
 }
 
 func (edge *dependencyEdge) Color() string {
+	if edge.provider {
+		return providerDependencyEdgeColor
+	}
 	return dependencyEdgeColor
 }
 
@@ -226,9 +259,13 @@ func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
 		if !ignoreDependencyEdges {
 			// Incoming edges are directly stored within the checkpoint file; they represent
 			// resources on which this vertex immediately depends upon.
+			reported := make(map[resource.URN]bool)
+			for _, dep := range vertex.resource.ProviderDependencies {
+				reported[dep] = true
+			}
 			for _, dep := range vertex.resource.Dependencies {
 				vertexWeDependOn := vertex.graph.vertices[dep]
-				edge := &dependencyEdge{to: vertex, from: vertexWeDependOn}
+				edge := &dependencyEdge{to: vertex, from: vertexWeDependOn, provider: reported[dep]}
 				vertex.incomingEdges = append(vertex.incomingEdges, edge)
 				vertexWeDependOn.outgoingEdges = append(vertexWeDependOn.outgoingEdges, edge)
 			}
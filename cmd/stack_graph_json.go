@@ -0,0 +1,157 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// graphJSONVersion is the version of the schema of the JSON form of a stack's dependency graph. It changes only if
+// the schema changes in a way that is not backwards compatible.
+const graphJSONVersion = 1
+
+// The kinds of the edges of the JSON form of a stack's dependency graph.
+const (
+	// graphEdgeProperty is a dependency of some of a resource's input properties on another resource's outputs.
+	graphEdgeProperty = "property"
+	// graphEdgeExplicit is a dependency that a resource's program declared, but that none of its properties have.
+	graphEdgeExplicit = "explicit"
+	// graphEdgeProvider is a dependency that a resource's provider reported, rather than its program declared.
+	graphEdgeProvider = "provider"
+	// graphEdgeParent relates a resource to its parent.
+	graphEdgeParent = "parent"
+)
+
+// graphJSON is the JSON form of a stack's dependency graph.
+type graphJSON struct {
+	// Version is the version of the schema, graphJSONVersion.
+	Version int `json:"version"`
+	// Nodes holds a node for each of the stack's resources, in the order of the stack's state.
+	Nodes []graphNodeJSON `json:"nodes"`
+	// Edges holds an edge from each resource to each resource that it depends on, or that is its parent.
+	Edges []graphEdgeJSON `json:"edges"`
+}
+
+// graphNodeJSON is a resource in the JSON form of a stack's dependency graph.
+type graphNodeJSON struct {
+	URN    resource.URN `json:"urn"`
+	Type   string       `json:"type"`
+	Name   string       `json:"name"`
+	Custom bool         `json:"custom"`
+}
+
+// graphEdgeJSON is an edge in the JSON form of a stack's dependency graph. An edge may name a resource that is not
+// one of the graph's nodes, if the stack's state refers to a resource that it does not hold.
+type graphEdgeJSON struct {
+	// From is the resource that depends on, or is a child of, the other.
+	From resource.URN `json:"from"`
+	// To is the resource that is depended on, or is the parent of, the other.
+	To resource.URN `json:"to"`
+	// Kind is "property", "explicit", "provider", or "parent".
+	Kind string `json:"kind"`
+	// Properties lists the input properties of the dependent resource that depend on the other, for property edges.
+	Properties []string `json:"properties,omitempty"`
+	// Cycle is true if the edge is part of a cycle of dependencies.
+	Cycle bool `json:"cycle,omitempty"`
+}
+
+// makeGraphJSON returns the JSON form of the dependency graph of the given snapshot. Resources that are pending
+// deletion are omitted, so that each node has a distinct URN. Cycles are represented as they are, and each edge that
+// is part of one is marked as such.
+func makeGraphJSON(snapshot *deploy.Snapshot) graphJSON {
+	g := graphJSON{Version: graphJSONVersion, Nodes: []graphNodeJSON{}, Edges: []graphEdgeJSON{}}
+	if snapshot == nil {
+		return g
+	}
+
+	for _, res := range snapshot.Resources {
+		if res.Delete {
+			continue
+		}
+		g.Nodes = append(g.Nodes, graphNodeJSON{
+			URN:    res.URN,
+			Type:   string(res.URN.Type()),
+			Name:   string(res.URN.Name()),
+			Custom: res.Custom,
+		})
+
+		if !ignoreDependencyEdges {
+			reported := make(map[resource.URN]bool)
+			for _, dep := range res.ProviderDependencies {
+				reported[dep] = true
+			}
+			properties := make(map[resource.URN][]string)
+			for key, deps := range res.PropertyDependencies {
+				for _, dep := range deps {
+					properties[dep] = append(properties[dep], string(key))
+				}
+			}
+			for _, dep := range res.Dependencies {
+				edge := graphEdgeJSON{From: res.URN, To: dep, Kind: graphEdgeExplicit}
+				switch {
+				case reported[dep]:
+					edge.Kind = graphEdgeProvider
+				case len(properties[dep]) > 0:
+					edge.Kind, edge.Properties = graphEdgeProperty, properties[dep]
+					sort.Strings(edge.Properties)
+				}
+				g.Edges = append(g.Edges, edge)
+			}
+		}
+		if !ignoreParentEdges && res.Parent != "" {
+			g.Edges = append(g.Edges, graphEdgeJSON{From: res.URN, To: res.Parent, Kind: graphEdgeParent})
+		}
+	}
+
+	markGraphCycles(g.Edges)
+	return g
+}
+
+// markGraphCycles marks each of the given edges that is part of a cycle, i.e. whose target can reach its source.
+func markGraphCycles(edges []graphEdgeJSON) {
+	outs := make(map[resource.URN][]resource.URN)
+	for _, edge := range edges {
+		outs[edge.From] = append(outs[edge.From], edge.To)
+	}
+	for i := range edges {
+		seen := map[resource.URN]bool{}
+		queue := []resource.URN{edges[i].To}
+		for len(queue) > 0 && !edges[i].Cycle {
+			urn := queue[0]
+			queue = queue[1:]
+			if urn == edges[i].From {
+				edges[i].Cycle = true
+			} else if !seen[urn] {
+				seen[urn] = true
+				queue = append(queue, outs[urn]...)
+			}
+		}
+	}
+}
+
+// printGraphJSON writes the JSON form of the dependency graph of the given snapshot to w.
+func printGraphJSON(snapshot *deploy.Snapshot, w io.Writer) error {
+	out, err := json.MarshalIndent(makeGraphJSON(snapshot), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
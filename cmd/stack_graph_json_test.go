@@ -0,0 +1,72 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestMakeGraphJSON(t *testing.T) {
+	urn := func(name string) resource.URN {
+		return resource.NewURN("dev", "proj", "", "pkg:m:typ", tokens.QName(name))
+	}
+	snap := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: urn("a"), Custom: true},
+		{URN: urn("old"), Custom: true, Delete: true},
+		{
+			URN:                  urn("b"),
+			Custom:               true,
+			Parent:               urn("a"),
+			Dependencies:         []resource.URN{urn("a"), urn("c"), urn("d")},
+			PropertyDependencies: map[resource.PropertyKey][]resource.URN{"y": {urn("a")}, "x": {urn("a")}},
+			ProviderDependencies: []resource.URN{urn("d")},
+		},
+		{URN: urn("c"), Custom: true, Dependencies: []resource.URN{urn("b")}},
+		{URN: urn("d"), Custom: true},
+	}}
+
+	g := makeGraphJSON(snap)
+	assert.Equal(t, graphJSONVersion, g.Version)
+	var names []string
+	for _, node := range g.Nodes {
+		names = append(names, node.Name)
+		assert.Equal(t, "pkg:m:typ", node.Type)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names)
+
+	// The cycle between b and c is represented, rather than rejected.
+	assert.Equal(t, []graphEdgeJSON{
+		{From: urn("b"), To: urn("a"), Kind: graphEdgeProperty, Properties: []string{"x", "y"}},
+		{From: urn("b"), To: urn("c"), Kind: graphEdgeExplicit, Cycle: true},
+		{From: urn("b"), To: urn("d"), Kind: graphEdgeProvider},
+		{From: urn("b"), To: urn("a"), Kind: graphEdgeParent},
+		{From: urn("c"), To: urn("b"), Kind: graphEdgeExplicit, Cycle: true},
+	}, g.Edges)
+
+	var buf bytes.Buffer
+	assert.NoError(t, printGraphJSON(snap, &buf))
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded["nodes"], 4)
+	assert.Len(t, decoded["edges"], 5)
+}
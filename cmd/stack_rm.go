@@ -44,6 +44,9 @@ func newStackRmCmd() *cobra.Command {
 			"This command removes a stack and its configuration state.  Please refer to the\n" +
 			"`destroy` command for removing a resources, as this is a distinct operation.\n" +
 			"\n" +
+			"The removal is rejected if the stack still has resources, unless --force is passed, in\n" +
+			"which case the resources are left behind, no longer managed by any stack.\n" +
+			"\n" +
 			"After this command completes, the stack will no longer be available for updates.",
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			// Use the stack provided or, if missing, default to the current one.
@@ -63,8 +66,16 @@ func newStackRmCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
-			// Ensure the user really wants to do this.
+			// Ensure the user really wants to do this, pointing out if the stack is the currently selected one.
+			isCurrent := false
+			if cur, curErr := state.CurrentStack(commandContext(), s.Backend()); curErr == nil && cur != nil {
+				isCurrent = cur.Ref().String() == s.Ref().String()
+			}
 			prompt := fmt.Sprintf("This will permanently remove the '%s' stack!", s.Ref())
+			if isCurrent {
+				prompt = fmt.Sprintf("This will permanently remove the '%s' stack, which is the currently "+
+					"selected stack!", s.Ref())
+			}
 			if !yes && !confirmPrompt(prompt, s.Ref().String(), opts) {
 				fmt.Println("confirmation declined")
 				return result.Bail()
@@ -73,8 +84,8 @@ func newStackRmCmd() *cobra.Command {
 			hasResources, err := s.Remove(commandContext(), force)
 			if err != nil {
 				if hasResources {
-					return result.Errorf(
-						"'%s' still has resources; removal rejected; pass --force to override", s.Ref())
+					return result.Errorf("'%s' still has resources; removal rejected; run 'pulumi destroy' to "+
+						"delete them first, or pass --force to remove the stack anyway and leave them behind", s.Ref())
 				}
 				return result.FromError(err)
 			}
@@ -91,7 +102,10 @@ func newStackRmCmd() *cobra.Command {
 			msg := fmt.Sprintf("%sStack '%s' has been removed!%s", colors.SpecAttention, s.Ref(), colors.Reset)
 			fmt.Println(opts.Color.Colorize(msg))
 
-			contract.IgnoreError(state.SetCurrentStack(""))
+			// Only deselect the stack if it was selected; removing another stack leaves the selection as-is.
+			if isCurrent {
+				contract.IgnoreError(state.SetCurrentStack(""))
+			}
 			return nil
 		}),
 	}
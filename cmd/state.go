@@ -47,7 +47,16 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 		Args: cmdutil.NoArgs,
 	}
 
+	cmd.AddCommand(newStateCatCommand())
 	cmd.AddCommand(newStateDeleteCommand())
+	cmd.AddCommand(newStateEditCommand())
+	cmd.AddCommand(newStateExportCommand())
+	cmd.AddCommand(newStateHistoryCommand())
+	cmd.AddCommand(newStateImportCommand())
+	cmd.AddCommand(newStateLogCommand())
+	cmd.AddCommand(newStatePruneCommand())
+	cmd.AddCommand(newStateQueryCommand())
+	cmd.AddCommand(newStateReplaceProviderCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
 	return cmd
 }
@@ -0,0 +1,115 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateCatCommand() *cobra.Command {
+	var stackName string
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "cat <resource URN>",
+		Short: "Print the state of a single resource in a stack",
+		Long: `Print the state of a single resource in a stack
+
+This command prints the complete state of the given resource, as it is recorded in the stack's state, as JSON. This
+includes the resource's inputs, outputs, provider, dependencies, and other metadata. Secret values are displayed as
+"[secret]" unless --show-secrets is passed.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.Errorf("stack '%s' has no resources", s.Ref())
+			}
+
+			res, err := locateStackResource(opts, snap, resource.URN(args[0]))
+			if err != nil {
+				return err
+			}
+
+			// Secrets have already been replaced by redactResourceSecrets, so there is nothing left to encrypt.
+			sres, err := stack.SerializeResource(redactResourceSecrets(res, showSecrets), config.NopEncrypter)
+			if err != nil {
+				return errors.Wrap(err, "serializing resource")
+			}
+			return printJSON(sres)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show secret values in plaintext instead of displaying blinded values")
+
+	return cmd
+}
+
+// redactResourceSecrets returns a copy of the given resource state whose secret inputs and outputs are either
+// replaced by "[secret]" or, if showSecrets is true, by their plaintext values.
+func redactResourceSecrets(res *resource.State, showSecrets bool) *resource.State {
+	redacted := *res
+	redacted.Inputs = redactSecrets(resource.NewObjectProperty(res.Inputs), showSecrets).ObjectValue()
+	redacted.Outputs = redactSecrets(resource.NewObjectProperty(res.Outputs), showSecrets).ObjectValue()
+	return &redacted
+}
+
+// redactSecrets returns a copy of the given property value whose secret values are either replaced by "[secret]" or,
+// if showSecrets is true, by their plaintext values.
+func redactSecrets(v resource.PropertyValue, showSecrets bool) resource.PropertyValue {
+	switch {
+	case v.IsSecret():
+		if !showSecrets {
+			return resource.NewStringProperty("[secret]")
+		}
+		return redactSecrets(v.SecretValue().Element, showSecrets)
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = redactSecrets(elem, showSecrets)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		obj := make(resource.PropertyMap)
+		for k, elem := range v.ObjectValue() {
+			obj[k] = redactSecrets(elem, showSecrets)
+		}
+		return resource.NewObjectProperty(obj)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestRedactResourceSecrets(t *testing.T) {
+	res := &resource.State{
+		Inputs: resource.PropertyMap{
+			"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+			"name":     resource.NewStringProperty("db"),
+		},
+		Outputs: resource.PropertyMap{
+			"nested": resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewObjectProperty(resource.PropertyMap{
+					"token": resource.MakeSecret(resource.NewStringProperty("abc123")),
+				}),
+			}),
+		},
+	}
+
+	redacted := redactResourceSecrets(res, false)
+	assert.Equal(t, "[secret]", redacted.Inputs["password"].StringValue())
+	assert.Equal(t, "db", redacted.Inputs["name"].StringValue())
+	nested := redacted.Outputs["nested"].ArrayValue()[0].ObjectValue()
+	assert.Equal(t, "[secret]", nested["token"].StringValue())
+
+	shown := redactResourceSecrets(res, true)
+	assert.Equal(t, "hunter2", shown.Inputs["password"].StringValue())
+	nested = shown.Outputs["nested"].ArrayValue()[0].ObjectValue()
+	assert.Equal(t, "abc123", nested["token"].StringValue())
+
+	// The original state is left untouched.
+	assert.True(t, res.Inputs["password"].IsSecret())
+}
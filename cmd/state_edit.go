@@ -0,0 +1,201 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/secrets"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newStateEditCommand() *cobra.Command {
+	var stack string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "edit <resource URN>",
+		Short: "Edit the state of a single resource in a stack",
+		Long: `Edit the state of a single resource in a stack
+
+This command opens the given resource's state, as it is recorded in the stack's state, as JSON in the editor named by
+the EDITOR environment variable. Once the editor exits, the edited state is checked and, if it is valid, written back
+to the stack. If the edited state is not valid, the stack's state is left unchanged and the edits are kept in a
+temporary file so that they can be corrected.
+
+Secret values are shown in their encrypted form and cannot be edited.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			// Show the confirmation prompt if the user didn't pass the --yes parameter to skip it.
+			showPrompt := !yes
+
+			urn := resource.URN(args[0])
+			return runStateEdit(stack, showPrompt, urn, func(snap *deploy.Snapshot, res *resource.State) error {
+				return editResource(res, snap.SecretsManager, runEditor, func(edited *resource.State) error {
+					return edit.ReplaceResource(snap, res, edited)
+				})
+			})
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
+
+// editResource writes the serialized form of the given resource to a temporary file, runs the given editor on that
+// file, and passes the resource described by the edited file to apply. If the edited file does not describe a valid
+// resource or apply fails, the file is left in place and the returned error describes where the problem lies.
+func editResource(res *resource.State, sm secrets.Manager,
+	runEditor func(path string) error, apply func(edited *resource.State) error) error {
+
+	var enc config.Encrypter = config.NewPanicCrypter()
+	var dec config.Decrypter = config.NewPanicCrypter()
+	if sm != nil {
+		var err error
+		if enc, err = sm.Encrypter(); err != nil {
+			return errors.Wrap(err, "getting encrypter for resource")
+		}
+		if dec, err = sm.Decrypter(); err != nil {
+			return errors.Wrap(err, "getting decrypter for resource")
+		}
+	}
+
+	sres, err := stack.SerializeResource(res, enc)
+	if err != nil {
+		return errors.Wrap(err, "serializing resource")
+	}
+	original, err := json.MarshalIndent(sres, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", "pulumi-state-edit-*.json")
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	if _, err = f.Write(append(original, '\n')); err != nil {
+		contract.IgnoreClose(f)
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	if err = runEditor(path); err != nil {
+		return errors.Wrapf(err, "editing %s", path)
+	}
+
+	edited, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Reject any edits that are not valid JSON, that do not have the structure of a resource, or that describe a
+	// resource that cannot be deserialized. The edits are kept so that they are not lost.
+	var eres apitype.ResourceV3
+	decoder := json.NewDecoder(bytes.NewReader(edited))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&eres); err != nil {
+		return errors.Errorf("%s: %v; the stack's state has not been changed",
+			describeJSONErrorPosition(path, edited, err), err)
+	}
+	if err = checkEditedResource(eres); err != nil {
+		return errors.Wrapf(err, "%s: invalid resource; the stack's state has not been changed", path)
+	}
+	newRes, err := stack.DeserializeResource(eres, dec)
+	if err != nil {
+		return errors.Wrapf(err, "%s: invalid resource; the stack's state has not been changed", path)
+	}
+	if err = apply(newRes); err != nil {
+		return errors.Wrapf(err, "%s: invalid resource; the stack's state has not been changed", path)
+	}
+
+	return os.Remove(path)
+}
+
+// checkEditedResource checks the invariants that stack.DeserializeResource relies upon.
+func checkEditedResource(res apitype.ResourceV3) error {
+	switch {
+	case res.URN == "":
+		return errors.New("the resource must have a URN")
+	case res.Type == "":
+		return errors.New("the resource must have a type")
+	case !res.Custom && res.ID != "":
+		return errors.New("only custom resources may have an ID")
+	default:
+		return nil
+	}
+}
+
+// describeJSONErrorPosition returns a "file:line:column" description of the location of the given JSON decoding error,
+// or just the file name if the error does not carry a location.
+func describeJSONErrorPosition(path string, contents []byte, err error) string {
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		return path
+	}
+
+	if offset > int64(len(contents)) {
+		offset = int64(len(contents))
+	}
+	prefix := contents[:offset]
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	column := len(prefix) - bytes.LastIndexByte(prefix, '\n')
+	return fmt.Sprintf("%s:%d:%d", path, line, column)
+}
+
+// runEditor runs the editor named by the EDITOR environment variable on the given file and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
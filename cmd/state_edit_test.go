@@ -0,0 +1,113 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/secrets/b64"
+)
+
+func TestEditResource(t *testing.T) {
+	res := &resource.State{
+		Type:   "a:b:c",
+		URN:    resource.NewURN("test", "test", "", "a:b:c", "res"),
+		ID:     "id",
+		Custom: true,
+		Inputs: resource.PropertyMap{
+			"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		},
+		Outputs: resource.PropertyMap{
+			"size": resource.NewStringProperty("small"),
+		},
+	}
+	sm := b64.NewBase64SecretsManager()
+
+	// Returns an editor that replaces the first occurrence of old with new in the edited file.
+	var editedPath string
+	replace := func(old, new string) func(path string) error {
+		return func(path string) error {
+			editedPath = path
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(path, []byte(strings.Replace(string(contents), old, new, 1)), 0600)
+		}
+	}
+
+	// A valid edit is applied, secrets are preserved, and the temporary file is removed.
+	var applied *resource.State
+	err := editResource(res, sm, replace(`"small"`, `"large"`), func(edited *resource.State) error {
+		applied = edited
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, applied) {
+		assert.Equal(t, "large", applied.Outputs["size"].StringValue())
+		assert.Equal(t, res.Inputs, applied.Inputs)
+	}
+	_, err = os.Stat(editedPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// Invalid JSON is rejected with its location, and the edits are kept.
+	err = editResource(res, sm, replace(`"small"`, `small`), func(*resource.State) error {
+		assert.Fail(t, "invalid edits must not be applied")
+		return nil
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), editedPath+":")
+		assert.Contains(t, err.Error(), "has not been changed")
+	}
+	_, err = os.Stat(editedPath)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(editedPath))
+
+	// So are unknown fields.
+	err = editResource(res, sm, replace(`"id"`, `"iden"`), func(*resource.State) error {
+		assert.Fail(t, "invalid edits must not be applied")
+		return nil
+	})
+	assert.Error(t, err)
+	assert.NoError(t, os.Remove(editedPath))
+
+	// And resources that are malformed.
+	err = editResource(res, sm, replace(`"custom": true`, `"custom": false`), func(*resource.State) error {
+		assert.Fail(t, "invalid edits must not be applied")
+		return nil
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "only custom resources may have an ID")
+	}
+	assert.NoError(t, os.Remove(editedPath))
+
+	// And edits that would make the stack's state invalid.
+	err = editResource(res, sm, replace(`"small"`, `"large"`), func(*resource.State) error {
+		return errors.New("invalid snapshot")
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid snapshot")
+	}
+	_, err = os.Stat(editedPath)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(editedPath))
+}
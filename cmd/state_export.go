@@ -0,0 +1,218 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newStateExportCommand() *cobra.Command {
+	var stackName string
+	var file string
+	var urnPatterns []string
+	var dependencies bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the state of some of a stack's resources",
+		Long: `Export the state of some of a stack's resources
+
+This command writes the state of the resources whose URNs match the '--resource' patterns, in which '*' matches any
+number of characters and '?' matches a single character, to standard out or to the file named by '--file'. The
+state is written in the same format that 'pulumi stack export' uses, and may be merged into this or another stack's
+state using 'pulumi state import'.
+
+With '--dependencies', the resources that the selected resources depend on, and their parents and providers, are
+exported too. Otherwise, the references of the selected resources to resources that are not exported are reported,
+as those resources must already exist in any stack into which the state is imported.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if len(urnPatterns) == 0 {
+				return errors.New("at least one --resource pattern is required")
+			}
+			patterns, err := resource.ParseURNPatterns(urnPatterns)
+			if err != nil {
+				return err
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.Errorf("stack '%s' has no resources", s.Ref())
+			}
+
+			selected := selectResources(snap.Resources, patterns, dependencies)
+			if len(selected) == 0 {
+				return errors.New("no resources match the given patterns")
+			}
+			for _, ref := range danglingReferences(selected) {
+				cmdutil.Diag().Warningf(diag.RawMessage(ref.from,
+					fmt.Sprintf("references %s, which is not exported", ref.to)))
+			}
+
+			exported := deploy.NewSnapshot(snap.Manifest, snap.SecretsManager, selected, nil)
+			sdep, err := stack.SerializeDeployment(exported, snap.SecretsManager)
+			if err != nil {
+				return errors.Wrap(err, "serializing the resources' state")
+			}
+			bytes, err := json.Marshal(sdep)
+			if err != nil {
+				return err
+			}
+			deployment := apitype.UntypedDeployment{
+				Version:    apitype.DeploymentSchemaVersionCurrent,
+				Deployment: bytes,
+			}
+
+			writer := os.Stdout
+			if file != "" {
+				if writer, err = os.Create(file); err != nil {
+					return errors.Wrap(err, "could not open file")
+				}
+				defer contract.IgnoreClose(writer)
+			}
+			enc := json.NewEncoder(writer)
+			enc.SetIndent("", "    ")
+			if err = enc.Encode(deployment); err != nil {
+				return errors.Wrap(err, "could not export the resources' state")
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringArrayVarP(
+		&urnPatterns, "resource", "r", []string{},
+		"Export the resources whose URNs match this pattern; may be specified multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&dependencies, "dependencies", false,
+		"Also export the resources that the selected resources depend on")
+	cmd.PersistentFlags().StringVar(
+		&file, "file", "", "A filename to write the state to")
+
+	return cmd
+}
+
+// resourceReferences returns the URNs of the resources that the given resource refers to: its parent, its
+// dependencies, and its provider.
+func resourceReferences(res *resource.State) []resource.URN {
+	var refs []resource.URN
+	if res.Parent != "" {
+		refs = append(refs, res.Parent)
+	}
+	refs = append(refs, res.Dependencies...)
+	for _, deps := range res.PropertyDependencies {
+		refs = append(refs, deps...)
+	}
+	if res.Provider != "" {
+		if ref, err := providers.ParseReference(res.Provider); err == nil {
+			refs = append(refs, ref.URN())
+		}
+	}
+	return refs
+}
+
+// selectResources returns the resources, which are in dependency order, whose URNs match any of the given patterns,
+// along with the resources that they refer to if dependencies is true. Resources that are pending deletion are never
+// selected.
+func selectResources(resources []*resource.State, patterns []resource.URNPattern,
+	dependencies bool) []*resource.State {
+
+	selected := make(map[resource.URN]bool)
+	for _, res := range resources {
+		if !res.Delete && resource.MatchesAnyURNPattern(patterns, res.URN) {
+			selected[res.URN] = true
+		}
+	}
+	if dependencies {
+		// Walking the resources backwards visits every resource that refers to a given resource before the resource
+		// itself.
+		for i := len(resources) - 1; i >= 0; i-- {
+			if res := resources[i]; !res.Delete && selected[res.URN] {
+				for _, ref := range resourceReferences(res) {
+					selected[ref] = true
+				}
+			}
+		}
+	}
+
+	var result []*resource.State
+	for _, res := range resources {
+		if !res.Delete && selected[res.URN] {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// resourceReference is a reference of one resource to another.
+type resourceReference struct {
+	from resource.URN // the resource that holds the reference.
+	to   resource.URN // the resource that it refers to.
+}
+
+// danglingReferences returns the references of the given resources to resources that are not among them, sorted.
+func danglingReferences(resources []*resource.State) []resourceReference {
+	urns := make(map[resource.URN]bool)
+	for _, res := range resources {
+		urns[res.URN] = true
+	}
+
+	seen := make(map[resourceReference]bool)
+	var refs []resourceReference
+	for _, res := range resources {
+		for _, to := range resourceReferences(res) {
+			ref := resourceReference{from: res.URN, to: to}
+			if !urns[to] && !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].from != refs[j].from {
+			return refs[i].from < refs[j].from
+		}
+		return refs[i].to < refs[j].to
+	})
+	return refs
+}
@@ -0,0 +1,70 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// testStateResources returns a provider, a bucket that uses it, and an object in the bucket, in dependency order.
+func testStateResources() []*resource.State {
+	prov := &resource.State{
+		Type: "pulumi:providers:aws",
+		URN:  "urn:pulumi:dev::web::pulumi:providers:aws::default",
+		ID:   "prov-id",
+	}
+	provRef := string(prov.URN) + "::" + string(prov.ID)
+	bucket := &resource.State{
+		Type:     "aws:s3/bucket:Bucket",
+		URN:      "urn:pulumi:dev::web::aws:s3/bucket:Bucket::logs",
+		ID:       "logs",
+		Provider: provRef,
+	}
+	object := &resource.State{
+		Type:                 "aws:s3/bucketObject:BucketObject",
+		URN:                  "urn:pulumi:dev::web::aws:s3/bucketObject:BucketObject::index",
+		ID:                   "index",
+		Provider:             provRef,
+		Dependencies:         []resource.URN{bucket.URN},
+		PropertyDependencies: map[resource.PropertyKey][]resource.URN{"bucket": {bucket.URN}},
+	}
+	return []*resource.State{prov, bucket, object}
+}
+
+func TestSelectResources(t *testing.T) {
+	resources := testStateResources()
+	patterns, err := resource.ParseURNPatterns([]string{"*::index"})
+	assert.NoError(t, err)
+
+	selected := selectResources(resources, patterns, false)
+	assert.Equal(t, resources[2:], selected)
+	assert.Equal(t, []resourceReference{
+		{from: resources[2].URN, to: resources[1].URN},
+		{from: resources[2].URN, to: resources[0].URN},
+	}, danglingReferences(selected))
+
+	// The dependency closure includes the resources' dependencies and providers.
+	selected = selectResources(resources, patterns, true)
+	assert.Equal(t, resources, selected)
+	assert.Empty(t, danglingReferences(selected))
+
+	// Resources that are pending deletion are never exported.
+	resources[2].Delete = true
+	assert.Empty(t, selectResources(resources, patterns, true))
+}
@@ -0,0 +1,221 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newStateHistoryCommand() *cobra.Command {
+	var stack string
+	var since string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "history [<number>]",
+		Short: "List or show the audit snapshots of a stack's state",
+		Long: `List or show the audit snapshots of a stack's state
+
+If the ` + backend.AuditDirEnvVar + ` environment variable names a directory, a read-only snapshot of a stack's
+state is written to that directory after each update, destroy, or import that changes the state. Snapshots are
+never overwritten, and each one records the SHA-256 hash of the stack's previous snapshot, so that the removal or
+alteration of a snapshot can be detected.
+
+Without arguments, this command lists the stack's snapshots, oldest first, and reports any whose record of the
+previous snapshot does not match. Given the number of a snapshot in that list, it writes the stack's state as of
+that snapshot to standard out, in the format that 'pulumi stack export' uses. The list may be limited to the
+snapshots taken after a point in time using '--since'; the snapshots keep their numbers.`,
+		Args: cmdutil.MaximumNArgs(1),
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			dir := backend.AuditDir()
+			if dir == "" {
+				return result.Errorf("no audit directory is configured; set %s to save snapshots of stacks' state",
+					backend.AuditDirEnvVar)
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stack, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			entries, err := backend.ReadAuditSnapshots(dir, s.Ref().String())
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			if len(args) == 0 {
+				startTime, err := parseSince(since, time.Now())
+				if err != nil {
+					return result.FromError(
+						errors.Wrapf(err, "failed to parse argument to '--since' as duration or timestamp"))
+				}
+				return printAuditSnapshots(entries, startTime, jsonOut)
+			}
+
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > len(entries) {
+				return result.Errorf("there is no snapshot numbered '%s'; the stack has %d snapshot(s)",
+					args[0], len(entries))
+			}
+			var compacted, indented bytes.Buffer
+			if err = json.Compact(&compacted, entries[n-1].Snapshot.Deployment); err != nil {
+				return result.FromError(errors.Wrapf(err, "reading %s", entries[n-1].Path))
+			}
+			if err = json.Indent(&indented, compacted.Bytes(), "", "    "); err != nil {
+				return result.FromError(errors.Wrapf(err, "reading %s", entries[n-1].Path))
+			}
+			fmt.Println(indented.String())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&since, "since", "",
+		"Only list the snapshots taken after a relative duration ('5s', '2m', '3h') or absolute timestamp")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit the list of snapshots as JSON")
+
+	return cmd
+}
+
+// auditSnapshotJSON is the shape of each element of the --json output of an audit snapshot listing.
+type auditSnapshotJSON struct {
+	Number    int    `json:"number"`
+	Time      string `json:"time"`
+	Kind      string `json:"kind"`
+	Resources *int   `json:"resources,omitempty"`
+	Intact    bool   `json:"intact"`
+	File      string `json:"file"`
+}
+
+// listAuditSnapshots returns a description of each of the given audit snapshots that was taken after the given time,
+// if it is non-nil. Each snapshot is numbered by its position among all of the snapshots.
+func listAuditSnapshots(entries []backend.AuditEntry, since *time.Time) []auditSnapshotJSON {
+	list := []auditSnapshotJSON{}
+	for i, entry := range entries {
+		if since != nil && !entry.Snapshot.Time.After(*since) {
+			continue
+		}
+
+		var deployment struct {
+			Resources []json.RawMessage `json:"resources"`
+		}
+		var resources *int
+		var untyped apitype.UntypedDeployment
+		if json.Unmarshal(entry.Snapshot.Deployment, &untyped) == nil &&
+			json.Unmarshal(untyped.Deployment, &deployment) == nil {
+			n := len(deployment.Resources)
+			resources = &n
+		}
+
+		list = append(list, auditSnapshotJSON{
+			Number:    i + 1,
+			Time:      entry.Snapshot.Time.UTC().Format(timeFormat),
+			Kind:      string(entry.Snapshot.Kind),
+			Resources: resources,
+			Intact:    entry.Intact,
+			File:      filepath.Base(entry.Path),
+		})
+	}
+	return list
+}
+
+// printAuditSnapshots prints a table, or JSON array, of the given audit snapshots that were taken after the given
+// time, if it is non-nil, and fails if any of them is not intact.
+func printAuditSnapshots(entries []backend.AuditEntry, since *time.Time, jsonOut bool) result.Result {
+	list := listAuditSnapshots(entries, since)
+	var broken int
+	for _, snapshot := range list {
+		if !snapshot.Intact {
+			broken++
+		}
+	}
+
+	if jsonOut {
+		if err := printJSON(list); err != nil {
+			return result.FromError(err)
+		}
+	} else if len(list) == 0 {
+		fmt.Println("The stack has no snapshots to show")
+		return nil
+	} else {
+		var rows []cmdutil.TableRow
+		for _, snapshot := range list {
+			resources := "?"
+			if snapshot.Resources != nil {
+				resources = strconv.Itoa(*snapshot.Resources)
+			}
+			intact := "yes"
+			if !snapshot.Intact {
+				intact = "NO"
+			}
+			rows = append(rows, cmdutil.TableRow{Columns: []string{
+				strconv.Itoa(snapshot.Number),
+				entries[snapshot.Number-1].Snapshot.Time.Local().Format(timeFormat),
+				snapshot.Kind,
+				resources,
+				intact,
+				snapshot.File,
+			}})
+		}
+
+		cmdutil.PrintTable(cmdutil.Table{
+			Headers: []string{"NUMBER", "TIME", "KIND", "RESOURCES", "INTACT", "FILE"},
+			Rows:    rows,
+		})
+	}
+
+	if broken > 0 {
+		return result.Errorf("%d snapshot(s) do not match the snapshots that precede them; "+
+			"a snapshot may have been removed or altered", broken)
+	}
+	return nil
+}
+
+// auditUpdate saves a snapshot of the given stack's state to the audit directory, if there is one, after an operation
+// of the given kind that returned the given result, which it returns. The snapshot is saved even if the operation
+// failed, since it may have changed the stack's state before it did.
+func auditUpdate(s backend.Stack, kind apitype.UpdateKind, res result.Result) result.Result {
+	err := backend.SaveAuditSnapshot(commandContext(), s, kind)
+	if err == nil {
+		return res
+	}
+	err = errors.Wrapf(err, "saving an audit snapshot to %s", backend.AuditDir())
+	if res == nil {
+		return result.FromError(err)
+	}
+	cmdutil.Diag().Errorf(diag.Message("", "%v"), err)
+	return res
+}
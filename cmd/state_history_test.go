@@ -0,0 +1,65 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+)
+
+func TestListAuditSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		state, err := json.Marshal(map[string]interface{}{"resources": make([]int, i)})
+		assert.NoError(t, err)
+		_, err = backend.WriteAuditSnapshot(dir, "dev", apitype.UpdateUpdate, now.Add(time.Duration(i)*time.Hour),
+			&apitype.UntypedDeployment{Version: 3, Deployment: state})
+		assert.NoError(t, err)
+	}
+	entries, err := backend.ReadAuditSnapshots(dir, "dev")
+	assert.NoError(t, err)
+
+	list := listAuditSnapshots(entries, nil)
+	if assert.Len(t, list, 3) {
+		assert.Equal(t, 1, list[0].Number)
+		assert.Equal(t, 0, *list[0].Resources)
+		assert.True(t, list[0].Intact)
+	}
+
+	// Snapshots that are filtered out do not change the numbers of the others.
+	since := now.Add(30 * time.Minute)
+	list = listAuditSnapshots(entries, &since)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, 2, list[0].Number)
+		assert.Equal(t, 1, *list[0].Resources)
+		assert.Equal(t, 3, list[1].Number)
+		assert.Equal(t, string(apitype.UpdateUpdate), list[1].Kind)
+	}
+
+	since = now.Add(3 * time.Hour)
+	assert.Empty(t, listAuditSnapshots(entries, &since))
+}
@@ -0,0 +1,192 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newStateImportCommand() *cobra.Command {
+	var stackName string
+	var file string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Merge the exported state of some resources into a stack's state",
+		Long: `Merge the exported state of some resources into a stack's state
+
+This command reads the state of some resources, as written by 'pulumi state export', from standard in or from the
+file named by '--file', and merges it into the stack's state. Each resource replaces the resource in the stack with
+the same URN, if there is one, and is otherwise added to the stack. Resources that were exported from a different
+stack are moved to this stack, and their secrets are encrypted using this stack's secrets provider.
+
+The import fails, and the stack's state is left unchanged, if any of the resources would refer to a resource that
+the stack does not have, or if the resources cannot be merged in dependency order.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			var reader io.Reader = os.Stdin
+			if file != "" {
+				f, err := os.Open(file)
+				if err != nil {
+					return result.FromError(errors.Wrap(err, "could not open file"))
+				}
+				defer contract.IgnoreClose(f)
+				reader = f
+			}
+			var deployment apitype.UntypedDeployment
+			if err := json.NewDecoder(reader).Decode(&deployment); err != nil {
+				return result.FromError(errors.Wrap(err, "could not read the exported state"))
+			}
+			exported, err := stack.DeserializeUntypedDeployment(&deployment, stack.DefaultSecretsProvider)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "could not deserialize the exported state"))
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			var replaced, added int
+			res := runTotalStateEdit(stackName, !yes, func(_ display.Options, snap *deploy.Snapshot) error {
+				if snap == nil {
+					return errors.New("the stack has no state to merge into; use 'pulumi stack import' to import " +
+						"the exported state as the stack's whole state")
+				}
+
+				imported := moveResourcesToStack(exported.Resources, s.Ref().Name())
+
+				var merged []*resource.State
+				merged, replaced, added = mergeResources(snap.Resources, imported)
+				candidate := deploy.NewSnapshot(snap.Manifest, snap.SecretsManager, merged, snap.PendingOperations)
+				if err := candidate.VerifyIntegrity(); err != nil {
+					return errors.Wrap(err, "the merged state would be invalid")
+				}
+				snap.Resources = merged
+				return nil
+			})
+			if res != nil {
+				return res
+			}
+			fmt.Printf("Imported %d resource(s): %d replaced, %d added\n", replaced+added, replaced, added)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&file, "file", "", "A filename to read the exported state from")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts")
+
+	return cmd
+}
+
+// moveResourcesToStack returns copies of the given resources whose URNs, and references to other resources, name
+// the given stack.
+func moveResourcesToStack(resources []*resource.State, stackName tokens.QName) []*resource.State {
+	move := func(urn resource.URN) resource.URN {
+		if urn == "" || urn.Stack() == stackName {
+			return urn
+		}
+		return resource.NewURN(stackName, urn.Project(), "", urn.QualifiedType(), urn.Name())
+	}
+	moveAll := func(urns []resource.URN) []resource.URN {
+		if urns == nil {
+			return nil
+		}
+		moved := make([]resource.URN, len(urns))
+		for i, urn := range urns {
+			moved[i] = move(urn)
+		}
+		return moved
+	}
+
+	moved := make([]*resource.State, len(resources))
+	for i, res := range resources {
+		r := *res
+		r.URN, r.Parent = move(res.URN), move(res.Parent)
+		r.Dependencies, r.Aliases = moveAll(res.Dependencies), moveAll(res.Aliases)
+		if res.PropertyDependencies != nil {
+			r.PropertyDependencies = make(map[resource.PropertyKey][]resource.URN)
+			for k, deps := range res.PropertyDependencies {
+				r.PropertyDependencies[k] = moveAll(deps)
+			}
+		}
+		if res.Provider != "" {
+			if ref, err := providers.ParseReference(res.Provider); err == nil {
+				moved, err := providers.NewReference(move(ref.URN()), ref.ID())
+				contract.AssertNoError(err)
+				r.Provider = moved.String()
+			}
+		}
+		moved[i] = &r
+	}
+	return moved
+}
+
+// mergeResources merges the given imported resources into the given existing resources. Each imported resource
+// replaces the existing resource with the same URN that is not pending deletion, if there is one, and is otherwise
+// added after the existing resources. It returns the merged resources and the numbers of replaced and added resources.
+func mergeResources(existing, imported []*resource.State) ([]*resource.State, int, int) {
+	byURN := make(map[resource.URN]*resource.State)
+	for _, res := range imported {
+		byURN[res.URN] = res
+	}
+
+	var merged []*resource.State
+	var replaced int
+	for _, res := range existing {
+		if imp, has := byURN[res.URN]; has && !res.Delete {
+			merged = append(merged, imp)
+			delete(byURN, res.URN)
+			replaced++
+		} else {
+			merged = append(merged, res)
+		}
+	}
+	var added int
+	for _, res := range imported {
+		if _, has := byURN[res.URN]; has {
+			merged = append(merged, res)
+			added++
+		}
+	}
+	return merged, replaced, added
+}
@@ -0,0 +1,48 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestMoveResourcesToStack(t *testing.T) {
+	resources := testStateResources()
+	moved := moveResourcesToStack(resources, "prod")
+	assert.Equal(t, resource.URN("urn:pulumi:prod::web::aws:s3/bucketObject:BucketObject::index"), moved[2].URN)
+	assert.Equal(t, []resource.URN{moved[1].URN}, moved[2].Dependencies)
+	assert.Equal(t, []resource.URN{moved[1].URN}, moved[2].PropertyDependencies["bucket"])
+	assert.Equal(t, string(moved[0].URN)+"::prov-id", moved[2].Provider)
+
+	// The original resources are not changed.
+	assert.Equal(t, tokens.QName("dev"), resources[2].URN.Stack())
+}
+
+func TestMergeResources(t *testing.T) {
+	resources := testStateResources()
+	bucket := *resources[1]
+	bucket.ID = "new-logs"
+	queue := &resource.State{Type: "aws:sqs/queue:Queue", URN: "urn:pulumi:dev::web::aws:sqs/queue:Queue::jobs"}
+
+	merged, replaced, added := mergeResources(resources, []*resource.State{&bucket, queue})
+	assert.Equal(t, []*resource.State{resources[0], &bucket, resources[2], queue}, merged)
+	assert.Equal(t, 1, replaced)
+	assert.Equal(t, 1, added)
+}
@@ -0,0 +1,141 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateLogCommand() *cobra.Command {
+	var stackName string
+	var since string
+	var operation string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "log <resource URN>",
+		Short: "Show the lifecycle events of a single resource in a stack",
+		Long: `Show the lifecycle events of a single resource in a stack
+
+Each resource's state records the latest operations that changed it: its creation, updates, replacements, and
+import, each with the time at which it completed and the ID of the update that performed it. This command lists
+those events, oldest first.
+
+The number of events that are kept for each resource is set by the '` + deploy.ResourceHistoryConfigKey.String() + `'
+configuration key, and defaults to ` + fmt.Sprint(deploy.DefaultResourceHistory) + `. A value of 0 keeps no events.
+
+The events may be limited to those that completed after a point in time using '--since', and to those of a single
+update using '--operation'.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			startTime, err := parseSince(since, time.Now())
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse argument to '--since' as duration or timestamp")
+			}
+
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.Errorf("stack '%s' has no resources", s.Ref())
+			}
+
+			res, err := locateStackResource(opts, snap, resource.URN(args[0]))
+			if err != nil {
+				return err
+			}
+			history := filterLifecycleEvents(res.History, startTime, operation)
+			if jsonOut {
+				if history == nil {
+					history = []resource.LifecycleEvent{}
+				}
+				return printJSON(history)
+			}
+			printLifecycleEvents(history)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&since, "since", "",
+		"Only show the events that completed after a relative duration ('5s', '2m', '3h') or absolute timestamp")
+	cmd.PersistentFlags().StringVar(
+		&operation, "operation", "",
+		"Only show the events of the update with this ID, as shown by 'pulumi history'")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit output as JSON")
+
+	return cmd
+}
+
+// filterLifecycleEvents returns the given lifecycle events that completed after the given time, if it is non-nil, and
+// that were recorded by the given update, if it is non-empty.
+func filterLifecycleEvents(history []resource.LifecycleEvent, since *time.Time,
+	operation string) []resource.LifecycleEvent {
+
+	var filtered []resource.LifecycleEvent
+	for _, event := range history {
+		if since != nil && !event.Time.After(*since) {
+			continue
+		}
+		if operation != "" && event.Operation != operation {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
+// printLifecycleEvents prints a table of the given lifecycle events.
+func printLifecycleEvents(history []resource.LifecycleEvent) {
+	if len(history) == 0 {
+		fmt.Println("There are no lifecycle events to show")
+		return
+	}
+
+	var rows []cmdutil.TableRow
+	for _, event := range history {
+		rows = append(rows, cmdutil.TableRow{Columns: []string{
+			event.Time.Local().Format(timeFormat),
+			event.Op,
+			event.Operation,
+		}})
+	}
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: []string{"TIME", "OPERATION", "UPDATE"},
+		Rows:    rows,
+	})
+}
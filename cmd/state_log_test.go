@@ -0,0 +1,40 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestFilterLifecycleEvents(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	history := []resource.LifecycleEvent{
+		{Op: "create", Time: now.Add(-3 * time.Hour), Operation: "update-1"},
+		{Op: "update", Time: now.Add(-2 * time.Hour), Operation: "update-2"},
+		{Op: "replace", Time: now.Add(-time.Hour), Operation: "update-3"},
+	}
+
+	assert.Equal(t, history, filterLifecycleEvents(history, nil, ""))
+
+	since := now.Add(-150 * time.Minute)
+	assert.Equal(t, history[1:], filterLifecycleEvents(history, &since, ""))
+	assert.Equal(t, history[1:2], filterLifecycleEvents(history, nil, "update-2"))
+	assert.Empty(t, filterLifecycleEvents(history, &since, "update-1"))
+}
@@ -0,0 +1,179 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newStatePruneCommand() *cobra.Command {
+	var dryRun bool
+	var stack string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Find, and optionally destroy, resources that a stack's program no longer defines",
+		Long: `Find, and optionally destroy, resources that a stack's program no longer defines
+
+This command previews an update of the stack in order to find the resources in its state that its program no
+longer defines, for example because an earlier update was interrupted before it could delete them. Resources that
+the program defines under a new name, and that declare their old name as an alias, are not reported.
+
+The resources that were found are then destroyed, after confirmation. Any of these resources that other resources
+depend on are retained. Pass --dry-run to list the resources without destroying them.
+
+The program to run is loaded from the project in the current directory. Use the ` + "`-C` or `--cwd`" + ` flag to
+use a different directory.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			interactive := cmdutil.Interactive()
+			if !interactive {
+				yes = true // auto-approve changes, since we cannot prompt.
+			}
+
+			opts, err := updateFlagsToOptions(interactive, false /*skipPreview*/, yes)
+			if err != nil {
+				return result.FromError(err)
+			}
+			opts.Engine = engine.UpdateOptions{
+				UseLegacyDiff: useLegacyDiff(),
+			}
+			opts.Display = display.Options{
+				Color:           cmdutil.GetGlobalColorization(),
+				SuppressOutputs: true,
+				IsInteractive:   interactive,
+				Type:            display.DisplayProgress,
+			}
+
+			s, err := requireStack(stack, false, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			proj, root, err := readProject(pulumiAppProj)
+			if err != nil {
+				return result.FromError(err)
+			}
+			m, err := getUpdateMetadata("", root)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "gathering environment metadata"))
+			}
+			sm, err := getStackSecretsManager(s)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting secrets manager"))
+			}
+			cfg, err := getStackConfiguration(s, sm)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting stack configuration"))
+			}
+			op := backend.UpdateOperation{
+				Proj:               proj,
+				Root:               root,
+				M:                  m,
+				Opts:               opts,
+				StackConfiguration: cfg,
+				SecretsManager:     sm,
+				Scopes:             cancellationScopes,
+			}
+
+			// Preview an update, and collect the resources that it would delete.
+			events := make(chan engine.Event)
+			orphansDone := make(chan []resource.URN)
+			go func() {
+				orphansDone <- findOrphans(events)
+			}()
+			op.Opts.PreviewEvents = events
+			_, res := s.Preview(commandContext(), op)
+			close(events)
+			orphans := <-orphansDone
+			if res != nil {
+				return PrintEngineResult(res)
+			}
+
+			if len(orphans) == 0 {
+				fmt.Println("No resources were found that the program no longer defines.")
+				return nil
+			}
+			fmt.Println(opts.Display.Color.Colorize(fmt.Sprintf(
+				"%sFound %d resource(s) that the program no longer defines:%s",
+				colors.SpecHeadline, len(orphans), colors.Reset)))
+			for _, urn := range orphans {
+				fmt.Printf("    %s\n", urn)
+			}
+			if dryRun {
+				return nil
+			}
+			fmt.Println()
+
+			// Destroy the orphans, and nothing else.
+			op.Opts.PreviewEvents = nil
+			op.Opts.Engine.DestroyTargets = orphans
+			_, res = s.Destroy(commandContext(), op)
+			res = auditUpdate(s, apitype.DestroyUpdate, res)
+			if res != nil && res.Error() == context.Canceled {
+				return result.FromError(errors.New("destroy cancelled"))
+			}
+			return PrintEngineResult(res)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
+	cmd.Flags().BoolVar(
+		&dryRun, "dry-run", false,
+		"List the resources that the program no longer defines without destroying them")
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform the destroy after previewing it")
+
+	return cmd
+}
+
+// findOrphans returns the URNs of the resources that the preview whose events are read from the given channel would
+// delete, in the order in which they are first reported.
+func findOrphans(events <-chan engine.Event) []resource.URN {
+	var orphans []resource.URN
+	seen := make(map[resource.URN]bool)
+	for e := range events {
+		if e.Type != engine.ResourcePreEvent {
+			continue
+		}
+		step := e.Payload.(engine.ResourcePreEventPayload).Metadata
+		if step.Op == deploy.OpDelete && !seen[step.URN] {
+			seen[step.URN] = true
+			orphans = append(orphans, step.URN)
+		}
+	}
+	return orphans
+}
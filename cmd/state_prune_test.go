@@ -0,0 +1,51 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestFindOrphans(t *testing.T) {
+	step := func(op deploy.StepOp, urn resource.URN) engine.Event {
+		return engine.Event{
+			Type: engine.ResourcePreEvent,
+			Payload: engine.ResourcePreEventPayload{
+				Metadata: engine.StepEventMetadata{Op: op, URN: urn},
+				Planning: true,
+			},
+		}
+	}
+
+	events := make(chan engine.Event, 6)
+	events <- step(deploy.OpSame, "urn:pulumi:stack::proj::pkgA:m:typA::kept")
+	events <- step(deploy.OpDelete, "urn:pulumi:stack::proj::pkgA:m:typA::orphanB")
+	events <- step(deploy.OpDeleteReplaced, "urn:pulumi:stack::proj::pkgA:m:typA::replaced")
+	events <- engine.Event{Type: engine.SummaryEvent, Payload: engine.SummaryEventPayload{}}
+	events <- step(deploy.OpDelete, "urn:pulumi:stack::proj::pkgA:m:typA::orphanA")
+	events <- step(deploy.OpDelete, "urn:pulumi:stack::proj::pkgA:m:typA::orphanB")
+	close(events)
+
+	assert.Equal(t, []resource.URN{
+		"urn:pulumi:stack::proj::pkgA:m:typA::orphanB",
+		"urn:pulumi:stack::proj::pkgA:m:typA::orphanA",
+	}, findOrphans(events))
+}
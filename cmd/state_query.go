@@ -0,0 +1,163 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/query"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateQueryCommand() *cobra.Command {
+	var fieldNames []string
+	var jsonOut bool
+	var showSecrets bool
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "query <expression>",
+		Short: "Find the resources in a stack's state that match a query",
+		Long: `Find the resources in a stack's state that match a query
+
+This command prints the URN of each resource in the stack's state that matches the given expression. The stack's
+program is not run. An expression is made up of predicates combined with "and" and "or", and may use parentheses
+for grouping:
+
+    field = value       the field's value is equal to the given value
+    field ^= value      the field's value starts with the given value
+    exists(field)       the field has a non-null value
+
+A field is one of urn, type, name, id, parent, provider, or protect, or a property path beginning with inputs or
+outputs, such as outputs.tags.Name or inputs.rules[0].port. Values containing spaces or parentheses must be quoted.
+
+For example:
+
+    pulumi state query 'type = aws:s3/bucket:Bucket and exists(outputs.website)'
+
+The values of other fields may be printed alongside each URN by passing --field. Secret values are displayed as
+"[secret]" unless --show-secrets is passed, and never match an equality or prefix predicate.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			q, err := query.Parse(args[0])
+			if err != nil {
+				return err
+			}
+			fields := make([]query.Field, len(fieldNames))
+			for i, name := range fieldNames {
+				if fields[i], err = query.ParseField(name); err != nil {
+					return err
+				}
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			var resources []*resource.State
+			if snap != nil {
+				resources = snap.Resources
+			}
+
+			matches := query.Filter(q, resources)
+			if jsonOut {
+				return printQueryResultsJSON(matches, fieldNames, fields, showSecrets)
+			}
+			return printQueryResults(os.Stdout, matches, fields, showSecrets)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringArrayVarP(
+		&fieldNames, "field", "f", []string{},
+		"Print the value of the given field for each matching resource; may be specified multiple times")
+	cmd.Flags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit output as JSON")
+	cmd.Flags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show secret values in plaintext instead of displaying blinded values")
+
+	return cmd
+}
+
+// queryResultJSON is the JSON representation of a resource that matches a query.
+type queryResultJSON struct {
+	URN    resource.URN           `json:"urn"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+func printQueryResultsJSON(matches []*resource.State, names []string, fields []query.Field, showSecrets bool) error {
+	results := make([]queryResultJSON, len(matches))
+	for i, res := range matches {
+		results[i].URN = res.URN
+		if len(fields) > 0 {
+			results[i].Fields = make(map[string]interface{})
+			for j, f := range fields {
+				var value interface{}
+				if v, ok := f.Get(res); ok {
+					value = v.Plain(showSecrets)
+				}
+				results[i].Fields[names[j]] = value
+			}
+		}
+	}
+	return printJSON(results)
+}
+
+// printQueryResults prints the URN of each matching resource, followed by the values of the requested fields, as a
+// line of tab-separated values. Strings are printed as-is, other values as JSON, and missing values as empty strings.
+func printQueryResults(w io.Writer, matches []*resource.State, fields []query.Field, showSecrets bool) error {
+	for _, res := range matches {
+		columns := []string{string(res.URN)}
+		for _, f := range fields {
+			var column string
+			if v, ok := f.Get(res); ok {
+				plain := v.Plain(showSecrets)
+				if s, isString := plain.(string); isString {
+					column = s
+				} else {
+					b, err := json.Marshal(plain)
+					if err != nil {
+						return errors.Wrapf(err, "formatting value of %s", res.URN)
+					}
+					column = string(b)
+				}
+			}
+			columns = append(columns, column)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(columns, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
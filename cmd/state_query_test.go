@@ -0,0 +1,55 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/query"
+)
+
+func TestPrintQueryResults(t *testing.T) {
+	res := &resource.State{
+		URN:  "urn:pulumi:stack::project::pkgA:m:typA::resA",
+		Type: "pkgA:m:typA",
+		Outputs: resource.PropertyMap{
+			"port":     resource.NewNumberProperty(80),
+			"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		},
+	}
+
+	var fields []query.Field
+	for _, name := range []string{"type", "outputs.port", "outputs.password", "outputs.missing"} {
+		f, err := query.ParseField(name)
+		assert.NoError(t, err)
+		fields = append(fields, f)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, printQueryResults(&buf, []*resource.State{res}, fields, false))
+	assert.Equal(t, string(res.URN)+"\tpkgA:m:typA\t80\t[secret]\t\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, printQueryResults(&buf, []*resource.State{res}, fields[2:3], true))
+	assert.Equal(t, string(res.URN)+"\thunter2\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, printQueryResults(&buf, []*resource.State{res}, nil, false))
+	assert.Equal(t, string(res.URN)+"\n", buf.String())
+}
@@ -0,0 +1,126 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newStateReplaceProviderCommand() *cobra.Command {
+	var oldProvider string
+	var newProvider string
+	var stack string
+	var types []string
+	var urns []string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "replace-provider",
+		Short: "Change the provider of resources in a stack's state",
+		Long: `Change the provider of resources in a stack's state
+
+This command changes the provider reference of each resource in the stack's state that uses the provider passed
+to --old, so that the resource uses the provider passed to --new instead. Both providers must already be recorded
+in the stack's state, and must be providers for the same package. A provider reference is the provider's URN,
+followed by "::" and its ID.
+
+Only the state is changed: the resources themselves are not touched, so the new provider must be able to manage
+the resources as they are, e.g. because it is configured to use the same account under new credentials.
+
+Pass --type or --urn to change only some of the resources. A --urn pattern may use '*' to match any number of
+characters and '?' to match a single character.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			if oldProvider == "" || newProvider == "" {
+				return result.Error("both --old and --new must be passed")
+			}
+			oldRef, err := providers.ParseReference(oldProvider)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "invalid --old provider reference"))
+			}
+			newRef, err := providers.ParseReference(newProvider)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "invalid --new provider reference"))
+			}
+			filter, err := newResourceFilter(types, urns)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			var changed int
+			res := runTotalStateEdit(stack, !yes, func(_ display.Options, snap *deploy.Snapshot) error {
+				changed, err = edit.ReplaceProvider(snap, oldRef, newRef, filter)
+				return err
+			})
+			if res != nil {
+				return res
+			}
+			fmt.Printf("Changed the provider of %d resource(s)\n", changed)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringVar(
+		&oldProvider, "old", "",
+		"The reference of the provider that the resources use now")
+	cmd.Flags().StringVar(
+		&newProvider, "new", "",
+		"The reference of the provider that the resources should use instead")
+	cmd.Flags().StringArrayVar(
+		&types, "type", nil,
+		"Only change the resources of this type; may be specified multiple times")
+	cmd.Flags().StringArrayVar(
+		&urns, "urn", nil,
+		"Only change the resources whose URNs match this pattern; may be specified multiple times")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
+
+// newResourceFilter returns a function that returns true for the resources that have one of the given types, if any,
+// and whose URNs match one of the given patterns, if any. In a pattern, '*' matches any number of characters and '?'
+// matches a single character.
+func newResourceFilter(types, urnPatterns []string) (func(*resource.State) bool, error) {
+	typeSet := make(map[tokens.Type]bool)
+	for _, t := range types {
+		typeSet[tokens.Type(t)] = true
+	}
+	patterns, err := resource.ParseURNPatterns(urnPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(res *resource.State) bool {
+		if len(typeSet) > 0 && !typeSet[res.Type] {
+			return false
+		}
+		return len(patterns) == 0 || resource.MatchesAnyURNPattern(patterns, res.URN)
+	}, nil
+}
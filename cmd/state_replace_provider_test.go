@@ -0,0 +1,41 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestNewResourceFilter(t *testing.T) {
+	bucket := &resource.State{Type: "aws:s3/bucket:Bucket", URN: "urn:pulumi:dev::web::aws:s3/bucket:Bucket::logs"}
+	queue := &resource.State{Type: "aws:sqs/queue:Queue", URN: "urn:pulumi:dev::web::aws:sqs/queue:Queue::jobs"}
+
+	matches := func(types, urns []string) []bool {
+		filter, err := newResourceFilter(types, urns)
+		assert.NoError(t, err)
+		return []bool{filter(bucket), filter(queue)}
+	}
+
+	assert.Equal(t, []bool{true, true}, matches(nil, nil))
+	assert.Equal(t, []bool{true, false}, matches([]string{"aws:s3/bucket:Bucket"}, nil))
+	assert.Equal(t, []bool{false, true}, matches(nil, []string{"*::jobs"}))
+	assert.Equal(t, []bool{true, true}, matches(nil, []string{"urn:pulumi:dev::web::aws:s*"}))
+	assert.Equal(t, []bool{true, false}, matches(nil, []string{"*::log?"}))
+	assert.Equal(t, []bool{false, false}, matches([]string{"aws:s3/bucket:Bucket"}, []string{"*::jobs"}))
+}
@@ -0,0 +1,41 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+const targetReplaceFlagUsage = "Replace the resource with this URN, and leave every other resource as it is; may be " +
+	"specified multiple times to replace several resources"
+
+const targetDependentsFlagUsage = "Also update the resources that depend on the targets of --target-replace, " +
+	"which may be affected by their replacement"
+
+// getReplaceTargets returns the URNs passed with --target-replace. It fails if --target-dependents was passed without
+// any targets.
+func getReplaceTargets(urns []string, dependents bool) ([]resource.URN, error) {
+	if dependents && len(urns) == 0 {
+		return nil, errors.New("--target-dependents may only be passed with --target-replace")
+	}
+
+	var targets []resource.URN
+	for _, urn := range urns {
+		targets = append(targets, resource.URN(urn))
+	}
+	return targets, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestGetReplaceTargets(t *testing.T) {
+	urn := "urn:pulumi:dev::proj::pkgA:m:typA::resA"
+	targets, err := getReplaceTargets([]string{urn}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{resource.URN(urn)}, targets)
+
+	// Without --target-replace, there is nothing to expand, and targeting is not strict.
+	targets, err = getReplaceTargets(nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, targets)
+	_, err = getReplaceTargets(nil, true)
+	assert.Error(t, err)
+}
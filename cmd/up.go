@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -27,9 +28,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
@@ -52,15 +56,42 @@ func newUpCmd() *cobra.Command {
 	var configArray []string
 
 	// Flags for engine.UpdateOptions.
+	var acceptDrift bool
+	var allowReplace bool
 	var analyzers []string
+	var batchSize int
+	var approvalWebhook string
+	var approvalTimeout time.Duration
+	var costEstimator string
+	var deprecationErrors bool
 	var diffDisplay bool
+	var features []string
+	var logResources []string
+	var maxErrors int
+	var mockFixtures string
 	var parallel int
+	var readParallel int
+	var sliceNames []string
+	var sliceCreateMissing bool
+	var targetReplaces []string
+	var targetDependents bool
+	var planFile string
+	var providerParallel []string
 	var refresh bool
+	var resourceTimeout time.Duration
+	var resourceTimeouts []string
+	var retryAttempts int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var showURNs bool
+	var previewOnly bool
 	var skipPreview bool
 	var suppressOutputs bool
+	var useMocks bool
+	var providerDryRun bool
+	var continueUpdate bool
+	var verifyConvergence bool
 	var yes bool
 	var secretsProvider string
 
@@ -92,6 +123,7 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(errors.Wrap(err, "gathering environment metadata"))
 		}
+		addFeatureMetadata(m, features)
 
 		sm, err := getStackSecretsManager(s)
 		if err != nil {
@@ -102,16 +134,59 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(errors.Wrap(err, "getting stack configuration"))
 		}
+		if err = checkStackConfig(s, proj, cfg); err != nil {
+			return result.FromError(err)
+		}
 
-		opts.Engine = engine.UpdateOptions{
-			Analyzers:     analyzers,
-			Parallel:      parallel,
-			Debug:         debug,
-			Refresh:       refresh,
-			UseLegacyDiff: useLegacyDiff(),
+		slices, err := getSlices(proj, sliceNames)
+		if err != nil {
+			return result.FromError(err)
+		}
+		if err = checkSliceCreateMissing(sliceNames, sliceCreateMissing); err != nil {
+			return result.FromError(err)
+		}
+		replaceTargets, err := getReplaceTargets(targetReplaces, targetDependents)
+		if err != nil {
+			return result.FromError(err)
 		}
 
-		changes, res := s.Update(commandContext(), backend.UpdateOperation{
+		mocks, err := getMocks(useMocks, mockFixtures)
+		if err != nil {
+			return result.FromError(err)
+		}
+		if err = checkProviderDryRun(providerDryRun, mocks); err != nil {
+			return result.FromError(err)
+		}
+
+		opts.Engine = engine.UpdateOptions{
+			Analyzers:          analyzers,
+			Parallel:           parallel,
+			ProviderParallel:   providerParallel,
+			ReadParallel:       readParallel,
+			BatchSize:          batchSize,
+			Slices:             slices,
+			SliceCreateMissing: sliceCreateMissing,
+			ReplaceTargets:     replaceTargets,
+			TargetDependents:   targetDependents,
+			Debug:              debug,
+			Refresh:            refresh,
+			UseLegacyDiff:      useLegacyDiff(),
+			Features:           features,
+			CostEstimator:      costEstimator,
+			DeprecationErrors:  deprecationErrors,
+			LogResources:       opts.Display.LogResources,
+			MaxErrors:          maxErrors,
+			ResourceTimeout:    resourceTimeout,
+			ResourceTimeouts:   resourceTimeouts,
+			RetryPolicy:        resource.RetryPolicy{Attempts: retryAttempts},
+			Mocks:              mocks,
+			ProviderDryRun:     providerDryRun,
+			Continue:           continueUpdate,
+			// If the update will not be confirmed interactively, replacements must be allowed explicitly.
+			DisallowReplace: opts.AutoApprove && !allowReplace,
+		}
+
+		op := backend.UpdateOperation{
 			Proj:               proj,
 			Root:               root,
 			M:                  m,
@@ -119,7 +194,23 @@ func newUpCmd() *cobra.Command {
 			StackConfiguration: cfg,
 			SecretsManager:     sm,
 			Scopes:             cancellationScopes,
-		})
+		}
+
+		// If only a preview was requested, run the same preview that `pulumi preview` would, and stop there.
+		if previewOnly {
+			changes, res := s.Preview(commandContext(), op)
+			switch {
+			case res != nil:
+				return PrintEngineResult(res)
+			case expectNop && changes != nil && changes.HasChanges():
+				return result.FromError(errors.New("error: no changes were expected but changes were proposed"))
+			default:
+				return nil
+			}
+		}
+
+		changes, res := s.Update(commandContext(), op)
+		res = auditUpdate(s, apitype.UpdateUpdate, res)
 		switch {
 		case res != nil && res.Error() == context.Canceled:
 			return result.FromError(errors.New("update cancelled"))
@@ -127,6 +218,8 @@ func newUpCmd() *cobra.Command {
 			return PrintEngineResult(res)
 		case expectNop && changes != nil && changes.HasChanges():
 			return result.FromError(errors.New("error: no changes were expected but changes occurred"))
+		case verifyConvergence:
+			return checkConvergence(s, op)
 		default:
 			return nil
 		}
@@ -260,12 +353,51 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(errors.Wrap(err, "getting stack configuration"))
 		}
+		if err = checkStackConfig(s, proj, cfg); err != nil {
+			return result.FromError(err)
+		}
+
+		slices, err := getSlices(proj, sliceNames)
+		if err != nil {
+			return result.FromError(err)
+		}
+		if err = checkSliceCreateMissing(sliceNames, sliceCreateMissing); err != nil {
+			return result.FromError(err)
+		}
+		replaceTargets, err := getReplaceTargets(targetReplaces, targetDependents)
+		if err != nil {
+			return result.FromError(err)
+		}
+
+		mocks, err := getMocks(useMocks, mockFixtures)
+		if err != nil {
+			return result.FromError(err)
+		}
+		if err = checkProviderDryRun(providerDryRun, mocks); err != nil {
+			return result.FromError(err)
+		}
 
 		opts.Engine = engine.UpdateOptions{
-			Analyzers: analyzers,
-			Parallel:  parallel,
-			Debug:     debug,
-			Refresh:   refresh,
+			Analyzers:          analyzers,
+			Parallel:           parallel,
+			ProviderParallel:   providerParallel,
+			ReadParallel:       readParallel,
+			BatchSize:          batchSize,
+			Slices:             slices,
+			SliceCreateMissing: sliceCreateMissing,
+			ReplaceTargets:     replaceTargets,
+			TargetDependents:   targetDependents,
+			Debug:              debug,
+			Refresh:            refresh,
+			CostEstimator:      costEstimator,
+			LogResources:       opts.Display.LogResources,
+			MaxErrors:          maxErrors,
+			ResourceTimeout:    resourceTimeout,
+			ResourceTimeouts:   resourceTimeouts,
+			RetryPolicy:        resource.RetryPolicy{Attempts: retryAttempts},
+			Mocks:              mocks,
+			ProviderDryRun:     providerDryRun,
+			Continue:           continueUpdate,
 		}
 
 		// TODO for the URL case:
@@ -273,7 +405,7 @@ func newUpCmd() *cobra.Command {
 		// - attempt `destroy` on any update errors.
 		// - show template.Quickstart?
 
-		changes, res := s.Update(commandContext(), backend.UpdateOperation{
+		op := backend.UpdateOperation{
 			Proj:               proj,
 			Root:               root,
 			M:                  m,
@@ -281,7 +413,9 @@ func newUpCmd() *cobra.Command {
 			StackConfiguration: cfg,
 			SecretsManager:     sm,
 			Scopes:             cancellationScopes,
-		})
+		}
+		changes, res := s.Update(commandContext(), op)
+		res = auditUpdate(s, apitype.UpdateUpdate, res)
 		switch {
 		case res != nil && res.Error() == context.Canceled:
 			return result.FromError(errors.New("update cancelled"))
@@ -289,6 +423,8 @@ func newUpCmd() *cobra.Command {
 			return PrintEngineResult(res)
 		case expectNop && changes != nil && changes.HasChanges():
 			return result.FromError(errors.New("error: no changes were expected but changes occurred"))
+		case verifyConvergence:
+			return checkConvergence(s, op)
 		default:
 			return nil
 		}
@@ -308,34 +444,88 @@ func newUpCmd() *cobra.Command {
 			"minimally disruptive way. This command records a full transactional snapshot of the stack's new state\n" +
 			"afterwards so that the stack may be updated incrementally again later on.\n" +
 			"\n" +
+			"Use `--skip-preview` to apply the changes immediately, or `--preview-only` to stop after the preview.\n" +
+			"\n" +
+			"Use `--approval-webhook` to require an external system to approve the changes before they are applied.\n" +
+			"After the preview, a JSON summary of the plan (its ID, the stack, and the number of resources affected\n" +
+			"by each operation) is POSTed to the webhook, which must respond with `{\"approved\": true}` within the\n" +
+			"`--approval-timeout` for the update to proceed.\n" +
+			"\n" +
+			"Use `--plan-file` to check that the update still does what was reviewed: the preview is compared with\n" +
+			"a plan saved by `pulumi preview --save-diff`, and any differences between them are shown. If the two\n" +
+			"differ, the update is not performed unless `--accept-drift` is also passed.\n" +
+			"\n" +
+			"Each resource that must be replaced is reported along with the properties that caused the replacement\n" +
+			"and whether its replacement may cause downtime. If the update is approved automatically (because `--yes`\n" +
+			"was passed or the terminal is not interactive), it fails if any resources must be replaced unless\n" +
+			"`--allow-replace` is also passed.\n" +
+			"\n" +
+			"Use `--verify-convergence` to check that the program's resources have converged: after a successful\n" +
+			"update, another update is previewed, and the command fails, showing the proposed changes, if that\n" +
+			"preview is not empty.\n" +
+			"\n" +
+			"Use `--log-resource` to see the details of the operations on particular resources, such as the\n" +
+			"inputs that changed, how long each operation took, and the provider's debug messages, without\n" +
+			"the debugging output for every other resource that `--debug` shows.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory by default. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.MaximumNArgs(1),
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			if previewOnly && skipPreview {
+				return result.FromError(errors.New("--preview-only and --skip-preview cannot be used together"))
+			}
+			if previewOnly && len(args) > 0 {
+				return result.FromError(errors.New("--preview-only cannot be used with a template or URL"))
+			}
+			if previewOnly && verifyConvergence {
+				return result.FromError(errors.New("--preview-only and --verify-convergence cannot be used together"))
+			}
+			if approvalWebhook != "" && skipPreview {
+				return result.FromError(errors.New("--approval-webhook cannot be used with --skip-preview"))
+			}
+			if planFile != "" && skipPreview {
+				return result.FromError(errors.New("--plan-file cannot be used with --skip-preview"))
+			}
+			if acceptDrift && planFile == "" {
+				return result.FromError(errors.New("--accept-drift can only be used with --plan-file"))
+			}
+
 			interactive := cmdutil.Interactive()
-			if !interactive {
-				yes = true // auto-approve changes, since we cannot prompt.
+			if !interactive || previewOnly {
+				yes = true // auto-approve changes, since we cannot prompt (or there is nothing to approve).
 			}
 
 			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
 			if err != nil {
 				return result.FromError(err)
 			}
+			opts.ApprovalWebhook = approvalWebhook
+			opts.ApprovalTimeout = approvalTimeout
+			opts.PlanFile = planFile
+			opts.AcceptDrift = acceptDrift
 
 			var displayType = display.DisplayProgress
 			if diffDisplay {
 				displayType = display.DisplayDiff
 			}
 
+			logPatterns, err := resource.ParseURNPatterns(logResources)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "invalid --log-resource"))
+			}
+
 			opts.Display = display.Options{
 				Color:                cmdutil.GetGlobalColorization(),
 				ShowConfig:           showConfig,
 				ShowReplacementSteps: showReplacementSteps,
 				ShowSameResources:    showSames,
+				ShowURNs:             showURNs,
 				SuppressOutputs:      suppressOutputs,
 				IsInteractive:        interactive,
 				Type:                 displayType,
 				Debug:                debug,
+				LogResources:         logPatterns,
 			}
 
 			if len(args) > 0 {
@@ -352,12 +542,16 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&expectNop, "expect-no-changes", false,
 		"Return an error if any changes occur during this update")
+	cmd.PersistentFlags().BoolVar(
+		&verifyConvergence, "verify-convergence", false,
+		"After the update, preview another update and return an error if it would make any changes")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
-	cmd.PersistentFlags().StringVar(
-		&stackConfigFile, "config-file", "",
-		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
 	cmd.PersistentFlags().StringArrayVarP(
 		&configArray, "config", "c", []string{},
 		"Config to use during the update")
@@ -371,18 +565,95 @@ func newUpCmd() *cobra.Command {
 		"Optional message to associate with the update operation")
 
 	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVar(
+		&acceptDrift, "accept-drift", false,
+		"Perform the update even if its preview differs from the plan given by --plan-file")
+	cmd.PersistentFlags().BoolVar(
+		&allowReplace, "allow-replace", false,
+		"Allow resources to be replaced when the update is approved automatically")
 	cmd.PersistentFlags().StringSliceVar(
 		&analyzers, "analyzer", []string{},
 		"Run one or more analyzers as part of this update")
+	cmd.PersistentFlags().StringArrayVar(
+		&features, "feature", []string{},
+		"Enable the named feature flag for this update; may be specified multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&deprecationErrors, "werror", false,
+		"Fail the update if any deprecated resource types or properties are used, rather than warning")
+	cmd.PersistentFlags().StringVar(
+		&costEstimator, "cost", "",
+		"Estimate the change in cost of the previewed changes using the named cost estimator plugin")
+	cmd.PersistentFlags().StringVar(
+		&approvalWebhook, "approval-webhook", "",
+		"A URL that must approve the previewed changes before they are applied")
+	cmd.PersistentFlags().DurationVar(
+		&approvalTimeout, "approval-timeout", backend.DefaultApprovalTimeout,
+		"The amount of time to wait for the approval webhook to respond")
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringArrayVar(
+		&logResources, "log-resource", []string{},
+		"Log the operations on the resources whose URNs match this pattern in detail, as --debug does for every "+
+			"resource; '*' matches any characters and '?' a single character. May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&maxErrors, "max-errors", 0,
+		"Stop the update after N errors have been reported, suppressing any further errors (0 for no limit)")
+	cmd.PersistentFlags().BoolVar(
+		&useMocks, "mock", false,
+		"Satisfy every resource provider operation with a mock rather than the provider, so that no cloud is contacted; "+
+			"the stack's state is not changed")
+	cmd.PersistentFlags().StringVar(
+		&mockFixtures, "mock-fixtures", "",
+		"Mock resource providers, as --mock does, returning the canned outputs and function results in this JSON or "+
+			"YAML file")
+	cmd.PersistentFlags().BoolVar(
+		&providerDryRun, "provider-dry-run", false,
+		"Ask resource providers to run their logic without calling cloud APIs, so that a provider can be tested "+
+			"without changing real resources; the stack's state is not changed")
+	cmd.PersistentFlags().BoolVar(
+		&continueUpdate, "continue", false,
+		"Continue an update that was interrupted, refreshing the resources whose operations did not complete rather "+
+			"than refusing to proceed")
+	cmd.PersistentFlags().IntVar(
+		&batchSize, "batch-size", 0,
+		"Send the creations of up to N resources of the same type to their provider at once, if it supports it. "+
+			"Defaults to creating each resource individually")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().IntVar(
+		&readParallel, "parallel-read", 0,
+		"Allow N resource reads to run in parallel at once when refreshing the stack's resources. Defaults to --parallel")
+	cmd.PersistentFlags().StringArrayVar(
+		&sliceNames, "slice", []string{}, sliceFlagUsage)
+	cmd.PersistentFlags().BoolVar(
+		&sliceCreateMissing, "slice-create-missing", false, sliceCreateMissingFlagUsage)
+	cmd.PersistentFlags().StringArrayVar(
+		&targetReplaces, "target-replace", []string{}, targetReplaceFlagUsage)
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false, targetDependentsFlagUsage)
+	cmd.PersistentFlags().StringVar(
+		&planFile, "plan-file", "",
+		"Fail the update if its preview differs from the plan saved to this file by `pulumi preview --save-diff`")
+	cmd.PersistentFlags().StringArrayVar(
+		&providerParallel, "provider-parallel", []string{},
+		"Allow at most N resource operations to run in parallel against the provider for package PKG (PKG=N)")
 	cmd.PersistentFlags().BoolVarP(
 		&refresh, "refresh", "r", false,
 		"Refresh the state of the stack's resources before this update")
+	cmd.PersistentFlags().DurationVar(
+		&resourceTimeout, "resource-timeout", 0,
+		"The time limit for each resource create, update, or delete that does not declare a custom timeout "+
+			"(0 for no limit)")
+	cmd.PersistentFlags().StringArrayVar(
+		&resourceTimeouts, "resource-timeouts", []string{},
+		"Limit an operation on the resources whose URNs match a pattern, overriding their declared timeouts and "+
+			"the stack's pulumi:resourceTimeouts configuration (PATTERN:OPERATION=DURATION, e.g. '*:create=20m')")
+	cmd.PersistentFlags().IntVar(
+		&retryAttempts, "retry-attempts", 0,
+		"The number of times to attempt each resource operation that fails transiently, for resources that do not "+
+			"declare a retry policy (0 for the default of 1)")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -392,6 +663,12 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that don't need be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&showURNs, "show-urns", false,
+		"Show the full URN of each resource in place of its name, e.g. to pass it to --target-replace or 'pulumi state'")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only perform a preview of the update, as `pulumi preview` would, without applying any changes")
 	cmd.PersistentFlags().BoolVar(
 		&skipPreview, "skip-preview", false,
 		"Do not perform a preview before performing the update")
@@ -523,3 +800,27 @@ func isPreconfiguredEmptyStack(
 
 	return true
 }
+
+// checkConvergence previews an update of the given stack immediately after it has been updated, and fails if the
+// preview proposes any changes. A stack whose resources have not converged, e.g. because a provider reports values for
+// some of their properties that differ from those that the program supplies, would otherwise change on every update.
+func checkConvergence(s backend.Stack, op backend.UpdateOperation) result.Result {
+	fmt.Println(op.Opts.Display.Color.Colorize(
+		colors.SpecHeadline + "Verifying that the stack has converged:" + colors.Reset))
+	fmt.Println()
+
+	// Show the full diff of each proposed change, and nothing else, so that the properties responsible are evident.
+	op.Opts.Display.Type = display.DisplayDiff
+	op.Opts.Display.ShowSameResources = false
+	op.Opts.Engine.Refresh = false
+
+	changes, res := s.Preview(commandContext(), op)
+	if res != nil {
+		return PrintEngineResult(res)
+	}
+	if changes.HasChanges() {
+		return result.FromError(errors.New("the stack did not converge: an update immediately after this one " +
+			"would make the changes shown above"))
+	}
+	return nil
+}
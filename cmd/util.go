@@ -41,12 +41,14 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend/state"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/mock"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
 	"github.com/pulumi/pulumi/pkg/util/ciutil"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/gitutil"
 	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
 	"github.com/pulumi/pulumi/pkg/util/tracing"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -59,6 +61,27 @@ func useLegacyDiff() bool {
 	return cmdutil.IsTruthy(os.Getenv("PULUMI_ENABLE_LEGACY_DIFF"))
 }
 
+// getMocks returns the mocks that satisfy resource provider operations if --mock or --mock-fixtures was passed, or nil
+// if providers are not to be mocked.
+func getMocks(useMocks bool, fixtures string) (*mock.Mocks, error) {
+	if fixtures != "" {
+		return mock.Load(fixtures)
+	}
+	if useMocks {
+		return &mock.Mocks{}, nil
+	}
+	return nil, nil
+}
+
+// checkProviderDryRun returns an error if --provider-dry-run was passed along with mocks, which replace the providers
+// whose logic a dry run exercises.
+func checkProviderDryRun(providerDryRun bool, mocks *mock.Mocks) error {
+	if providerDryRun && mocks != nil {
+		return errors.New("--provider-dry-run may not be used with --mock or --mock-fixtures, which do not run providers")
+	}
+	return nil
+}
+
 func currentBackend(opts display.Options) (backend.Backend, error) {
 	url, err := workspace.GetCurrentCloudURL()
 	if err != nil {
@@ -74,6 +97,9 @@ func currentBackend(opts display.Options) (backend.Backend, error) {
 // This is used to control the contents of the tracing header.
 var tracingHeader = os.Getenv("PULUMI_TRACING_HEADER")
 
+// metricsSink receives the metrics of each update if --metrics-addr was passed.
+var metricsSink metrics.Sink
+
 func commandContext() context.Context {
 	ctx := context.Background()
 	if cmdutil.IsTracingEnabled() {
@@ -101,13 +127,13 @@ func createStack(
 	// while initialing a stack).  The only other supported provider today (the provider that uses the pulumi service
 	// does not need to be initialized explicitly, as creating the stack inside the Pulumi service does this).
 	if _, ok := b.(filestate.Backend); ok || secretsProvider == "passphrase" {
-		if _, pharseErr := newPassphraseSecretsManager(stackRef.Name(), stackConfigFile); pharseErr != nil {
+		if _, pharseErr := newPassphraseSecretsManager(stackRef.Name(), stackConfigPath()); pharseErr != nil {
 			return nil, pharseErr
 		}
 	} else if secretsProvider != "" && secretsProvider != "default" {
 		// All other non-default secrets providers are handled by the cloud secrets provider which
 		// uses a URL schema to identify the provider
-		if _, secretsErr := newCloudSecretsManager(stackRef.Name(), stackConfigFile, secretsProvider); secretsErr != nil {
+		if _, secretsErr := newCloudSecretsManager(stackRef.Name(), stackConfigPath(), secretsProvider); secretsErr != nil {
 			return nil, secretsErr
 		}
 	}
@@ -396,6 +422,14 @@ func getUpdateMetadata(msg, root string) (*backend.UpdateMetadata, error) {
 	return m, nil
 }
 
+// addFeatureMetadata records the feature flags that were enabled for an update in its environment metadata, so that
+// the update can be reproduced later on.
+func addFeatureMetadata(m *backend.UpdateMetadata, features []string) {
+	if len(features) > 0 {
+		m.Environment[backend.Features] = strings.Join(features, ",")
+	}
+}
+
 // addGitMetadata populate's the environment metadata bag with Git-related values.
 func addGitMetadata(repoRoot string, m *backend.UpdateMetadata) error {
 	var allErrors *multierror.Error
@@ -610,6 +644,38 @@ func (cancellationScopeSource) NewScope(events chan<- engine.Event, isPreview bo
 	return c
 }
 
+// changeWorkingDirectory makes the given directory the current working directory, so that the project, its
+// configuration, and any relative paths passed as arguments are all resolved against it. A directory that does not
+// exist is reported as such rather than as a failure to find the project within it.
+func changeWorkingDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return errors.Errorf("the directory '%s' passed to --cwd does not exist", dir)
+	} else if err != nil {
+		return errors.Wrapf(err, "could not read the directory '%s' passed to --cwd", dir)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("'%s' passed to --cwd is not a directory", dir)
+	}
+	return os.Chdir(dir)
+}
+
+// createLogDirectory creates the directory passed to --log-dir, if it does not already exist, and returns its absolute
+// path so that the same directory is used by any plugins that logging settings are flowed to.
+func createLogDirectory(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not resolve the directory '%s' passed to --log-dir", dir)
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		return "", errors.Errorf("'%s' passed to --log-dir is not a directory", dir)
+	}
+	if err = os.MkdirAll(abs, 0700); err != nil {
+		return "", errors.Wrapf(err, "could not create the directory '%s' passed to --log-dir", dir)
+	}
+	return abs, nil
+}
+
 // printJSON simply prints out some object, formatted as JSON, using standard indentation.
 func printJSON(v interface{}) error {
 	out, err := json.MarshalIndent(v, "", "  ")
@@ -631,5 +697,6 @@ func updateFlagsToOptions(interactive, skipPreview, yes bool) (backend.UpdateOpt
 	return backend.UpdateOptions{
 		AutoApprove: yes,
 		SkipPreview: skipPreview,
+		Metrics:     metricsSink,
 	}, nil
 }
@@ -14,7 +14,9 @@
 package cmd
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pulumi/pulumi/pkg/backend"
@@ -226,3 +228,64 @@ func TestReadingGitLabMetadata(t *testing.T) {
 		assertEnvValue(t, test, backend.VCSRepoKind, gitutil.GitLabHostName)
 	}
 }
+
+func TestChangeWorkingDirectory(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, os.Chdir(wd)) }()
+
+	dir, err := ioutil.TempDir("", "pulumi-cwd-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "Pulumi.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("name: test"), 0600))
+
+	err = changeWorkingDirectory(filepath.Join(dir, "missing"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+	err = changeWorkingDirectory(file)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is not a directory")
+	}
+
+	assert.NoError(t, changeWorkingDirectory(dir))
+	abs, err := filepath.Abs("Pulumi.yaml")
+	assert.NoError(t, err)
+	expected, err := filepath.EvalSymlinks(file)
+	assert.NoError(t, err)
+	actual, err := filepath.EvalSymlinks(abs)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestCreateLogDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-log-dir-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logDir := filepath.Join(dir, "logs", "pulumi")
+	abs, err := createLogDirectory(logDir)
+	assert.NoError(t, err)
+	assert.Equal(t, logDir, abs)
+	info, err := os.Stat(logDir)
+	if assert.NoError(t, err) {
+		assert.True(t, info.IsDir())
+	}
+
+	// Creating a directory that already exists is not an error.
+	_, err = createLogDirectory(logDir)
+	assert.NoError(t, err)
+
+	file := filepath.Join(dir, "file")
+	assert.NoError(t, ioutil.WriteFile(file, nil, 0600))
+	_, err = createLogDirectory(file)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is not a directory")
+	}
+	_, err = createLogDirectory(filepath.Join(file, "logs"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "could not create the directory")
+	}
+}
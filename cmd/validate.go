@@ -0,0 +1,146 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+func newValidateCmd() *cobra.Command {
+	var debug bool
+	var stack string
+
+	// Flags for engine.UpdateOptions.
+	var analyzers []string
+	var features []string
+	var parallel int
+
+	var cmd = &cobra.Command{
+		Use:        "validate",
+		SuggestFor: []string{"check", "lint"},
+		Short:      "Check that a stack's program and configuration are valid",
+		Long: "Check that a stack's program and configuration are valid.\n" +
+			"\n" +
+			"This command runs the Pulumi program in the current directory against the stack's\n" +
+			"configuration and asks each resource's provider to check the resource's inputs, reporting\n" +
+			"any diagnostics that are produced. Unlike `pulumi preview`, resources are not compared\n" +
+			"against the stack's existing state, so no changes are computed, and the stack's state is\n" +
+			"never modified. The command exits with a non-zero status if any errors are reported.\n" +
+			"\n" +
+			"This makes `pulumi validate` a fast check that is suitable for pre-commit hooks and\n" +
+			"pull request builds.\n" +
+			"\n" +
+			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
+			"`--cwd` flag to use a different directory.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			opts := backend.UpdateOptions{
+				Engine: engine.UpdateOptions{
+					Analyzers:     analyzers,
+					Parallel:      parallel,
+					Debug:         debug,
+					UseLegacyDiff: useLegacyDiff(),
+					Features:      features,
+					ValidateOnly:  true,
+				},
+				Display: display.Options{
+					Color:           cmdutil.GetGlobalColorization(),
+					SuppressOutputs: true,
+					IsInteractive:   cmdutil.Interactive(),
+					Type:            display.DisplayProgress,
+					Debug:           debug,
+				},
+			}
+
+			s, err := requireStack(stack, false, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			proj, root, err := readProject(pulumiAppProj)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			m, err := getUpdateMetadata("", root)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "gathering environment metadata"))
+			}
+			addFeatureMetadata(m, features)
+
+			sm, err := getStackSecretsManager(s)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting secrets manager"))
+			}
+
+			cfg, err := getStackConfiguration(s, sm)
+			if err != nil {
+				return result.FromError(errors.Wrap(err, "getting stack configuration"))
+			}
+			if err = checkStackConfig(s, proj, cfg); err != nil {
+				return result.FromError(err)
+			}
+
+			_, res := s.Preview(commandContext(), backend.UpdateOperation{
+				Proj:               proj,
+				Root:               root,
+				M:                  m,
+				Opts:               opts,
+				StackConfiguration: cfg,
+				SecretsManager:     sm,
+				Scopes:             cancellationScopes,
+			})
+			if res != nil {
+				return PrintEngineResult(res)
+			}
+
+			fmt.Printf("The program and configuration for stack '%s' are valid.\n", s.Ref())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringArrayVar(
+		&stackConfigFiles, "config-file", []string{},
+		"Use the configuration values in the specified file rather than detecting the file name; may be specified "+
+			"multiple times, in which case later files take precedence")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().StringSliceVar(
+		&analyzers, "analyzer", []string{},
+		"Run one or more analyzers as part of this validation")
+	cmd.PersistentFlags().StringArrayVar(
+		&features, "feature", []string{},
+		"Enable the named feature flag for this validation; may be specified multiple times")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+
+	return cmd
+}
@@ -287,6 +287,20 @@ type ResourceV3 struct {
 	Aliases []resource.URN `json:"aliases,omitempty" yaml:"aliases,omitempty"`
 	// CustomTimeouts is a configuration block that can be used to control timeouts of CRUD operations
 	CustomTimeouts *resource.CustomTimeouts `json:"customTimeouts,omitempty" yaml:"customTimeouts,omitempty"`
+	// DeleteBeforeReplace is true if the resource's registration requires it to be deleted before it is replaced.
+	DeleteBeforeReplace bool `json:"deleteBeforeReplace,omitempty" yaml:"deleteBeforeReplace,omitempty"`
+	// Priority orders the resource's operations among those that do not depend on one another; higher priorities
+	// are performed first.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// RetryPolicy controls how the resource's operations that fail transiently are retried, if it overrides the
+	// default policy.
+	RetryPolicy *resource.RetryPolicy `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
+	// History records the latest operations that changed the resource, such as its creation and replacements, oldest
+	// first.
+	History []resource.LifecycleEvent `json:"history,omitempty" yaml:"history,omitempty"`
+	// ProviderDependencies lists the dependencies that the resource's provider reported, rather than its program,
+	// which are also in Dependencies.
+	ProviderDependencies []resource.URN `json:"providerDependencies,omitempty" yaml:"providerDependencies,omitempty"`
 }
 
 // ManifestV1 captures meta-information about this checkpoint file, such as versions of binaries, etc.
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/result"
 )
@@ -85,11 +87,17 @@ func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack
 	// Note that eventsChannel is not closed in a `defer`. It is generally unsafe to do so, since defers run during
 	// panics and we can't know whether or not we were in the middle of writing to this channel when the panic occurred.
 	//
-	// Instead of using a `defer`, we manually close `eventsChannel` on every exit of this function.
+	// Instead of using a `defer`, we manually close `eventsChannel` once the preview has finished writing to it.
 	eventsChannel := make(chan engine.Event)
 
-	var events []engine.Event
+	// If the preview is to be compared with a saved plan, every event is kept, as the saved plan renders them all.
+	checkPlan := op.Opts.PlanFile != "" && kind != apitype.PreviewUpdate
+
+	var events, allEvents []engine.Event
+	collected := make(chan bool)
 	go func() {
+		defer close(collected)
+
 		// pull the events from the channel and store them locally
 		for e := range eventsChannel {
 			if e.Type == engine.ResourcePreEvent ||
@@ -98,6 +106,9 @@ func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack
 
 				events = append(events, e)
 			}
+			if checkPlan {
+				allEvents = append(allEvents, e)
+			}
 		}
 	}()
 
@@ -111,21 +122,65 @@ func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack
 	}
 
 	changes, res := apply(ctx, kind, stack, op, opts, eventsChannel)
+	close(eventsChannel)
+	<-collected
 	if res != nil {
-		close(eventsChannel)
 		return changes, res
 	}
 
+	// If the plan was reviewed ahead of time, refuse to go any further if the preview proposes a different one.
+	if checkPlan {
+		if res = checkPlanDrift(kind, allEvents, op.Opts); res != nil {
+			return changes, res
+		}
+	}
+
+	// If replacements are not allowed, refuse to go any further if the preview proposed any. The preview will already
+	// have reported each replacement, and why it is necessary.
+	if op.Opts.Engine.DisallowReplace && changes[deploy.OpReplace] > 0 && kind != apitype.PreviewUpdate {
+		return changes, result.Errorf("%d resource(s) would be replaced, but replacements are not allowed; "+
+			"pass --allow-replace to permit replacements", changes[deploy.OpReplace])
+	}
+
+	// If an approval webhook was supplied, it must approve the changes before we go any further.
+	if op.Opts.ApprovalWebhook != "" && kind != apitype.PreviewUpdate {
+		if res = requestApproval(kind, stack, changes, op.Opts.ApprovalWebhook, op.Opts.ApprovalTimeout); res != nil {
+			return changes, res
+		}
+	}
+
 	// If there are no changes, or we're auto-approving or just previewing, we can skip the confirmation prompt.
-	if op.Opts.AutoApprove || kind == apitype.PreviewUpdate {
-		close(eventsChannel)
+	if op.Opts.AutoApprove || op.Opts.PreviewOnly || kind == apitype.PreviewUpdate {
 		return changes, nil
 	}
 
 	// Otherwise, ensure the user wants to proceed.
-	res = confirmBeforeUpdating(kind, stack, events, op.Opts)
-	close(eventsChannel)
-	return changes, res
+	return changes, confirmBeforeUpdating(kind, stack, events, op.Opts)
+}
+
+// checkPlanDrift compares the plan saved in opts.PlanFile with the plan proposed by the given events of a preview,
+// and reports any differences between them. It fails if they differ, unless opts.AcceptDrift is true.
+func checkPlanDrift(kind apitype.UpdateKind, events []engine.Event, opts UpdateOptions) result.Result {
+	saved, err := ioutil.ReadFile(opts.PlanFile)
+	if err != nil {
+		return result.FromError(errors.Wrap(err, "reading saved plan"))
+	}
+
+	drift := display.RenderPlanDrift(string(saved), kind, events, opts.Display)
+	if drift == "" {
+		fmt.Printf("The preview matches the saved plan in %s\n", opts.PlanFile)
+		return nil
+	}
+
+	fmt.Print(opts.Display.Color.Colorize(
+		fmt.Sprintf("%sThe preview differs from the saved plan in %s:%s\n", colors.SpecWarning, opts.PlanFile,
+			colors.Reset)))
+	fmt.Println(drift)
+	if !opts.AcceptDrift {
+		return result.Errorf("the preview differs from the saved plan; pass --accept-drift to perform the %s anyway",
+			kind)
+	}
+	return nil
 }
 
 // confirmBeforeUpdating asks the user whether to proceed. A nil error means yes.
@@ -194,7 +249,7 @@ func PreviewThenPromptThenExecute(ctx context.Context, kind apitype.UpdateKind,
 
 	if !op.Opts.SkipPreview {
 		changes, res := PreviewThenPrompt(ctx, kind, stack, op, apply)
-		if res != nil || kind == apitype.PreviewUpdate {
+		if res != nil || op.Opts.PreviewOnly || kind == apitype.PreviewUpdate {
 			return changes, res
 		}
 	}
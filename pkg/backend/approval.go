@@ -0,0 +1,115 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+// DefaultApprovalTimeout is the amount of time to wait for an approval webhook to respond if no timeout is specified.
+const DefaultApprovalTimeout = 10 * time.Minute
+
+// ApprovalRequest is the payload that is POSTed to an approval webhook after an update has been previewed.
+type ApprovalRequest struct {
+	// PlanID uniquely identifies the previewed plan that is awaiting approval.
+	PlanID string `json:"planId"`
+	// Kind is the kind of update that is awaiting approval.
+	Kind apitype.UpdateKind `json:"kind"`
+	// Stack is the name of the stack that is being updated.
+	Stack string `json:"stack"`
+	// ResourceChanges contains the number of resources affected by each kind of operation in the plan.
+	ResourceChanges map[string]int `json:"resourceChanges"`
+}
+
+// ApprovalResponse is the payload that an approval webhook responds with.
+type ApprovalResponse struct {
+	// Approved is true if the update may proceed.
+	Approved bool `json:"approved"`
+	// Message is an optional explanation of the decision, which is displayed to the user.
+	Message string `json:"message,omitempty"`
+}
+
+// requestApproval POSTs a summary of the previewed changes to the given webhook and waits for it to approve or deny
+// the update. The update may only proceed if the webhook responds in time and approves it.
+func requestApproval(kind apitype.UpdateKind, stack Stack, changes engine.ResourceChanges,
+	url string, timeout time.Duration) result.Result {
+
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+
+	req := ApprovalRequest{
+		PlanID:          uuid.NewV4().String(),
+		Kind:            kind,
+		Stack:           stack.Ref().String(),
+		ResourceChanges: make(map[string]int),
+	}
+	for op, count := range changes {
+		req.ResourceChanges[string(op)] = count
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	fmt.Printf("Waiting for approval of plan %s from %s...\n", req.PlanID, url)
+	logging.V(7).Infof("requestApproval(%s): %s", url, body)
+
+	client := httputil.Client(timeout)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return result.FromError(errors.Wrapf(err, "requesting approval of the %s", kind))
+	}
+	defer contract.IgnoreClose(resp.Body)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return result.FromError(errors.Wrapf(err, "reading approval of the %s", kind))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result.Errorf("approval webhook responded with %s, not proceeding with the %s", resp.Status, kind)
+	}
+
+	var approval ApprovalResponse
+	if err = json.Unmarshal(respBody, &approval); err != nil {
+		return result.FromError(errors.Wrapf(err, "decoding approval of the %s", kind))
+	}
+
+	if !approval.Approved {
+		msg := fmt.Sprintf("the %s was denied by the approval webhook", kind)
+		if approval.Message != "" {
+			msg += ": " + approval.Message
+		}
+		return result.Error(msg)
+	}
+
+	fmt.Printf("The %s was approved.\n", kind)
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+type mockStackReference string
+
+func (r mockStackReference) String() string     { return string(r) }
+func (r mockStackReference) Name() tokens.QName { return tokens.QName(r) }
+
+func TestRequestApproval(t *testing.T) {
+	stack := &mockStack{
+		RefF: func() StackReference { return mockStackReference("prod") },
+	}
+	changes := engine.ResourceChanges{deploy.OpCreate: 2, deploy.OpSame: 1}
+
+	var received ApprovalRequest
+	respond := func(resp ApprovalResponse) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			assert.NoError(t, json.NewEncoder(w).Encode(resp))
+		}))
+	}
+
+	// An approved update may proceed, and the webhook receives a summary of the plan.
+	approve := respond(ApprovalResponse{Approved: true})
+	defer approve.Close()
+	res := requestApproval(apitype.UpdateUpdate, stack, changes, approve.URL, time.Minute)
+	assert.Nil(t, res)
+	assert.NotEmpty(t, received.PlanID)
+	assert.Equal(t, apitype.UpdateUpdate, received.Kind)
+	assert.Equal(t, "prod", received.Stack)
+	assert.Equal(t, map[string]int{"create": 2, "same": 1}, received.ResourceChanges)
+
+	// A denied update may not, and the webhook's message is reported.
+	deny := respond(ApprovalResponse{Approved: false, Message: "change freeze"})
+	defer deny.Close()
+	res = requestApproval(apitype.UpdateUpdate, stack, changes, deny.URL, time.Minute)
+	if assert.NotNil(t, res) {
+		assert.Contains(t, res.Error().Error(), "change freeze")
+	}
+
+	// Neither may an update whose webhook fails to respond in time.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer slow.Close()
+	res = requestApproval(apitype.UpdateUpdate, stack, changes, slow.URL, 10*time.Millisecond)
+	assert.NotNil(t, res)
+}
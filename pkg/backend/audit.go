@@ -0,0 +1,185 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// AuditDirEnvVar is the environment variable that names the directory to which a snapshot of a stack's state is
+// written after each update or destroy, if it is set.
+const AuditDirEnvVar = "PULUMI_AUDIT_DIR"
+
+// AuditSnapshot is a copy of a stack's state that is saved to the audit directory after an operation on the stack.
+// Each snapshot records the hash of the stack's previous snapshot, so that the removal or alteration of any snapshot
+// but the latest can be detected.
+type AuditSnapshot struct {
+	// Stack is the stack whose state this is.
+	Stack string `json:"stack"`
+	// Kind is the kind of the operation that left the stack in this state.
+	Kind apitype.UpdateKind `json:"kind"`
+	// Time is the time at which the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Previous is the SHA-256 hash of the file that holds the stack's previous snapshot, if there is one.
+	Previous string `json:"previous,omitempty"`
+	// Deployment is the stack's state, as `pulumi stack export` would write it.
+	Deployment json.RawMessage `json:"deployment"`
+}
+
+// AuditEntry is an audit snapshot that has been read from the audit directory.
+type AuditEntry struct {
+	Path     string        // the file that holds the snapshot.
+	Hash     string        // the SHA-256 hash of the file.
+	Intact   bool          // true if the snapshot's record of the previous snapshot matches the file that precedes it.
+	Snapshot AuditSnapshot // the snapshot itself.
+}
+
+// AuditDir returns the audit directory named by the environment, or "" if snapshots are not to be saved.
+func AuditDir() string {
+	return os.Getenv(AuditDirEnvVar)
+}
+
+// SaveAuditSnapshot saves a snapshot of the given stack's current state to the audit directory, if there is one, after
+// an operation of the given kind. Nothing is saved if the stack's state is unchanged since its last snapshot.
+func SaveAuditSnapshot(ctx context.Context, s Stack, kind apitype.UpdateKind) error {
+	dir := AuditDir()
+	if dir == "" {
+		return nil
+	}
+
+	deployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return errors.Wrap(err, "exporting the stack's state")
+	}
+	_, err = WriteAuditSnapshot(dir, s.Ref().String(), kind, time.Now(), deployment)
+	return err
+}
+
+// WriteAuditSnapshot writes a snapshot of the given stack's state to the given audit directory, and returns the path
+// of the file that holds it, or "" if the state is the same as that of the stack's latest snapshot. Snapshot files are
+// created read-only and are never overwritten.
+func WriteAuditSnapshot(dir, stack string, kind apitype.UpdateKind, now time.Time,
+	deployment *apitype.UntypedDeployment) (string, error) {
+
+	state, err := json.Marshal(deployment)
+	if err != nil {
+		return "", errors.Wrap(err, "serializing the stack's state")
+	}
+
+	entries, err := ReadAuditSnapshots(dir, stack)
+	if err != nil {
+		return "", err
+	}
+	snapshot := AuditSnapshot{Stack: stack, Kind: kind, Time: now.UTC(), Deployment: state}
+	if len(entries) > 0 {
+		// The snapshot's deployment is indented when it is written, so compact it to compare it.
+		latest := entries[len(entries)-1]
+		var compacted bytes.Buffer
+		err = json.Compact(&compacted, latest.Snapshot.Deployment)
+		if err == nil && bytes.Equal(compacted.Bytes(), state) {
+			return "", nil
+		}
+		snapshot.Previous = latest.Hash
+	}
+
+	b, err := json.MarshalIndent(snapshot, "", "    ")
+	if err != nil {
+		return "", errors.Wrap(err, "serializing the snapshot")
+	}
+
+	stackDir := auditStackDir(dir, stack)
+	if err = os.MkdirAll(stackDir, 0700); err != nil {
+		return "", errors.Wrap(err, "creating the audit directory")
+	}
+	// The file name starts with the time so that the snapshots of a stack sort in the order in which they were taken.
+	path := filepath.Join(stackDir, fmt.Sprintf("%020d-%s.json", now.UnixNano(), kind))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0400)
+	if err != nil {
+		return "", errors.Wrap(err, "creating the snapshot")
+	}
+	if _, err = f.Write(b); err != nil {
+		contract.IgnoreClose(f)
+		return "", errors.Wrapf(err, "writing %s", path)
+	}
+	if err = f.Close(); err != nil {
+		return "", errors.Wrapf(err, "writing %s", path)
+	}
+	return path, nil
+}
+
+// ReadAuditSnapshots reads the snapshots of the given stack from the given audit directory, oldest first, and checks
+// that each one's record of the previous snapshot is intact.
+func ReadAuditSnapshots(dir, stack string) ([]AuditEntry, error) {
+	stackDir := auditStackDir(dir, stack)
+	files, err := ioutil.ReadDir(stackDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading the audit directory")
+	}
+
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var entries []AuditEntry
+	for _, name := range names {
+		path := filepath.Join(stackDir, name)
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+		var snapshot AuditSnapshot
+		if err = json.Unmarshal(b, &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+
+		sum := sha256.Sum256(b)
+		entry := AuditEntry{Path: path, Hash: hex.EncodeToString(sum[:]), Snapshot: snapshot}
+		if len(entries) == 0 {
+			entry.Intact = snapshot.Previous == ""
+		} else {
+			entry.Intact = snapshot.Previous == entries[len(entries)-1].Hash
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// auditStackDir returns the directory that holds the snapshots of the given stack.
+func auditStackDir(dir, stack string) string {
+	return filepath.Join(dir, filepath.FromSlash(stack))
+}
@@ -0,0 +1,86 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+)
+
+func TestAuditSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	deployment := func(resources string) *apitype.UntypedDeployment {
+		return &apitype.UntypedDeployment{
+			Version:    3,
+			Deployment: json.RawMessage(`{"resources":` + resources + `}`),
+		}
+	}
+	now := time.Unix(1500000000, 0)
+
+	entries, err := ReadAuditSnapshots(dir, "org/dev")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first, err := WriteAuditSnapshot(dir, "org/dev", apitype.UpdateUpdate, now, deployment(`[]`))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "org", "dev", "01500000000000000000-update.json"), first)
+
+	// A snapshot of an unchanged state is not written.
+	path, err := WriteAuditSnapshot(dir, "org/dev", apitype.UpdateUpdate, now.Add(time.Minute), deployment(`[]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "", path)
+
+	second, err := WriteAuditSnapshot(dir, "org/dev", apitype.DestroyUpdate, now.Add(time.Hour), deployment(`[{}]`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", second)
+
+	// Snapshots are never overwritten.
+	_, err = WriteAuditSnapshot(dir, "org/dev", apitype.DestroyUpdate, now.Add(time.Hour), deployment(`[]`))
+	assert.Error(t, err)
+
+	entries, err = ReadAuditSnapshots(dir, "org/dev")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, first, entries[0].Path)
+		assert.True(t, entries[0].Intact)
+		assert.Equal(t, apitype.UpdateUpdate, entries[0].Snapshot.Kind)
+		assert.Equal(t, "org/dev", entries[0].Snapshot.Stack)
+		assert.True(t, now.Equal(entries[0].Snapshot.Time))
+
+		assert.Equal(t, second, entries[1].Path)
+		assert.True(t, entries[1].Intact)
+		assert.Equal(t, entries[0].Hash, entries[1].Snapshot.Previous)
+		assert.Equal(t, apitype.DestroyUpdate, entries[1].Snapshot.Kind)
+	}
+
+	// Removing the first snapshot breaks the chain.
+	assert.NoError(t, os.Remove(first))
+	entries, err = ReadAuditSnapshots(dir, "org/dev")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.False(t, entries[0].Intact)
+	}
+}
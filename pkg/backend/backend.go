@@ -33,6 +33,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/secrets"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
 	"github.com/pulumi/pulumi/pkg/util/result"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -180,6 +181,22 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
+	// PreviewOnly, when true, causes the operation to be previewed but not performed.
+	PreviewOnly bool
+	// ApprovalWebhook, if non-empty, is a URL that must approve the previewed changes before they are applied.
+	ApprovalWebhook string
+	// ApprovalTimeout is the amount of time to wait for the approval webhook to respond.
+	ApprovalTimeout time.Duration
+	// PlanFile, if non-empty, is the path of a diff saved by `pulumi preview --save-diff`. The operation is only
+	// performed if its preview proposes the same plan, unless AcceptDrift is true.
+	PlanFile string
+	// AcceptDrift, when true, performs the operation even if its preview differs from the plan in PlanFile.
+	AcceptDrift bool
+	// PreviewEvents, if non-nil, receives each event that the engine reports during a call to Preview. Events are sent
+	// synchronously, so the channel must be drained until Preview returns.
+	PreviewEvents chan<- engine.Event
+	// Metrics, if non-nil, receives the metrics of each update (see MetricsRecorder).
+	Metrics metrics.Sink
 }
 
 // CancellationScope provides a scoped source of cancellation and termination requests.
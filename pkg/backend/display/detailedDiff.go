@@ -153,3 +153,20 @@ func translateDetailedDiff(step engine.StepEventMetadata) *resource.ObjectDiff {
 
 	return diff.Object
 }
+
+// translateRefreshDiff converts the detailed diff stored in the outputs event of a refresh step into an ObjectDiff
+// that is appropriate for display. Unlike the detailed diffs of other steps, a refresh diff compares outputs with
+// outputs, so both old and new values are taken from the step's Outputs.
+func translateRefreshDiff(step engine.StepEventMetadata) *resource.ObjectDiff {
+	contract.Assert(step.DetailedDiff != nil)
+
+	var diff resource.ValueDiff
+	olds, news := resource.NewObjectProperty(step.Old.Outputs), resource.NewObjectProperty(step.New.Outputs)
+	for path, pdiff := range step.DetailedDiff {
+		elements, err := resource.ParsePropertyPath(path)
+		contract.Assert(err == nil)
+		addDiff(elements, pdiff.Kind, &diff, olds, news)
+	}
+
+	return diff.Object
+}
@@ -721,3 +721,47 @@ func TestTranslateDetailedDiff(t *testing.T) {
 		assert.Equal(t, c.expected, diff)
 	}
 }
+
+func TestTranslateRefreshDiff(t *testing.T) {
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":      42,
+		"lastRead": "t0",
+		"tags":     map[string]interface{}{"a": "1", "b": "2"},
+	})
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":      24,
+		"lastRead": "t1",
+		"tags":     map[string]interface{}{"a": "1"},
+	})
+
+	// Both old and new values come from the outputs, and properties that are not in the detailed diff are left out.
+	diff := translateRefreshDiff(engine.StepEventMetadata{
+		Old: &engine.StepEventStateMetadata{Outputs: olds},
+		New: &engine.StepEventStateMetadata{Inputs: resource.PropertyMap{}, Outputs: news},
+		DetailedDiff: map[string]plugin.PropertyDiff{
+			"foo":    {Kind: plugin.DiffUpdate},
+			"tags.b": {Kind: plugin.DiffDelete},
+		},
+	})
+	assert.Equal(t, &resource.ObjectDiff{
+		Adds:    resource.PropertyMap{},
+		Deletes: resource.PropertyMap{},
+		Sames:   resource.PropertyMap{},
+		Updates: map[resource.PropertyKey]resource.ValueDiff{
+			"foo": {
+				Old: resource.NewNumberProperty(42),
+				New: resource.NewNumberProperty(24),
+			},
+			"tags": {
+				Object: &resource.ObjectDiff{
+					Adds: resource.PropertyMap{},
+					Deletes: resource.PropertyMap{
+						"b": resource.NewStringProperty("2"),
+					},
+					Sames:   resource.PropertyMap{},
+					Updates: map[resource.PropertyKey]resource.ValueDiff{},
+				},
+			},
+		},
+	}, diff)
+}
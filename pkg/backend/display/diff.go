@@ -66,7 +66,7 @@ func ShowDiffEvents(op string, action apitype.UpdateKind,
 		case event := <-events:
 			spinner.Reset()
 
-			out := os.Stdout
+			out := opts.Stdout()
 			if event.Type == engine.DiagEvent {
 				payload := event.Payload.(engine.DiagEventPayload)
 				if payload.Severity == diag.Error || payload.Severity == diag.Warning {
@@ -123,7 +123,7 @@ func RenderDiffEvent(action apitype.UpdateKind, event engine.Event,
 }
 
 func renderDiffDiagEvent(payload engine.DiagEventPayload, opts Options) string {
-	if payload.Severity == diag.Debug && !opts.Debug {
+	if payload.Severity == diag.Debug && !opts.showsDebug(payload.URN) {
 		return ""
 	}
 	return opts.Color.Colorize(payload.Prefix + payload.Message)
@@ -208,6 +208,10 @@ func renderSummaryEvent(action apitype.UpdateKind, event engine.SummaryEventPayl
 		fprintfIgnoreError(out, "\n")
 	}
 
+	if event.CostEstimate != nil {
+		renderCostEstimate(out, event.CostEstimate, opts)
+	}
+
 	// For actual deploys, we print some additional summary information
 	if !event.IsPreview {
 		// Round up to the nearest second.  It's not useful to spit out time with 9 digits of
@@ -222,6 +226,36 @@ func renderSummaryEvent(action apitype.UpdateKind, event engine.SummaryEventPayl
 	return out.String()
 }
 
+// renderCostEstimate prints the estimated change in cost of each changed resource, followed by the total.
+func renderCostEstimate(out *bytes.Buffer, estimate *engine.CostEstimate, opts Options) {
+	fprintIgnoreError(out, opts.Color.Colorize(
+		fmt.Sprintf("\n%sEstimated cost:%s\n", colors.SpecHeadline, colors.Reset)))
+
+	formatCost := func(delta float64) string {
+		cost := fmt.Sprintf("%+.2f", delta)
+		if estimate.Currency != "" {
+			cost += " " + estimate.Currency
+		}
+		return cost
+	}
+
+	for _, res := range estimate.Resources {
+		cost := "cost unknown"
+		if res.Known {
+			cost = formatCost(res.Delta)
+		}
+		name := string(res.URN.Name())
+		if opts.ShowURNs {
+			name = string(res.URN)
+		}
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("    %s%s %s: %s%s\n",
+			res.Op.Prefix(), res.URN.Type(), name, cost, colors.Reset)))
+	}
+
+	fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("    %sTotal: %s%s\n",
+		colors.Bold, formatCost(estimate.Total), colors.Reset)))
+}
+
 func renderPreludeEvent(event engine.PreludeEventPayload, opts Options) string {
 	// Only if we have been instructed to show configuration values will we print anything during the prelude.
 	if !opts.ShowConfig {
@@ -297,6 +331,13 @@ func renderDiffResourcePreEvent(
 		return ""
 	}
 
+	if opts.Type == DisplayUnifiedDiff {
+		if !shouldShow(payload.Metadata, opts) || isRootStack(payload.Metadata) {
+			return ""
+		}
+		return renderUnifiedDiff(payload.Metadata)
+	}
+
 	out := &bytes.Buffer{}
 	if shouldShow(payload.Metadata, opts) || isRootStack(payload.Metadata) {
 		renderDiff(out, payload.Metadata, payload.Planning, payload.Debug, seen, opts)
@@ -309,6 +350,11 @@ func renderDiffResourceOutputsEvent(
 	seen map[resource.URN]engine.StepEventMetadata,
 	opts Options) string {
 
+	// Unified diffs only describe changes to resources' inputs.
+	if opts.Type == DisplayUnifiedDiff {
+		return ""
+	}
+
 	out := &bytes.Buffer{}
 	if shouldShow(payload.Metadata, opts) || isRootStack(payload.Metadata) {
 		// If this is the output step for an import, we actually want to display the diff at this point.
@@ -327,8 +373,20 @@ func renderDiffResourceOutputsEvent(
 		}
 
 		if !opts.SuppressOutputs {
-			if text := engine.GetResourceOutputsPropertiesString(
-				payload.Metadata, indent+1, payload.Planning, payload.Debug, refresh); text != "" {
+			var text string
+			if refresh && payload.Metadata.DetailedDiff != nil {
+				// Show exactly which outputs drifted, leaving out those that the provider does not compare.
+				if diff := translateRefreshDiff(payload.Metadata); diff != nil {
+					var buf bytes.Buffer
+					engine.PrintObjectDiff(
+						&buf, *diff, nil /*include*/, payload.Planning, indent+1, opts.SummaryDiff, payload.Debug)
+					text = buf.String()
+				}
+			} else {
+				text = engine.GetResourceOutputsPropertiesString(
+					payload.Metadata, indent+1, payload.Planning, payload.Debug, refresh)
+			}
+			if text != "" {
 
 				header := fmt.Sprintf("%v%v--outputs:--%v\n",
 					payload.Metadata.Op.Color(), engine.GetIndentationString(indent+1), colors.Reset)
@@ -33,6 +33,16 @@ func ShowEvents(
 	op string, action apitype.UpdateKind, stack tokens.QName, proj tokens.PackageName,
 	events <-chan engine.Event, done chan<- bool, opts Options, isPreview bool) {
 
+	if opts.SaveDiffPath != "" {
+		showEventsAndSaveDiff(op, action, stack, proj, events, done, opts, isPreview)
+		return
+	}
+
+	if opts.JSONDisplay && opts.JSONPath != "" {
+		showEventsAndJSON(op, action, stack, proj, events, done, opts, isPreview)
+		return
+	}
+
 	if opts.JSONDisplay {
 		// TODO[pulumi/pulumi#2390]: enable JSON display for real deployments.
 		contract.Assertf(isPreview, "JSON display only available in preview mode")
@@ -41,7 +51,7 @@ func ShowEvents(
 	}
 
 	switch opts.Type {
-	case DisplayDiff:
+	case DisplayDiff, DisplayUnifiedDiff:
 		ShowDiffEvents(op, action, events, done, opts)
 	case DisplayProgress:
 		ShowProgressEvents(op, action, stack, proj, events, done, opts, isPreview)
@@ -84,7 +94,8 @@ func shouldShow(step engine.StepEventMetadata, opts Options) bool {
 
 	// For logical replacement operations, only show them during progress-style updates (since this is integrated
 	// into the resource status update), or if it is requested explicitly (for diffs and JSON outputs).
-	if (opts.Type == DisplayDiff || opts.JSONDisplay) && !step.Logical && !opts.ShowReplacementSteps {
+	isDiff := opts.Type == DisplayDiff || opts.Type == DisplayUnifiedDiff
+	if (isDiff || opts.JSONDisplay) && !step.Logical && !opts.ShowReplacementSteps {
 		return false
 	}
 
@@ -17,8 +17,12 @@ package display
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
@@ -27,6 +31,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 )
@@ -83,9 +88,9 @@ func stateForJSONOutput(s *resource.State, opts Options) *resource.State {
 		outputs = resource.PropertyMap{}
 	}
 
-	return resource.NewState(s.Type, s.URN, s.Custom, s.Delete, s.ID, inputs,
-		outputs, s.Parent, s.Protect, s.External, s.Dependencies, s.InitErrors, s.Provider,
-		s.PropertyDependencies, s.PendingReplacement, s.AdditionalSecretOutputs, s.Aliases, &s.CustomTimeouts)
+	state := s.Copy()
+	state.Inputs, state.Outputs = inputs, outputs
+	return state
 }
 
 // ShowJSONEvents renders engine events from a preview into a well-formed JSON document. Note that this does not
@@ -98,6 +103,7 @@ func ShowJSONEvents(op string, action apitype.UpdateKind, events <-chan engine.E
 
 	// Now loop and accumulate our digest until the event stream is closed, or we hit a cancellation.
 	var digest previewDigest
+	refreshes := make(map[resource.URN]bool)
 	for e := range events {
 		// In the event of cancelation, break out of the loop immediately.
 		if e.Type == engine.CancelEvent {
@@ -132,52 +138,28 @@ func ShowJSONEvents(op string, action apitype.UpdateKind, events <-chan engine.E
 		case engine.ResourcePreEvent:
 			// Create the detailed metadata for this step and the initial state of its resource. Later,
 			// if new outputs arrive, we'll search for and swap in those new values.
-			if m := e.Payload.(engine.ResourcePreEventPayload).Metadata; shouldShow(m, opts) || isRootStack(m) {
-				var detailedDiff map[string]propertyDiff
-				if m.DetailedDiff != nil {
-					detailedDiff = make(map[string]propertyDiff)
-					for k, v := range m.DetailedDiff {
-						detailedDiff[k] = propertyDiff{
-							Kind:      v.Kind.String(),
-							InputDiff: v.InputDiff,
-						}
-					}
-				}
-
-				step := &previewStep{
-					Op:             m.Op,
-					URN:            m.URN,
-					Provider:       m.Provider,
-					DiffReasons:    m.Diffs,
-					ReplaceReasons: m.Keys,
-					DetailedDiff:   detailedDiff,
-				}
-
-				if m.Old != nil {
-					oldState := stateForJSONOutput(m.Old.State, opts)
-					res, err := stack.SerializeResource(oldState, config.NewPanicCrypter())
-					if err == nil {
-						step.OldState = &res
-					} else {
-						logging.V(7).Infof("not adding old state as there was an error serialzing: %s", err)
-					}
-				}
-				if m.New != nil {
-					newState := stateForJSONOutput(m.New.State, opts)
-					res, err := stack.SerializeResource(newState, config.NewPanicCrypter())
-					if err == nil {
-						step.NewState = &res
-					} else {
-						logging.V(7).Infof("not adding new state as there was an error serialzing: %s", err)
-					}
+			m := e.Payload.(engine.ResourcePreEventPayload).Metadata
+			if m.Op == deploy.OpRefresh {
+				// Refreshes only know whether, and how, a resource drifted once it has been read.
+				refreshes[m.URN] = true
+			} else if shouldShow(m, opts) || isRootStack(m) {
+				digest.Steps = append(digest.Steps, newPreviewStep(m, opts))
+			}
+		case engine.ResourceOutputsEvent:
+			// Record the result of each refresh, including the precise set of properties that drifted.
+			m := e.Payload.(engine.ResourceOutputsEventPayload).Metadata
+			if refreshes[m.URN] && (m.Op != deploy.OpSame || opts.ShowSameResources) {
+				step := newPreviewStep(m, opts)
+				for path, diff := range step.DetailedDiff {
+					diff.Old, diff.New = driftedValues(m, path)
+					step.DetailedDiff[path] = diff
 				}
-
 				digest.Steps = append(digest.Steps, step)
 			}
-		case engine.ResourceOutputsEvent, engine.ResourceOperationFailed:
-			// Because we are only JSON serializing previews, we don't need to worry about outputs
-			// resolving or operations failing. In the future, if we serialize actual deployments, we will
-			// need to come up with a scheme for matching the failure to the associated step.
+		case engine.ResourceOperationFailed:
+			// Because we are only JSON serializing previews, we don't need to worry about operations failing. In the
+			// future, if we serialize actual deployments, we will need to come up with a scheme for matching the
+			// failure to the associated step.
 
 		// Events ocurring late:
 		case engine.SummaryEvent:
@@ -191,10 +173,116 @@ func ShowJSONEvents(op string, action apitype.UpdateKind, events <-chan engine.E
 		}
 	}
 
-	// Finally, go ahead and render the JSON to stdout.
+	// Finally, go ahead and render the JSON to stdout, or to the file that the options name.
 	out, err := json.MarshalIndent(&digest, "", "    ")
 	contract.Assertf(err == nil, "unexpected JSON error: %v", err)
-	fmt.Println(string(out))
+	if opts.JSONPath == "" || opts.JSONPath == JSONToStdout {
+		fmt.Println(string(out))
+	} else if err = ioutil.WriteFile(opts.JSONPath, append(out, '\n'), 0600); err != nil {
+		// The caller reports the error if it asked for it, so that it can fail the command.
+		err = errors.Wrapf(err, "could not write JSON to %s", opts.JSONPath)
+		if opts.JSONError != nil {
+			*opts.JSONError = err
+		} else {
+			fprintIgnoreError(os.Stderr, opts.Color.Colorize(
+				fmt.Sprintf("%serror:%s %v\n", colors.SpecError, colors.Reset, err)))
+		}
+	}
+}
+
+// showEventsAndJSON displays events in the human-readable form that the options select and also renders them as
+// JSON, which is written to the file named by opts.JSONPath, or to stdout if that is JSONToStdout. Once both are
+// finished, it closes the `done` channel.
+func showEventsAndJSON(
+	op string, action apitype.UpdateKind, stack tokens.QName, proj tokens.PackageName,
+	events <-chan engine.Event, done chan<- bool, opts Options, isPreview bool) {
+
+	displayEvents := make(chan engine.Event)
+	displayDone := make(chan bool)
+
+	jsonEvents := make(chan engine.Event)
+	jsonDone := make(chan bool)
+
+	defer func() {
+		<-displayDone
+		<-jsonDone
+		close(done)
+	}()
+
+	displayOpts := opts
+	displayOpts.JSONDisplay = false
+	go ShowEvents(op, action, stack, proj, displayEvents, displayDone, displayOpts, isPreview)
+	go ShowJSONEvents(op, action, jsonEvents, jsonDone, opts)
+
+	for e := range events {
+		displayEvents <- e
+		jsonEvents <- e
+
+		// Both listeners stop reading once they see the CancelEvent, so we must stop sending events to them as well.
+		if e.Type == engine.CancelEvent {
+			break
+		}
+	}
+}
+
+// newPreviewStep creates the JSON-serializable overview of the step described by the given metadata.
+func newPreviewStep(m engine.StepEventMetadata, opts Options) *previewStep {
+	var detailedDiff map[string]propertyDiff
+	if m.DetailedDiff != nil {
+		detailedDiff = make(map[string]propertyDiff)
+		for k, v := range m.DetailedDiff {
+			detailedDiff[k] = propertyDiff{
+				Kind:      v.Kind.String(),
+				InputDiff: v.InputDiff,
+			}
+		}
+	}
+
+	step := &previewStep{
+		Op:             m.Op,
+		URN:            m.URN,
+		Provider:       m.Provider,
+		DiffReasons:    m.Diffs,
+		ReplaceReasons: m.Keys,
+		DetailedDiff:   detailedDiff,
+	}
+
+	if m.Old != nil {
+		oldState := stateForJSONOutput(m.Old.State, opts)
+		res, err := stack.SerializeResource(oldState, config.NewPanicCrypter())
+		if err == nil {
+			step.OldState = &res
+		} else {
+			logging.V(7).Infof("not adding old state as there was an error serialzing: %s", err)
+		}
+	}
+	if m.New != nil {
+		newState := stateForJSONOutput(m.New.State, opts)
+		res, err := stack.SerializeResource(newState, config.NewPanicCrypter())
+		if err == nil {
+			step.NewState = &res
+		} else {
+			logging.V(7).Infof("not adding new state as there was an error serialzing: %s", err)
+		}
+	}
+
+	return step
+}
+
+// driftedValues returns the old and new values of the output property at the given path of a refreshed resource. A
+// value is nil if the property does not exist. Secret values are never revealed.
+func driftedValues(m engine.StepEventMetadata, path string) (interface{}, interface{}) {
+	elements, err := resource.ParsePropertyPath(path)
+	contract.Assert(err == nil)
+
+	var old, new interface{}
+	if v, ok := elements.Get(resource.NewObjectProperty(m.Old.Outputs)); ok {
+		old = v.Plain(false /*showSecrets*/)
+	}
+	if v, ok := elements.Get(resource.NewObjectProperty(m.New.Outputs)); ok {
+		new = v.Plain(false /*showSecrets*/)
+	}
+	return old, new
 }
 
 // previewDigest is a JSON-serializable overview of a preview operation.
@@ -222,6 +310,10 @@ type propertyDiff struct {
 	Kind string `json:"kind"`
 	// InputDiff is true if this is a difference between old and new inputs instead of old state and new inputs.
 	InputDiff bool `json:"inputDiff"`
+	// Old is the old value of the property, if this is a difference found by a refresh and the property existed.
+	Old interface{} `json:"old,omitempty"`
+	// New is the new value of the property, if this is a difference found by a refresh and the property exists.
+	New interface{} `json:"new,omitempty"`
 }
 
 // previewStep is a detailed overview of a step the engine intends to take.
@@ -0,0 +1,113 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestShowJSONEventsToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-json")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "preview.json")
+
+	events := make(chan engine.Event)
+	done := make(chan bool)
+	go ShowJSONEvents("preview", apitype.PreviewUpdate, events, done,
+		Options{Color: colors.Never, JSONDisplay: true, JSONPath: path})
+
+	events <- engine.Event{Type: engine.StdoutColorEvent, Payload: engine.StdoutEventPayload{
+		Message: colors.SpecHeadline + "hello" + colors.Reset,
+		Color:   colors.Always,
+	}}
+	close(events)
+	<-done
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	var digest previewDigest
+	assert.NoError(t, json.Unmarshal(b, &digest))
+	if assert.Len(t, digest.Diagnostics, 1) {
+		assert.Equal(t, "hello", digest.Diagnostics[0].Message)
+	}
+}
+
+func TestShowJSONEventsToFileError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-json")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "missing", "preview.json")
+
+	var jsonErr error
+	events := make(chan engine.Event)
+	done := make(chan bool)
+	go ShowJSONEvents("preview", apitype.PreviewUpdate, events, done,
+		Options{Color: colors.Never, JSONDisplay: true, JSONPath: path, JSONError: &jsonErr})
+	close(events)
+	<-done
+
+	if assert.Error(t, jsonErr) {
+		assert.Contains(t, jsonErr.Error(), "could not write JSON to "+path)
+	}
+}
+
+func TestOptionsStdout(t *testing.T) {
+	assert.True(t, Options{}.ShowsHumanReadable())
+	assert.Equal(t, os.Stdout, Options{}.Stdout())
+
+	assert.False(t, Options{JSONDisplay: true}.ShowsHumanReadable())
+	assert.Equal(t, os.Stdout, Options{JSONDisplay: true}.Stdout())
+
+	toFile := Options{JSONDisplay: true, JSONPath: "preview.json"}
+	assert.True(t, toFile.ShowsHumanReadable())
+	assert.Equal(t, os.Stdout, toFile.Stdout())
+
+	toStdout := Options{JSONDisplay: true, JSONPath: JSONToStdout}
+	assert.True(t, toStdout.ShowsHumanReadable())
+	assert.Equal(t, os.Stderr, toStdout.Stdout())
+}
+
+func TestOptionsShowsDebug(t *testing.T) {
+	logs := resource.URN("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs")
+	web := resource.URN("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::web")
+	patterns, err := resource.ParseURNPatterns([]string{"*::logs"})
+	assert.NoError(t, err)
+
+	assert.False(t, Options{}.showsDebug(logs))
+	assert.True(t, Options{Debug: true}.showsDebug(web))
+	assert.True(t, Options{LogResources: patterns}.showsDebug(logs))
+	assert.False(t, Options{LogResources: patterns}.showsDebug(web))
+	assert.False(t, Options{LogResources: patterns}.showsDebug(""))
+
+	opts := Options{Color: colors.Never, LogResources: patterns}
+	debug := func(urn resource.URN) engine.DiagEventPayload {
+		return engine.DiagEventPayload{URN: urn, Message: "applying create step", Severity: diag.Debug}
+	}
+	assert.Equal(t, "applying create step", renderDiffDiagEvent(debug(logs), opts))
+	assert.Equal(t, "", renderDiffDiagEvent(debug(web), opts))
+}
@@ -14,7 +14,12 @@
 
 package display
 
-import "github.com/pulumi/pulumi/pkg/diag/colors"
+import (
+	"os"
+
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
 
 // Type of output to display.
 type Type int
@@ -26,18 +31,48 @@ const (
 	DisplayDiff
 	// DisplayQuery displays query output.
 	DisplayQuery
+	// DisplayUnifiedDiff displays the changes to each resource's inputs as a unified diff.
+	DisplayUnifiedDiff
 )
 
 // Options controls how the output of events are rendered
 type Options struct {
-	Color                colors.Colorization // colorization to apply to events.
-	ShowConfig           bool                // true if we should show configuration information.
-	ShowReplacementSteps bool                // true to show the replacement steps in the plan.
-	ShowSameResources    bool                // true to show the resources that aren't updated in addition to updates.
-	SuppressOutputs      bool                // true to suppress output summarization, e.g. if contains sensitive info.
-	SummaryDiff          bool                // true if diff display should be summarized.
-	IsInteractive        bool                // true if we should display things interactively.
-	Type                 Type                // type of display (rich diff, progress, or query).
-	JSONDisplay          bool                // true if we should emit the entire diff as JSON.
-	Debug                bool                // true to enable debug output.
+	Color                colors.Colorization   // colorization to apply to events.
+	ShowConfig           bool                  // true if we should show configuration information.
+	ShowReplacementSteps bool                  // true to show the replacement steps in the plan.
+	ShowSameResources    bool                  // true to show the resources that aren't updated in addition to updates.
+	ShowURNs             bool                  // true to show each resource's full URN in place of its name.
+	SuppressOutputs      bool                  // true to suppress output summarization, e.g. if contains sensitive info.
+	SummaryDiff          bool                  // true if diff display should be summarized.
+	IsInteractive        bool                  // true if we should display things interactively.
+	Type                 Type                  // type of display (rich diff, unified diff, progress, or query).
+	JSONDisplay          bool                  // true if we should emit the entire diff as JSON.
+	JSONPath             string                // if non-empty, where to write the JSON alongside the usual display.
+	JSONError            *error                // if non-nil, receives any error writing the JSON to JSONPath.
+	Debug                bool                  // true to enable debug output.
+	LogResources         []resource.URNPattern // the resources whose debug output to show without Debug.
+	SaveDiffPath         string                // if non-empty, the path of a file to save an uncolored diff to.
+}
+
+// JSONToStdout is the JSONPath that writes the JSON display to stdout, and the usual display to stderr instead.
+const JSONToStdout = "-"
+
+// ShowsHumanReadable returns true if the options display events in a human-readable form, possibly alongside JSON.
+func (opts Options) ShowsHumanReadable() bool {
+	return !opts.JSONDisplay || opts.JSONPath != ""
+}
+
+// Stdout returns the stream to which the human-readable display is written: stderr if the JSON display is written
+// to stdout, and stdout otherwise.
+func (opts Options) Stdout() *os.File {
+	if opts.JSONPath == JSONToStdout {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// showsDebug returns true if the debug output about the resource with the given URN should be displayed, either
+// because debug output is enabled, or because the resource's operations are to be logged.
+func (opts Options) showsDebug(urn resource.URN) bool {
+	return opts.Debug || (urn != "" && resource.MatchesAnyURNPattern(opts.LogResources, urn))
 }
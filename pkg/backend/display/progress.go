@@ -267,7 +267,7 @@ func ShowProgressEvents(op string, action apitype.UpdateKind, stack tokens.QName
 		nonInteractiveSpinner:  spinner,
 	}
 
-	terminalWidth, terminalHeight, err := terminal.GetSize(int(os.Stdout.Fd()))
+	terminalWidth, terminalHeight, err := terminal.GetSize(int(opts.Stdout().Fd()))
 	contract.IgnoreError(err)
 	display.isTerminal = opts.IsInteractive
 	display.terminalWidth = terminalWidth
@@ -282,7 +282,10 @@ func ShowProgressEvents(op string, action apitype.UpdateKind, stack tokens.QName
 		close(progressOutput)
 	}()
 
-	_, stdout, _ := term.StdStreams()
+	_, stdout, stderr := term.StdStreams()
+	if opts.Stdout() == os.Stderr {
+		stdout = stderr
+	}
 	ShowProgressOutput(progressOutput, stdout, display.isTerminal)
 
 	ticker.Stop()
@@ -391,7 +394,7 @@ func (display *ProgressDisplay) refreshColumns(
 func (display *ProgressDisplay) updateTerminalDimensions() {
 	// don't do any refreshing if we're not in a terminal
 	if display.isTerminal {
-		currentTerminalWidth, currentTerminalHeight, err := terminal.GetSize(int(os.Stdout.Fd()))
+		currentTerminalWidth, currentTerminalHeight, err := terminal.GetSize(int(display.opts.Stdout().Fd()))
 		contract.IgnoreError(err)
 
 		if currentTerminalWidth != display.terminalWidth ||
@@ -1042,7 +1045,7 @@ func (display *ProgressDisplay) processEvents(ticker *time.Ticker, events <-chan
 }
 
 func (display *ProgressDisplay) renderProgressDiagEvent(payload engine.DiagEventPayload, includePrefix bool) string {
-	if payload.Severity == diag.Debug && !display.opts.Debug {
+	if payload.Severity == diag.Debug && !display.opts.showsDebug(payload.URN) {
 		return ""
 	}
 
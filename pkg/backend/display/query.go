@@ -101,7 +101,7 @@ func renderQueryEvent(event engine.Event, opts Options) string {
 
 func renderQueryDiagEvent(payload engine.DiagEventPayload, opts Options) string {
 	// Ignore debug messages unless we're in debug mode.
-	if payload.Severity == diag.Debug && !opts.Debug {
+	if payload.Severity == diag.Debug && !opts.showsDebug(payload.URN) {
 		return ""
 	}
 
@@ -28,6 +28,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
@@ -100,7 +101,11 @@ func (data *headerRowData) ColorizedColumns() []string {
 		} else {
 			statusColumn = header("Status")
 		}
-		data.columns = []string{"", header("Type"), header("Name"), statusColumn, header("Info")}
+		nameHeader := header("Name")
+		if data.display.opts.ShowURNs {
+			nameHeader = header("URN")
+		}
+		data.columns = []string{"", header("Type"), nameHeader, statusColumn, header("Info")}
 	}
 
 	return data.columns
@@ -317,6 +322,9 @@ func (data *resourceRowData) ColorizedColumns() []string {
 		urn = resource.DefaultRootStackURN(data.display.stack, data.display.proj)
 	}
 	name := string(urn.Name())
+	if data.display.opts.ShowURNs {
+		name = string(urn)
+	}
 	typ := simplifyTypeName(urn.Type())
 
 	columns := make([]string, 5)
@@ -337,6 +345,28 @@ func (data *resourceRowData) ColorizedColumns() []string {
 	return columns
 }
 
+// explicitProviderName returns the name of the provider instance that the given step uses, or the empty string if the
+// step uses its package's default provider or no provider at all.
+func explicitProviderName(step engine.StepEventMetadata) string {
+	if step.Provider == "" {
+		return ""
+	}
+	ref, err := providers.ParseReference(step.Provider)
+	if err != nil || providers.IsDefaultProvider(ref.URN()) {
+		return ""
+	}
+	return string(ref.URN().Name())
+}
+
+// isProtected returns true if the resource that the given step operates on is protected once the step is done, or, if
+// the step deletes the resource, was protected before it.
+func isProtected(step engine.StepEventMetadata) bool {
+	if step.New != nil {
+		return step.New.Protect
+	}
+	return step.Old != nil && step.Old.Protect
+}
+
 func (data *resourceRowData) getInfoColumn() string {
 	step := data.step
 	switch step.Op {
@@ -372,6 +402,15 @@ func (data *resourceRowData) getInfoColumn() string {
 		appendDiagMessage("[" + changes + "]")
 	}
 
+	// Name the provider instance that manages the resource, unless it is its package's default provider.
+	if name := explicitProviderName(step); name != "" {
+		appendDiagMessage("provider: " + name)
+	}
+
+	if isProtected(step) {
+		appendDiagMessage("protected")
+	}
+
 	diagInfo := data.diagInfo
 	if data.display.done {
 		// If we are done, show a summary of how many messages were printed.
@@ -426,14 +465,16 @@ func (data *resourceRowData) getDiffInfo(step engine.StepEventMetadata) string {
 	changesBuf := &bytes.Buffer{}
 	if step.Old != nil && step.New != nil {
 		var diff *resource.ObjectDiff
-		if step.DetailedDiff != nil {
+		if step.DetailedDiff != nil && data.diffOutputs {
+			diff = translateRefreshDiff(step)
+		} else if step.DetailedDiff != nil {
 			diff = translateDetailedDiff(step)
 		} else if data.diffOutputs {
 			if step.Old.Outputs != nil && step.New.Outputs != nil {
-				diff = step.Old.Outputs.Diff(step.New.Outputs)
+				diff = step.Old.Outputs.DiffWithArrayKeys(step.New.Outputs, step.ArrayKeys)
 			}
 		} else if step.Old.Inputs != nil && step.New.Inputs != nil {
-			diff = step.Old.Inputs.Diff(step.New.Inputs)
+			diff = step.Old.Inputs.DiffWithArrayKeys(step.New.Inputs, step.ArrayKeys)
 		}
 
 		// Show a diff if either `provider` or `protect` changed; they might not show a diff via inputs or outputs, but
@@ -0,0 +1,68 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestExplicitProviderName(t *testing.T) {
+	step := func(provider string) engine.StepEventMetadata {
+		return engine.StepEventMetadata{Provider: provider}
+	}
+
+	assert.Equal(t, "", explicitProviderName(step("")))
+	assert.Equal(t, "", explicitProviderName(step(
+		"urn:pulumi:stack::proj::pulumi:providers:aws::default::id")))
+	assert.Equal(t, "", explicitProviderName(step(
+		"urn:pulumi:stack::proj::pulumi:providers:aws::default_1_2_3::id")))
+	assert.Equal(t, "us-west", explicitProviderName(step(
+		"urn:pulumi:stack::proj::pulumi:providers:aws::us-west::id")))
+}
+
+func TestIsProtected(t *testing.T) {
+	protected := &engine.StepEventStateMetadata{Protect: true}
+	unprotected := &engine.StepEventStateMetadata{}
+
+	assert.False(t, isProtected(engine.StepEventMetadata{}))
+	assert.True(t, isProtected(engine.StepEventMetadata{New: protected}))
+	assert.True(t, isProtected(engine.StepEventMetadata{Old: unprotected, New: protected}))
+	assert.False(t, isProtected(engine.StepEventMetadata{Old: protected, New: unprotected}))
+	assert.True(t, isProtected(engine.StepEventMetadata{Old: protected}))
+}
+
+func TestResourceRowShowURNs(t *testing.T) {
+	urn := resource.URN("urn:pulumi:stack::proj::aws:s3/bucket:Bucket::logs")
+	columns := func(showURNs bool) []string {
+		display := &ProgressDisplay{opts: Options{ShowURNs: showURNs}, isPreview: true}
+		row := &resourceRowData{
+			display:  display,
+			step:     engine.StepEventMetadata{Op: deploy.OpCreate, URN: urn},
+			diagInfo: &DiagInfo{},
+		}
+		header := &headerRowData{display: display}
+		return []string{header.ColorizedColumns()[nameColumn], row.ColorizedColumns()[nameColumn]}
+	}
+
+	assert.Equal(t, []string{colors.Underline + colors.BrightBlue + "Name" + colors.Reset, "logs"}, columns(false))
+	assert.Equal(t, []string{colors.Underline + colors.BrightBlue + "URN" + colors.Reset, string(urn)}, columns(true))
+}
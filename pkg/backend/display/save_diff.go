@@ -0,0 +1,165 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// showEventsAndSaveDiff displays events as ShowEvents does and also saves their rendering as a diff to the file named
+// by opts.SaveDiffPath. Once both the display and the file are finished, it closes the `done` channel.
+func showEventsAndSaveDiff(
+	op string, action apitype.UpdateKind, stack tokens.QName, proj tokens.PackageName,
+	events <-chan engine.Event, done chan<- bool, opts Options, isPreview bool) {
+
+	displayEvents := make(chan engine.Event)
+	displayDone := make(chan bool)
+
+	saveEvents := make(chan engine.Event)
+	saveDone := make(chan bool)
+
+	defer func() {
+		<-displayDone
+		<-saveDone
+		close(done)
+	}()
+
+	displayOpts := opts
+	displayOpts.SaveDiffPath = ""
+	go ShowEvents(op, action, stack, proj, displayEvents, displayDone, displayOpts, isPreview)
+	go saveDiffEvents(opts.SaveDiffPath, action, stack, saveEvents, saveDone, opts)
+
+	for e := range events {
+		displayEvents <- e
+		saveEvents <- e
+
+		// Both listeners stop reading once they see the CancelEvent, so we must stop sending events to them as well.
+		if e.Type == engine.CancelEvent {
+			break
+		}
+	}
+}
+
+// saveDiffEvents reads events from the `events` channel until it sees a CancelEvent, renders each event as the diff
+// display would but without colors, and writes the result to the file at path. The rendering is preceded by a header
+// that names the stack and the time at which the operation started. Once the file has been written, it closes the
+// `done` channel.
+func saveDiffEvents(path string, action apitype.UpdateKind, stack tokens.QName,
+	events <-chan engine.Event, done chan<- bool, opts Options) {
+
+	defer close(done)
+
+	color := opts.Color
+	opts = savedDiffOptions(opts)
+
+	out := &bytes.Buffer{}
+	fprintIgnoreError(out, renderSaveDiffHeader(stack, time.Now(), opts.Type))
+
+	seen := make(map[resource.URN]engine.StepEventMetadata)
+	for event := range events {
+		fprintIgnoreError(out, RenderDiffEvent(action, event, seen, opts))
+		if event.Type == engine.CancelEvent {
+			break
+		}
+	}
+
+	if err := ioutil.WriteFile(path, out.Bytes(), 0600); err != nil {
+		fprintIgnoreError(os.Stderr, color.Colorize(
+			fmt.Sprintf("%swarning:%s could not save diff to %s: %v\n", colors.SpecWarning, colors.Reset, path, err)))
+	}
+}
+
+// savedDiffOptions returns the options with which a saved diff is rendered: without colors, and in the diff format
+// that the given options select.
+func savedDiffOptions(opts Options) Options {
+	opts.Color = colors.Never
+	if opts.Type != DisplayUnifiedDiff {
+		opts.Type = DisplayDiff
+	}
+	return opts
+}
+
+// savedDiffUnifiedFormat is the line with which the header of a saved diff records that it is a unified diff.
+const savedDiffUnifiedFormat = "Format: unified"
+
+// renderSaveDiffHeader renders the header of a saved diff, which is separated from the diff by a blank line.
+func renderSaveDiffHeader(stack tokens.QName, t time.Time, displayType Type) string {
+	header := fmt.Sprintf("Stack: %s\nDate: %s\n", stack, t.Format(time.RFC3339))
+	if displayType == DisplayUnifiedDiff {
+		header += savedDiffUnifiedFormat + "\n"
+	}
+	return header + "\n"
+}
+
+// RenderPlanDrift compares the plan in the given diff, saved by `pulumi preview --save-diff`, with the plan proposed by
+// the given events of a preview, and renders the differences between the two as a unified diff of their renderings.
+// It returns the empty string if the plans are the same.
+func RenderPlanDrift(saved string, action apitype.UpdateKind, events []engine.Event, opts Options) string {
+	savedPlan, displayType, ok := savedDiffPlan(saved)
+	if ok {
+		opts.Type = displayType
+	}
+	opts = savedDiffOptions(opts)
+
+	current := &bytes.Buffer{}
+	seen := make(map[resource.URN]engine.StepEventMetadata)
+	for _, event := range events {
+		fprintIgnoreError(current, RenderDiffEvent(action, event, seen, opts))
+	}
+
+	hunks := unifiedDiffHunks(savedPlan, current.String(), unifiedDiffContext)
+	if hunks == "" {
+		return ""
+	}
+	return "--- saved plan\n+++ current plan\n" + hunks
+}
+
+// RenderPlanComparison compares two plans saved by `pulumi preview --save-diff`, the base plan and the current plan,
+// and renders the differences between the two as a unified diff of their renderings, whose sides are labelled with the
+// given names. It returns the empty string if the plans are the same.
+func RenderPlanComparison(base, current string, baseName, currentName string) string {
+	basePlan, _, _ := savedDiffPlan(base)
+	currentPlan, _, _ := savedDiffPlan(current)
+	hunks := unifiedDiffHunks(basePlan, currentPlan, unifiedDiffContext)
+	if hunks == "" {
+		return ""
+	}
+	return fmt.Sprintf("--- %s\n+++ %s\n", baseName, currentName) + hunks
+}
+
+// savedDiffPlan returns the plan in the given diff, saved by `pulumi preview --save-diff`, without the header that
+// records when it was saved, and the display type with which it was rendered. It returns false if the diff has no
+// header, in which case the whole diff is the plan.
+func savedDiffPlan(saved string) (string, Type, bool) {
+	i := strings.Index(saved, "\n\n")
+	if i == -1 {
+		return saved, DisplayDiff, false
+	}
+	if strings.Contains(saved[:i], savedDiffUnifiedFormat) {
+		return saved[i+2:], DisplayUnifiedDiff, true
+	}
+	return saved[i+2:], DisplayDiff, true
+}
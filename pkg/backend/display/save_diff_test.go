@@ -0,0 +1,91 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+)
+
+func TestSaveDiffEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-save-diff")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "preview.txt")
+
+	events := make(chan engine.Event)
+	done := make(chan bool)
+	go saveDiffEvents(path, apitype.PreviewUpdate, "dev", events, done, Options{Color: colors.Always})
+
+	events <- engine.Event{Type: engine.StdoutColorEvent, Payload: engine.StdoutEventPayload{
+		Message: colors.SpecHeadline + "hello" + colors.Reset + "\n",
+		Color:   colors.Always,
+	}}
+	events <- engine.Event{Type: engine.CancelEvent}
+	<-done
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	contents := string(b)
+
+	assert.True(t, strings.HasPrefix(contents, "Stack: dev\nDate: "), contents)
+	assert.Contains(t, contents, "\nhello\n")
+	assert.NotContains(t, contents, "\x1b")
+}
+
+func TestRenderPlanDrift(t *testing.T) {
+	stdout := func(msg string) engine.Event {
+		return engine.Event{Type: engine.StdoutColorEvent, Payload: engine.StdoutEventPayload{
+			Message: colors.SpecHeadline + msg + colors.Reset + "\n",
+			Color:   colors.Always,
+		}}
+	}
+	saved := renderSaveDiffHeader("dev", time.Now(), DisplayDiff) + "one\ntwo\n"
+	opts := Options{Color: colors.Always, Type: DisplayProgress}
+
+	// The time at which the plan was saved does not matter.
+	assert.Equal(t, "", RenderPlanDrift(saved, apitype.UpdateUpdate, []engine.Event{stdout("one"), stdout("two")}, opts))
+
+	drift := RenderPlanDrift(saved, apitype.UpdateUpdate, []engine.Event{stdout("one"), stdout("three")}, opts)
+	assert.Contains(t, drift, "--- saved plan\n+++ current plan\n")
+	assert.Contains(t, drift, "-two\n+three\n")
+	assert.NotContains(t, drift, "\x1b")
+
+	// The header of a unified diff records its format.
+	assert.Contains(t, renderSaveDiffHeader("dev", time.Now(), DisplayUnifiedDiff), "\nFormat: unified\n\n")
+}
+
+func TestRenderPlanComparison(t *testing.T) {
+	base := renderSaveDiffHeader("dev", time.Now(), DisplayDiff) + "one\ntwo\n"
+	current := renderSaveDiffHeader("dev", time.Now().Add(time.Minute), DisplayDiff) + "one\ntwo\n"
+
+	// The times at which the plans were saved do not matter.
+	assert.Equal(t, "", RenderPlanComparison(base, current, "main", "working tree"))
+
+	current = renderSaveDiffHeader("dev", time.Now(), DisplayDiff) + "one\nthree\n"
+	comparison := RenderPlanComparison(base, current, "main", "working tree")
+	assert.Contains(t, comparison, "--- main\n+++ working tree\n")
+	assert.Contains(t, comparison, "-two\n+three\n")
+}
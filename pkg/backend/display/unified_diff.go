@@ -0,0 +1,199 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// unifiedDiffContext is the number of unchanged lines shown around each change in a unified diff.
+const unifiedDiffContext = 3
+
+// renderUnifiedDiff renders the change to the inputs of the resource described by the given step as a unified diff
+// between the canonical serializations of its old and new inputs. Nothing is rendered if the inputs are unchanged.
+func renderUnifiedDiff(step engine.StepEventMetadata) string {
+	fromFile, toFile := "a/"+string(step.URN), "b/"+string(step.URN)
+
+	var before, after string
+	if step.Old != nil {
+		before = canonicalizeInputs(step.Old.Inputs)
+	} else {
+		fromFile = "/dev/null"
+	}
+	if step.New != nil {
+		after = canonicalizeInputs(step.New.Inputs)
+	} else {
+		toFile = "/dev/null"
+	}
+
+	hunks := unifiedDiffHunks(before, after, unifiedDiffContext)
+	if hunks == "" {
+		return ""
+	}
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", fromFile, toFile, hunks)
+}
+
+// canonicalizeInputs serializes the given inputs as indented JSON with sorted keys. Secrets are masked and internal
+// properties are omitted so that the serialization is suitable for display.
+func canonicalizeInputs(inputs resource.PropertyMap) string {
+	b, err := json.MarshalIndent(canonicalizeValue(resource.NewObjectProperty(inputs)), "", "    ")
+	contract.AssertNoError(err)
+	return string(b) + "\n"
+}
+
+func canonicalizeValue(v resource.PropertyValue) interface{} {
+	switch {
+	case v.IsSecret():
+		return "[secret]"
+	case v.IsComputed() || v.IsOutput():
+		return "[unknown]"
+	case v.IsAsset():
+		return fmt.Sprintf("[asset %s]", v.AssetValue().Hash)
+	case v.IsArchive():
+		return fmt.Sprintf("[archive %s]", v.ArchiveValue().Hash)
+	case v.IsArray():
+		arr := make([]interface{}, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = canonicalizeValue(elem)
+		}
+		return arr
+	case v.IsObject():
+		obj := make(map[string]interface{})
+		for k, elem := range v.ObjectValue() {
+			if !engine.IsInternalPropertyKey(k) {
+				obj[string(k)] = canonicalizeValue(elem)
+			}
+		}
+		return obj
+	default:
+		return v.V
+	}
+}
+
+// diffLine is a single line of a line-by-line diff, along with its kind: ' ' for unchanged lines, '-' for deleted
+// lines, and '+' for inserted lines.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// diffLines computes a line-by-line diff of the given texts.
+func diffLines(before, after string) []diffLine {
+	differ := diffmatchpatch.New()
+	chars1, chars2, lines := differ.DiffLinesToChars(before, after)
+	diffs := differ.DiffCharsToLines(differ.DiffMain(chars1, chars2, false), lines)
+
+	var result []diffLine
+	for _, d := range diffs {
+		var kind byte
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			kind = ' '
+		case diffmatchpatch.DiffDelete:
+			kind = '-'
+		case diffmatchpatch.DiffInsert:
+			kind = '+'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				result = append(result, diffLine{kind: kind, text: text})
+			}
+		}
+	}
+	return result
+}
+
+// unifiedDiffHunks renders the hunks of a unified diff of the given texts, with the given number of lines of context
+// around each change.
+func unifiedDiffHunks(before, after string, context int) string {
+	lines := diffLines(before, after)
+
+	out := &bytes.Buffer{}
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk until the next change is further away than twice the context, so that the contexts of
+		// adjacent changes do not overlap.
+		start, end := i-context, i
+		for end < len(lines) {
+			if lines[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].kind == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		if start < 0 {
+			start = 0
+		}
+		if end+context < len(lines) {
+			end += context
+		} else {
+			end = len(lines)
+		}
+
+		// Lines are numbered from one. If a hunk has no lines on one side, that side is numbered after the line that
+		// precedes the hunk.
+		oldStart, newStart := 1, 1
+		for _, l := range lines[:start] {
+			if l.kind != '+' {
+				oldStart++
+			}
+			if l.kind != '-' {
+				newStart++
+			}
+		}
+		var oldCount, newCount int
+		for _, l := range lines[start:end] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		fprintfIgnoreError(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, l := range lines[start:end] {
+			fprintfIgnoreError(out, "%c%s", l.kind, l.text)
+		}
+		i = end
+	}
+	return out.String()
+}
@@ -0,0 +1,73 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	assert.Equal(t, "", unifiedDiffHunks("a\nb\n", "a\nb\n", 3))
+
+	assert.Equal(t, "@@ -0,0 +1,2 @@\n+a\n+b\n", unifiedDiffHunks("", "a\nb\n", 3))
+	assert.Equal(t, "@@ -1,2 +0,0 @@\n-a\n-b\n", unifiedDiffHunks("a\nb\n", "", 3))
+
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	after := "1\nx\n3\n4\n5\n6\n7\n8\n9\n10\ny\n12\n"
+	assert.Equal(t,
+		"@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n 5\n"+
+			"@@ -8,5 +8,5 @@\n 8\n 9\n 10\n-11\n+y\n 12\n",
+		unifiedDiffHunks(before, after, 3))
+
+	// Changes whose contexts would overlap are shown in a single hunk.
+	after = "1\nx\n3\n4\n5\n6\ny\n8\n9\n10\n11\n12\n"
+	assert.Equal(t,
+		"@@ -1,10 +1,10 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n-7\n+y\n 8\n 9\n 10\n",
+		unifiedDiffHunks(before, after, 3))
+}
+
+func TestRenderUnifiedDiff(t *testing.T) {
+	urn := resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA")
+	oldState := &engine.StepEventStateMetadata{Inputs: resource.PropertyMap{
+		"name":     resource.NewStringProperty("a"),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+	}}
+	newState := &engine.StepEventStateMetadata{Inputs: resource.PropertyMap{
+		"name":     resource.NewStringProperty("b"),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter3")),
+		"__meta":   resource.NewStringProperty("internal"),
+	}}
+
+	diff := renderUnifiedDiff(engine.StepEventMetadata{Op: deploy.OpUpdate, URN: urn, Old: oldState, New: newState})
+	assert.Equal(t,
+		"--- a/"+string(urn)+"\n+++ b/"+string(urn)+"\n"+
+			"@@ -1,4 +1,4 @@\n {\n-    \"name\": \"a\",\n+    \"name\": \"b\",\n     \"password\": \"[secret]\"\n }\n",
+		diff)
+
+	diff = renderUnifiedDiff(engine.StepEventMetadata{Op: deploy.OpCreate, URN: urn, New: newState})
+	assert.Contains(t, diff, "--- /dev/null\n+++ b/"+string(urn)+"\n@@ -0,0 +1,4 @@\n")
+	assert.NotContains(t, diff, "hunter")
+
+	// Secrets are masked before diffing, so a change to a secret alone is not shown.
+	oldState.Inputs["name"] = resource.NewStringProperty("b")
+	diff = renderUnifiedDiff(engine.StepEventMetadata{Op: deploy.OpUpdate, URN: urn, Old: oldState, New: newState})
+	assert.Equal(t, "", diff)
+}
@@ -27,11 +27,13 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 	"gocloud.dev/blob"
 	_ "gocloud.dev/blob/azureblob" // driver for azblob://
 	_ "gocloud.dev/blob/fileblob"  // driver for file://
 	_ "gocloud.dev/blob/gcsblob"   // driver for gs://
 	_ "gocloud.dev/blob/s3blob"    // driver for s3://
+	"gocloud.dev/gcerrors"
 
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend"
@@ -149,16 +151,29 @@ func massageBlobPath(path string) (string, error) {
 	// functions we run into can't handle this either.
 	//
 	// From https://stackoverflow.com/questions/17609732/expand-tilde-to-home-directory
+	//
+	// If the CLI's home directory has been relocated, ~ refers to that directory instead, so that the local backend
+	// does not store state in the user's real home directory.
 	if strings.HasPrefix(path, "~") {
-		usr, err := user.Current()
-		if err != nil {
-			return "", errors.Wrap(err, "Could not determine current user to resolve `file://~` path.")
+		var home string
+		if os.Getenv(workspace.PulumiHomeEnvVar) != "" {
+			dir, err := workspace.GetPulumiHomeDir()
+			if err != nil {
+				return "", err
+			}
+			home = dir
+		} else {
+			usr, err := user.Current()
+			if err != nil {
+				return "", errors.Wrap(err, "Could not determine current user to resolve `file://~` path.")
+			}
+			home = usr.HomeDir
 		}
 
 		if path == "~" {
-			path = usr.HomeDir
+			path = home
 		} else {
-			path = filepath.Join(usr.HomeDir, path[2:])
+			path = filepath.Join(home, path[2:])
 		}
 	}
 
@@ -252,7 +267,7 @@ func (b *localBackend) GetStack(ctx context.Context, stackRef backend.StackRefer
 	stackName := stackRef.Name()
 	snapshot, path, err := b.getStack(stackName)
 	switch {
-	case os.IsNotExist(errors.Cause(err)):
+	case os.IsNotExist(errors.Cause(err)) || gcerrors.Code(errors.Cause(err)) == gcerrors.NotFound:
 		return nil, nil
 	case err != nil:
 		return nil, err
@@ -323,7 +338,7 @@ func (b *localBackend) RenameStack(ctx context.Context, stackRef backend.StackRe
 
 	// To remove the old stack, just make a backup of the file and don't write out anything new.
 	file := b.stackPath(stackName)
-	backupTarget(b.bucket, file)
+	backupTarget(b.bucket, file, false /*keepOriginal*/)
 
 	// And move the history over as well.
 	oldHistoryDir := b.historyDirectory(stackName)
@@ -367,7 +382,7 @@ func (b *localBackend) Preview(ctx context.Context, stackRef backend.StackRefere
 		DryRun:   true,
 		ShowLink: true,
 	}
-	return b.apply(ctx, apitype.PreviewUpdate, stack, op, opts, nil /*events*/)
+	return b.apply(ctx, apitype.PreviewUpdate, stack, op, opts, op.Opts.PreviewEvents)
 }
 
 func (b *localBackend) Update(ctx context.Context, stackRef backend.StackReference,
@@ -418,9 +433,9 @@ func (b *localBackend) apply(
 	stackName := stackRef.Name()
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
 
-	if !op.Opts.Display.JSONDisplay {
+	if op.Opts.Display.ShowsHumanReadable() {
 		// Print a banner so it's clear this is a local deployment.
-		fmt.Printf(op.Opts.Display.Color.Colorize(
+		fmt.Fprintf(op.Opts.Display.Stdout(), op.Opts.Display.Color.Colorize(
 			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
 	}
 
@@ -442,10 +457,12 @@ func (b *localBackend) apply(
 
 	scope := op.Scopes.NewScope(engineEvents, opts.DryRun)
 	eventsDone := make(chan bool)
+	recorder := backend.NewMetricsRecorder(op.Opts.Metrics, op.Proj.Name, stackName, kind, opts.DryRun)
 	go func() {
 		// Pull in all events from the engine and send them to the two listeners.
 		for e := range engineEvents {
 			displayEvents <- e
+			recorder.Record(e)
 
 			// If the caller also wants to see the events, stream them there also.
 			if events != nil {
@@ -456,6 +473,10 @@ func (b *localBackend) apply(
 		close(eventsDone)
 	}()
 
+	// Identify the update in the stack's history and in the history of its resources.
+	updateID := uuid.NewV4().String()
+	op.Opts.Engine.UpdateID = updateID
+
 	// Create the management machinery.
 	persister := b.newSnapshotPersister(stackName, op.SecretsManager)
 	manager := backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
@@ -493,6 +514,7 @@ func (b *localBackend) apply(
 	// Make sure the goroutine writing to displayEvents and events has exited before proceeding.
 	<-eventsDone
 	close(displayEvents)
+	recorder.Finish(updateRes != nil)
 
 	// Save update results.
 	backendUpdateResult := backend.SucceededResult
@@ -500,6 +522,7 @@ func (b *localBackend) apply(
 		backendUpdateResult = backend.FailedResult
 	}
 	info := backend.UpdateInfo{
+		ID:          updateID,
 		Kind:        kind,
 		StartTime:   start,
 		Message:     op.M.Message,
@@ -535,7 +558,7 @@ func (b *localBackend) apply(
 	}
 
 	// Make sure to print a link to the stack's checkpoint before exiting.
-	if opts.ShowLink && !op.Opts.Display.JSONDisplay {
+	if opts.ShowLink && op.Opts.Display.ShowsHumanReadable() {
 		// Note we get a real signed link for aws/azure/gcp links.  But no such option exists for
 		// file:// links so we manually create the link ourselves.
 		var link string
@@ -550,7 +573,7 @@ func (b *localBackend) apply(
 			}
 		}
 
-		fmt.Printf(op.Opts.Display.Color.Colorize(
+		fmt.Fprintf(op.Opts.Display.Stdout(), op.Opts.Display.Color.Colorize(
 			colors.SpecHeadline+"Permalink: "+
 				colors.Underline+colors.BrightBlue+"%s"+colors.Reset+"\n"), link)
 	}
@@ -1,12 +1,19 @@
 package filestate
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gocloud.dev/blob/fileblob"
+
+	"github.com/pulumi/pulumi/pkg/diag"
 )
 
 func TestMassageBlobPath(t *testing.T) {
@@ -63,3 +70,66 @@ func TestMassageBlobPath(t *testing.T) {
 		testMassagePath(t, FilePathPrefix+"/1/2/3/../4/..", FilePathPrefix+expected)
 	})
 }
+
+func TestGetMissingStack(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A bucket reports a missing stack file as not found rather than as a missing OS file, and either means that the
+	// stack does not exist.
+	b, err := New(diag.DefaultSink(ioutil.Discard, ioutil.Discard, diag.FormatOptions{}), FilePathPrefix+dir)
+	assert.NoError(t, err)
+	stack, err := b.GetStack(context.Background(), localBackendReference{name: "missing"})
+	assert.NoError(t, err)
+	assert.Nil(t, stack)
+}
+
+func TestBackupTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := fileblob.OpenBucket(dir, nil)
+	assert.NoError(t, err)
+	bucket := &wrappedBucket{bucket: b}
+
+	// Backing up a checkpoint before overwriting it must leave the original in place, so that the stack still has a
+	// checkpoint if the new one is never written.
+	assert.NoError(t, bucket.WriteAll(context.TODO(), "stack.json", []byte("v1"), nil))
+	bck := backupTarget(bucket, "stack.json", true /*keepOriginal*/)
+	assert.Equal(t, "stack.json.bak", bck)
+	for _, key := range []string{"stack.json", "stack.json.bak"} {
+		contents, err := bucket.ReadAll(context.TODO(), key)
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", string(contents))
+	}
+
+	// Backing up a checkpoint before removing it moves it out of the way.
+	assert.NoError(t, bucket.WriteAll(context.TODO(), "stack.json", []byte("v2"), nil))
+	backupTarget(bucket, "stack.json", false /*keepOriginal*/)
+	_, err = bucket.ReadAll(context.TODO(), "stack.json")
+	assert.Error(t, err)
+	contents, err := bucket.ReadAll(context.TODO(), "stack.json.bak")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", string(contents))
+}
+
+func TestCompressCheckpoint(t *testing.T) {
+	chk := []byte(`{"version":3,"checkpoint":{}}`)
+
+	compressed, err := compressCheckpoint(chk)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(compressed, gzipMagic))
+
+	// Both compressed and uncompressed checkpoints are read.
+	for _, b := range [][]byte{compressed, chk} {
+		decompressed, err := decompressCheckpoint(b)
+		assert.NoError(t, err)
+		assert.Equal(t, chk, decompressed)
+	}
+
+	// A checkpoint whose compressed data is corrupt is an error.
+	_, err = decompressCheckpoint(compressed[:len(compressed)/2])
+	assert.Error(t, err)
+}
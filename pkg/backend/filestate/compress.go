@@ -0,0 +1,69 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+// CompressCheckpointsEnvVar is the environment variable that, if it is truthy, causes checkpoints to be compressed
+// with gzip when they are saved. Checkpoints are read whether or not they are compressed, so compression may be turned
+// on or off at any time.
+const CompressCheckpointsEnvVar = "PULUMI_COMPRESS_CHECKPOINTS"
+
+// gzipMagic is the header with which every gzip stream starts, and which no JSON document does.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressCheckpoints returns true if checkpoints are to be compressed when they are saved.
+func compressCheckpoints() bool {
+	return cmdutil.IsTruthy(os.Getenv(CompressCheckpointsEnvVar))
+}
+
+// compressCheckpoint compresses the given serialized checkpoint with gzip.
+func compressCheckpoint(chk []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(chk); err != nil {
+		return nil, errors.Wrap(err, "compressing checkpoint")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "compressing checkpoint")
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressCheckpoint returns the given checkpoint decompressed, if it was compressed with gzip, or as it is, if it
+// was not.
+func decompressCheckpoint(chk []byte) ([]byte, error) {
+	if !bytes.HasPrefix(chk, gzipMagic) {
+		return chk, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(chk))
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing checkpoint")
+	}
+	decompressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing checkpoint")
+	}
+	return decompressed, nil
+}
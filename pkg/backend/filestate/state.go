@@ -135,6 +135,9 @@ func (b *localBackend) getCheckpoint(stackName tokens.QName) (*apitype.Checkpoin
 	if err != nil {
 		return nil, err
 	}
+	if bytes, err = decompressCheckpoint(bytes); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", chkpath)
+	}
 
 	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
 }
@@ -157,11 +160,19 @@ func (b *localBackend) saveStack(name tokens.QName, snap *deploy.Snapshot, sm se
 	if err != nil {
 		return "", errors.Wrap(err, "An IO error occurred during the current operation")
 	}
+	if compressCheckpoints() {
+		if byts, err = compressCheckpoint(byts); err != nil {
+			return "", err
+		}
+	}
 
-	// Back up the existing file if it already exists.
-	bck := backupTarget(b.bucket, file)
+	// Back up the existing file if it already exists. The existing file is copied rather than moved so that the
+	// stack always has a checkpoint, even if we crash before the new one has been written.
+	bck := backupTarget(b.bucket, file, true /*keepOriginal*/)
 
-	// And now write out the new snapshot file, overwriting that location.
+	// And now write out the new snapshot file, overwriting that location. The bucket writes the object atomically
+	// (for local files, by writing to a temporary file and renaming it into place), so a crash part way through
+	// this write leaves the previous checkpoint intact.
 	if err = b.bucket.WriteAll(context.TODO(), file, byts, nil); err != nil {
 		return "", errors.Wrap(err, "An IO error occurred during the current operation")
 	}
@@ -195,18 +206,23 @@ func (b *localBackend) removeStack(name tokens.QName) error {
 
 	// Just make a backup of the file and don't write out anything new.
 	file := b.stackPath(name)
-	backupTarget(b.bucket, file)
+	backupTarget(b.bucket, file, false /*keepOriginal*/)
 
 	historyDir := b.historyDirectory(name)
 	return removeAllByPrefix(b.bucket, historyDir)
 }
 
-// backupTarget makes a backup of an existing file, in preparation for writing a new one.  Instead of a copy, it
-// simply renames the file, which is simpler, more efficient, etc.
-func backupTarget(bucket Bucket, file string) string {
+// backupTarget makes a backup of an existing file, in preparation for writing a new one or removing it. If
+// keepOriginal is false, the file is simply renamed rather than copied, which is simpler, more efficient, etc.
+func backupTarget(bucket Bucket, file string, keepOriginal bool) string {
 	contract.Require(file != "", "file")
 	bck := file + ".bak"
-	err := renameObject(bucket, file, bck)
+	var err error
+	if keepOriginal {
+		err = bucket.Copy(context.TODO(), bck, file, nil)
+	} else {
+		err = renameObject(bucket, file, bck)
+	}
 	contract.IgnoreError(err) // ignore errors.
 	// IDEA: consider multiple backups (.bak.bak.bak...etc).
 	return bck
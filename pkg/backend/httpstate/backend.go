@@ -588,7 +588,7 @@ func (b *cloudBackend) Preview(ctx context.Context, stackRef backend.StackRefere
 		ShowLink: true,
 	}
 	return b.apply(
-		ctx, apitype.PreviewUpdate, stack, op, opts, nil /*events*/)
+		ctx, apitype.PreviewUpdate, stack, op, opts, op.Opts.PreviewEvents)
 }
 
 func (b *cloudBackend) Update(ctx context.Context, stackRef backend.StackReference,
@@ -692,9 +692,9 @@ func (b *cloudBackend) apply(
 
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
 
-	if !op.Opts.Display.JSONDisplay {
+	if op.Opts.Display.ShowsHumanReadable() {
 		// Print a banner so it's clear this is going to the cloud.
-		fmt.Printf(op.Opts.Display.Color.Colorize(
+		fmt.Fprintf(op.Opts.Display.Stdout(), op.Opts.Display.Color.Colorize(
 			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stack.Ref())
 	}
 
@@ -705,7 +705,7 @@ func (b *cloudBackend) apply(
 		return nil, result.FromError(err)
 	}
 
-	if opts.ShowLink && !op.Opts.Display.JSONDisplay {
+	if opts.ShowLink && op.Opts.Display.ShowsHumanReadable() {
 		// Print a URL at the end of the update pointing to the Pulumi Service.
 		var link string
 		base := b.cloudConsoleStackPath(update.StackIdentifier)
@@ -716,13 +716,16 @@ func (b *cloudBackend) apply(
 		}
 		if link != "" {
 			defer func() {
-				fmt.Printf(op.Opts.Display.Color.Colorize(
+				fmt.Fprintf(op.Opts.Display.Stdout(), op.Opts.Display.Color.Colorize(
 					colors.SpecHeadline+"Permalink: "+
 						colors.Underline+colors.BrightBlue+"%s"+colors.Reset+"\n"), link)
 			}()
 		}
 	}
 
+	// Record the update's version, by which the stack's history identifies it, in the history of its resources.
+	op.Opts.Engine.UpdateID = strconv.Itoa(version)
+
 	return b.runEngineAction(ctx, kind, stack.Ref(), op, update, token, events, opts.DryRun)
 }
 
@@ -809,9 +812,11 @@ func (b *cloudBackend) runEngineAction(
 	// channels for actual processing. (displayEvents and callerEventsOpt.)
 	engineEvents := make(chan engine.Event)
 	eventsDone := make(chan bool)
+	recorder := backend.NewMetricsRecorder(op.Opts.Metrics, op.Proj.Name, stackRef.Name(), kind, dryRun)
 	go func() {
 		for e := range engineEvents {
 			displayEvents <- e
+			recorder.Record(e)
 			if callerEventsOpt != nil {
 				callerEventsOpt <- e
 			}
@@ -863,6 +868,7 @@ func (b *cloudBackend) runEngineAction(
 	// has exited before proceeding
 	<-eventsDone
 	close(displayEvents)
+	recorder.Finish(res != nil)
 
 	// Mark the update as complete.
 	status := apitype.UpdateStatusSucceeded
@@ -923,6 +929,7 @@ func (b *cloudBackend) GetHistory(ctx context.Context, stackRef backend.StackRef
 		}
 
 		beUpdates = append(beUpdates, backend.UpdateInfo{
+			ID:              strconv.Itoa(update.Version),
 			Kind:            update.Kind,
 			Message:         update.Message,
 			Environment:     update.Environment,
@@ -198,9 +198,9 @@ func pulumiAPICall(ctx context.Context, d diag.Sink, cloudAPI, method, path stri
 
 	var resp *http.Response
 	if req.Method == "GET" || opts.RetryAllMethods {
-		resp, err = httputil.DoWithRetry(req, http.DefaultClient)
+		resp, err = httputil.DoWithRetry(req, httputil.Client(0))
 	} else {
-		resp, err = http.DefaultClient.Do(req)
+		resp, err = httputil.Client(0).Do(req)
 	}
 
 	if err != nil {
@@ -36,6 +36,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
 	"github.com/pulumi/pulumi/pkg/util/validation"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -349,6 +350,10 @@ func (pc *Client) CreateUpdate(
 	// First create the update program request.
 	wireConfig := make(map[string]apitype.ConfigValue)
 	for k, cv := range cfg {
+		// The secret to which a reference refers is never saved, so it is not sent to the service.
+		if cv.Reference() {
+			continue
+		}
 		v, err := cv.Value(config.NopDecrypter)
 		contract.AssertNoError(err)
 
@@ -475,7 +480,7 @@ func (pc *Client) PublishPolicyPack(ctx context.Context, orgName string,
 		return errors.Wrapf(err, "Failed to upload compressed PolicyPack")
 	}
 
-	_, err = http.DefaultClient.Do(putS3Req)
+	_, err = httputil.Client(0).Do(putS3Req)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to upload compressed PolicyPack")
 	}
@@ -517,7 +522,7 @@ func (pc *Client) DownloadPolicyPack(ctx context.Context, url string) ([]byte, e
 		return nil, errors.Wrapf(err, "Failed to download compressed PolicyPack")
 	}
 
-	resp, err := http.DefaultClient.Do(getS3Req)
+	resp, err := httputil.Client(0).Do(getS3Req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to download compressed PolicyPack")
 	}
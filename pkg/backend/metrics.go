@@ -0,0 +1,117 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
+)
+
+// MetricsRecorder turns the events of an update into metrics. Each metric is labeled with the update's project,
+// stack, and kind, and whether it is a preview. A nil recorder records nothing.
+//
+// The recorder emits the following metrics:
+//
+//     * pulumi_program_startup_duration: the time from the start of the update until its program registers its first
+//       resource.
+//     * pulumi_operation_steps: the number of steps, other than sames, in the update.
+//     * pulumi_resource_operations: the number of resource operations, labeled by their op and whether they
+//       succeeded or failed.
+//     * pulumi_operation_duration: the duration of the update, labeled by whether it succeeded or failed.
+//     * pulumi_operations: the number of updates, labeled by whether they succeeded or failed.
+type MetricsRecorder struct {
+	sink     metrics.Sink
+	labels   metrics.Labels
+	start    time.Time
+	started  bool  // true once the program has registered its first resource.
+	steps    int64 // the number of steps, other than sames, reported so far.
+	finished bool
+}
+
+// NewMetricsRecorder returns a recorder for an update of the given kind, which sends its metrics to the given sink.
+// If the sink is nil, so is the recorder.
+func NewMetricsRecorder(sink metrics.Sink, project tokens.PackageName, stack tokens.QName,
+	kind apitype.UpdateKind, dryRun bool) *MetricsRecorder {
+
+	if sink == nil {
+		return nil
+	}
+	return &MetricsRecorder{
+		sink: sink,
+		labels: metrics.Labels{
+			"project":   string(project),
+			"stack":     string(stack),
+			"operation": string(kind),
+			"preview":   strconv.FormatBool(dryRun || kind == apitype.PreviewUpdate),
+		},
+		start: time.Now(),
+	}
+}
+
+// Record records the metrics for the given event. It must not be called concurrently.
+func (r *MetricsRecorder) Record(e engine.Event) {
+	if r == nil {
+		return
+	}
+
+	switch e.Type {
+	case engine.ResourcePreEvent:
+		step := e.Payload.(engine.ResourcePreEventPayload).Metadata
+		if !r.started {
+			r.started = true
+			r.sink.Time("pulumi_program_startup_duration", time.Since(r.start), r.labels)
+		}
+		if step.Op != deploy.OpSame {
+			r.steps++
+		}
+	case engine.ResourceOutputsEvent:
+		step := e.Payload.(engine.ResourceOutputsEventPayload).Metadata
+		if step.Op != deploy.OpSame {
+			r.sink.Count("pulumi_resource_operations", 1,
+				r.labels.With("op", string(step.Op)).With("result", "succeeded"))
+		}
+	case engine.ResourceOperationFailed:
+		step := e.Payload.(engine.ResourceOperationFailedPayload).Metadata
+		r.sink.Count("pulumi_resource_operations", 1, r.labels.With("op", string(step.Op)).With("result", "failed"))
+	}
+}
+
+// Finish records the metrics that summarize the update, and flushes the sink. Any error flushing the sink is logged
+// rather than returned, so that metrics never fail an update.
+func (r *MetricsRecorder) Finish(failed bool) {
+	if r == nil || r.finished {
+		return
+	}
+	r.finished = true
+
+	result := "succeeded"
+	if failed {
+		result = "failed"
+	}
+	r.sink.Count("pulumi_operation_steps", r.steps, r.labels)
+	r.sink.Time("pulumi_operation_duration", time.Since(r.start), r.labels.With("result", result))
+	r.sink.Count("pulumi_operations", 1, r.labels.With("result", result))
+
+	if err := r.sink.Flush(); err != nil {
+		logging.Warningf("could not send metrics: %v", err)
+	}
+}
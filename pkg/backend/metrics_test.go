@@ -0,0 +1,85 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
+)
+
+type testSink struct {
+	counts  map[string]int64
+	timers  []string
+	flushes int
+}
+
+func (s *testSink) Count(name string, value int64, labels metrics.Labels) {
+	s.counts[name+" "+labels["op"]+" "+labels["result"]] += value
+}
+
+func (s *testSink) Time(name string, d time.Duration, labels metrics.Labels) {
+	s.timers = append(s.timers, name)
+}
+
+func (s *testSink) Flush() error {
+	s.flushes++
+	return nil
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	// A recorder without a sink records nothing.
+	none := NewMetricsRecorder(nil, "proj", "dev", apitype.UpdateUpdate, false)
+	assert.Nil(t, none)
+	none.Record(engine.Event{})
+	none.Finish(false)
+
+	sink := &testSink{counts: make(map[string]int64)}
+	r := NewMetricsRecorder(sink, "proj", "dev", apitype.UpdateUpdate, false)
+	assert.Equal(t, "false", r.labels["preview"])
+
+	pre := func(op deploy.StepOp) engine.Event {
+		return engine.Event{Type: engine.ResourcePreEvent,
+			Payload: engine.ResourcePreEventPayload{Metadata: engine.StepEventMetadata{Op: op}}}
+	}
+	outputs := func(op deploy.StepOp) engine.Event {
+		return engine.Event{Type: engine.ResourceOutputsEvent,
+			Payload: engine.ResourceOutputsEventPayload{Metadata: engine.StepEventMetadata{Op: op}}}
+	}
+	r.Record(pre(deploy.OpSame))
+	r.Record(outputs(deploy.OpSame))
+	r.Record(pre(deploy.OpCreate))
+	r.Record(outputs(deploy.OpCreate))
+	r.Record(pre(deploy.OpUpdate))
+	r.Record(engine.Event{Type: engine.ResourceOperationFailed,
+		Payload: engine.ResourceOperationFailedPayload{Metadata: engine.StepEventMetadata{Op: deploy.OpUpdate}}})
+	r.Finish(true)
+	r.Finish(true)
+
+	assert.Equal(t, map[string]int64{
+		"pulumi_resource_operations create succeeded": 1,
+		"pulumi_resource_operations update failed":    1,
+		"pulumi_operation_steps  ":                    2,
+		"pulumi_operations  failed":                   1,
+	}, sink.counts)
+	assert.Equal(t, []string{"pulumi_program_startup_duration", "pulumi_operation_duration"}, sink.timers)
+	assert.Equal(t, 1, sink.flushes)
+}
@@ -121,6 +121,11 @@ func (sm *SnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
 	return sm.mutate(func() bool { return true })
 }
 
+// Flush persists the snapshot, including any changes that the engine has made to the base snapshot.
+func (sm *SnapshotManager) Flush() error {
+	return sm.mutate(func() bool { return true })
+}
+
 // BeginMutation signals to the SnapshotManager that the engine intends to mutate the global snapshot
 // by performing the given Step. This function gives the SnapshotManager a chance to record the
 // intent to mutate before the mutation occurs.
@@ -541,8 +546,13 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 		}
 	}
 
-	// Record any pending operations, if there are any outstanding that have not completed yet.
+	// Record any pending operations, if there are any outstanding that have not completed yet. The pending operations
+	// of the base snapshot are those of an interrupted update that is being continued, which the engine drops once it
+	// succeeds.
 	var operations []resource.Operation
+	if base := sm.baseSnapshot; base != nil {
+		operations = append(operations, base.PendingOperations...)
+	}
 	for _, op := range sm.operations {
 		if !sm.completeOps[op.Resource] {
 			operations = append(operations, op)
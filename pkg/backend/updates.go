@@ -81,11 +81,15 @@ const (
 	// CIPRNumber is the PR number, for which the current CI job may be executing.
 	// Combining this information with the `VCSRepoKind` will give us the PR URL.
 	CIPRNumber = "ci.pr.number"
+
+	// Features is a comma-separated list of the feature flags that were enabled for the update.
+	Features = "pulumi.features"
 )
 
 // UpdateInfo describes a previous update.
 type UpdateInfo struct {
 	// Information known before an update is started.
+	ID        string             `json:"id,omitempty"`
 	Kind      apitype.UpdateKind `json:"kind"`
 	StartTime int64              `json:"startTime"`
 
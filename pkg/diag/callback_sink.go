@@ -0,0 +1,98 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// Diagnostic is a diagnostic that a callback sink delivers, with its message formatted but not decorated for display.
+type Diagnostic struct {
+	Severity Severity     // the diagnostic's severity.
+	ID       ID           // the diagnostic's unique identifier, or 0 if it has none.
+	URN      resource.URN // the resource that the diagnostic is about, if any.
+	Message  string       // the diagnostic's message, without a trailing newline, colors, or a severity prefix.
+	StreamID int32        // the stream of sequential messages that the diagnostic is part of, or 0 if none.
+}
+
+// CallbackSink returns a sink that delivers each diagnostic to the given function, rather than printing it. This lets
+// a program that embeds Pulumi send diagnostics to its own logging system. The function may be called concurrently.
+func CallbackSink(callback func(Diagnostic)) Sink {
+	contract.Require(callback != nil, "callback")
+	return &callbackSink{callback: callback}
+}
+
+// callbackSink is a sink that delivers diagnostics to a callback.
+type callbackSink struct {
+	callback func(Diagnostic)
+}
+
+func (d *callbackSink) Logf(sev Severity, diag *Diag, args ...interface{}) {
+	switch sev {
+	case Debug, Info, Infoerr, Warning, Error:
+	default:
+		contract.Failf("Unrecognized severity: %v", sev)
+	}
+	d.callback(Diagnostic{
+		Severity: sev,
+		ID:       diag.ID,
+		URN:      diag.URN,
+		Message:  d.message(diag, args...),
+		StreamID: diag.StreamID,
+	})
+}
+
+func (d *callbackSink) Debugf(diag *Diag, args ...interface{}) {
+	logging.V(3).Infof(diag.Message, args...)
+	d.Logf(Debug, diag, args...)
+}
+
+func (d *callbackSink) Infof(diag *Diag, args ...interface{}) {
+	d.Logf(Info, diag, args...)
+}
+
+func (d *callbackSink) Infoerrf(diag *Diag, args ...interface{}) {
+	d.Logf(Infoerr, diag, args...)
+}
+
+func (d *callbackSink) Errorf(diag *Diag, args ...interface{}) {
+	d.Logf(Error, diag, args...)
+}
+
+func (d *callbackSink) Warningf(diag *Diag, args ...interface{}) {
+	d.Logf(Warning, diag, args...)
+}
+
+func (d *callbackSink) Stringify(sev Severity, diag *Diag, args ...interface{}) (string, string) {
+	var prefix string
+	if sev != Info && sev != Infoerr {
+		prefix = string(sev) + ": "
+	}
+	return prefix, d.message(diag, args...) + "\n"
+}
+
+// message formats the given diagnostic's message, and removes any colors and sensitive data from it.
+func (d *callbackSink) message(diag *Diag, args ...interface{}) string {
+	msg := diag.Message
+	if !diag.Raw {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return logging.FilterString(colors.Never.Colorize(msg))
+}
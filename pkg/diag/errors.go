@@ -30,7 +30,8 @@ func GetPlanApplyFailedError(urn resource.URN) *Diag {
 }
 
 func GetDuplicateResourceURNError(urn resource.URN) *Diag {
-	return newError(urn, 2001, "Duplicate resource URN '%v'; try giving it a unique name")
+	return newError(urn, 2001, "Duplicate resource URN '%v'; try giving it a unique name. "+
+		"It was registered first with the parent '%v', and again with the parent '%v'")
 }
 
 func GetResourceInvalidError(urn resource.URN) *Diag {
@@ -58,3 +59,58 @@ func GetDuplicateResourceAliasError(urn resource.URN) *Diag {
 		"Duplicate resource alias '%v' applied to resource with URN '%v' conflicting with resource with URN '%v'",
 	)
 }
+
+func GetIgnoredPropertyChangesInfo(urn resource.URN) *Diag {
+	return newError(urn, 2009, "Ignoring changes to the following properties: %v")
+}
+
+func GetResourcesRetainedDueToProtectionWarning(urn resource.URN) *Diag {
+	return newError(urn, 2010, "%v protected resource(s), and the resources they depend on, were retained due to "+
+		"protection:\n    %v")
+}
+
+func GetResourceReplacementWarning(urn resource.URN) *Diag {
+	return newError(urn, 2011, "This resource will be replaced because of %v. %v")
+}
+
+func GetResourceReplacementNotAllowedError(urn resource.URN) *Diag {
+	return newError(urn, 2012, "This resource would be replaced because of %v, but replacements are not allowed. "+
+		"Pass --allow-replace to permit replacements.")
+}
+
+func GetUnknownFeatureFlagWarning(urn resource.URN) *Diag {
+	return newError(urn, 2013, "unknown feature flag '%v' will be ignored")
+}
+
+func GetAmbiguousResourceAliasError(urn resource.URN) *Diag {
+	return newError(urn, 2014,
+		"Resource with URN '%v' matches more than one existing resource: its aliases '%v' and '%v' both exist")
+}
+
+func GetDeprecatedResourceTypeWarning(urn resource.URN) *Diag {
+	return newError(urn, 2015, "Resource type '%v' is deprecated: %v")
+}
+
+func GetDeprecatedResourcePropertyWarning(urn resource.URN) *Diag {
+	return newError(urn, 2016, "Property '%v' of resource type '%v' is deprecated: %v")
+}
+
+func GetTooManyErrorsInfo(urn resource.URN) *Diag {
+	return newError(urn, 2017, "Stopping after %v errors; further errors were suppressed. "+
+		"Pass --max-errors 0 to report all errors.")
+}
+
+func GetTargetedResourcesRetainedWarning(urn resource.URN) *Diag {
+	return newError(urn, 2018, "%v resource(s) targeted for deletion were retained because other resources that "+
+		"are not being deleted depend on them:\n    %v")
+}
+
+func GetResourceUnchangedInfo(urn resource.URN) *Diag {
+	return newError(urn, 2019, "This resource is unchanged because %v.\n"+
+		"    compared: %v\n    normalized by the provider: %v\n    ignored: %v")
+}
+
+func GetReplaceOnChangesInfo(urn resource.URN) *Diag {
+	return newError(urn, 2020, "Replacing this resource because its replaceOnChanges option lists the following "+
+		"changed properties: %v")
+}
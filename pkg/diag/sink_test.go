@@ -65,3 +65,24 @@ func TestEscape(t *testing.T) {
 	pmiss, smiss := sink.Stringify(Error, Message("", "lots of %v %s %d chars"))
 	assert.Equal(t, "error: lots of %!v(MISSING) %!s(MISSING) %!d(MISSING) chars\n", pmiss+smiss)
 }
+
+func TestCallbackSink(t *testing.T) {
+	t.Parallel()
+
+	var delivered []Diagnostic
+	sink := CallbackSink(func(d Diagnostic) {
+		delivered = append(delivered, d)
+	})
+
+	sink.Errorf(&Diag{URN: "urn:pulumi:test::test::pkg:m:typ::res", ID: 2000, Message: "failed: %v"}, "%v")
+	sink.Warningf(RawMessage("", colors.SpecWarning+"raw %v"+colors.Reset))
+	sink.Logf(Info, StreamMessage("", "streamed", 7))
+	assert.Equal(t, []Diagnostic{
+		{Severity: Error, ID: 2000, URN: "urn:pulumi:test::test::pkg:m:typ::res", Message: "failed: %v"},
+		{Severity: Warning, Message: "raw %v"},
+		{Severity: Info, Message: "streamed", StreamID: 7},
+	}, delivered)
+
+	p, s := sink.Stringify(Error, Message("", "%s"), "message")
+	assert.Equal(t, "error: message\n", p+s)
+}
@@ -0,0 +1,122 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// CostEstimateFunction is the function that a cost estimator plugin must implement. Cost estimators are resource
+// provider plugins, so the function is called using the provider protocol's Invoke method.
+//
+// The function is passed a single argument, "resources", which is an array of objects describing each changed
+// resource with the properties "urn", "type", "op", "inputs", and "oldInputs". It must return an object with a
+// "resources" property that maps the URN of each resource the estimator recognizes to its change in cost, and may
+// return a "currency" property that describes the unit that those changes are expressed in.
+const CostEstimateFunction tokens.ModuleMember = "pulumi:cost:estimate"
+
+// costEstimateOps is the set of operations whose resources are passed to a cost estimator.
+var costEstimateOps = map[deploy.StepOp]bool{
+	deploy.OpCreate:  true,
+	deploy.OpUpdate:  true,
+	deploy.OpDelete:  true,
+	deploy.OpReplace: true,
+}
+
+// ResourceCostEstimate is the estimated change in cost of a single changed resource.
+type ResourceCostEstimate struct {
+	URN   resource.URN  // the URN of the changed resource.
+	Op    deploy.StepOp // the operation that changes the resource.
+	Known bool          // true if the estimator recognized the resource.
+	Delta float64       // the change in cost, if known.
+}
+
+// CostEstimate is the estimated change in cost of all of the resources changed by a plan.
+type CostEstimate struct {
+	Currency  string                 // the unit that cost changes are expressed in, if the estimator reported one.
+	Resources []ResourceCostEstimate // the estimates for each changed resource, sorted by URN.
+	Total     float64                // the sum of all known changes in cost.
+}
+
+// estimateCost asks the named cost estimator plugin to estimate the change in cost of the given steps.
+func estimateCost(host plugin.Host, estimator string, steps []deploy.Step) (*CostEstimate, error) {
+	prov, err := host.Provider(tokens.Package(estimator), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading cost estimator '%v'", estimator)
+	}
+	if err = prov.Configure(resource.PropertyMap{}); err != nil {
+		return nil, errors.Wrapf(err, "configuring cost estimator '%v'", estimator)
+	}
+
+	sort.Slice(steps, func(i, j int) bool { return steps[i].URN() < steps[j].URN() })
+
+	args := make([]resource.PropertyValue, len(steps))
+	for i, step := range steps {
+		obj := resource.PropertyMap{
+			"urn":  resource.NewStringProperty(string(step.URN())),
+			"type": resource.NewStringProperty(string(step.Type())),
+			"op":   resource.NewStringProperty(string(step.Op())),
+		}
+		if step.New() != nil {
+			obj["inputs"] = resource.NewObjectProperty(step.New().Inputs)
+		}
+		if step.Old() != nil {
+			obj["oldInputs"] = resource.NewObjectProperty(step.Old().Inputs)
+		}
+		args[i] = resource.NewObjectProperty(obj)
+	}
+
+	ret, failures, err := prov.Invoke(CostEstimateFunction, resource.PropertyMap{
+		"resources": resource.NewArrayProperty(args),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "estimating cost with '%v'", estimator)
+	}
+	if len(failures) > 0 {
+		return nil, errors.Errorf("cost estimator '%v' rejected its arguments: %v", estimator, failures[0].Reason)
+	}
+
+	var deltas resource.PropertyMap
+	if rs, has := ret["resources"]; has && rs.IsObject() {
+		deltas = rs.ObjectValue()
+	}
+
+	estimate := &CostEstimate{}
+	if c, has := ret["currency"]; has && c.IsString() {
+		estimate.Currency = c.StringValue()
+	}
+	for _, step := range steps {
+		e := ResourceCostEstimate{URN: step.URN(), Op: step.Op()}
+		if d, has := deltas[resource.PropertyKey(step.URN())]; has && d.IsNumber() {
+			e.Known, e.Delta = true, d.NumberValue()
+			estimate.Total += e.Delta
+		}
+		estimate.Resources = append(estimate.Resources, e)
+	}
+	return estimate, nil
+}
+
+// shouldEstimateCost returns true if the given step's resource should be passed to a cost estimator.
+func shouldEstimateCost(step deploy.Step) bool {
+	return costEstimateOps[step.Op()] && !providers.IsProviderType(step.Type())
+}
@@ -43,8 +43,9 @@ func Destroy(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (Resou
 		UpdateOptions: opts,
 		SourceFunc:    newDestroySource,
 		Events:        emitter,
-		Diag:          newEventSink(emitter, false),
+		Diag:          newErrorLimitSink(ctx.diagSink(emitter), opts.MaxErrors),
 		StatusDiag:    newEventSink(emitter, true),
+		isDestroy:     true,
 	}, dryRun)
 }
 
@@ -172,7 +172,7 @@ func GetResourcePropertiesSummary(step StepEventMetadata, indent int) string {
 			contract.Assert(err == nil)
 
 			// Elide references to default providers.
-			if prov.URN().Name() != "default" {
+			if !providers.IsDefaultProvider(prov.URN()) {
 				writeWithIndentNoPrefix(&b, indent+1, simplePropOp, "[provider=%s]\n", step.Provider)
 			}
 		}
@@ -203,9 +203,10 @@ func GetResourcePropertiesDetails(
 			PrintObject(&b, old.Inputs, planning, indent, step.Op, false, debug)
 		}
 	} else if len(new.Outputs) > 0 && step.Op != deploy.OpImport && step.Op != deploy.OpImportReplacement {
-		printOldNewDiffs(&b, old.Outputs, new.Outputs, nil, planning, indent, step.Op, summary, debug)
+		printOldNewDiffs(&b, old.Outputs, new.Outputs, nil, step.ArrayKeys, planning, indent, step.Op, summary, debug)
 	} else {
-		printOldNewDiffs(&b, old.Inputs, new.Inputs, step.Diffs, planning, indent, step.Op, summary, debug)
+		printOldNewDiffs(&b, old.Inputs, new.Inputs, step.Diffs, step.ArrayKeys, planning, indent, step.Op, summary,
+			debug)
 	}
 
 	return b.String()
@@ -285,7 +286,7 @@ func GetResourceOutputsPropertiesString(
 	// the new outputs, we want to print the diffs.
 	var outputDiff *resource.ObjectDiff
 	if step.Old != nil && step.Old.Outputs != nil {
-		outputDiff = step.Old.Outputs.Diff(outs, IsInternalPropertyKey)
+		outputDiff = step.Old.Outputs.DiffWithArrayKeys(outs, step.ArrayKeys, IsInternalPropertyKey)
 	}
 
 	var keys []resource.PropertyKey
@@ -460,10 +461,10 @@ func shortHash(hash string) string {
 
 func printOldNewDiffs(
 	b *bytes.Buffer, olds resource.PropertyMap, news resource.PropertyMap, include []resource.PropertyKey,
-	planning bool, indent int, op deploy.StepOp, summary bool, debug bool) {
+	arrayKeys resource.ArrayKeys, planning bool, indent int, op deploy.StepOp, summary bool, debug bool) {
 
 	// Get the full diff structure between the two, and print it (recursively).
-	if diff := olds.Diff(news, IsInternalPropertyKey); diff != nil {
+	if diff := olds.DiffWithArrayKeys(news, arrayKeys, IsInternalPropertyKey); diff != nil {
 		PrintObjectDiff(b, *diff, include, planning, indent, summary, debug)
 	} else {
 		// If there's no diff, report the op as Same - there's no diff to render
@@ -535,7 +536,7 @@ func printPropertyValueDiff(
 		a := diff.Array
 		for i := 0; i < a.Len(); i++ {
 			elemTitleFunc := func(eop deploy.StepOp, eprefix bool) {
-				writeWithIndent(b, indent+1, eop, eprefix, "[%d]: ", i)
+				writeWithIndent(b, indent+1, eop, eprefix, "[%s]: ", arrayElementLabel(a, i))
 			}
 			if add, isadd := a.Adds[i]; isadd {
 				printAdd(b, add, elemTitleFunc, planning, indent+2, debug)
@@ -591,6 +592,31 @@ func printPropertyValueDiff(
 	}
 }
 
+// arrayElementLabel returns the label of the element at the given index of the given array diff: its index, or, if the
+// elements of the array were matched by key, its key and the key's value (e.g. `name="web"`).
+func arrayElementLabel(a *resource.ArrayDiff, i int) string {
+	if a.Key == "" {
+		return strconv.Itoa(i)
+	}
+
+	var elem resource.PropertyValue
+	if add, isadd := a.Adds[i]; isadd {
+		elem = add
+	} else if delete, isdelete := a.Deletes[i]; isdelete {
+		elem = delete
+	} else if update, isupdate := a.Updates[i]; isupdate {
+		elem = update.New
+	} else {
+		elem = a.Sames[i]
+	}
+
+	key := elem.ObjectValue()[a.Key]
+	if key.IsString() {
+		return fmt.Sprintf("%s=%q", a.Key, key.StringValue())
+	}
+	return fmt.Sprintf("%s=%v", a.Key, key.V)
+}
+
 func isPrimitive(value resource.PropertyValue) bool {
 	return value.IsNull() || value.IsString() || value.IsNumber() ||
 		value.IsBool() || value.IsComputed() || value.IsOutput()
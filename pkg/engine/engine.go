@@ -17,6 +17,7 @@ package engine
 import (
 	"github.com/opentracing/opentracing-go"
 
+	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
 	"github.com/pulumi/pulumi/pkg/workspace"
@@ -43,4 +44,9 @@ type Context struct {
 	SnapshotManager SnapshotManager
 	BackendClient   deploy.BackendClient
 	ParentSpan      opentracing.SpanContext
+
+	// Diag is an optional sink to which the operation's diagnostics are sent instead of being emitted as events, e.g.
+	// so that a program that embeds the engine can deliver them to its own logging system using diag.CallbackSink.
+	// Status messages, which only report progress, are still emitted as events.
+	Diag diag.Sink
 }
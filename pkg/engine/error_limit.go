@@ -0,0 +1,78 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+)
+
+// newErrorLimitSink returns a sink that reports at most maxErrors errors to the given sink. Once the last of these
+// has been reported, the sink notes that any further errors will be suppressed and signals that the operation in
+// progress should stop. If maxErrors is zero or less, the given sink is returned as-is.
+func newErrorLimitSink(sink diag.Sink, maxErrors int) diag.Sink {
+	if maxErrors <= 0 {
+		return sink
+	}
+	return &errorLimitSink{
+		Sink:      sink,
+		maxErrors: maxErrors,
+		reached:   make(chan struct{}),
+	}
+}
+
+// errorLimitSink is a sink that stops reporting errors after a maximum number of them have been reported.
+type errorLimitSink struct {
+	diag.Sink
+
+	maxErrors int           // the maximum number of errors to report.
+	errors    int           // the number of errors seen so far, including suppressed errors.
+	lock      sync.Mutex    // a lock that protects errors.
+	reached   chan struct{} // a channel that is closed once the maximum number of errors has been reported.
+}
+
+// errorLimitReached returns a channel that is closed once the given sink has reported its maximum number of errors.
+// If the sink has no such limit, the returned channel is nil, and is therefore never ready.
+func errorLimitReached(sink diag.Sink) <-chan struct{} {
+	if s, ok := sink.(*errorLimitSink); ok {
+		return s.reached
+	}
+	return nil
+}
+
+func (s *errorLimitSink) Logf(sev diag.Severity, d *diag.Diag, args ...interface{}) {
+	if sev == diag.Error {
+		s.Errorf(d, args...)
+	} else {
+		s.Sink.Logf(sev, d, args...)
+	}
+}
+
+func (s *errorLimitSink) Errorf(d *diag.Diag, args ...interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.errors++
+	if s.errors > s.maxErrors {
+		return
+	}
+
+	s.Sink.Errorf(d, args...)
+	if s.errors == s.maxErrors {
+		s.Sink.Infoerrf(diag.GetTooManyErrorsInfo(""), s.maxErrors)
+		close(s.reached)
+	}
+}
@@ -17,6 +17,7 @@ package engine
 import (
 	"bytes"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/pulumi/pulumi/pkg/apitype"
@@ -95,6 +96,7 @@ type SummaryEventPayload struct {
 	MaybeCorrupt    bool            // true if one or more resources may be corrupt
 	Duration        time.Duration   // the duration of the entire update operation (zero values for previews)
 	ResourceChanges ResourceChanges // count of changed resources, useful for reporting
+	CostEstimate    *CostEstimate   // the estimated change in cost of the changed resources, if requested
 }
 
 type ResourceOperationFailedPayload struct {
@@ -126,6 +128,7 @@ type StepEventMetadata struct {
 	Keys         []resource.PropertyKey         // the keys causing replacement (only for CreateStep and ReplaceStep).
 	Diffs        []resource.PropertyKey         // the keys causing diffs
 	DetailedDiff map[string]plugin.PropertyDiff // the rich, structured diff
+	ArrayKeys    resource.ArrayKeys             // the keys that identify the elements of arrays, for diffing.
 	Logical      bool                           // true if this step represents a logical operation in the program.
 	Provider     string                         // the provider that performed this step.
 }
@@ -168,20 +171,21 @@ type StepEventStateMetadata struct {
 func makeEventEmitter(events chan<- Event, update UpdateInfo) (eventEmitter, error) {
 	target := update.GetTarget()
 	var secrets []string
-	if target.Config.HasSecureValue() {
+	if target.Config.HasSecureValue() || target.Config.HasReference() {
 		for k, v := range target.Config {
-			if !v.Secure() {
+			if !v.Secure() && !v.Reference() {
 				continue
 			}
 
-			secret, err := v.Value(target.Decrypter)
+			// Only the secure leaves of an object are masked, so that the rest of it may be shown.
+			values, err := v.SecretValues(target.Decrypter)
 			if err != nil {
 				return eventEmitter{}, DecryptError{
 					Key: k,
 					Err: err,
 				}
 			}
-			secrets = append(secrets, secret)
+			secrets = append(secrets, values...)
 		}
 	}
 
@@ -221,6 +225,7 @@ func makeStepEventMetadata(op deploy.StepOp, step deploy.Step, debug bool) StepE
 		Keys:         keys,
 		Diffs:        diffs,
 		DetailedDiff: detailedDiff,
+		ArrayKeys:    deploy.ArrayKeys(step),
 		Old:          makeStepEventStateMetadata(step.Old(), debug),
 		New:          makeStepEventStateMetadata(step.New(), debug),
 		Res:          makeStepEventStateMetadata(step.Res(), debug),
@@ -246,10 +251,81 @@ func makeStepEventStateMetadata(state *resource.State, debug bool) *StepEventSta
 		Inputs:     filterPropertyMap(state.Inputs, debug),
 		Outputs:    filterPropertyMap(state.Outputs, debug),
 		Provider:   state.Provider,
-		InitErrors: state.InitErrors,
+		InitErrors: filterStrings(state.InitErrors),
 	}
 }
 
+func filterStrings(strs []string) []string {
+	if strs == nil {
+		return nil
+	}
+	filtered := make([]string, len(strs))
+	for i, s := range strs {
+		filtered[i] = logging.FilterString(s)
+	}
+	return filtered
+}
+
+// filterStepSecrets adds the plaintext values of any secrets in the inputs or outputs of the given step's resources to
+// the global logging filter, so that they are masked in all output from then on, including messages that don't
+// know which values are secret.
+func filterStepSecrets(step deploy.Step) {
+	var secrets []string
+	for _, state := range []*resource.State{step.Old(), step.New()} {
+		if state != nil {
+			secrets = collectSecretStrings(resource.NewObjectProperty(state.Inputs), false, secrets)
+			secrets = collectSecretStrings(resource.NewObjectProperty(state.Outputs), false, secrets)
+		}
+	}
+	if len(secrets) == 0 {
+		return
+	}
+
+	// Only add a filter for secrets that we haven't already seen, so that the number of filters doesn't grow with
+	// the number of steps.
+	filteredSecrets.Lock()
+	defer filteredSecrets.Unlock()
+	var unseen []string
+	for _, secret := range secrets {
+		if !filteredSecrets.seen[secret] {
+			filteredSecrets.seen[secret] = true
+			unseen = append(unseen, secret)
+		}
+	}
+	if len(unseen) > 0 {
+		logging.AddGlobalFilter(logging.CreateFilter(unseen, "[secret]"))
+	}
+}
+
+// filteredSecrets is the set of secrets that filterStepSecrets has added to the global logging filter.
+var filteredSecrets = struct {
+	sync.Mutex
+	seen map[string]bool
+}{seen: make(map[string]bool)}
+
+// collectSecretStrings appends every string that is nested within a secret in the given value to secrets.
+func collectSecretStrings(v resource.PropertyValue, inSecret bool, secrets []string) []string {
+	switch {
+	case v.IsSecret():
+		return collectSecretStrings(v.SecretValue().Element, true, secrets)
+	case v.IsComputed():
+		return collectSecretStrings(v.Input().Element, inSecret, secrets)
+	case v.IsOutput():
+		return collectSecretStrings(v.OutputValue().Element, inSecret, secrets)
+	case v.IsArray():
+		for _, elem := range v.ArrayValue() {
+			secrets = collectSecretStrings(elem, inSecret, secrets)
+		}
+	case v.IsObject():
+		for _, elem := range v.ObjectValue() {
+			secrets = collectSecretStrings(elem, inSecret, secrets)
+		}
+	case v.IsString() && inSecret:
+		secrets = append(secrets, v.StringValue())
+	}
+	return secrets
+}
+
 func filterPropertyMap(propertyMap resource.PropertyMap, debug bool) resource.PropertyMap {
 	mappable := propertyMap.Mappable()
 
@@ -379,6 +455,9 @@ func (e *eventEmitter) resourceOperationFailedEvent(
 func (e *eventEmitter) resourceOutputsEvent(op deploy.StepOp, step deploy.Step, planning bool, debug bool) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
+	// Any secrets that the provider returned must be masked from now on.
+	filterStepSecrets(step)
+
 	e.Chan <- Event{
 		Type: ResourceOutputsEvent,
 		Payload: ResourceOutputsEventPayload{
@@ -394,6 +473,10 @@ func (e *eventEmitter) resourcePreEvent(
 
 	contract.Requiref(e != nil, "e", "!= nil")
 
+	// Any secrets that the program passed to the resource must be masked before the step runs, since the provider may
+	// echo them back (e.g. in an error message).
+	filterStepSecrets(step)
+
 	e.Chan <- Event{
 		Type: ResourcePreEvent,
 		Payload: ResourcePreEventPayload{
@@ -424,7 +507,7 @@ func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 	}
 }
 
-func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges) {
+func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges, costEstimate *CostEstimate) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
 	e.Chan <- Event{
@@ -434,6 +517,7 @@ func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges) {
 			MaybeCorrupt:    false,
 			Duration:        0,
 			ResourceChanges: resourceChanges,
+			CostEstimate:    costEstimate,
 		},
 	}
 }
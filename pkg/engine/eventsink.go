@@ -25,6 +25,15 @@ import (
 	"github.com/pulumi/pulumi/pkg/util/logging"
 )
 
+// diagSink returns the sink for the diagnostics of an operation in the given context: the context's sink, if it has
+// one, or else a sink that emits them as events.
+func (ctx *Context) diagSink(events eventEmitter) diag.Sink {
+	if ctx.Diag != nil {
+		return ctx.Diag
+	}
+	return newEventSink(events, false)
+}
+
 func newEventSink(events eventEmitter, statusSink bool) diag.Sink {
 	return &eventSink{
 		events:     events,
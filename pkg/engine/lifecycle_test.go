@@ -18,10 +18,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/secrets"
 
@@ -38,6 +40,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/mock"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
@@ -107,6 +110,10 @@ func (j *Journal) RegisterResourceOutputs(step deploy.Step) error {
 	}
 }
 
+func (j *Journal) Flush() error {
+	return nil
+}
+
 func (j *Journal) RecordPlugin(plugin workspace.PluginInfo) error {
 	return nil
 }
@@ -186,8 +193,11 @@ func (j *Journal) Snap(base *deploy.Snapshot) *deploy.Snapshot {
 		}
 	}
 
-	// Append any pending operations.
+	// Append any pending operations, starting with those of the base snapshot.
 	var operations []resource.Operation
+	if base != nil {
+		operations = append(operations, base.PendingOperations...)
+	}
 	for _, op := range ops {
 		if !doneOps[op.Resource] {
 			operations = append(operations, op)
@@ -1299,6 +1309,49 @@ func TestExternalRefresh(t *testing.T) {
 	assert.True(t, snap.Resources[1].External)
 }
 
+// TestRefreshThenRead tests that a resource that is refreshed before an update, and then read by the update's program,
+// is only read from its provider once.
+func TestRefreshThenRead(t *testing.T) {
+	reads := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					reads++
+					return plugin.ReadResult{
+						Outputs: resource.PropertyMap{"read": resource.NewNumberProperty(float64(reads))},
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, err := monitor.ReadResource("pkgA:m:typA", "resA", "resA-some-id", "", resource.PropertyMap{}, "", "")
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, 1, reads)
+
+	// The update refreshes the resource, and its program then reads the same resource, which reuses the refresh's
+	// result rather than reading it again.
+	reads = 0
+	p.Options.Refresh = true
+	snap = p.Run(t, snap)
+	assert.Equal(t, 1, reads)
+	if assert.Len(t, snap.Resources, 2) {
+		assert.Equal(t, 1.0, snap.Resources[1].Outputs["read"].NumberValue())
+	}
+}
+
 func TestRefreshInitFailure(t *testing.T) {
 	p := &TestPlan{}
 
@@ -1748,9 +1801,10 @@ func TestRefreshBasics(t *testing.T) {
 					}
 
 					// Only the inputs and outputs should have changed (if anything changed).
-					old.Inputs = expected.Inputs
-					old.Outputs = expected.Outputs
-					assert.Equal(t, old, new)
+					expectedNew := *old
+					expectedNew.Inputs = expected.Inputs
+					expectedNew.Outputs = expected.Outputs
+					assert.Equal(t, &expectedNew, new)
 				}
 			}
 			return res
@@ -1894,8 +1948,9 @@ func TestCanceledRefresh(t *testing.T) {
 				}
 
 				// Only the outputs should have changed (if anything changed).
-				old.Outputs = expected
-				assert.Equal(t, old, new)
+				expectedNew := *old
+				expectedNew.Outputs = expected
+				assert.Equal(t, &expectedNew, new)
 			}
 		}
 		return res
@@ -2204,6 +2259,168 @@ func TestPreviewWithPendingOperations(t *testing.T) {
 	assert.EqualError(t, res.Error(), deploy.PlanPendingOperationsError{}.Error())
 }
 
+// Tests that an update whose pending operations are continued refreshes the resources whose operations were
+// interrupted, and drops the pending operations.
+func TestContinueWithPendingOperations(t *testing.T) {
+	p := &TestPlan{}
+
+	const resType = "pkgA:m:typA"
+	urnA, urnB, urnC := p.NewURN(resType, "resA", ""), p.NewURN(resType, "resB", ""), p.NewURN(resType, "resC", "")
+
+	newResource := func(urn resource.URN, id resource.ID) *resource.State {
+		return &resource.State{
+			Type:    urn.Type(),
+			URN:     urn,
+			Custom:  true,
+			ID:      id,
+			Inputs:  resource.PropertyMap{},
+			Outputs: resource.PropertyMap{},
+		}
+	}
+
+	// resA was being updated, resB was being deleted, and resC was being created when the update was interrupted.
+	old := &deploy.Snapshot{
+		PendingOperations: []resource.Operation{
+			{Resource: newResource(urnA, "0"), Type: resource.OperationTypeUpdating},
+			{Resource: newResource(urnB, "1"), Type: resource.OperationTypeDeleting},
+			{Resource: newResource(urnC, ""), Type: resource.OperationTypeCreating},
+		},
+		Resources: []*resource.State{
+			newResource(urnA, "0"),
+			newResource(urnB, "1"),
+		},
+	}
+
+	var readsLock sync.Mutex
+	var reads []resource.URN
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					readsLock.Lock()
+					reads = append(reads, urn)
+					readsLock.Unlock()
+					if urn == urnB {
+						// The delete of resB completed.
+						return plugin.ReadResult{}, resource.StatusOK, nil
+					}
+					return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource(resType, "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource(resType, "resC", true)
+		assert.NoError(t, err)
+		return nil
+	})
+
+	op := TestOp(Update)
+	options := UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...), Continue: true}
+	project, target := p.GetProject(), p.GetTarget(old)
+
+	snap, res := op.Run(project, target, options, false, nil, func(_ workspace.Project, _ deploy.Target,
+		j *Journal, events []Event, res result.Result) result.Result {
+
+		var warned bool
+		for _, e := range events {
+			if e.Type == DiagEvent {
+				payload := e.Payload.(DiagEventPayload)
+				warned = warned || payload.URN == urnC && payload.Severity == diag.Warning
+			}
+		}
+		assert.True(t, warned)
+		return res
+	})
+	assert.Nil(t, res)
+
+	// Only the resources whose updates or deletes were interrupted are refreshed.
+	assert.ElementsMatch(t, []resource.URN{urnA, urnB}, reads)
+	assert.Empty(t, snap.PendingOperations)
+	var urns []resource.URN
+	for _, r := range snap.Resources {
+		if !providers.IsProviderType(r.Type) {
+			urns = append(urns, r.URN)
+		}
+	}
+	assert.Equal(t, []resource.URN{urnA, urnC}, urns)
+}
+
+// Tests that the pending operations that an update cannot reconcile remain in the snapshot if the update that
+// continues it fails, and are dropped once a later update succeeds.
+func TestContinueFailedUpdate(t *testing.T) {
+	p := &TestPlan{}
+
+	const resType = "pkgA:m:typA"
+	urnA, urnB := p.NewURN(resType, "resA", ""), p.NewURN(resType, "resB", "")
+
+	newResource := func(urn resource.URN, id resource.ID) *resource.State {
+		return &resource.State{
+			Type:    urn.Type(),
+			URN:     urn,
+			Custom:  true,
+			ID:      id,
+			Inputs:  resource.PropertyMap{},
+			Outputs: resource.PropertyMap{},
+		}
+	}
+
+	// resA was being updated, and resB was being created, when the update was interrupted.
+	old := &deploy.Snapshot{
+		PendingOperations: []resource.Operation{
+			{Resource: newResource(urnA, "0"), Type: resource.OperationTypeUpdating},
+			{Resource: newResource(urnB, ""), Type: resource.OperationTypeCreating},
+		},
+		Resources: []*resource.State{newResource(urnA, "0")},
+	}
+
+	fail := true
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if fail {
+						return "", nil, resource.StatusOK, errors.New("create failed")
+					}
+					return "1", news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource(resType, "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource(resType, "resB", true)
+		return err
+	})
+
+	op := TestOp(Update)
+	options := UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...), Continue: true}
+	project := p.GetProject()
+
+	// The update fails. The update of resA has been reconciled by refreshing it, but the create of resB has not.
+	snap, res := op.Run(project, p.GetTarget(old), options, false, nil, nil)
+	assert.NotNil(t, res)
+	if assert.Len(t, snap.PendingOperations, 1) {
+		assert.Equal(t, urnB, snap.PendingOperations[0].Resource.URN)
+		assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+	}
+
+	// The update is continued again, and succeeds, so the pending create is dropped.
+	fail = false
+	snap, res = op.Run(project, p.GetTarget(snap), options, false, nil, nil)
+	assert.Nil(t, res)
+	assert.Empty(t, snap.PendingOperations)
+}
+
 // Tests that a failed partial update causes the engine to persist the resource's old inputs and new outputs.
 func TestUpdatePartialFailure(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
@@ -2872,6 +3089,16 @@ func TestExplicitDeleteBeforeReplace(t *testing.T) {
 	}}
 	snap = p.Run(t, snap)
 
+	// The option is recorded in resA's state, but not in resB's.
+	for _, res := range snap.Resources {
+		switch res.URN {
+		case urnA:
+			assert.True(t, res.DeleteBeforeReplace)
+		case urnB:
+			assert.False(t, res.DeleteBeforeReplace)
+		}
+	}
+
 	// Change the value of resB.A. Only resB should be replaced, and the replacement should be create-before-delete.
 	inputsB["A"] = resource.NewStringProperty("qux")
 	p.Steps = []TestStep{{
@@ -3527,6 +3754,39 @@ func TestAliases(t *testing.T) {
 	}}, []deploy.StepOp{deploy.OpSame, deploy.OpReplace, deploy.OpCreateReplacement, deploy.OpDeleteReplaced})
 }
 
+func TestAmbiguousAlias(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	resources := []Resource{{t: "pkgA:index:t1", name: "n1"}, {t: "pkgA:index:t1", name: "n2"}}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return registerResources(t, monitor, resources)
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	// Renaming a resource with an alias that matches a single existing resource is fine, but a resource whose aliases
+	// match both n1 and n2 could be a rename of either, so the update must fail.
+	resources = []Resource{{
+		t:    "pkgA:index:t1",
+		name: "n3",
+		aliases: []resource.URN{
+			"urn:pulumi:test::test::pkgA:index:t1::n1",
+			"urn:pulumi:test::test::pkgA:index:t1::n2",
+		},
+	}}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, snap)
+}
+
 func TestPersistentDiff(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
@@ -3910,6 +4170,94 @@ func TestImport(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestImportFromManifest(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap, timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", news, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					if id == "missing" {
+						return plugin.ReadResult{}, resource.StatusOK, nil
+					}
+					return plugin.ReadResult{
+						Inputs:  resource.PropertyMap{"foo": resource.NewStringProperty(string(id))},
+						Outputs: resource.PropertyMap{"foo": resource.NewStringProperty(string(id))},
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	project := p.GetProject()
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	resB := p.NewURN("pkgA:m:typA", "resB", "")
+	resC := p.NewURN("pkgA:m:typA", "resC", "")
+
+	// Create a resource using the program.
+	snap, res := TestOp(Update).Run(project, p.GetTarget(nil), p.Options, false, p.BackendClient, nil)
+	assert.Nil(t, res)
+	assert.Len(t, snap.Resources, 2)
+
+	// Import three resources: one that the stack already has, one that exists, and one that does not. The first
+	// should be skipped, the second imported using the inputs that the provider reads, and the third should fail
+	// without stopping the import of the others.
+	opts := p.Options
+	opts.Imports = []deploy.Import{
+		{Type: "pkgA:m:typA", Name: "resA", ID: "a"},
+		{Type: "pkgA:m:typA", Name: "resB", ID: "b"},
+		{Type: "pkgA:m:typA", Name: "resC", ID: "missing"},
+	}
+	snap, res = TestOp(Update).Run(project, p.GetTarget(snap), opts, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, res result.Result) result.Result {
+			for _, entry := range j.Entries {
+				if entry.Kind == JournalEntryBegin {
+					continue
+				}
+				switch urn := entry.Step.URN(); urn {
+				case resB:
+					assert.Equal(t, deploy.OpImport, entry.Step.Op())
+					assert.Equal(t, JournalEntrySuccess, entry.Kind)
+				case resC:
+					assert.Equal(t, deploy.OpImport, entry.Step.Op())
+					assert.Equal(t, JournalEntryFailure, entry.Kind)
+				case resA:
+					t.Fatalf("unexpected step for existing resource %v", urn)
+				}
+			}
+			return res
+		})
+	assert.NotNil(t, res)
+
+	// The existing resources should have been retained, and the imported resource added.
+	urns := make(map[resource.URN]*resource.State)
+	for _, r := range snap.Resources {
+		urns[r.URN] = r
+	}
+	assert.Contains(t, urns, resA)
+	assert.Equal(t, resource.ID("created-id"), urns[resA].ID)
+	if assert.Contains(t, urns, resB) {
+		assert.Equal(t, resource.ID("b"), urns[resB].ID)
+		assert.Equal(t, resource.NewStringProperty("b"), urns[resB].Inputs["foo"])
+	}
+	assert.NotContains(t, urns, resC)
+}
+
 func TestCustomTimeouts(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
@@ -4035,3 +4383,3014 @@ func TestProviderDiffMissingOldOutputs(t *testing.T) {
 	}}
 	p.Run(t, snap)
 }
+
+func TestDestroyRetainsProtectedResources(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Protect:      true,
+			Dependencies: []resource.URN{resA},
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 4)
+
+	// By default, a destroy retains the protected resource along with the resources it depends on (including its
+	// provider), and deletes everything else.
+	p.Steps = []TestStep{{Op: Destroy}}
+	retained := p.Run(t, snap)
+	assert.Len(t, retained.Resources, 3)
+	for _, res := range retained.Resources {
+		assert.NotEqual(t, "resC", string(res.URN.Name()))
+	}
+
+	// If FailOnProtected is set, the destroy fails instead.
+	p.Options.FailOnProtected = true
+	p.Steps = []TestStep{{Op: Destroy, ExpectFailure: true}}
+	p.Run(t, snap)
+}
+
+func TestDestroyReportsProtectedDependencies(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Protect: true,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Protect:      true,
+			Dependencies: []resource.URN{resA},
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	// A protected resource that another protected resource depends on is reported as protected itself.
+	p.Steps = []TestStep{{
+		Op:          Destroy,
+		SkipPreview: true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			var warning string
+			for _, evt := range evts {
+				if evt.Type == DiagEvent && evt.Payload.(DiagEventPayload).Severity == diag.Warning {
+					warning += colors.Never.Colorize(evt.Payload.(DiagEventPayload).Message)
+				}
+			}
+			assert.Contains(t, warning, "2 protected resource(s)")
+			assert.Contains(t, warning, string(p.NewURN("pkgA:m:typA", "resA", "")))
+			assert.Contains(t, warning, string(p.NewURN("pkgA:m:typA", "resB", "")))
+			return res
+		},
+	}}
+	retained := p.Run(t, snap)
+	assert.Len(t, retained.Resources, 3)
+}
+
+func TestProtectFromProgram(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap,
+					ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if !olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{ReplaceKeys: []resource.PropertyKey{"foo"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	protect, foo := true, "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs:  resource.PropertyMap{"foo": resource.NewStringProperty(foo)},
+			Protect: protect,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.True(t, snap.Resources[1].Protect)
+
+	// While the program protects the resource, it cannot be replaced.
+	foo = "baz"
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, snap)
+
+	// Once the program no longer protects it, and that is deployed, it can.
+	protect, foo = false, "bar"
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	assert.False(t, snap.Resources[1].Protect)
+
+	foo = "baz"
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, "baz", snap.Resources[1].Inputs["foo"].StringValue())
+}
+
+func TestDuplicateResourceURN(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	// The two children have the same URN, because a URN names the type of its resource's parent but not its name.
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		compA, _, _, err := monitor.RegisterResource("pkgA:m:typComp", "compA", false)
+		assert.NoError(t, err)
+		compB, _, _, err := monitor.RegisterResource("pkgA:m:typComp", "compB", false)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Parent: compA,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Parent: compB,
+		})
+		assert.Error(t, err)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{host: host}}
+	p.Steps = []TestStep{{
+		Op:            Update,
+		ExpectFailure: true,
+		SkipPreview:   true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			compA, compB := p.NewURN("pkgA:m:typComp", "compA", ""), p.NewURN("pkgA:m:typComp", "compB", "")
+			urn := p.NewURN("pkgA:m:typA", "resA", compA)
+			reported := false
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					msg := colors.Never.Colorize(e.Message)
+					if e.Severity == diag.Error && strings.Contains(msg, "Duplicate resource URN '"+string(urn)+"'") {
+						reported = strings.Contains(msg, string(compA)) && strings.Contains(msg, string(compB))
+					}
+				}
+			}
+			assert.True(t, reported)
+			return res
+		},
+	}}
+	p.Run(t, nil)
+}
+
+func TestDestroyContinueOnError(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap,
+					timeout float64) (resource.Status, error) {
+
+					if urn.Name() == "resB" {
+						return resource.StatusOK, errors.New("resB is still in use")
+					}
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Dependencies: []resource.URN{resA},
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 4)
+
+	// The destroy fails, but deletes resC anyway. resB, which could not be deleted, and resA, which it depends on, are
+	// retained along with their provider, and both are reported.
+	p.Options.ContinueOnError = true
+	p.Steps = []TestStep{{
+		Op:            Destroy,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			reported := false
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					msg := colors.Never.Colorize(e.Message)
+					if e.Severity == diag.Error && strings.Contains(msg, "could not be deleted") {
+						reported = strings.Contains(msg, "resB is still in use") &&
+							strings.Contains(msg, "resA: "+string(p.NewURN("pkgA:m:typA", "resB", ""))+" depends on it")
+					}
+				}
+			}
+			assert.True(t, reported)
+			return res
+		},
+	}}
+	retained := p.Run(t, snap)
+	assert.Len(t, retained.Resources, 3)
+	for _, res := range retained.Resources {
+		assert.NotEqual(t, "resC", string(res.URN.Name()))
+	}
+}
+
+func TestDestroyTargets(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Dependencies: []resource.URN{resA},
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 4)
+
+	// Only the targeted resources are deleted, and resA is retained because resB, which is not targeted, depends on it.
+	p.Options.DestroyTargets = []resource.URN{p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resC", "")}
+	p.Steps = []TestStep{{
+		Op: Destroy,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			sawWarning := false
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					msg := colors.Never.Colorize(e.Message)
+					if e.Severity == diag.Warning && strings.Contains(msg, "targeted for deletion were retained") {
+						sawWarning = strings.Contains(msg, "resA")
+					}
+				}
+			}
+			assert.True(t, sawWarning)
+			return res
+		},
+	}}
+	retained := p.Run(t, snap)
+	assert.Len(t, retained.Resources, 3)
+	for _, res := range retained.Resources {
+		assert.NotEqual(t, "resC", string(res.URN.Name()))
+	}
+}
+
+func TestValidateOnlyChecksWithoutDiffing(t *testing.T) {
+	diffs, checkFailures := 0, []plugin.CheckFailure(nil)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+					return news, checkFailures, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap,
+					ignoreChanges []string) (plugin.DiffResult, error) {
+					diffs++
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+
+	// Change the resource's inputs and validate the program: the resource must be checked but never diffed.
+	inputs = resource.PropertyMap{"foo": resource.NewStringProperty("baz")}
+	diffs = 0
+	p.Options.ValidateOnly = true
+	_, res := TestOp(Update).Run(p.GetProject(), p.GetTarget(CloneSnapshot(t, snap)), p.Options, true, nil, nil)
+	assert.Nil(t, res)
+	assert.Equal(t, 0, diffs)
+
+	// If the provider reports a check failure, validation fails.
+	checkFailures = []plugin.CheckFailure{{Property: "foo", Reason: "bad foo"}}
+	_, res = TestOp(Update).Run(p.GetProject(), p.GetTarget(CloneSnapshot(t, snap)), p.Options, true, nil, nil)
+	assert.NotNil(t, res)
+	assert.Equal(t, 0, diffs)
+}
+
+func TestDisallowReplace(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if !olds["A"].DeepEquals(news["A"]) {
+						return plugin.DiffResult{ReplaceKeys: []resource.PropertyKey{"A"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{"A": resource.NewStringProperty("foo")}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, DisallowReplace: true},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	inputs = resource.PropertyMap{"A": resource.NewStringProperty("bar")}
+
+	// A preview reports the replacement, and why it is necessary, but does not fail.
+	validate := func(project workspace.Project, target deploy.Target, j *Journal,
+		evts []Event, res result.Result) result.Result {
+
+		reported := false
+		for _, evt := range evts {
+			if evt.Type == DiagEvent {
+				msg := evt.Payload.(DiagEventPayload).Message
+				reported = reported || strings.Contains(msg, "replaced because of changes to A")
+			}
+		}
+		assert.True(t, reported)
+		return res
+	}
+	_, res := TestOp(Update).Run(p.GetProject(), p.GetTarget(CloneSnapshot(t, snap)), p.Options, true, nil, validate)
+	assert.Nil(t, res)
+
+	// The update itself fails rather than replacing the resource.
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	p.Run(t, snap)
+
+	// Once replacements are allowed, the update succeeds.
+	p.Options.DisallowReplace = false
+	p.Steps = []TestStep{{Op: Update}}
+	p.Run(t, snap)
+}
+
+func TestUnknownFeatureFlagWarning(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Features: []string{"no-such-feature"}},
+		Steps: []TestStep{{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				warned := false
+				for _, evt := range evts {
+					if evt.Type == DiagEvent {
+						e := evt.Payload.(DiagEventPayload)
+						warned = warned || e.Severity == diag.Warning && strings.Contains(e.Message, "no-such-feature")
+					}
+				}
+				assert.True(t, warned)
+				return res
+			},
+		}},
+	}
+	p.Run(t, nil)
+}
+
+func TestResourceSecretsAreMasked(t *testing.T) {
+	const inputSecret, outputSecret = "s3cr3t-input", "s3cr3t-output"
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					// The test resource monitor does not pass secrets to the engine, so mark the input secret here.
+					if pw, has := news["password"]; has {
+						news["password"] = resource.MakeSecret(pw)
+					}
+					return news, nil, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if urn.Name() == "resB" {
+						// Leak both secrets in an error message.
+						return "", nil, resource.StatusOK,
+							errors.Errorf("could not create resource using %v and %v", inputSecret, outputSecret)
+					}
+					return "id", resource.PropertyMap{
+						"token": resource.MakeSecret(resource.NewStringProperty(outputSecret)),
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"password": resource.NewStringProperty(inputSecret)},
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true)
+		assert.Error(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps: []TestStep{{
+			Op:            Update,
+			SkipPreview:   true,
+			ExpectFailure: true,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				sawError := false
+				for _, evt := range evts {
+					if evt.Type == DiagEvent {
+						msg := evt.Payload.(DiagEventPayload).Message
+						sawError = sawError || strings.Contains(msg, "could not create resource")
+						assert.NotContains(t, msg, inputSecret)
+						assert.NotContains(t, msg, outputSecret)
+					}
+				}
+				assert.True(t, sawError)
+
+				// Other output paths are masked as well.
+				assert.NotContains(t, logging.FilterString("leaked "+inputSecret), inputSecret)
+				assert.NotContains(t, logging.FilterString("leaked "+outputSecret), outputSecret)
+				return res
+			},
+		}},
+	}
+	p.Run(t, nil)
+}
+
+func TestCostEstimate(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+		deploytest.NewProviderLoader("costs", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					assert.Equal(t, CostEstimateFunction, tok)
+
+					// Only resA is recognized by this estimator.
+					deltas := resource.PropertyMap{}
+					for _, r := range args["resources"].ArrayValue() {
+						urn := r.ObjectValue()["urn"].StringValue()
+						if resource.URN(urn).Name() == "resA" {
+							assert.Equal(t, "create", r.ObjectValue()["op"].StringValue())
+							deltas[resource.PropertyKey(urn)] = resource.NewNumberProperty(12.5)
+						}
+					}
+					return resource.PropertyMap{
+						"currency":  resource.NewStringProperty("USD"),
+						"resources": resource.NewObjectProperty(deltas),
+					}, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, CostEstimator: "costs"},
+	}
+
+	events := make(chan Event)
+	var summary *SummaryEventPayload
+	drained := make(chan bool)
+	go func() {
+		for e := range events {
+			if e.Type == SummaryEvent {
+				payload := e.Payload.(SummaryEventPayload)
+				summary = &payload
+			}
+		}
+		close(drained)
+	}()
+
+	cancelCtx, _ := cancel.NewContext(context.Background())
+	info := &updateInfo{project: p.GetProject(), target: p.GetTarget(nil)}
+	_, res := Update(info, &Context{Cancel: cancelCtx, Events: events, SnapshotManager: newJournal()}, p.Options, true)
+	close(events)
+	<-drained
+	assert.Nil(t, res)
+
+	if assert.NotNil(t, summary) && assert.NotNil(t, summary.CostEstimate) {
+		estimate := summary.CostEstimate
+		assert.Equal(t, "USD", estimate.Currency)
+		assert.Equal(t, 12.5, estimate.Total)
+		assert.Equal(t, []ResourceCostEstimate{
+			{URN: p.NewURN("pkgA:m:typA", "resA", ""), Op: deploy.OpCreate, Known: true, Delta: 12.5},
+			{URN: p.NewURN("pkgA:m:typA", "resB", ""), Op: deploy.OpCreate},
+		}, estimate.Resources)
+	}
+}
+
+func TestConditionalResource(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	// resB is only declared if the "test:createB" configuration value is true.
+	createB := config.MustMakeKey("test", "createB")
+	program := deploytest.NewLanguageRuntime(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		if info.Config[createB] == "true" {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+				Dependencies: []resource.URN{resA},
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Config:  config.Map{},
+	}
+	resA, resB := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resB", "")
+
+	// Runs an update with the condition set to the given value and checks the operations performed on each resource.
+	update := func(snap *deploy.Snapshot, enabled string, expected map[resource.URN]deploy.StepOp) *deploy.Snapshot {
+		p.Config[createB] = config.NewValue(enabled)
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				_ []Event, res result.Result) result.Result {
+
+				ops := make(map[resource.URN]deploy.StepOp)
+				for _, entry := range j.Entries {
+					if entry.Kind == JournalEntrySuccess && !providers.IsProviderType(entry.Step.Type()) {
+						ops[entry.Step.URN()] = entry.Step.Op()
+					}
+				}
+				assert.Equal(t, expected, ops)
+				return res
+			},
+		}}
+		return p.Run(t, snap)
+	}
+
+	// A true condition creates the resource...
+	snap := update(nil, "true", map[resource.URN]deploy.StepOp{resA: deploy.OpCreate, resB: deploy.OpCreate})
+	assert.Len(t, snap.Resources, 3)
+
+	// ...changing it to false destroys it...
+	snap = update(snap, "false", map[resource.URN]deploy.StepOp{resA: deploy.OpSame, resB: deploy.OpDelete})
+	assert.Len(t, snap.Resources, 2)
+
+	// ...and changing it back to true creates it again.
+	snap = update(snap, "true", map[resource.URN]deploy.StepOp{resA: deploy.OpSame, resB: deploy.OpCreate})
+	assert.Len(t, snap.Resources, 3)
+}
+
+func TestDeprecationWarnings(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{Deprecations: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.DeprecationsFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					return resource.PropertyMap{
+						"types": resource.NewObjectProperty(resource.PropertyMap{
+							"pkgA:m:typA": resource.NewStringProperty("use pkgA:m:typB instead"),
+						}),
+						"properties": resource.NewObjectProperty(resource.PropertyMap{
+							"pkgA:m:typB": resource.NewObjectProperty(resource.PropertyMap{
+								"size": resource.NewStringProperty("use sizeInGb instead"),
+							}),
+						}),
+					}, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true)
+			assert.NoError(t, err)
+		}
+		for _, name := range []string{"resC", "resD"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typB", name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(10)},
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// Each notice is reported once as a warning, no matter how many resources it applies to.
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps: []TestStep{{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				var messages []string
+				for _, evt := range evts {
+					if evt.Type == DiagEvent {
+						e := evt.Payload.(DiagEventPayload)
+						if strings.Contains(e.Message, "deprecated") {
+							assert.Equal(t, diag.Warning, e.Severity)
+							messages = append(messages, e.Message)
+						}
+					}
+				}
+				if assert.Len(t, messages, 2) {
+					assert.Contains(t, messages[0], "use pkgA:m:typB instead")
+					assert.Contains(t, messages[1], "use sizeInGb instead")
+				}
+				return res
+			},
+		}},
+	}
+	p.Run(t, nil)
+
+	// Deprecations can also be treated as errors.
+	p.Options.DeprecationErrors = true
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, nil)
+}
+
+// TestProviderParallelism tests that per-provider parallelism limits, whether given in the stack's configuration or as
+// options, cap the number of resource operations that run concurrently against each provider.
+func TestProviderParallelism(t *testing.T) {
+	const resourceCount = 8
+
+	var m sync.Mutex
+	active, maxActive := make(map[tokens.Package]int), make(map[tokens.Package]int)
+	newProviderLoader := func(pkg tokens.Package) *deploytest.ProviderLoader {
+		return deploytest.NewProviderLoader(pkg, semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap, timeout float64) (resource.ID, resource.PropertyMap,
+					resource.Status, error) {
+
+					m.Lock()
+					active[pkg]++
+					if active[pkg] > maxActive[pkg] {
+						maxActive[pkg] = active[pkg]
+					}
+					m.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					m.Lock()
+					active[pkg]--
+					m.Unlock()
+
+					return resource.ID(urn.Name()), resource.PropertyMap{}, resource.StatusOK, nil
+				},
+			}, nil
+		})
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var resources sync.WaitGroup
+		for _, typ := range []tokens.Type{"pkgA:m:typA", "pkgB:m:typB"} {
+			for i := 0; i < resourceCount; i++ {
+				resources.Add(1)
+				go func(typ tokens.Type, idx int) {
+					defer resources.Done()
+					_, _, _, err := monitor.RegisterResource(typ, fmt.Sprintf("res%d", idx), true)
+					assert.NoError(t, err)
+				}(typ, i)
+			}
+		}
+		resources.Wait()
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, newProviderLoader("pkgA"), newProviderLoader("pkgB"))
+
+	// The limit for pkgA given as an option overrides the one in the stack's configuration.
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Parallel:         2 * resourceCount,
+			ProviderParallel: []string{"pkgA=2"},
+			host:             host,
+		},
+		Config: config.Map{
+			deploy.ProviderParallelismConfigKey: config.NewValue("pkgA=5,pkgB=3"),
+		},
+		Steps: []TestStep{{Op: Update}},
+	}
+	p.Run(t, nil)
+
+	assert.True(t, maxActive["pkgA"] > 0 && maxActive["pkgA"] <= 2, "pkgA: %d", maxActive["pkgA"])
+	assert.True(t, maxActive["pkgB"] > 0 && maxActive["pkgB"] <= 3, "pkgB: %d", maxActive["pkgB"])
+
+	// Invalid limits are rejected.
+	p.Options.ProviderParallel = []string{"pkgA=0"}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, nil)
+}
+
+// TestNormalizeInputs tests that a provider's normalized form of a resource's inputs is used to compute diffs, so that
+// changes the provider considers insignificant are not applied, while the resource's state keeps the literal inputs.
+func TestNormalizeInputs(t *testing.T) {
+	normalize := func(inputs resource.PropertyMap) resource.PropertyMap {
+		normalized := inputs.Copy()
+		if name, has := inputs["name"]; has && name.IsString() {
+			normalized["name"] = resource.NewStringProperty(strings.ToLower(name.StringValue()))
+		}
+		return normalized
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{NormalizeInputs: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.NormalizeInputsFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					assert.Equal(t, "pkgA:m:typA", args["type"].StringValue())
+					return resource.PropertyMap{
+						"olds": resource.NewObjectProperty(normalize(args["olds"].ObjectValue())),
+						"news": resource.NewObjectProperty(normalize(args["news"].ObjectValue())),
+					}, nil, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					// Like a real provider, this one records the normalized form of the inputs in the outputs.
+					return "created-id", normalize(news), resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap,
+					timeout float64, ignoreChanges []string) (resource.PropertyMap, resource.Status, error) {
+
+					return normalize(news), resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	name := "Foo"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"name": resource.NewStringProperty(name)},
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{host: host}}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	// Runs an update and checks the operation performed on resA and the name recorded in its inputs.
+	update := func(snap *deploy.Snapshot, expected deploy.StepOp) *deploy.Snapshot {
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				_ []Event, res result.Result) result.Result {
+
+				for _, entry := range j.Entries {
+					if entry.Kind == JournalEntrySuccess && entry.Step.URN() == resA {
+						assert.Equal(t, expected, entry.Step.Op())
+					}
+				}
+				return res
+			},
+		}}
+		snap = p.Run(t, snap)
+		for _, res := range snap.Resources {
+			if res.URN == resA {
+				assert.Equal(t, name, res.Inputs["name"].StringValue())
+			}
+		}
+		return snap
+	}
+
+	snap := update(nil, deploy.OpCreate)
+
+	// A change that the provider normalizes away is not applied, but the new literal value is recorded...
+	name = "FOO"
+	snap = update(snap, deploy.OpSame)
+
+	// ...while a real change is.
+	name = "Bar"
+	update(snap, deploy.OpUpdate)
+}
+
+func TestPreviewOperation(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{PreviewOperation: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.PreviewOperationFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					outputs := args["news"].ObjectValue().Copy()
+					if args["operation"].StringValue() == "create" {
+						outputs["arn"] = resource.NewStringProperty("arn:" + args["urn"].StringValue())
+					} else {
+						outputs["arn"] = args["olds"].ObjectValue()["arn"]
+					}
+					return resource.PropertyMap{"outputs": resource.NewObjectProperty(outputs)}, nil, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds["size"].DeepEquals(news["size"]) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := news.Copy()
+					outputs["arn"] = resource.NewStringProperty("arn:" + string(urn))
+					return "created-id", outputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+		deploytest.NewProviderLoader("pkgB", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	size := 1.0
+	var previewed map[string]resource.PropertyMap
+	program := deploytest.NewLanguageRuntime(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.PropertyMap{"size": resource.NewNumberProperty(size)}
+		_, _, outsA, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		_, _, outsB, err := monitor.RegisterResource("pkgB:m:typB", "resB", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		if info.DryRun {
+			previewed = map[string]resource.PropertyMap{"resA": outsA, "resB": outsB}
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := string(p.NewURN("pkgA:m:typA", "resA", ""))
+
+	// The preview of a creation shows the outputs that pkgA predicts, but none for pkgB, which predicts nothing.
+	snap := p.Run(t, nil)
+	assert.Equal(t, "arn:"+resA, previewed["resA"]["arn"].StringValue())
+	assert.Equal(t, 1.0, previewed["resA"]["size"].NumberValue())
+	assert.Empty(t, previewed["resB"])
+
+	// The same goes for the preview of an update.
+	size = 2.0
+	previewed = nil
+	p.Run(t, snap)
+	assert.Equal(t, "arn:"+resA, previewed["resA"]["arn"].StringValue())
+	assert.Equal(t, 2.0, previewed["resA"]["size"].NumberValue())
+	assert.Empty(t, previewed["resB"])
+}
+
+// TestExplainSames tests that, when asked to, the engine explains why an unchanged resource is unchanged.
+func TestExplainSames(t *testing.T) {
+	normalize := func(inputs resource.PropertyMap) resource.PropertyMap {
+		normalized := inputs.Copy()
+		normalized["name"] = resource.NewStringProperty(strings.ToLower(inputs["name"].StringValue()))
+		return normalized
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{NormalizeInputs: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.NormalizeInputsFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					return resource.PropertyMap{
+						"olds": resource.NewObjectProperty(normalize(args["olds"].ObjectValue())),
+						"news": resource.NewObjectProperty(normalize(args["news"].ObjectValue())),
+					}, nil, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", normalize(news), resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+			}, nil
+		}),
+	}
+
+	name, size := "Foo", 1.0
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{
+				"name": resource.NewStringProperty(name),
+				"size": resource.NewNumberProperty(size),
+			},
+			IgnoreChanges: []string{"size"},
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	snap := p.Run(t, nil)
+
+	// The second update changes the case of the name, which the provider normalizes away, and the size, which is
+	// ignored, so the resource is unchanged.
+	name, size = "FOO", 2.0
+	explain := func(expected bool) {
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				explained := false
+				for _, evt := range evts {
+					if evt.Type == DiagEvent {
+						e := evt.Payload.(DiagEventPayload)
+						if e.URN == resA && strings.Contains(e.Message, "This resource is unchanged") {
+							explained = true
+							assert.Equal(t, diag.Info, e.Severity)
+							assert.Contains(t, e.Message, "its provider's diff reported no changes")
+							assert.Contains(t, e.Message, "compared: name, size")
+							assert.Contains(t, e.Message, "normalized by the provider: name")
+							assert.Contains(t, e.Message, "ignored: size")
+						}
+					}
+				}
+				assert.Equal(t, expected, explained)
+				return res
+			},
+		}}
+		p.Run(t, snap)
+	}
+
+	// Nothing is explained unless the engine is asked to.
+	explain(false)
+
+	p.Options.ExplainSames = true
+	explain(true)
+}
+
+// TestMaxErrors tests that the engine stops reporting errors once the maximum number of errors has been reported, and
+// that the update still fails.
+func TestMaxErrors(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					var failures []plugin.CheckFailure
+					for i := 0; i < 5; i++ {
+						failures = append(failures, plugin.CheckFailure{Reason: fmt.Sprintf("invalid value %d", i)})
+					}
+					return nil, failures, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.Error(t, err)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// Runs an update with the given maximum number of errors and returns the reasons that were reported.
+	run := func(maxErrors int) ([]string, bool) {
+		var reasons []string
+		var sawNote bool
+		p := &TestPlan{
+			Options: UpdateOptions{host: host, MaxErrors: maxErrors},
+			Steps: []TestStep{{
+				Op:            Update,
+				ExpectFailure: true,
+				SkipPreview:   true,
+				Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+					evts []Event, res result.Result) result.Result {
+
+					for _, evt := range evts {
+						if evt.Type != DiagEvent {
+							continue
+						}
+						e := evt.Payload.(DiagEventPayload)
+						msg := colors.Never.Colorize(e.Message)
+						if e.Severity == diag.Error && strings.Contains(msg, "invalid value") {
+							reasons = append(reasons, msg[strings.Index(msg, "invalid value"):len(msg)-1])
+						}
+						if strings.Contains(msg, "further errors were suppressed") {
+							sawNote = true
+						}
+					}
+					return res
+				},
+			}},
+		}
+		p.Run(t, nil)
+		return reasons, sawNote
+	}
+
+	reasons, sawNote := run(2)
+	assert.Equal(t, []string{"invalid value 0", "invalid value 1"}, reasons)
+	assert.True(t, sawNote)
+
+	reasons, sawNote = run(0)
+	assert.Len(t, reasons, 5)
+	assert.False(t, sawNote)
+}
+
+func TestRefreshDrift(t *testing.T) {
+	outputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name":     "foo",
+		"lastRead": "t0",
+		"tags":     map[string]interface{}{"a": "1", "b": "2"},
+	})
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{NonComparableProperties: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.NonComparablePropertiesFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					assert.Equal(t, "pkgA:m:typA", args["type"].StringValue())
+					return resource.NewPropertyMapFromMap(map[string]interface{}{
+						"properties": []interface{}{"lastRead"},
+					}), nil, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", outputs, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					return plugin.ReadResult{Inputs: inputs, Outputs: outputs}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{host: host}}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	// Runs a refresh and checks the drift that it reports for resA.
+	refresh := func(expectedOp deploy.StepOp, expectedDiffs []resource.PropertyKey,
+		expectedDetailedDiff map[string]plugin.PropertyDiff) {
+
+		p.Steps = []TestStep{{
+			Op: Refresh,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				events []Event, res result.Result) result.Result {
+
+				found := false
+				for _, e := range events {
+					if e.Type != ResourceOutputsEvent {
+						continue
+					}
+					m := e.Payload.(ResourceOutputsEventPayload).Metadata
+					if m.URN == resA {
+						found = true
+						assert.Equal(t, expectedOp, m.Op)
+						assert.Equal(t, expectedDiffs, m.Diffs)
+						assert.Equal(t, expectedDetailedDiff, m.DetailedDiff)
+					}
+				}
+				assert.True(t, found)
+				return res
+			},
+		}}
+		snap = p.Run(t, snap)
+	}
+
+	// A change to a non-comparable property is not drift...
+	outputs = outputs.Copy()
+	outputs["lastRead"] = resource.NewStringProperty("t1")
+	refresh(deploy.OpSame, nil, map[string]plugin.PropertyDiff{})
+
+	// ...but changes to other properties are, and are reported precisely.
+	outputs = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name":     "bar",
+		"lastRead": "t2",
+		"tags":     map[string]interface{}{"a": "1", "c": "3"},
+	})
+	refresh(deploy.OpUpdate, []resource.PropertyKey{"name", "tags"}, map[string]plugin.PropertyDiff{
+		"name":   {Kind: plugin.DiffUpdate},
+		"tags.b": {Kind: plugin.DiffDelete},
+		"tags.c": {Kind: plugin.DiffAdd},
+	})
+
+	// The refreshed outputs are recorded regardless.
+	assert.Equal(t, "t2", snap.Resources[1].Outputs["lastRead"].StringValue())
+}
+
+func TestCustomTimeoutsEnforced(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					// The "slow" resource is given the time limit that applies to it, and fails once it passes.
+					if urn.Name() == "slow" {
+						assert.True(t, timeout > 0 && timeout <= 0.1, "timeout %v", timeout)
+						time.Sleep(time.Duration(timeout * float64(time.Second)))
+						return "", nil, resource.StatusOK, errors.New("timed out")
+					}
+					return "created-id", news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	// Runs an update that creates a fast resource and a slow one, and returns the error reported for the slow one.
+	run := func(customTimeouts *resource.CustomTimeouts, resourceTimeout time.Duration, cfg config.Map,
+		resourceTimeouts ...string) string {
+
+		program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "fast", true, deploytest.ResourceOptions{
+				CustomTimeouts: &resource.CustomTimeouts{Create: 60},
+			})
+			assert.NoError(t, err)
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "slow", true, deploytest.ResourceOptions{
+				CustomTimeouts: customTimeouts,
+			})
+			assert.Error(t, err)
+			return nil
+		})
+		host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+		var message string
+		p := &TestPlan{
+			Config: cfg,
+			Options: UpdateOptions{
+				host:             host,
+				ResourceTimeout:  resourceTimeout,
+				ResourceTimeouts: resourceTimeouts,
+			},
+			Steps: []TestStep{{
+				Op:            Update,
+				ExpectFailure: true,
+				SkipPreview:   true,
+				Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+					evts []Event, res result.Result) result.Result {
+
+					for _, evt := range evts {
+						if evt.Type != DiagEvent {
+							continue
+						}
+						e := evt.Payload.(DiagEventPayload)
+						if e.Severity == diag.Error && e.URN != "" && e.URN.Name() == "slow" {
+							message = colors.Never.Colorize(e.Message)
+						}
+					}
+					return res
+				},
+			}},
+		}
+		snap := p.Run(t, nil)
+
+		// Only the fast resource was created.
+		var names []tokens.QName
+		for _, res := range snap.Resources {
+			if !providers.IsProviderType(res.Type) {
+				names = append(names, res.URN.Name())
+			}
+		}
+		assert.Equal(t, []tokens.QName{"fast"}, names)
+		return message
+	}
+
+	// A declared timeout is honored, and cited when it is exceeded...
+	message := run(&resource.CustomTimeouts{Create: 0.1}, time.Hour, nil)
+	assert.Contains(t, message, "did not complete within 100ms, the timeout declared by its customTimeouts")
+
+	// ...while the default applies to operations that do not declare one.
+	message = run(&resource.CustomTimeouts{Update: 60}, 100*time.Millisecond, nil)
+	assert.Contains(t, message, "did not complete within 100ms, the default resource timeout")
+
+	// The stack's configuration overrides a declared timeout...
+	cfg := config.Map{
+		deploy.ResourceTimeoutsConfigKey: config.NewValue("*::slow:create=100ms,*::fast:delete=1ms"),
+	}
+	message = run(&resource.CustomTimeouts{Create: 3600}, 0, cfg)
+	assert.Contains(t, message, "did not complete within 100ms, the timeout set by the stack's configuration")
+
+	// ...and the command line overrides the stack's configuration.
+	cfg = config.Map{deploy.ResourceTimeoutsConfigKey: config.NewValue("*::slow:create=1h")}
+	message = run(&resource.CustomTimeouts{Create: 3600}, 0, cfg, "*::slow:create=100ms")
+	assert.Contains(t, message, "did not complete within 100ms, the timeout set on the command line")
+}
+
+// TestCustomTimeoutsCompletedLate tests that a resource operation that the provider completes after its time limit has
+// passed is recorded as it would be otherwise, and draws a warning.
+func TestCustomTimeoutsCompletedLate(t *testing.T) {
+	creates, deletes := 0, 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					creates++
+					time.Sleep(2 * time.Duration(timeout*float64(time.Second)))
+					return "created-id", news, resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap,
+					timeout float64) (resource.Status, error) {
+
+					deletes++
+					time.Sleep(2 * time.Duration(timeout*float64(time.Second)))
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs:         resource.PropertyMap{"foo": resource.NewStringProperty("bar")},
+			CustomTimeouts: &resource.CustomTimeouts{Create: 0.1, Delete: 0.1},
+		})
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	validate := func(project workspace.Project, target deploy.Target, j *Journal,
+		evts []Event, res result.Result) result.Result {
+
+		var messages []string
+		for _, evt := range evts {
+			if evt.Type == DiagEvent && evt.Payload.(DiagEventPayload).Severity == diag.Warning {
+				messages = append(messages, colors.Never.Colorize(evt.Payload.(DiagEventPayload).Message))
+			}
+		}
+		assert.Contains(t, strings.Join(messages, "\n"), "did not complete within 100ms")
+		return res
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true, Validate: validate}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, 1, creates)
+
+	// The resource that the provider created late is in the snapshot, so the next update does not create it again.
+	if assert.Len(t, snap.Resources, 2) {
+		assert.Equal(t, resource.ID("created-id"), snap.Resources[1].ID)
+		assert.Equal(t, "bar", snap.Resources[1].Outputs["foo"].StringValue())
+	}
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, 1, creates)
+
+	// The resource that the provider deleted late is removed from the snapshot.
+	p.Steps = []TestStep{{Op: Destroy, SkipPreview: true, Validate: validate}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, 1, deletes)
+	assert.Len(t, snap.Resources, 0)
+}
+
+func TestArrayKeys(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{ArrayKeys: true}
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.ArrayKeysFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					assert.Equal(t, "pkgA:m:typA", args["type"].StringValue())
+					return resource.NewPropertyMapFromMap(map[string]interface{}{
+						"keys": map[string]interface{}{"rules": "name"},
+					}), nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	rule := func(name string, port int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "port": port}
+	}
+	rules := []interface{}{rule("a", 80), rule("b", 443)}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.NewPropertyMapFromMap(map[string]interface{}{"rules": rules}),
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{host: host}}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	// Insert a rule ahead of the others and change the port of the last. Only those elements should be shown as
+	// changed, and each element should be labeled by its name.
+	rules = []interface{}{rule("c", 22), rule("a", 80), rule("b", 8443)}
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			events []Event, res result.Result) result.Result {
+
+			found := false
+			for _, e := range events {
+				if e.Type != ResourcePreEvent {
+					continue
+				}
+				m := e.Payload.(ResourcePreEventPayload).Metadata
+				if m.URN != resA {
+					continue
+				}
+				found = true
+				assert.Equal(t, deploy.OpUpdate, m.Op)
+				assert.Equal(t, resource.ArrayKeys{"rules": "name"}, m.ArrayKeys)
+
+				details := GetResourcePropertiesDetails(m, 0, false, true /*summary*/, false)
+				assert.Contains(t, details, `[name="c"]`)
+				assert.Contains(t, details, `[name="b"]`)
+				assert.NotContains(t, details, `[name="a"]`)
+			}
+			assert.True(t, found)
+			return res
+		},
+	}}
+	p.Run(t, snap)
+}
+
+// TestProviderCapabilities tests that the engine does not call the optional provider functions of a provider that
+// does not report them as capabilities.
+func TestProviderCapabilities(t *testing.T) {
+	gated := map[tokens.ModuleMember]bool{
+		deploy.NormalizeInputsFunction:         true,
+		deploy.DeprecationsFunction:            true,
+		deploy.NonComparablePropertiesFunction: true,
+		deploy.ArrayKeysFunction:               true,
+		deploy.PollOperationFunction:           true,
+		deploy.PreviewOperationFunction:        true,
+		deploy.BatchCreateFunction:             true,
+		deploy.ResourceDependenciesFunction:    true,
+	}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					assert.False(t, gated[tok], "unexpected call to %v", tok)
+					return nil, nil, errors.New("unsupported")
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+			}, nil
+		}),
+	}
+
+	size := 1.0
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(size)},
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{host: host}}
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	// Refresh the resources, then preview and apply an update to both of them.
+	size = 2.0
+	p.Steps = []TestStep{{Op: Refresh, SkipPreview: true}, {Op: Update}}
+	p.Run(t, snap)
+}
+
+// TestAsyncOperations tests that the engine polls operations that a provider completes asynchronously, and only
+// records their resources once the operations complete.
+func TestAsyncOperations(t *testing.T) {
+	var polls []string
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{PollOperation: true}
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", resource.PropertyMap{
+						deploy.InProgressKey: resource.NewStringProperty(string(urn.Name()) + "-1"),
+					}, resource.StatusOK, nil
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.PollOperationFunction {
+						return resource.PropertyMap{}, nil, nil
+					}
+					assert.Equal(t, "created-id", args["id"].StringValue())
+					token := args["token"].StringValue()
+					polls = append(polls, token)
+					switch token {
+					case "resA-1":
+						// The first poll reports progress, and a new token with which to poll next.
+						return resource.PropertyMap{
+							"token":   resource.NewStringProperty("resA-2"),
+							"message": resource.NewStringProperty("provisioning"),
+						}, nil, nil
+					case "resA-2":
+						outputs := resource.PropertyMap{"ready": resource.NewBoolProperty(true)}
+						return resource.PropertyMap{
+							"done":    resource.NewBoolProperty(true),
+							"outputs": resource.NewObjectProperty(outputs),
+						}, nil, nil
+					case "resB-1":
+						return resource.PropertyMap{
+							"done":    resource.NewBoolProperty(true),
+							"failed":  resource.NewBoolProperty(true),
+							"message": resource.NewStringProperty("quota exceeded"),
+						}, nil, nil
+					default:
+						// Any other operation never completes.
+						return resource.PropertyMap{}, nil, nil
+					}
+				},
+			}, nil
+		}),
+	}
+
+	names := []string{"resA"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range names {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				CustomTimeouts: &resource.CustomTimeouts{Create: 2},
+			})
+			if name == "resA" {
+				assert.NoError(t, err)
+			}
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps: []TestStep{{
+			Op:          Update,
+			SkipPreview: true,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				reported := false
+				for _, evt := range evts {
+					if evt.Type == DiagEvent {
+						e := evt.Payload.(DiagEventPayload)
+						reported = reported || e.Ephemeral && strings.Contains(e.Message, "provisioning")
+					}
+				}
+				assert.True(t, reported)
+				return res
+			},
+		}},
+	}
+
+	// The resource is recorded with the outputs reported once its creation completed.
+	snap := p.Run(t, nil)
+	assert.Equal(t, []string{"resA-1", "resA-2"}, polls)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, resource.PropertyMap{"ready": resource.NewBoolProperty(true)}, snap.Resources[1].Outputs)
+
+	// A resource whose creation fails is recorded with the failure, so that the next update tries again...
+	names = []string{"resA", "resB"}
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 3)
+	assert.Equal(t, []string{"quota exceeded"}, snap.Resources[2].InitErrors)
+	assert.NotContains(t, snap.Resources[2].Outputs, deploy.InProgressKey)
+
+	// ...as is one whose creation does not complete within its timeout, so that a later refresh or destroy finds it.
+	names = []string{"resC"}
+	p.Steps = []TestStep{{
+		Op:            Update,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			timedOut := false
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					timedOut = timedOut || strings.Contains(e.Message, "did not complete within 2s")
+				}
+			}
+			assert.True(t, timedOut)
+			return res
+		},
+	}}
+	snap = p.Run(t, nil)
+	if assert.Len(t, snap.Resources, 2) {
+		resC := snap.Resources[1]
+		assert.Equal(t, "resC", string(resC.URN.Name()))
+		assert.Equal(t, resource.ID("created-id"), resC.ID)
+		if assert.Len(t, resC.InitErrors, 1) {
+			assert.Contains(t, resC.InitErrors[0], "may still be in progress")
+		}
+		assert.NotContains(t, resC.Outputs, deploy.InProgressKey)
+	}
+}
+
+func TestResourcePriority(t *testing.T) {
+	var order []string
+	var lock sync.Mutex
+	record := func(urn resource.URN) {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, string(urn.Name()))
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					record(urn)
+					return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap,
+					timeout float64) (resource.Status, error) {
+
+					record(urn)
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Priority: 1,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Priority: 5,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, deploytest.ResourceOptions{
+			Priority: 3,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resD", true, deploytest.ResourceOptions{
+			Priority:     10,
+			Dependencies: []resource.URN{resA},
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Parallel: 1},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+
+	// Each resource's priority is recorded in the snapshot.
+	priorities := make(map[string]int)
+	for _, res := range snap.Resources {
+		priorities[string(res.URN.Name())] = res.Priority
+	}
+	assert.Equal(t, map[string]int{"default": 0, "resA": 1, "resB": 5, "resC": 3, "resD": 10}, priorities)
+
+	// Resources are refreshed in order of priority, since none of them have to wait for another...
+	order = nil
+	p.Steps = []TestStep{{Op: Refresh, SkipPreview: true}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"resD", "resB", "resC", "resA"}, order)
+
+	// ...and deleted in the reverse order of priority, except that a resource is never deleted before the resources
+	// that depend on it: resD must be deleted before resA despite its higher priority.
+	order = nil
+	p.Steps = []TestStep{{Op: Destroy, SkipPreview: true}}
+	p.Run(t, snap)
+	assert.Equal(t, []string{"resD", "resA", "resC", "resB"}, order)
+}
+
+func TestMockProviders(t *testing.T) {
+	program := deploytest.NewLanguageRuntime(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		region, _, err := monitor.Invoke("pkgA:index:getRegion", resource.PropertyMap{}, "", "")
+		assert.NoError(t, err)
+		_, _, outs, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"region": region["name"]},
+		})
+		assert.NoError(t, err)
+		if !info.DryRun {
+			assert.Equal(t, resource.NewStringProperty("mock-arn"), outs["arn"])
+		}
+		return nil
+	})
+
+	// No provider plugin is available for pkgA, so every provider operation must be satisfied by the mocks.
+	mocks := &mock.Mocks{
+		Resources: map[tokens.Type]resource.PropertyMap{
+			"pkgA:m:typA": {"arn": resource.NewStringProperty("mock-arn")},
+		},
+		Invokes: map[tokens.ModuleMember]resource.PropertyMap{
+			"pkgA:index:getRegion": {"name": resource.NewStringProperty("us-west-2")},
+		},
+	}
+	p := &TestPlan{
+		Options: UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program), Mocks: mocks},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	// The preview and the update each invoke the function, but only the update creates the resource.
+	var methods []string
+	for _, c := range mocks.Calls() {
+		methods = append(methods, c.Method)
+		if c.Method == "Create" {
+			assert.Equal(t, "us-west-2", c.Inputs["region"].StringValue())
+		}
+	}
+	assert.Equal(t, []string{"Invoke", "Invoke", "Create"}, methods)
+
+	// The mocked resource is not saved as the stack's state.
+	assert.Len(t, snap.Resources, 0)
+}
+
+func TestProviderDryRun(t *testing.T) {
+	var creates []string
+	newProvider := func(dryRun bool) func() (plugin.Provider, error) {
+		return func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{DryRun: dryRun}
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					creates = append(creates, string(urn.Name()))
+					return "created-id", news, resource.StatusOK, nil
+				},
+			}, nil
+		}
+	}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), newProvider(true)),
+		deploytest.NewProviderLoader("pkgB", semver.MustParse("1.0.0"), newProvider(false)),
+	}
+
+	types := []tokens.Type{"pkgA:m:typA"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for i, typ := range types {
+			_, _, _, err := monitor.RegisterResource(typ, fmt.Sprintf("res%d", i), true)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// The resources of a provider that supports dry-run mode are created, but the stack's state is not saved.
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, ProviderDryRun: true},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, []string{"res0"}, creates)
+	assert.Len(t, snap.Resources, 0)
+
+	// An operation on a resource of a provider that does not support the mode fails without calling the provider.
+	creates = nil
+	types = []tokens.Type{"pkgB:m:typB"}
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	p.Run(t, nil)
+	assert.Empty(t, creates)
+}
+
+func TestResourceRetryPolicy(t *testing.T) {
+	var attempts sync.Map
+	var deletes int
+	retriesCreates := true
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{CreateAndUpdateRetries: retriesCreates}
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap,
+					timeout float64) (resource.Status, error) {
+
+					deletes++
+					if deletes < 3 {
+						return resource.StatusOK, rpcerror.New(codes.Unavailable, "rate limit exceeded")
+					}
+					return resource.StatusOK, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					n, _ := attempts.LoadOrStore(urn.Name(), 0)
+					attempts.Store(urn.Name(), n.(int)+1)
+					switch {
+					case urn.Name() == "permanent":
+						return "", nil, resource.StatusOK, errors.New("invalid bucket name")
+					case n.(int) < 2:
+						return "", nil, resource.StatusOK, rpcerror.New(codes.Unavailable, "rate limit exceeded")
+					default:
+						return resource.ID(urn.Name()), news, resource.StatusOK, nil
+					}
+				},
+			}, nil
+		}),
+	}
+
+	// Only the last resource that the program registers may fail, since a failure ends the update.
+	policy := resource.RetryPolicy{Attempts: 3, Delay: 0.001}
+	var names []tokens.QName
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range names {
+			var opts deploytest.ResourceOptions
+			if name != "default" {
+				opts.RetryPolicy = policy
+			}
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", string(name), true, opts)
+			assert.Equal(t, name == "flaky" && retriesCreates, err == nil)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+	run := func(opts UpdateOptions, resources ...tokens.QName) (*deploy.Snapshot, map[string]int) {
+		attempts, names = sync.Map{}, resources
+		p := &TestPlan{
+			Options: opts,
+			Steps:   []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}},
+		}
+		snap := p.Run(t, nil)
+
+		counts := make(map[string]int)
+		attempts.Range(func(k, v interface{}) bool {
+			counts[string(k.(tokens.QName))] = v.(int)
+			return true
+		})
+		return snap, counts
+	}
+
+	// A resource's own policy is applied to its transient failures, but not to its other failures...
+	snap, counts := run(UpdateOptions{host: host, Parallel: 1}, "flaky", "permanent")
+	assert.Equal(t, map[string]int{"flaky": 3, "permanent": 1}, counts)
+
+	// ...and is recorded with the resource.
+	var flaky *resource.State
+	for _, res := range snap.Resources {
+		if res.URN.Name() == "flaky" {
+			flaky = res
+		}
+	}
+	if assert.NotNil(t, flaky) {
+		assert.Equal(t, policy, flaky.RetryPolicy)
+	}
+
+	// Other resources take the default policy, which does not retry them unless it is overridden.
+	_, counts = run(UpdateOptions{host: host, Parallel: 1}, "flaky", "default")
+	assert.Equal(t, map[string]int{"flaky": 3, "default": 1}, counts)
+	_, counts = run(UpdateOptions{host: host, Parallel: 1, RetryPolicy: resource.RetryPolicy{Attempts: 2, Delay: 0.001}},
+		"flaky", "default")
+	assert.Equal(t, map[string]int{"flaky": 3, "default": 2}, counts)
+
+	// Deletions may always be retried, since deleting a resource twice does no harm...
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Destroy, SkipPreview: true}},
+	}
+	snap = p.Run(t, snap)
+	assert.Equal(t, 3, deletes)
+	assert.Len(t, snap.Resources, 0)
+
+	// ...but creations are only retried if the provider reports that its transient failures leave nothing behind.
+	retriesCreates = false
+	_, counts = run(UpdateOptions{host: host, Parallel: 1}, "flaky")
+	assert.Equal(t, map[string]int{"flaky": 1}, counts)
+}
+
+func TestLogResource(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", news, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome, ChangedKeys: []resource.PropertyKey{"foo"}}, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo := "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"foo": resource.NewStringProperty(foo)},
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	patterns, err := resource.ParseURNPatterns([]string{"*::resA"})
+	assert.NoError(t, err)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, LogResources: patterns},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	// expectLogs returns a step that checks that the debug diagnostics about resA, and no other resource, are the
+	// given messages, in order, each of which is given by the strings that it contains.
+	expectLogs := func(expected ...[]string) TestStep {
+		return TestStep{
+			Op: Update,
+			Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+				evts []Event, res result.Result) result.Result {
+
+				var logs []string
+				for _, evt := range evts {
+					if evt.Type != DiagEvent {
+						continue
+					}
+					e := evt.Payload.(DiagEventPayload)
+					if e.Severity == diag.Debug && strings.Contains(e.Message, " step") {
+						assert.Equal(t, resA, e.URN)
+						logs = append(logs, e.Message)
+					}
+				}
+				if assert.Len(t, logs, len(expected)) {
+					for i, parts := range expected {
+						for _, part := range parts {
+							assert.Contains(t, logs[i], part)
+						}
+					}
+				}
+				return res
+			},
+		}
+	}
+
+	p.Steps = []TestStep{expectLogs(
+		[]string{"planned create step"},
+		[]string{"applying create step (provider urn:pulumi:test::test::pulumi:providers:pkgA::default::", "; inputs foo)"},
+		[]string{"create step succeeded after", "(id created-id; outputs foo)"},
+	)}
+	snap := p.Run(t, nil)
+
+	foo = "baz"
+	p.Steps = []TestStep{expectLogs(
+		[]string{"planned update step"},
+		[]string{"applying update step (id created-id; provider ", "; inputs foo; changed inputs foo)"},
+		[]string{"update step succeeded after"},
+	)}
+	p.Run(t, snap)
+}
+
+func TestAutoName(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", news, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					var replaceKeys []resource.PropertyKey
+					if !olds["zone"].DeepEquals(news["zone"]) {
+						replaceKeys = []resource.PropertyKey{"zone"}
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: replaceKeys}, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					return plugin.ReadResult{Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{
+		"foo":  resource.NewStringProperty("bar"),
+		"zone": resource.NewStringProperty("a"),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs:   inputs,
+			AutoName: "name",
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	nameOf := func(snap *deploy.Snapshot) string {
+		for _, res := range snap.Resources {
+			if res.URN == resA {
+				assert.Equal(t, res.Inputs["name"], res.Outputs["name"])
+				return res.Inputs["name"].StringValue()
+			}
+		}
+		assert.Fail(t, "resA is not in the snapshot")
+		return ""
+	}
+
+	// The resource is created with a name made from its name and a random suffix.
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	name := nameOf(snap)
+	assert.True(t, strings.HasPrefix(name, "resA-"))
+	assert.Len(t, name, len("resA-")+8)
+
+	// An unrelated update and a refresh keep the name.
+	inputs["foo"] = resource.NewStringProperty("baz")
+	snap = p.Run(t, snap)
+	assert.Equal(t, name, nameOf(snap))
+
+	p.Steps = []TestStep{{Op: Refresh}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, name, nameOf(snap))
+
+	// A replacement gets a new name.
+	inputs["zone"] = resource.NewStringProperty("b")
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	replacementName := nameOf(snap)
+	assert.True(t, strings.HasPrefix(replacementName, "resA-"))
+	assert.NotEqual(t, name, replacementName)
+
+	// A name that the program sets takes precedence.
+	inputs["name"] = resource.NewStringProperty("my-resource")
+	snap = p.Run(t, snap)
+	assert.Equal(t, "my-resource", nameOf(snap))
+}
+
+func TestRefreshReadParallel(t *testing.T) {
+	var lock sync.Mutex
+	var reading, maxReading, reads int
+	failing := map[string]bool{}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					lock.Lock()
+					reading, reads = reading+1, reads+1
+					if reading > maxReading {
+						maxReading = reading
+					}
+					fail := failing[string(urn.Name())]
+					lock.Unlock()
+
+					time.Sleep(100 * time.Millisecond)
+
+					lock.Lock()
+					reading--
+					lock.Unlock()
+
+					if fail {
+						return plugin.ReadResult{}, resource.StatusUnknown, errors.New("read failed")
+					}
+					return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	names := []string{"resA", "resB", "resC"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range names {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// The reads of a refresh use the read parallelism rather than the degree of parallelism.
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Parallel: 1, ReadParallel: len(names)},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	p.Steps = []TestStep{{Op: Refresh, SkipPreview: true}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, len(names), reads)
+	assert.Equal(t, len(names), maxReading)
+
+	// Reads that fail do not stop the others, and their failures are reported together.
+	failing["resA"], failing["resB"] = true, true
+	reads, maxReading = 0, 0
+	p.Steps = []TestStep{{
+		Op:            Refresh,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			var summaries []string
+			for _, evt := range evts {
+				if evt.Type != DiagEvent {
+					continue
+				}
+				e := evt.Payload.(DiagEventPayload)
+				if e.Severity == diag.Error && strings.Contains(e.Message, "could not be refreshed") {
+					summaries = append(summaries, e.Message)
+				}
+			}
+			if assert.Len(t, summaries, 1) {
+				assert.Contains(t, summaries[0], "2 resource(s) could not be refreshed")
+				assert.Contains(t, summaries[0], string(p.NewURN("pkgA:m:typA", "resA", ""))+": read failed")
+				assert.Contains(t, summaries[0], string(p.NewURN("pkgA:m:typA", "resB", ""))+": read failed")
+			}
+			return res
+		},
+	}}
+	p.Run(t, snap)
+	assert.Equal(t, len(names), reads)
+}
+
+func TestResourceHistory(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", news, resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap, timeout float64,
+					ignoreChanges []string) (resource.PropertyMap, resource.Status, error) {
+
+					return news, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					var replaceKeys []resource.PropertyKey
+					if !olds["zone"].DeepEquals(news["zone"]) {
+						replaceKeys = []resource.PropertyKey{"zone"}
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: replaceKeys}, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+					return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{
+		"foo":  resource.NewStringProperty("bar"),
+		"zone": resource.NewStringProperty("a"),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	historyOf := func(snap *deploy.Snapshot) []string {
+		for _, res := range snap.Resources {
+			if res.URN == resA {
+				var ops []string
+				for _, event := range res.History {
+					assert.False(t, event.Time.IsZero())
+					ops = append(ops, event.Op+"@"+event.Operation)
+				}
+				return ops
+			}
+		}
+		assert.Fail(t, "resA is not in the snapshot")
+		return nil
+	}
+
+	// Each event records the ID of the update that made it.
+	p.Options.UpdateID = "1"
+	snap := p.Run(t, nil)
+	assert.Equal(t, []string{"create@1"}, historyOf(snap))
+
+	// Updates that change nothing, and refreshes, keep the history as it is.
+	p.Options.UpdateID = "2"
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"create@1"}, historyOf(snap))
+	p.Steps = []TestStep{{Op: Refresh}}
+	p.Options.UpdateID = "3"
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"create@1"}, historyOf(snap))
+
+	p.Steps = []TestStep{{Op: Update}}
+	inputs["foo"] = resource.NewStringProperty("baz")
+	p.Options.UpdateID = "4"
+	snap = p.Run(t, snap)
+	inputs["zone"] = resource.NewStringProperty("b")
+	p.Options.UpdateID = "5"
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"create@1", "update@4", "replace@5"}, historyOf(snap))
+
+	// The stack's configuration limits the length of the history.
+	p.Config = config.Map{deploy.ResourceHistoryConfigKey: config.NewValue("2")}
+	inputs["foo"] = resource.NewStringProperty("qux")
+	p.Options.UpdateID = "6"
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"replace@5", "update@6"}, historyOf(snap))
+
+	p.Config = config.Map{deploy.ResourceHistoryConfigKey: config.NewValue("0")}
+	inputs["foo"] = resource.NewStringProperty("quux")
+	snap = p.Run(t, snap)
+	assert.Empty(t, historyOf(snap))
+}
+
+func TestContextDiagSink(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					return nil, []plugin.CheckFailure{{Property: "foo", Reason: "must be bar"}}, nil
+				},
+			}, nil
+		}),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"foo": resource.NewStringProperty("baz")},
+		})
+		assert.Error(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{}
+	project, target := p.GetProject(), p.GetTarget(nil)
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	var lock sync.Mutex
+	var delivered []diag.Diagnostic
+	events := make(chan Event)
+	var eventsDone sync.WaitGroup
+	var errorEvents int
+	eventsDone.Add(1)
+	go func() {
+		defer eventsDone.Done()
+		for e := range events {
+			if e.Type == DiagEvent && e.Payload.(DiagEventPayload).Severity == diag.Error {
+				errorEvents++
+			}
+		}
+	}()
+
+	cancelCtx, _ := cancel.NewContext(context.Background())
+	ctx := &Context{
+		Cancel: cancelCtx,
+		Events: events,
+		Diag: diag.CallbackSink(func(d diag.Diagnostic) {
+			lock.Lock()
+			defer lock.Unlock()
+			delivered = append(delivered, d)
+		}),
+	}
+	_, res := Update(&updateInfo{project: project, target: target}, ctx, UpdateOptions{host: host}, true)
+	assert.NotNil(t, res)
+	close(events)
+	eventsDone.Wait()
+
+	// The diagnostics are delivered to the context's sink rather than emitted as events.
+	assert.Equal(t, 0, errorEvents)
+	var found bool
+	for _, d := range delivered {
+		if d.Severity == diag.Error && d.URN == resA {
+			found = true
+			assert.Contains(t, d.Message, "must be bar")
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestBatchCreate(t *testing.T) {
+	var lock sync.Mutex
+	var batches [][]string
+	var creates []string
+	batching, batchErr := true, error(nil)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{BatchCreate: batching}
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					lock.Lock()
+					creates = append(creates, string(urn.Name()))
+					lock.Unlock()
+					return resource.ID(urn.Name() + "-id"), news, resource.StatusOK, nil
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.BatchCreateFunction {
+						return nil, nil, errors.New("unknown function")
+					}
+					assert.True(t, batching)
+					assert.Equal(t, "pkgA:m:typA", args["type"].StringValue())
+
+					var names []string
+					var results []resource.PropertyValue
+					for _, v := range args["resources"].ArrayValue() {
+						urn := resource.URN(v.ObjectValue()["urn"].StringValue())
+						name := string(urn.Name())
+						names = append(names, name)
+						result := resource.PropertyMap{
+							"id":      resource.NewStringProperty(name + "-id"),
+							"outputs": v.ObjectValue()["news"],
+						}
+						switch name {
+						case "resC":
+							result = resource.PropertyMap{"error": resource.NewStringProperty("create failed")}
+						case "resD":
+							result["error"] = resource.NewStringProperty("init failed")
+						}
+						results = append(results, resource.NewObjectProperty(result))
+					}
+					lock.Lock()
+					sort.Strings(names)
+					batches = append(batches, names)
+					lock.Unlock()
+					if batchErr != nil {
+						return nil, nil, batchErr
+					}
+					return resource.PropertyMap{"results": resource.NewArrayProperty(results)}, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	names := []string{"resA", "resB", "resC", "resD"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var wg sync.WaitGroup
+		for _, name := range names {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				_, _, _, _ = monitor.RegisterResource("pkgA:m:typA", name, true)
+			}(name)
+		}
+		wg.Wait()
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// Creations of the same type are sent to the provider together, and the failure of one resource in the batch does
+	// not fail the others.
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Parallel: 10, BatchSize: len(names)},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, [][]string{names}, batches)
+	assert.Empty(t, creates)
+
+	states := map[string]*resource.State{}
+	for _, res := range snap.Resources {
+		states[string(res.URN.Name())] = res
+	}
+	assert.Equal(t, resource.ID("resA-id"), states["resA"].ID)
+	assert.Equal(t, resource.ID("resB-id"), states["resB"].ID)
+	assert.NotContains(t, states, "resC")
+	if assert.Contains(t, states, "resD") {
+		assert.Equal(t, resource.ID("resD-id"), states["resD"].ID)
+		assert.Equal(t, []string{"init failed"}, states["resD"].InitErrors)
+	}
+
+	// If the provider fails the batch as a whole, every creation in it fails, and none is retried individually, as
+	// that could create a resource twice.
+	batchErr, batches = errors.New("batch failed"), nil
+	snap = p.Run(t, nil)
+	assert.Equal(t, [][]string{names}, batches)
+	assert.Empty(t, creates)
+	for _, res := range snap.Resources {
+		assert.True(t, providers.IsProviderType(res.Type))
+	}
+
+	// Providers that do not report that they batch creations create each resource individually.
+	batching, batchErr, batches = false, nil, nil
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true}}
+	snap = p.Run(t, nil)
+	assert.Empty(t, batches)
+	sort.Strings(creates)
+	assert.Equal(t, names, creates)
+	assert.Len(t, snap.Resources, len(names)+1)
+}
+
+func TestSlices(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	value, withB, withC, withE := "1", true, false, false
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := func(tags resource.PropertyMap) resource.PropertyMap {
+			m := resource.PropertyMap{"foo": resource.NewStringProperty(value)}
+			if tags != nil {
+				m["tags"] = resource.NewObjectProperty(tags)
+			}
+			return m
+		}
+
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs(nil),
+		})
+		assert.NoError(t, err)
+		deps := []resource.URN{urnA}
+		if withE {
+			urnE, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resE", true, deploytest.ResourceOptions{
+				Inputs: inputs(nil),
+			})
+			assert.NoError(t, err)
+			deps = append(deps, urnE)
+		}
+		if withB {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+				Inputs:       inputs(nil),
+				Dependencies: deps,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if withC {
+			_, _, _, err = monitor.RegisterResource("pkgA:n:typC", "resC", true, deploytest.ResourceOptions{
+				Inputs: inputs(resource.PropertyMap{"team": resource.NewStringProperty("web")}),
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	sliceB := deploy.Slice{Name: "b", Resources: []resource.URNPattern{mustParseURNPattern(t, "*::resB")}}
+	sliceWeb := deploy.Slice{Name: "web", Tags: map[string]string{"team": "web"}}
+	sliceN := deploy.Slice{Name: "n", Modules: []tokens.Module{"pkgA:n"}}
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	inputsOf := func(snap *deploy.Snapshot) map[string]string {
+		values := map[string]string{}
+		for _, res := range snap.Resources {
+			if !providers.IsProviderType(res.Type) {
+				values[string(res.URN.Name())] = res.Inputs["foo"].StringValue()
+			}
+		}
+		return values
+	}
+
+	// Only the resources in the slice change. The resources outside of it keep their state, those that do not exist
+	// are not created, and the slice's dependencies on the resources outside of it are reported.
+	value, withC = "2", true
+	p.Options.Slices = []deploy.Slice{sliceB}
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			var warned bool
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					warned = warned || (e.Severity == diag.Warning &&
+						strings.Contains(e.Message, "depends on resources outside of the slice") &&
+						strings.Contains(e.Message, string(p.NewURN("pkgA:m:typA", "resA", ""))))
+				}
+			}
+			assert.True(t, warned)
+			return res
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resB": "2"}, inputsOf(snap))
+
+	// A slice may select resources by their tags, and several slices may be combined.
+	p.Options.Slices = []deploy.Slice{sliceWeb, sliceN}
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resB": "2", "resC": "2"}, inputsOf(snap))
+
+	// Only the resources in the slice are deleted.
+	withB, withC = false, false
+	p.Options.Slices = []deploy.Slice{sliceN}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resB": "2"}, inputsOf(snap))
+
+	// A resource in the slice may not depend on a resource outside of it that does not exist.
+	withB, withE = true, true
+	p.Options.Slices = []deploy.Slice{sliceB}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resB": "2"}, inputsOf(snap))
+
+	// Unless the missing resources outside of the slice are created. Those that exist still keep their state.
+	value = "3"
+	p.Options.SliceCreateMissing = true
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resB": "3", "resE": "3"}, inputsOf(snap))
+	p.Options.SliceCreateMissing = false
+
+	// A destroy that is restricted to a slice only deletes the resources in the slice.
+	p.Steps = []TestStep{{Op: Destroy}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "1", "resE": "3"}, inputsOf(snap))
+}
+
+func mustParseURNPattern(t *testing.T, pattern string) resource.URNPattern {
+	p, err := resource.ParseURNPattern(pattern)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestProviderDependencies(t *testing.T) {
+	var lock sync.Mutex
+	var created []string
+	var candidates map[string][]string
+	aStarted := make(chan bool)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{ResourceDependencies: true}
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap,
+					timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if urn.Name() == "resA" {
+						close(aStarted)
+						time.Sleep(100 * time.Millisecond)
+					}
+					lock.Lock()
+					created = append(created, string(urn.Name()))
+					lock.Unlock()
+					return resource.ID(urn.Name() + "-id"), news, resource.StatusOK, nil
+				},
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					if tok != deploy.ResourceDependenciesFunction {
+						return nil, nil, errors.New("unknown function")
+					}
+
+					// Each resource depends on every resource that was registered before it, and on one that does
+					// not exist. resB also depends on resC, which is registered after it and depends on it in turn.
+					var names []string
+					var deps []resource.PropertyValue
+					for _, v := range args["resources"].ArrayValue() {
+						urn := v.ObjectValue()["urn"].StringValue()
+						names = append(names, string(resource.URN(urn).Name()))
+						deps = append(deps, resource.NewStringProperty(urn),
+							resource.NewStringProperty(strings.Replace(urn, "::res", "::missing", 1)))
+					}
+					if urn := args["urn"].StringValue(); resource.URN(urn).Name() == "resB" {
+						deps = append(deps, resource.NewStringProperty(strings.Replace(urn, "::resB", "::resC", 1)))
+					}
+					lock.Lock()
+					candidates[string(resource.URN(args["urn"].StringValue()).Name())] = names
+					lock.Unlock()
+					return resource.PropertyMap{"dependencies": resource.NewArrayProperty(deps)}, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		// resB is registered while resA is being created, so that it would be created first if it did not wait for
+		// the dependency that its provider reported.
+		var urnA resource.URN
+		done := make(chan error)
+		go func() {
+			var err error
+			urnA, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true)
+			done <- err
+		}()
+		<-aStarted
+		urnB, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resB", true)
+		if err != nil {
+			return err
+		}
+		if err = <-done; err != nil {
+			return err
+		}
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, deploytest.ResourceOptions{
+			Dependencies: []resource.URN{urnA, urnB},
+		})
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	candidates = map[string][]string{}
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Parallel: 10},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, []string{"resA", "resB", "resC"}, created)
+	assert.Equal(t, map[string][]string{"resB": {"resA"}, "resC": {"resA", "resB"}}, candidates)
+
+	states := map[string]*resource.State{}
+	for _, res := range snap.Resources {
+		states[string(res.URN.Name())] = res
+	}
+	if assert.Contains(t, states, "resB") {
+		// The dependencies on a resource that does not exist and on one that was registered later are ignored.
+		assert.Equal(t, []resource.URN{states["resA"].URN}, states["resB"].Dependencies)
+		assert.Equal(t, []resource.URN{states["resA"].URN}, states["resB"].ProviderDependencies)
+	}
+	if assert.Contains(t, states, "resC") {
+		// Dependencies that the program declared are not recorded as reported by the provider.
+		assert.Equal(t, []resource.URN{states["resA"].URN, states["resB"].URN}, states["resC"].Dependencies)
+		assert.Empty(t, states["resC"].ProviderDependencies)
+	}
+
+	// A refresh keeps the dependencies that the provider reported and the resources' histories.
+	p.Steps = []TestStep{{Op: Refresh, SkipPreview: true}}
+	snap = p.Run(t, snap)
+	for _, res := range snap.Resources {
+		if res.URN.Name() == "resB" {
+			assert.Equal(t, []resource.URN{states["resA"].URN}, res.ProviderDependencies)
+			assert.Equal(t, states["resB"].History, res.History)
+		}
+	}
+
+	// If the strict-resource-dependencies feature is enabled, a dependency that cannot be honored is an error.
+	created, candidates, aStarted = nil, map[string][]string{}, make(chan bool)
+	p.Options.Features = []string{deploy.StrictResourceDependenciesFeature}
+	p.Steps = []TestStep{{
+		Op:            Update,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			var errs []string
+			for _, evt := range evts {
+				if e, ok := evt.Payload.(DiagEventPayload); ok && e.Severity == diag.Error && e.URN != "" {
+					errs = append(errs, string(e.URN.Name())+": "+colors.Never.Colorize(e.Message))
+				}
+			}
+			if assert.Len(t, errs, 1) {
+				assert.Contains(t, errs[0], "resB: the provider reported dependencies on resources that were not "+
+					"registered before this one")
+			}
+			return res
+		},
+	}}
+	snap = p.Run(t, nil)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, "resB", string(res.URN.Name()))
+	}
+}
+
+func TestReplaceOnChanges(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				// The provider can update every property in place.
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error) {
+
+					if olds.DeepEquals(news) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+			}, nil
+		}),
+	}
+
+	name, version := "web", "1"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{
+				"name": resource.NewStringProperty(name),
+				"tags": resource.NewObjectProperty(resource.PropertyMap{
+					"version": resource.NewStringProperty(version),
+				}),
+			},
+			ReplaceOnChanges: []string{"tags.version"},
+		})
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// validate checks that the update performed the given operation on the resource, and whether it reported that the
+	// resource's replaceOnChanges option required it to be replaced.
+	validate := func(op deploy.StepOp, triggered bool) ValidateFunc {
+		return func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			assert.Nil(t, res)
+			var ops []deploy.StepOp
+			for _, entry := range j.Entries {
+				if entry.Step.URN().Type() == "pkgA:m:typA" {
+					ops = append(ops, entry.Step.Op())
+				}
+			}
+			assert.Contains(t, ops, op)
+
+			var reported bool
+			for _, e := range evts {
+				if e.Type == DiagEvent {
+					payload := e.Payload.(DiagEventPayload)
+					reported = reported || strings.Contains(payload.Message, "replaceOnChanges option lists the "+
+						"following changed properties: tags.version")
+				}
+			}
+			assert.Equal(t, triggered, reported)
+			return res
+		}
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	// A change to a property that is not listed is an in-place update.
+	name = "api"
+	p.Steps = []TestStep{{Op: Update, Validate: validate(deploy.OpUpdate, false)}}
+	snap = p.Run(t, snap)
+
+	// A change to a listed property replaces the resource, although its provider could update it in place.
+	version = "2"
+	p.Steps = []TestStep{{Op: Update, Validate: validate(deploy.OpReplace, true)}}
+	p.Run(t, snap)
+}
+
+func TestTargetReplace(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	value := "1"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.PropertyMap{"foo": resource.NewStringProperty(value)}
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Inputs:       inputs,
+			Dependencies: []resource.URN{urnA},
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	urnA, urnB := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resB", "")
+
+	inputsOf := func(snap *deploy.Snapshot) map[string]string {
+		values := map[string]string{}
+		for _, res := range snap.Resources {
+			if !providers.IsProviderType(res.Type) {
+				values[string(res.URN.Name())] = res.Inputs["foo"].StringValue()
+			}
+		}
+		return values
+	}
+
+	// validate checks that the update replaced exactly the given resources, and whether it warned that resB, which
+	// depends on resA, keeps its current state.
+	validate := func(replaced []string, warned bool) ValidateFunc {
+		return func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			assert.Nil(t, res)
+			names := map[string]bool{}
+			for _, entry := range j.Entries {
+				if entry.Step.Op() == deploy.OpReplace {
+					names[string(entry.Step.URN().Name())] = true
+				}
+			}
+			assert.Len(t, names, len(replaced))
+			for _, name := range replaced {
+				assert.True(t, names[name])
+			}
+
+			var reported bool
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					reported = reported || (e.Severity == diag.Warning &&
+						strings.Contains(e.Message, "are not targets of --target-replace") &&
+						strings.Contains(e.Message, string(urnB)))
+				}
+			}
+			assert.Equal(t, warned, reported)
+			return res
+		}
+	}
+
+	// Only the target is replaced, although nothing about it has changed. The other resources keep their state, and
+	// the resource that depends on the target is reported.
+	value = "2"
+	p.Options.ReplaceTargets = []resource.URN{urnA}
+	p.Steps = []TestStep{{Op: Update, Validate: validate([]string{"resA"}, true)}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "2", "resB": "1", "resC": "1"}, inputsOf(snap))
+
+	// A target and its dependent may be replaced together.
+	p.Options.ReplaceTargets = []resource.URN{urnA, urnB}
+	p.Steps = []TestStep{{Op: Update, Validate: validate([]string{"resA", "resB"}, false)}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "2", "resB": "2", "resC": "1"}, inputsOf(snap))
+
+	// The target's dependents may be included, too, in which case they are updated rather than replaced, and are
+	// reported as having been included.
+	value = "3"
+	p.Options.ReplaceTargets, p.Options.TargetDependents = []resource.URN{urnA}, true
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal,
+			evts []Event, res result.Result) result.Result {
+
+			res = validate([]string{"resA"}, false)(project, target, j, evts, res)
+			var included bool
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					included = included || (e.URN == urnB &&
+						strings.Contains(e.Message, "included because it depends on a target of --target-replace"))
+				}
+			}
+			assert.True(t, included)
+			return res
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, map[string]string{"resA": "3", "resB": "3", "resC": "1"}, inputsOf(snap))
+
+	// A target must exist.
+	p.Options.TargetDependents = false
+	p.Options.ReplaceTargets = []resource.URN{p.NewURN("pkgA:m:typA", "resD", "")}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, snap)
+}
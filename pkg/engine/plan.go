@@ -16,17 +16,23 @@ package engine
 
 import (
 	"context"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/mock"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/fsutil"
+	"github.com/pulumi/pulumi/pkg/util/logging"
 	"github.com/pulumi/pulumi/pkg/util/result"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -98,6 +104,12 @@ type planOptions struct {
 	// true if we're planning a refresh.
 	isRefresh bool
 
+	// true if we're planning a destroy.
+	isDestroy bool
+
+	// if non-nil, the resources to refresh in order to continue an interrupted update.
+	continueTargets map[resource.URN]bool
+
 	// true if we should trust the dependency graph reported by the language host. Not all Pulumi-supported languages
 	// correctly report their dependencies, in which case this will be false.
 	trustDependencies bool
@@ -125,6 +137,21 @@ func plan(ctx *Context, info *planContext, opts planOptions, dryRun bool) (*plan
 	if err != nil {
 		return nil, err
 	}
+	if opts.Mocks != nil {
+		plugctx.Host = mock.NewHost(plugctx.Host, opts.Mocks)
+	}
+	if opts.ProviderDryRun {
+		plugctx.ProviderDryRun = true
+		opts.Diag.Warningf(diag.Message("", "resource providers are running in dry-run mode: they do not call cloud "+
+			"APIs, and the stack's state is not saved"))
+	}
+
+	// Let the user know about any feature flags that won't have any effect.
+	for _, f := range opts.Features {
+		if !deploy.IsKnownFeature(f) {
+			opts.Diag.Warningf(diag.GetUnknownFeatureFlagWarning(""), f)
+		}
+	}
 
 	opts.trustDependencies = proj.TrustResourceDependencies()
 	// Now create the state source.  This may issue an error if it can't create the source.  This entails,
@@ -148,12 +175,41 @@ func plan(ctx *Context, info *planContext, opts planOptions, dryRun bool) (*plan
 		analyzers = append(analyzers, tokens.QName(a))
 	}
 
+	// If we are continuing an interrupted update, reconcile the operations that were pending when it was interrupted.
+	// The resources whose updates or deletes may not have completed are refreshed before the plan executes. The other
+	// operations cannot be reconciled, so they remain pending in the snapshots written during the update until it
+	// succeeds, and an update that fails may be continued again.
+	var unreconciled []resource.Operation
+	if opts.Continue {
+		if target.Snapshot == nil || len(target.Snapshot.PendingOperations) == 0 {
+			opts.Diag.Infof(diag.Message("", "there is no interrupted update to continue"))
+		} else {
+			var refresh map[resource.URN]bool
+			refresh, unreconciled = deploy.ContinuePendingOperations(target.Snapshot)
+			for _, op := range unreconciled {
+				if op.Type == resource.OperationTypeCreating {
+					opts.Diag.Warningf(diag.Message(op.Resource.URN, "the create of this resource was interrupted; "+
+						"if the resource was created, it is not managed by the stack, and must be deleted or imported"))
+				}
+			}
+			if !opts.Refresh {
+				opts.continueTargets = refresh
+			}
+			if !dryRun {
+				target.Snapshot.PendingOperations = nil
+			}
+		}
+	}
+
 	// Generate a plan; this API handles all interesting cases (create, update, delete).
 	plan, err := deploy.NewPlan(plugctx, target, target.Snapshot, source, analyzers, dryRun, ctx.BackendClient)
 	if err != nil {
 		contract.IgnoreClose(plugctx)
 		return nil, err
 	}
+	if !dryRun && len(unreconciled) > 0 {
+		target.Snapshot.PendingOperations = unreconciled
+	}
 	return &planResult{
 		Ctx:     info,
 		Plugctx: plugctx,
@@ -179,29 +235,115 @@ func (planResult *planResult) Chdir() (func(), error) {
 // resulting Snapshot, no matter whether an error occurs or not; an error, if something went wrong; the step that
 // failed, if the error is non-nil; and finally the state of the resource modified in the failing step.
 func (planResult *planResult) Walk(cancelCtx *Context, events deploy.Events, preview bool) result.Result {
+	providerParallel, err := providerParallelism(planResult.Plan.Target(), planResult.Options.ProviderParallel)
+	if err != nil {
+		return result.FromError(err)
+	}
+	resourceHistory, err := resourceHistoryLength(planResult.Plan.Target())
+	if err != nil {
+		return result.FromError(err)
+	}
+	resourceTimeouts, err := resourceTimeouts(planResult.Plan.Target(), planResult.Options.ResourceTimeouts)
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	var deleteTargets map[resource.URN]bool
+	if planResult.Options.isDestroy && len(planResult.Options.DestroyTargets) > 0 {
+		deleteTargets = make(map[resource.URN]bool)
+		for _, urn := range planResult.Options.DestroyTargets {
+			deleteTargets[urn] = true
+		}
+	}
+
+	// A plan that is restricted to slices of the stack may only delete the resources in those slices.
+	if len(planResult.Options.Slices) > 0 {
+		sliced := deploy.SliceResources(planResult.Plan.Target().Snapshot, planResult.Options.Slices)
+		if deleteTargets == nil {
+			deleteTargets = sliced
+		} else {
+			for urn := range deleteTargets {
+				if !sliced[urn] {
+					delete(deleteTargets, urn)
+				}
+			}
+		}
+	}
+
+	// An import must not delete any of the resources that the stack already manages, and nor may an update that only
+	// replaces its targets, other than the resources that it replaces.
+	isImport := len(planResult.Options.Imports) > 0
+	if isImport {
+		deleteTargets = map[resource.URN]bool{}
+	}
+	var replaceTargets, dependentTargets map[resource.URN]bool
+	if len(planResult.Options.ReplaceTargets) > 0 {
+		snap := planResult.Plan.Target().Snapshot
+		replaceTargets, err = deploy.ReplaceTargetsOf(snap, planResult.Options.ReplaceTargets)
+		if err != nil {
+			return result.FromError(err)
+		}
+		if planResult.Options.TargetDependents {
+			dependentTargets = deploy.ReplaceTargetDependents(snap, replaceTargets)
+		}
+		deleteTargets = map[resource.URN]bool{}
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
 	done := make(chan bool)
 	var walkResult result.Result
 	go func() {
+		features := make(map[string]bool)
+		for _, f := range planResult.Options.Features {
+			features[f] = true
+		}
+
 		opts := deploy.Options{
-			Events:            events,
-			Parallel:          planResult.Options.Parallel,
-			Refresh:           planResult.Options.Refresh,
-			RefreshOnly:       planResult.Options.isRefresh,
-			TrustDependencies: planResult.Options.trustDependencies,
-			UseLegacyDiff:     planResult.Options.UseLegacyDiff,
+			Events:              events,
+			Parallel:            planResult.Options.Parallel,
+			ReadParallel:        planResult.Options.ReadParallel,
+			BatchSize:           planResult.Options.BatchSize,
+			Refresh:             planResult.Options.Refresh,
+			RefreshTargets:      planResult.Options.continueTargets,
+			RefreshOnly:         planResult.Options.isRefresh,
+			TrustDependencies:   planResult.Options.trustDependencies,
+			UseLegacyDiff:       planResult.Options.UseLegacyDiff,
+			ExplainSames:        planResult.Options.ExplainSames,
+			LogResources:        planResult.Options.LogResources,
+			RetainProtected:     planResult.Options.isDestroy && !planResult.Options.FailOnProtected,
+			ValidateOnly:        planResult.Options.ValidateOnly,
+			DisallowReplace:     planResult.Options.DisallowReplace,
+			DeprecationErrors:   planResult.Options.DeprecationErrors,
+			ProviderParallel:    providerParallel,
+			Slices:              planResult.Options.Slices,
+			SliceCreateMissing:  planResult.Options.SliceCreateMissing,
+			ReplaceTargets:      replaceTargets,
+			DependentTargets:    dependentTargets,
+			DeleteTargets:       deleteTargets,
+			ResourceTimeout:     planResult.Options.ResourceTimeout,
+			ResourceTimeouts:    resourceTimeouts,
+			RetryPolicy:         planResult.Options.RetryPolicy,
+			ResourceHistory:     resourceHistory,
+			UpdateID:            planResult.Options.UpdateID,
+			ContinueOnError:     (planResult.Options.isDestroy && planResult.Options.ContinueOnError) || isImport,
+			AdoptImportedInputs: isImport,
+			Features:            features,
 		}
 		walkResult = planResult.Plan.Execute(ctx, opts, preview)
 		close(done)
 	}()
 
-	// Asynchronously listen for cancellation, and deliver that signal to plan.
+	// Asynchronously listen for cancellation, and deliver that signal to plan. Reaching the maximum number of errors
+	// also cancels the plan, so that a badly broken program does not bury its first errors.
+	limitReached := errorLimitReached(planResult.Options.Diag)
 	go func() {
 		select {
 		case <-cancelCtx.Cancel.Canceled():
 			// Cancel the plan's execution context, so it begins to shut down.
 			cancelFunc()
+		case <-limitReached:
+			cancelFunc()
 		case <-done:
 			return
 		}
@@ -212,7 +354,14 @@ func (planResult *planResult) Walk(cancelCtx *Context, events deploy.Events, pre
 		return result.WrapIfNonNil(cancelCtx.Cancel.TerminateErr())
 
 	case <-done:
-		return walkResult
+		// The plan does not consider being stopped by the error limit a failure, but the errors that were reported
+		// mean that it has failed.
+		select {
+		case <-limitReached:
+			return result.Bail()
+		default:
+			return walkResult
+		}
 	}
 }
 
@@ -234,9 +383,20 @@ func printPlan(ctx *Context, planResult *planResult, dryRun bool) (ResourceChang
 		return nil, result.Error("an error occurred while advancing the preview")
 	}
 
+	// If requested, estimate the change in cost of the changed resources.
+	var cost *CostEstimate
+	if estimator := planResult.Options.CostEstimator; estimator != "" {
+		estimate, err := estimateCost(planResult.Plugctx.Host, estimator, actions.Changed)
+		if err != nil {
+			planResult.Options.Diag.Warningf(diag.Message("", "could not estimate the cost of this update: %v"), err)
+		} else {
+			cost = estimate
+		}
+	}
+
 	// Emit an event with a summary of operation counts.
 	changes := ResourceChanges(actions.Ops)
-	planResult.Options.Events.previewSummaryEvent(changes)
+	planResult.Options.Events.previewSummaryEvent(changes, cost)
 	return changes, nil
 }
 
@@ -244,6 +404,7 @@ type planActions struct {
 	Ops     map[deploy.StepOp]int
 	Opts    planOptions
 	Seen    map[resource.URN]deploy.Step
+	Changed []deploy.Step // the steps whose resources should be passed to a cost estimator, if any.
 	MapLock sync.Mutex
 }
 
@@ -270,6 +431,12 @@ func (acts *planActions) OnResourceStepPre(step deploy.Step) (interface{}, error
 		return nil, nil
 	}
 
+	if acts.Opts.CostEstimator != "" && shouldEstimateCost(step) {
+		acts.MapLock.Lock()
+		acts.Changed = append(acts.Changed, step)
+		acts.MapLock.Unlock()
+	}
+
 	acts.Opts.Events.resourcePreEvent(step, true /*planning*/, acts.Opts.Debug)
 
 	return nil, nil
@@ -357,3 +524,95 @@ func assertSeen(seen map[resource.URN]deploy.Step, step deploy.Step) {
 func isDefaultProviderStep(step deploy.Step) bool {
 	return providers.IsDefaultProvider(step.URN())
 }
+
+// resourceHistoryLength returns the number of lifecycle events to keep in the history of each resource of the given
+// target, as deploy.Options.ResourceHistory expects it.
+func resourceHistoryLength(target *deploy.Target) (int, error) {
+	if target == nil {
+		return 0, nil
+	}
+	v, has := target.Config[deploy.ResourceHistoryConfigKey]
+	if !has {
+		return 0, nil
+	}
+	s, err := v.Value(target.Decrypter)
+	if err != nil {
+		return 0, errors.Wrapf(err, "reading configuration key '%v'", deploy.ResourceHistoryConfigKey)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("configuration key '%v' must be a non-negative integer, but is %q",
+			deploy.ResourceHistoryConfigKey, s)
+	}
+	if n == 0 {
+		return -1, nil
+	}
+	return n, nil
+}
+
+// resourceTimeouts returns the time limits that override those of resource operations in a plan against the given
+// target: first those in the target's configuration, and then those in specs, which take precedence.
+func resourceTimeouts(target *deploy.Target, specs []string) ([]deploy.ResourceTimeout, error) {
+	var timeouts []deploy.ResourceTimeout
+	if target != nil {
+		if v, has := target.Config[deploy.ResourceTimeoutsConfigKey]; has {
+			s, err := v.Value(target.Decrypter)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading configuration key '%v'", deploy.ResourceTimeoutsConfigKey)
+			}
+			configured, err := deploy.ParseResourceTimeouts(strings.Split(s, ","), deploy.TimeoutSourceConfig)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading configuration key '%v'", deploy.ResourceTimeoutsConfigKey)
+			}
+			timeouts = append(timeouts, configured...)
+		}
+	}
+
+	requested, err := deploy.ParseResourceTimeouts(specs, deploy.TimeoutSourceFlag)
+	if err != nil {
+		return nil, err
+	}
+	return append(timeouts, requested...), nil
+}
+
+// providerParallelism returns the per-provider parallelism limits for a plan against the given target, combining the
+// limits in the target's configuration with those in specs, which take precedence.
+func providerParallelism(target *deploy.Target, specs []string) (map[tokens.Package]int, error) {
+	limits := make(map[tokens.Package]int)
+	if target != nil {
+		if v, has := target.Config[deploy.ProviderParallelismConfigKey]; has {
+			s, err := v.Value(target.Decrypter)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading configuration key '%v'", deploy.ProviderParallelismConfigKey)
+			}
+			configured, err := deploy.ParseProviderParallelism(strings.Split(s, ","))
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading configuration key '%v'", deploy.ProviderParallelismConfigKey)
+			}
+			for pkg, n := range configured {
+				limits[pkg] = n
+			}
+		}
+	}
+
+	requested, err := deploy.ParseProviderParallelism(specs)
+	if err != nil {
+		return nil, err
+	}
+	for pkg, n := range requested {
+		limits[pkg] = n
+	}
+
+	if logging.V(4) {
+		pkgs := make([]string, 0, len(limits))
+		for pkg := range limits {
+			pkgs = append(pkgs, string(pkg))
+		}
+		sort.Strings(pkgs)
+		for _, pkg := range pkgs {
+			logging.V(4).Infof("limiting concurrent resource operations for provider '%v' to %d",
+				pkg, limits[tokens.Package(pkg)])
+		}
+	}
+	return limits, nil
+}
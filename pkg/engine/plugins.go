@@ -15,6 +15,7 @@
 package engine
 
 import (
+	"path/filepath"
 	"sort"
 
 	"github.com/blang/semver"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/mock"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -96,6 +98,10 @@ func gatherPluginsFromSnapshot(plugctx *plugin.Context, target *deploy.Target) (
 		logging.V(preparePluginLog).Infof("gatherPluginsFromSnapshot(): no snapshot available, skipping")
 		return set, nil
 	}
+	if mock.IsMocked(plugctx.Host) {
+		logging.V(preparePluginLog).Infof("gatherPluginsFromSnapshot(): resource providers are mocked, skipping")
+		return set, nil
+	}
 	for _, res := range target.Snapshot.Resources {
 		urn := res.URN
 		if !providers.IsProviderType(urn.Type()) {
@@ -118,6 +124,25 @@ func gatherPluginsFromSnapshot(plugctx *plugin.Context, target *deploy.Target) (
 	return set, nil
 }
 
+// verifyPluginLock ensures that each of the given plugins that is recorded in the plugin lock file of the project
+// containing pwd, if there is one, matches the checksum that the lock file records.
+func verifyPluginLock(pwd string, plugins pluginSet) error {
+	projPath, err := workspace.DetectProjectPathFrom(pwd)
+	if err != nil || projPath == "" {
+		logging.V(preparePluginLog).Infof("verifyPluginLock(): no project found, skipping")
+		return nil
+	}
+
+	lock, err := workspace.LoadPluginLock(filepath.Join(filepath.Dir(projPath), workspace.PluginLockFile))
+	if err != nil {
+		return err
+	} else if lock == nil {
+		logging.V(preparePluginLog).Infof("verifyPluginLock(): no plugin lock file, skipping")
+		return nil
+	}
+	return lock.Verify(plugins.Values())
+}
+
 // ensurePluginsAreInstalled inspects all plugins in the plugin set and, if any plugins are not currently installed,
 // uses the given backend client to install them. Installations are processed in parallel, though
 // ensurePluginsAreInstalled does not return until all installations are completed.
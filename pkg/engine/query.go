@@ -62,7 +62,7 @@ func Query(ctx *Context, u UpdateInfo, opts UpdateOptions) result.Result {
 
 	// First, load the package metadata and the deployment target in preparation for executing the package's program
 	// and creating resources.  This includes fetching its pwd and main overrides.
-	diag := newEventSink(emitter, false)
+	diag := ctx.diagSink(emitter)
 	statusDiag := newEventSink(emitter, true)
 
 	proj, target := u.GetProject(), u.GetTarget()
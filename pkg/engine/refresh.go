@@ -47,7 +47,7 @@ func Refresh(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (Resou
 		UpdateOptions: opts,
 		SourceFunc:    newRefreshSource,
 		Events:        emitter,
-		Diag:          newEventSink(emitter, false),
+		Diag:          newErrorLimitSink(ctx.diagSink(emitter), opts.MaxErrors),
 		StatusDiag:    newEventSink(emitter, true),
 		isRefresh:     true,
 	}, dryRun)
@@ -34,6 +34,10 @@ type SnapshotManager interface {
 	// RegisterResourceOutputs registers the set of resource outputs generated by performing the
 	// given step. These outputs are persisted in the snapshot.
 	RegisterResourceOutputs(step deploy.Step) error
+
+	// Flush persists the snapshot as it stands. The engine calls it after it changes the base snapshot
+	// directly, e.g. to drop the pending operations of an interrupted update once it has been continued.
+	Flush() error
 }
 
 // SnapshotMutation represents an outstanding mutation that is yet to be completed. When the engine completes
@@ -43,3 +47,18 @@ type SnapshotMutation interface {
 	// failed to complete.
 	End(step deploy.Step, successful bool) error
 }
+
+// discardSnapshotManager is a SnapshotManager that records nothing. It stands in for the real one during updates whose
+// results do not reflect real resources, so that they are not saved as the stack's state.
+type discardSnapshotManager struct{}
+
+func (discardSnapshotManager) Close() error                                   { return nil }
+func (discardSnapshotManager) Flush() error                                   { return nil }
+func (discardSnapshotManager) RegisterResourceOutputs(step deploy.Step) error { return nil }
+func (discardSnapshotManager) BeginMutation(step deploy.Step) (SnapshotMutation, error) {
+	return discardSnapshotMutation{}, nil
+}
+
+type discardSnapshotMutation struct{}
+
+func (discardSnapshotMutation) End(step deploy.Step, successful bool) error { return nil }
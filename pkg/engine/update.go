@@ -23,6 +23,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/mock"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -56,6 +57,13 @@ type UpdateOptions struct {
 	// the degree of parallelism for resource operations (<=1 for serial).
 	Parallel int
 
+	// the degree of parallelism for the reads of a refresh, or zero to use Parallel.
+	ReadParallel int
+
+	// the maximum number of creations of resources of the same type that are sent to a provider at once, or <=1 to
+	// create each resource individually.
+	BatchSize int
+
 	// true if debugging output it enabled
 	Debug bool
 
@@ -65,6 +73,93 @@ type UpdateOptions struct {
 	// true if the engine should use legacy diffing behavior during an update.
 	UseLegacyDiff bool
 
+	// true if the engine should explain why each unchanged resource is unchanged.
+	ExplainSames bool
+
+	// the patterns of the URNs of the resources whose operations should be logged in detail, as if debugging output
+	// were enabled for those resources alone.
+	LogResources []resource.URNPattern
+
+	// true if a destroy should fail if it encounters protected resources rather than retaining them.
+	FailOnProtected bool
+
+	// true if the update should only check the program's resources rather than computing or applying changes.
+	ValidateOnly bool
+
+	// true if the update should fail rather than replace any resources.
+	DisallowReplace bool
+
+	// true if the update should fail rather than warn when deprecated resource types or properties are used.
+	DeprecationErrors bool
+
+	// the names of the feature flags to enable for this update.
+	Features []string
+
+	// limits on the number of resource operations that may run concurrently against each provider package, each of the
+	// form "<package>=<n>". These override any limits in the stack's configuration.
+	ProviderParallel []string
+
+	// the name of the cost estimator plugin to use to estimate the change in cost of a preview, if any.
+	CostEstimator string
+
+	// the maximum number of errors to report before stopping the update, or zero to report all errors.
+	MaxErrors int
+
+	// the URNs of the only resources that a destroy may delete. If empty, a destroy deletes all of a stack's resources.
+	DestroyTargets []resource.URN
+
+	// the URNs of the resources to replace. If non-empty, an update replaces these resources, whether or not their
+	// inputs have changed, and leaves every other resource as it is.
+	ReplaceTargets []resource.URN
+
+	// true if the resources that depend, directly or indirectly, on the ReplaceTargets may also be updated, as they may
+	// be affected by the replacements. They are only replaced if their changes require it.
+	TargetDependents bool
+
+	// the slices of the stack to which the operation is restricted, if any. Only the resources in the slices are
+	// created, updated, replaced, or deleted.
+	Slices []deploy.Slice
+
+	// true if the resources outside of the Slices that do not exist are created, so that the resources in the slices
+	// that depend on them may be deployed.
+	SliceCreateMissing bool
+
+	// the time limit for each create, update, or delete of a resource that does not declare its own custom timeout
+	// for the operation, or zero for no limit.
+	ResourceTimeout time.Duration
+
+	// time limits for the operations of the resources whose URNs match patterns, each of the form
+	// "<urn pattern>:<operation>=<duration>". These override both the timeouts that resources declare and those in
+	// the stack's configuration.
+	ResourceTimeouts []string
+
+	// the retry policy of the resources that do not declare their own, for operations that fail transiently.
+	RetryPolicy resource.RetryPolicy
+
+	// true if a destroy should continue deleting resources after it fails to delete one.
+	ContinueOnError bool
+
+	// the resources to import into the stack. If non-empty, the update imports these resources, and nothing else,
+	// rather than running the stack's program. The update continues past any resources that fail to import.
+	Imports []deploy.Import
+
+	// if non-nil, the mocks that satisfy every resource provider operation in place of the providers' plugins, which
+	// are neither installed nor loaded. The mocks record the calls that are made to them, and the stack's state is
+	// not saved.
+	Mocks *mock.Mocks
+
+	// true if resource provider plugins are to run in dry-run mode, in which they run their logic but do not call
+	// cloud APIs. Every provider that the update uses must support the mode, and the stack's state is not saved.
+	ProviderDryRun bool
+
+	// true to continue an update that was interrupted, by first refreshing the resources whose operations were pending
+	// when it was interrupted.
+	Continue bool
+
+	// the ID with which the backend records the update in the stack's history, if any. It is recorded in the history
+	// of each resource that the update changes.
+	UpdateID string
+
 	// true if we should report events for steps that involve default providers.
 	reportDefaultProviderSteps bool
 
@@ -102,11 +197,15 @@ func Update(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (Resour
 	if err != nil {
 		return nil, result.FromError(err)
 	}
+	sourceFunc := newUpdateSource
+	if len(opts.Imports) > 0 {
+		sourceFunc = newImportSource
+	}
 	return update(ctx, info, planOptions{
 		UpdateOptions: opts,
-		SourceFunc:    newUpdateSource,
+		SourceFunc:    sourceFunc,
 		Events:        emitter,
-		Diag:          newEventSink(emitter, false),
+		Diag:          newErrorLimitSink(ctx.diagSink(emitter), opts.MaxErrors),
 		StatusDiag:    newEventSink(emitter, true),
 	}, dryRun)
 }
@@ -150,6 +249,11 @@ func installPlugins(
 		logging.V(7).Infof("newUpdateSource(): failed to install missing plugins: %v", err)
 	}
 
+	// If the project locks the plugins it uses, refuse to run any plugins whose contents have changed.
+	if err := verifyPluginLock(pwd, allPlugins); err != nil {
+		return nil, nil, err
+	}
+
 	// Collect the version information for default providers.
 	defaultProviderVersions := computeDefaultProviderPlugins(languagePlugins, allPlugins)
 
@@ -212,6 +316,41 @@ func newUpdateSource(
 	}, defaultProviderVersions, dryRun), nil
 }
 
+func newImportSource(
+	client deploy.BackendClient, opts planOptions, proj *workspace.Project, pwd, main string,
+	target *deploy.Target, plugctx *plugin.Context, dryRun bool) (deploy.Source, error) {
+
+	// Like refresh, an import does not run the program, so it only needs the plugins described in the snapshot. The
+	// plugins for the packages of the resources to import are loaded by the provider registry.
+	plugins, err := gatherPluginsFromSnapshot(plugctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePluginsAreInstalled(plugins); err != nil {
+		logging.V(7).Infof("newImportSource(): failed to install missing plugins: %v", err)
+	}
+
+	// Skip any resources that the stack already manages.
+	existing := make(map[resource.URN]bool)
+	if target.Snapshot != nil {
+		for _, res := range target.Snapshot.Resources {
+			existing[res.URN] = true
+		}
+	}
+	var imports []deploy.Import
+	for _, imp := range opts.Imports {
+		urn := resource.NewURN(target.Name, proj.Name, "", imp.Type, imp.Name)
+		if existing[urn] {
+			opts.Diag.Warningf(diag.Message(urn, "skipping import of %s: the stack already has a resource named %s"),
+				imp.ID, imp.Name)
+			continue
+		}
+		imports = append(imports, imp)
+	}
+
+	return deploy.NewImportSource(proj.Name, target.Name, target, imports), nil
+}
+
 func update(ctx *Context, info *planContext, opts planOptions, dryRun bool) (ResourceChanges, result.Result) {
 	planResult, err := plan(ctx, info, opts, dryRun)
 	if err != nil {
@@ -237,6 +376,14 @@ func update(ctx *Context, info *planContext, opts planOptions, dryRun bool) (Res
 			// Otherwise, we will actually deploy the latest bits.
 			opts.Events.preludeEvent(dryRun, planResult.Ctx.Update.GetTarget().Config)
 
+			// The results of mocks, and of providers that run in dry-run mode, do not describe real resources, so
+			// they are not saved as the stack's state.
+			if opts.Mocks != nil || opts.ProviderDryRun {
+				discard := *ctx
+				discard.SnapshotManager = discardSnapshotManager{}
+				ctx = &discard
+			}
+
 			// Walk the plan, reporting progress and executing the actual operations as we go.
 			start := time.Now()
 			actions := newUpdateActions(ctx, info.Update, opts)
@@ -244,6 +391,17 @@ func update(ctx *Context, info *planContext, opts planOptions, dryRun bool) (Res
 			res = planResult.Walk(ctx, actions, false)
 			resourceChanges = ResourceChanges(actions.Ops)
 
+			// Once an update that continues an interrupted one succeeds, the operations that remained pending from
+			// the interrupted update are dropped.
+			if base := info.Update.GetTarget().Snapshot; res == nil && opts.Continue && base != nil &&
+				len(base.PendingOperations) > 0 {
+
+				base.PendingOperations = nil
+				if err := ctx.SnapshotManager.Flush(); err != nil {
+					res = result.FromError(err)
+				}
+			}
+
 			if len(resourceChanges) != 0 {
 				// Print out the total number of steps performed (and their kinds), the duration, and any summary info.
 				opts.Events.updateSummaryEvent(actions.MaybeCorrupt, time.Since(start), resourceChanges)
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -354,7 +353,7 @@ func (a *Asset) readURI() (*Blob, error) {
 	contract.Assertf(isurl, "Expected a URI-based asset")
 	switch s := url.Scheme; s {
 	case "http", "https":
-		resp, err := httputil.GetWithRetry(url.String(), http.DefaultClient)
+		resp, err := httputil.GetWithRetry(url.String(), httputil.Client(0))
 		if err != nil {
 			return nil, err
 		}
@@ -900,7 +899,7 @@ func (a *Archive) readURI() (ArchiveReader, error) {
 func (a *Archive) openURLStream(url *url.URL) (io.ReadCloser, error) {
 	switch s := url.Scheme; s {
 	case "http", "https":
-		resp, err := httputil.GetWithRetry(url.String(), http.DefaultClient)
+		resp, err := httputil.GetWithRetry(url.String(), httputil.Client(0))
 		if err != nil {
 			return nil, err
 		}
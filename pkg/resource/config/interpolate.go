@@ -0,0 +1,94 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Builtins holds the values of the built-in variables to which configuration values may refer.
+type Builtins struct {
+	Project string // the name of the project, referred to as ${project}.
+	Stack   string // the name of the stack, referred to as ${stack}.
+}
+
+// Interpolate returns the given value with each reference to a built-in variable, of the form ${name}, replaced by
+// the variable's value. A reference may be escaped by doubling its "$": "$${stack}" becomes the literal "${stack}". Any
+// other "$" is left as-is. It is an error to refer to an unknown variable, or to leave a reference unterminated.
+func (b Builtins) Interpolate(v string) (string, error) {
+	if !strings.Contains(v, "${") {
+		return v, nil
+	}
+
+	var result strings.Builder
+	for {
+		start := strings.Index(v, "${")
+		if start == -1 {
+			result.WriteString(v)
+			return result.String(), nil
+		}
+
+		// An escaped reference is written as-is, less its escape.
+		if start > 0 && v[start-1] == '$' {
+			result.WriteString(v[:start-1])
+			result.WriteString("${")
+			v = v[start+2:]
+			continue
+		}
+
+		end := strings.Index(v[start:], "}")
+		if end == -1 {
+			return "", errors.Errorf("unterminated reference %q; write $${ for a literal ${", v[start:])
+		}
+		name := v[start+2 : start+end]
+
+		var value string
+		switch name {
+		case "project":
+			value = b.Project
+		case "stack":
+			value = b.Stack
+		default:
+			return "", errors.Errorf("unknown variable ${%s}; the available variables are ${project} and ${stack}, "+
+				"and $${ may be written for a literal ${", name)
+		}
+
+		result.WriteString(v[:start])
+		result.WriteString(value)
+		v = v[start+end+1:]
+	}
+}
+
+// Interpolate returns a copy of the map in which the references to built-in variables in each value that is not
+// secure are replaced by the variables' values (see Builtins.Interpolate). Secure values, objects, and references are
+// left as-is.
+func (m Map) Interpolate(builtins Builtins) (Map, error) {
+	result := make(Map, len(m))
+	for k, c := range m {
+		if c.Secure() || c.object || c.ref {
+			result[k] = c
+			continue
+		}
+
+		v, err := builtins.Interpolate(c.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "interpolating configuration value %s", k)
+		}
+		result[k] = NewValue(v)
+	}
+	return result, nil
+}
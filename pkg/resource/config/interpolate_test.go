@@ -0,0 +1,87 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	b := Builtins{Project: "web", Stack: "dev"}
+
+	tests := map[string]string{
+		"":                            "",
+		"plain":                       "plain",
+		"${stack}":                    "dev",
+		"${project}-${stack}-db":      "web-dev-db",
+		"$${stack}":                   "${stack}",
+		"a$b$$c":                      "a$b$$c",
+		"$$${stack}":                  "$${stack}",
+		"cost: $5 for ${stack}":       "cost: $5 for dev",
+		"}${stack}}":                  "}dev}",
+		"${stack}$${project}${stack}": "dev${project}dev",
+	}
+	for in, expected := range tests {
+		actual, err := b.Interpolate(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, expected, actual, in)
+	}
+
+	// References to unknown variables, and unterminated references, are errors.
+	failures := map[string]string{
+		"${env}":              "unknown variable ${env}",
+		"${}":                 "unknown variable ${}",
+		"ok ${stack} ${nope}": "unknown variable ${nope}",
+		"${stack":             "unterminated reference \"${stack\"",
+		"a ${stack} ${":       "unterminated reference \"${\"",
+	}
+	for in, expected := range failures {
+		_, err := b.Interpolate(in)
+		if assert.Error(t, err, in) {
+			assert.Contains(t, err.Error(), expected, in)
+		}
+	}
+}
+
+func TestInterpolateMap(t *testing.T) {
+	k1 := MustMakeKey("my", "name")
+	k2 := MustMakeKey("my", "password")
+	m := Map{
+		k1: NewValue("db-${stack}"),
+		k2: NewSecureValue("${stack}"),
+	}
+
+	result, err := m.Interpolate(Builtins{Stack: "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, NewValue("db-prod"), result[k1])
+	assert.Equal(t, NewSecureValue("${stack}"), result[k2])
+
+	// The original map is left as-is.
+	assert.Equal(t, NewValue("db-${stack}"), m[k1])
+
+	// An unknown variable or an unterminated reference in any value is an error that names the value's key.
+	_, err = Map{k1: NewValue("${region}")}.Interpolate(Builtins{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "my:name")
+		assert.Contains(t, err.Error(), "unknown variable ${region}")
+	}
+	_, err = Map{k1: NewValue("db-${stack")}.Interpolate(Builtins{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "my:name")
+		assert.Contains(t, err.Error(), "unterminated reference")
+	}
+}
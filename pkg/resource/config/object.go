@@ -0,0 +1,434 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NewObjectValue returns an object value from the given JSON text of an object or array. Any leaf of the object may be
+// secure, which is written as {"secure": "<ciphertext>"}; only those leaves are encrypted.
+func NewObjectValue(text string) (Value, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return Value{}, errors.Wrap(err, "parsing object")
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return newObjectValue(v)
+	default:
+		return Value{}, errors.New("an object value must be a JSON object or array")
+	}
+}
+
+// NewSecureObjectValue returns an object value from the given JSON text of an object or array, in which every string
+// leaf is encrypted using the encrypter.
+func NewSecureObjectValue(text string, encrypter Encrypter) (Value, error) {
+	object, err := NewObjectValue(text)
+	if err != nil {
+		return Value{}, err
+	}
+	if object.Secure() {
+		return Value{}, errors.New("the object already has secure leaves")
+	}
+	tree, err := object.tree()
+	if err != nil {
+		return Value{}, err
+	}
+	encrypted, err := encryptLeaves(tree, encrypter)
+	if err != nil {
+		return Value{}, err
+	}
+	return newObjectValue(encrypted)
+}
+
+// newObjectValue returns the object value whose tree, as decoded from JSON, is given.
+func newObjectValue(tree interface{}) (Value, error) {
+	if _, err := mapSecureLeaves(tree, nil, func(_ []interface{}, ciphertext string) (interface{}, error) {
+		return ciphertext, nil
+	}); err != nil {
+		return Value{}, err
+	}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{value: string(b), object: true}, nil
+}
+
+// SetPath returns a copy of the object value in which the leaf at the given path, a list of keys separated by dots,
+// is set to the given string value, which may be secure. Objects along the path are created as necessary. The zero
+// Value may be used to create a new object. No key in the path may be "secure", since an object with that key is how
+// a secure leaf is encoded.
+func (c Value) SetPath(path string, leaf Value) (Value, error) {
+	if !c.object && c != (Value{}) {
+		return Value{}, errors.New("the value is not an object")
+	}
+	if leaf.object {
+		return Value{}, errors.New("the leaf of an object must be a string")
+	}
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return Value{}, errors.Errorf("invalid path '%s'", path)
+		}
+		if key == "secure" {
+			return Value{}, errors.Errorf("invalid path '%s'; 'secure' is reserved for the leaves of secrets", path)
+		}
+	}
+
+	root := map[string]interface{}{}
+	if c.object {
+		tree, err := c.tree()
+		if err != nil {
+			return Value{}, err
+		}
+		m, ok := tree.(map[string]interface{})
+		if !ok {
+			return Value{}, errors.New("the value is an array, not an object")
+		}
+		root = m
+	}
+
+	var leafTree interface{} = leaf.value
+	if leaf.secure {
+		leafTree = map[string]interface{}{"secure": leaf.value}
+	}
+	m := root
+	for i, key := range keys[:len(keys)-1] {
+		next, has := m[key]
+		if !has {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		nextMap, ok := next.(map[string]interface{})
+		if _, secure := secureLeaf(next); !ok || secure {
+			return Value{}, errors.Errorf("'%s' is not an object", strings.Join(keys[:i+1], "."))
+		}
+		m = nextMap
+	}
+	m[keys[len(keys)-1]] = leafTree
+	return newObjectValue(root)
+}
+
+// SecretValues returns the plaintexts of the value's secrets, decrypted using the decrypter: its plaintext, if it is a
+// secure value, or those of its secure leaves, if it is an object.
+func (c Value) SecretValues(decrypter Decrypter) ([]string, error) {
+	if !c.object {
+		if !c.secure && !c.ref {
+			return nil, nil
+		}
+		plaintext, err := c.Value(decrypter)
+		if err != nil {
+			return nil, err
+		}
+		return []string{plaintext}, nil
+	}
+
+	var secrets []string
+	_, err := c.mapSecureLeaves(func(_ []interface{}, ciphertext string) (interface{}, error) {
+		plaintext, err := decryptLeaf(decrypter, ciphertext)
+		secrets = append(secrets, plaintext)
+		return plaintext, err
+	})
+	return secrets, err
+}
+
+// Masked returns the value with each of its secrets replaced by "[secret]": the whole of a secure value, or each
+// secure leaf of an object. It does not decrypt anything.
+func (c Value) Masked() string {
+	if !c.object {
+		if c.secure || c.ref {
+			return "[secret]"
+		}
+		return c.value
+	}
+
+	masked, err := c.mapSecureLeaves(func(_ []interface{}, _ string) (interface{}, error) {
+		return "[secret]", nil
+	})
+	if err != nil {
+		return "[secret]"
+	}
+	b, err := json.Marshal(masked)
+	if err != nil {
+		return "[secret]"
+	}
+	return string(b)
+}
+
+// Reencrypt returns a value whose plaintext, as Value would return it, is the given plaintext of this value, with the
+// same parts secret, encrypted using the encrypter: the whole of a secure value, or the same leaves of an object. It
+// returns the value unchanged if it has no secrets, or if it is a reference, whose secret is never saved.
+func (c Value) Reencrypt(plaintext string, encrypter Encrypter) (Value, error) {
+	if c.ref {
+		return Value{value: c.value, ref: true}, nil
+	}
+	if !c.object {
+		if !c.secure {
+			return c, nil
+		}
+		ciphertext, err := encrypter.EncryptValue(plaintext)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewSecureValue(ciphertext), nil
+	}
+
+	var plain interface{}
+	if err := json.Unmarshal([]byte(plaintext), &plain); err != nil {
+		return Value{}, errors.Wrap(err, "parsing object")
+	}
+	tree, err := c.mapSecureLeaves(func(path []interface{}, _ string) (interface{}, error) {
+		leaf, ok := lookupPath(plain, path).(string)
+		if !ok {
+			return nil, errors.Errorf("the plaintext of the object has no string at '%s'", renderPath(path))
+		}
+		ciphertext, err := encrypter.EncryptValue(leaf)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"secure": ciphertext}, nil
+	})
+	if err != nil {
+		return Value{}, err
+	}
+	return newObjectValue(tree)
+}
+
+// objectValue returns the JSON text of the object value, in which each secure leaf is replaced by its plaintext.
+func (c Value) objectValue(decrypter Decrypter) (string, error) {
+	tree, err := c.mapSecureLeaves(func(_ []interface{}, ciphertext string) (interface{}, error) {
+		return decryptLeaf(decrypter, ciphertext)
+	})
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// secretPaths returns the paths of the secure leaves of the object value, such as "db.password" or "users[0].key".
+func (c Value) secretPaths() []string {
+	var paths []string
+	_, err := c.mapSecureLeaves(func(path []interface{}, ciphertext string) (interface{}, error) {
+		paths = append(paths, renderPath(path))
+		return ciphertext, nil
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// tree returns the object value as decoded from its JSON text, in which secure leaves are {"secure": ...} objects.
+func (c Value) tree() (interface{}, error) {
+	var tree interface{}
+	if err := json.Unmarshal([]byte(c.value), &tree); err != nil {
+		return nil, errors.Wrap(err, "parsing object")
+	}
+	return tree, nil
+}
+
+// mapSecureLeaves returns a copy of the tree of the object value in which each secure leaf is replaced by the result
+// of calling f with its path and ciphertext.
+func (c Value) mapSecureLeaves(
+	f func(path []interface{}, ciphertext string) (interface{}, error)) (interface{}, error) {
+
+	tree, err := c.tree()
+	if err != nil {
+		return nil, err
+	}
+	return mapSecureLeaves(tree, nil, f)
+}
+
+// mapSecureLeaves returns a copy of the given tree, at the given path, in which each secure leaf is replaced by the
+// result of calling f with its path and ciphertext. It fails if the tree has anything but objects, arrays, scalars,
+// and well-formed secure leaves.
+func mapSecureLeaves(tree interface{}, path []interface{},
+	f func(path []interface{}, ciphertext string) (interface{}, error)) (interface{}, error) {
+
+	switch v := tree.(type) {
+	case nil, bool, float64, string:
+		return v, nil
+	case map[string]interface{}:
+		if _, has := v["secure"]; has {
+			ciphertext, ok := secureLeaf(v)
+			if !ok {
+				return nil, errors.Errorf("malformed secure data at '%s'", renderPath(path))
+			}
+			return f(path, ciphertext)
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			mapped, err := mapSecureLeaves(elem, append(path[:len(path):len(path)], key), f)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = mapped
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			mapped, err := mapSecureLeaves(elem, append(path[:len(path):len(path)], i), f)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = mapped
+		}
+		return result, nil
+	default:
+		return nil, errors.Errorf("unsupported value of type %T at '%s'", v, renderPath(path))
+	}
+}
+
+// encryptLeaves returns a copy of the given tree, which has no secure leaves, in which each string leaf is replaced by
+// a secure leaf that holds its ciphertext.
+func encryptLeaves(tree interface{}, encrypter Encrypter) (interface{}, error) {
+	switch v := tree.(type) {
+	case string:
+		ciphertext, err := encrypter.EncryptValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"secure": ciphertext}, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			encrypted, err := encryptLeaves(elem, encrypter)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = encrypted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			encrypted, err := encryptLeaves(elem, encrypter)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = encrypted
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+// secureLeaf returns the ciphertext of the given tree if it is a secure leaf, i.e. {"secure": "<ciphertext>"}.
+func secureLeaf(tree interface{}) (string, bool) {
+	m, ok := tree.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ciphertext, ok := m["secure"].(string)
+	return ciphertext, ok
+}
+
+// decryptLeaf decrypts the ciphertext of a secure leaf of an object.
+func decryptLeaf(decrypter Decrypter, ciphertext string) (string, error) {
+	if decrypter == nil {
+		return "", errors.New("non-nil decrypter required for secret")
+	}
+	return decrypter.DecryptValue(ciphertext)
+}
+
+// lookupPath returns the element of the given tree at the given path, or nil if there is none.
+func lookupPath(tree interface{}, path []interface{}) interface{} {
+	for _, elem := range path {
+		switch elem := elem.(type) {
+		case string:
+			m, ok := tree.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			tree = m[elem]
+		case int:
+			a, ok := tree.([]interface{})
+			if !ok || elem >= len(a) {
+				return nil
+			}
+			tree = a[elem]
+		}
+	}
+	return tree
+}
+
+// renderPath renders a path within an object, e.g. "users[0].key".
+func renderPath(path []interface{}) string {
+	var b strings.Builder
+	for _, elem := range path {
+		switch elem := elem.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(elem)
+		case int:
+			b.WriteString("[" + strconv.Itoa(elem) + "]")
+		}
+	}
+	return b.String()
+}
+
+// fromYAMLTree converts a tree decoded from YAML, whose objects have keys of any type, to the form in which it would
+// have been decoded from JSON, whose objects have string keys.
+func fromYAMLTree(tree interface{}) (interface{}, error) {
+	switch v := tree.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			converted, err := fromYAMLTree(elem)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(key)] = converted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			converted, err := fromYAMLTree(elem)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	default:
+		return v, nil
+	}
+}
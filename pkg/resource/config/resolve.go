@@ -0,0 +1,138 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver fetches the secrets to which references of a particular scheme refer. It is given the part of a reference's
+// URI that follows "<scheme>://".
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc is a function that implements Resolver.
+type ResolverFunc func(ref string) (string, error)
+
+// Resolve calls f(ref).
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// Resolvers maps URI schemes to the resolvers of the references that use them. The secret to which a reference refers
+// is fetched each time the stack is deployed, and is never saved, whether in the stack's configuration file or its
+// checkpoint, even encrypted.
+type Resolvers map[string]Resolver
+
+// DefaultResolvers returns the built-in resolvers: "env", whose references name environment variables, as in
+// env://DB_PASSWORD; and "file", whose references name files, as in file:///run/secrets/db-password, whose contents
+// are read without any final newline.
+func DefaultResolvers() Resolvers {
+	return Resolvers{
+		"env": ResolverFunc(func(name string) (string, error) {
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				return "", errors.Errorf("the environment variable %s is not set", name)
+			}
+			return v, nil
+		}),
+		"file": ResolverFunc(func(path string) (string, error) {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+		}),
+	}
+}
+
+// NewReference returns a reference to the secret with the given URI, of the form <scheme>://<ref>, which is fetched
+// each time the stack is deployed by the resolver for the URI's scheme.
+func NewReference(uri string) (Value, error) {
+	if _, _, err := splitReference(uri); err != nil {
+		return Value{}, err
+	}
+	return Value{value: uri, ref: true}, nil
+}
+
+// ReferenceURI returns the URI of the secret to which the value refers, or "" if the value is not a reference.
+func (c Value) ReferenceURI() string {
+	if !c.ref {
+		return ""
+	}
+	return c.value
+}
+
+// splitReference splits the URI of a reference into its scheme and the rest of the URI.
+func splitReference(uri string) (string, string, error) {
+	sep := strings.Index(uri, "://")
+	if sep <= 0 || sep+3 == len(uri) {
+		return "", "", errors.Errorf("invalid reference '%s'; references must be of the form <scheme>://<ref>", uri)
+	}
+	return uri[:sep], uri[sep+3:], nil
+}
+
+// HasReference returns true if the config map contains a reference to a secret that is fetched when the stack is
+// deployed.
+func (m Map) HasReference() bool {
+	for _, v := range m {
+		if v.ref {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns a copy of the map in which each reference holds the secret to which it refers, as fetched by the
+// resolver for the reference's scheme. It fails if any reference cannot be resolved. The keys are resolved in order,
+// so that the first failure is always the same one.
+func (m Map) Resolve(resolvers Resolvers) (Map, error) {
+	var keys KeyArray
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(keys)
+
+	result := make(Map, len(m))
+	for _, k := range keys {
+		c := m[k]
+		if !c.ref {
+			result[k] = c
+			continue
+		}
+
+		scheme, ref, err := splitReference(c.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving configuration value %s", k)
+		}
+		resolver, has := resolvers[scheme]
+		if !has {
+			return nil, errors.Errorf("resolving configuration value %s: there is no resolver for %s:// references",
+				k, scheme)
+		}
+		secret, err := resolver.Resolve(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving configuration value %s from %s", k, c.value)
+		}
+		result[k] = Value{value: c.value, ref: true, resolved: true, plaintext: secret}
+	}
+	return result, nil
+}
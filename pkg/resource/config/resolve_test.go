@@ -0,0 +1,107 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestMarshallReference(t *testing.T) {
+	v, err := NewReference("env://DB_PASSWORD")
+	assert.NoError(t, err)
+	assert.True(t, v.Reference())
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"resolve":"env://DB_PASSWORD"}`, string(b))
+	b, err = yaml.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "resolve: env://DB_PASSWORD\n", string(b))
+
+	newV, err := roundtripValueJSON(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+	newV, err = roundtripValueYAML(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+
+	for _, uri := range []string{"DB_PASSWORD", "env://", "://DB_PASSWORD"} {
+		_, err = NewReference(uri)
+		assert.Error(t, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resolve")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("from-file\n"), 0600))
+	assert.NoError(t, os.Setenv("PULUMI_TEST_RESOLVE", "from-env"))
+	defer os.Unsetenv("PULUMI_TEST_RESOLVE")
+
+	env, err := NewReference("env://PULUMI_TEST_RESOLVE")
+	assert.NoError(t, err)
+	file, err := NewReference("file://" + path)
+	assert.NoError(t, err)
+	m := Map{
+		MustMakeKey("test", "plain"): NewValue("value"),
+		MustMakeKey("test", "env"):   env,
+		MustMakeKey("test", "file"):  file,
+	}
+	assert.False(t, m.HasSecureValue())
+	assert.True(t, m.HasReference())
+
+	// A reference has no value until it is resolved.
+	_, err = env.Value(NopDecrypter)
+	assert.Error(t, err)
+
+	resolved, err := m.Resolve(DefaultResolvers())
+	assert.NoError(t, err)
+	values, err := resolved.Decrypt(NopDecrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, map[Key]string{
+		MustMakeKey("test", "plain"): "value",
+		MustMakeKey("test", "env"):   "from-env",
+		MustMakeKey("test", "file"):  "from-file",
+	}, values)
+
+	// The secrets are masked, and are never marshaled.
+	secrets, err := resolved[MustMakeKey("test", "env")].SecretValues(NopDecrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"from-env"}, secrets)
+	assert.Equal(t, "[secret]", resolved[MustMakeKey("test", "env")].Masked())
+	b, err := json.Marshal(resolved)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "from-env")
+	assert.NotContains(t, string(b), "from-file")
+
+	// A reference that cannot be resolved is an error.
+	missing, err := NewReference("env://PULUMI_TEST_RESOLVE_MISSING")
+	assert.NoError(t, err)
+	_, err = Map{MustMakeKey("test", "missing"): missing}.Resolve(DefaultResolvers())
+	assert.Error(t, err)
+	vault, err := NewReference("vault://secret/db")
+	assert.NoError(t, err)
+	_, err = Map{MustMakeKey("test", "vault"): vault}.Resolve(DefaultResolvers())
+	assert.EqualError(t, err, "resolving configuration value test:vault: there is no resolver for vault:// references")
+}
@@ -17,12 +17,19 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
-// Value is a single config value.
+// Value is a single config value. A value is either a string, which may be secure (encrypted), or an object, which is
+// a JSON object or array whose leaves are plain values except for those that are individually secure, or a reference
+// to a secret that is fetched each time the stack is deployed (see Resolvers).
 type Value struct {
-	value  string
-	secure bool
+	value     string
+	secure    bool
+	object    bool   // true if value is the JSON text of an object, whose secure leaves are encoded as {"secure": ...}.
+	ref       bool   // true if value is the URI of a secret that is fetched when the stack is deployed.
+	resolved  bool   // true if the secret to which a reference refers has been fetched.
+	plaintext string // the secret to which a reference refers, once it has been fetched. It is never marshaled.
 }
 
 func NewSecureValue(v string) Value {
@@ -34,8 +41,18 @@ func NewValue(v string) Value {
 }
 
 // Value fetches the value of this configuration entry, using decrypter to decrypt if necessary.  If the value
-// is a secret and decrypter is nil, or if decryption fails for any reason, a non-nil error is returned.
+// is a secret and decrypter is nil, or if decryption fails for any reason, a non-nil error is returned. The value of an
+// object is its JSON text, in which each of its secure leaves is replaced by its plaintext.
 func (c Value) Value(decrypter Decrypter) (string, error) {
+	if c.ref {
+		if !c.resolved {
+			return "", fmt.Errorf("the value refers to %s, which is only fetched when the stack is deployed", c.value)
+		}
+		return c.plaintext, nil
+	}
+	if c.object {
+		return c.objectValue(decrypter)
+	}
 	if !c.secure {
 		return c.value, nil
 	}
@@ -46,11 +63,31 @@ func (c Value) Value(decrypter Decrypter) (string, error) {
 	return decrypter.DecryptValue(c.value)
 }
 
+// Secure returns true if the value is secure, or is an object that has secure leaves.
 func (c Value) Secure() bool {
+	if c.object {
+		return len(c.secretPaths()) > 0
+	}
 	return c.secure
 }
 
+// Object returns true if the value is an object rather than a string.
+func (c Value) Object() bool {
+	return c.object
+}
+
+// Reference returns true if the value is a reference to a secret that is fetched each time the stack is deployed.
+func (c Value) Reference() bool {
+	return c.ref
+}
+
 func (c Value) MarshalJSON() ([]byte, error) {
+	if c.object {
+		return []byte(c.value), nil
+	}
+	if c.ref {
+		return json.Marshal(map[string]string{"resolve": c.value})
+	}
 	if !c.secure {
 		return json.Marshal(c.value)
 	}
@@ -62,27 +99,20 @@ func (c Value) MarshalJSON() ([]byte, error) {
 }
 
 func (c *Value) UnmarshalJSON(b []byte) error {
-	var m map[string]string
-	err := json.Unmarshal(b, &m)
-	if err == nil {
-		if len(m) != 1 {
-			return errors.New("malformed secure data")
-		}
-
-		val, has := m["secure"]
-		if !has {
-			return errors.New("malformed secure data")
-		}
-
-		c.value = val
-		c.secure = true
-		return nil
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
 	}
-
-	return json.Unmarshal(b, &c.value)
+	return c.fromTree(v)
 }
 
 func (c Value) MarshalYAML() (interface{}, error) {
+	if c.object {
+		return c.tree()
+	}
+	if c.ref {
+		return map[string]string{"resolve": c.value}, nil
+	}
 	if !c.secure {
 		return c.value, nil
 	}
@@ -94,23 +124,61 @@ func (c Value) MarshalYAML() (interface{}, error) {
 }
 
 func (c *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var m map[string]string
-	err := unmarshal(&m)
-	if err == nil {
-		if len(m) != 1 {
-			return errors.New("malformed secure data")
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	switch v.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		tree, err := fromYAMLTree(v)
+		if err != nil {
+			return err
 		}
+		return c.fromTree(tree)
+	}
 
-		val, has := m["secure"]
-		if !has {
-			return errors.New("malformed secure data")
-		}
+	// Scalars are read as strings, whatever their YAML types.
+	*c = Value{}
+	return unmarshal(&c.value)
+}
 
-		c.value = val
-		c.secure = true
+// fromTree sets the value to the given JSON-like tree: a string, a secure value of the form {"secure": ...}, a
+// reference of the form {"resolve": ...}, or an object.
+func (c *Value) fromTree(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		*c = NewValue(v)
 		return nil
+	case map[string]interface{}:
+		if _, has := v["secure"]; has {
+			ciphertext, ok := secureLeaf(v)
+			if !ok {
+				return errors.New("malformed secure data")
+			}
+			*c = NewSecureValue(ciphertext)
+			return nil
+		}
+		if _, has := v["resolve"]; has {
+			uri, ok := v["resolve"].(string)
+			if !ok || len(v) != 1 {
+				return errors.New("malformed reference")
+			}
+			ref, err := NewReference(uri)
+			if err != nil {
+				return err
+			}
+			*c = ref
+			return nil
+		}
+	case []interface{}:
+	default:
+		return errors.New("a configuration value must be a string or an object")
 	}
 
-	c.secure = false
-	return unmarshal(&c.value)
+	object, err := newObjectValue(v)
+	if err != nil {
+		return err
+	}
+	*c = object
+	return nil
 }
@@ -70,6 +70,101 @@ func TestMarshallSecureValueJSON(t *testing.T) {
 	assert.Equal(t, v, newV)
 }
 
+func TestMarshallObjectValue(t *testing.T) {
+	v, err := NewObjectValue(`{"host":"db.example.com","port":5432,"password":{"secure":"ciphertext"}}`)
+	assert.NoError(t, err)
+	assert.True(t, v.Object())
+	assert.True(t, v.Secure())
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"db.example.com","password":{"secure":"ciphertext"},"port":5432}`, string(b))
+
+	b, err = yaml.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "host: db.example.com\npassword:\n  secure: ciphertext\nport: 5432\n", string(b))
+
+	newV, err := roundtripValueJSON(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+
+	newV, err = roundtripValueYAML(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+
+	// An object whose secure data is malformed is rejected.
+	_, err = NewObjectValue(`{"password":{"secure":"ciphertext","extra":true}}`)
+	assert.Error(t, err)
+}
+
+func TestObjectValueSecrets(t *testing.T) {
+	crypter := NewSymmetricCrypter(make([]byte, 32))
+	ciphertext, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	v, err := Value{}.SetPath("host", NewValue("db.example.com"))
+	assert.NoError(t, err)
+	v, err = v.SetPath("users.admin", NewSecureValue(ciphertext))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users.admin"}, v.secretPaths())
+
+	// Only the secure leaves are decrypted, and only they are masked.
+	plaintext, err := v.Value(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"db.example.com","users":{"admin":"hunter2"}}`, plaintext)
+	_, err = v.Value(nil)
+	assert.Error(t, err)
+	assert.Equal(t, `{"host":"db.example.com","users":{"admin":"[secret]"}}`, v.Masked())
+	secrets, err := v.SecretValues(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hunter2"}, secrets)
+
+	// Reencrypting the object encrypts only the same leaves.
+	other := NewSymmetricCrypter([]byte("0123456789abcdef0123456789abcdef"))
+	reencrypted, err := v.Reencrypt(plaintext, other)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users.admin"}, reencrypted.secretPaths())
+	newPlaintext, err := reencrypted.Value(other)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, newPlaintext)
+
+	// The leaves of a path must be objects.
+	_, err = v.SetPath("host.name", NewValue("db"))
+	assert.Error(t, err)
+	_, err = v.SetPath("users.admin.name", NewValue("db"))
+	assert.Error(t, err)
+	_, err = NewValue("value").SetPath("host", NewValue("db"))
+	assert.Error(t, err)
+
+	// A key named "secure" would be read back as a secure leaf, so it is rejected.
+	for _, path := range []string{"secure", "tls.secure", "secure.port"} {
+		_, err = v.SetPath(path, NewValue("true"))
+		assert.EqualError(t, err, "invalid path '"+path+"'; 'secure' is reserved for the leaves of secrets")
+	}
+	assert.Equal(t, []string{"users.admin"}, v.secretPaths())
+}
+
+func TestSecureObjectValue(t *testing.T) {
+	crypter := NewSymmetricCrypter(make([]byte, 32))
+
+	// Every string leaf is encrypted, and nothing else.
+	text := `{"host":"db.example.com","port":5432,"users":[{"name":"admin"}]}`
+	v, err := NewSecureObjectValue(text, crypter)
+	assert.NoError(t, err)
+	assert.True(t, v.Object())
+	assert.Equal(t, []string{"host", "users[0].name"}, v.secretPaths())
+	plaintext, err := v.Value(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, text, plaintext)
+	assert.Equal(t, `{"host":"[secret]","port":5432,"users":[{"name":"[secret]"}]}`, v.Masked())
+
+	// Text that already has secure leaves is rejected, as is text that is not an object.
+	_, err = NewSecureObjectValue(`{"password":{"secure":"ciphertext"}}`, crypter)
+	assert.Error(t, err)
+	_, err = NewSecureObjectValue(`"db.example.com"`, crypter)
+	assert.Error(t, err)
+}
+
 func roundtripValueYAML(v Value) (Value, error) {
 	return roundtripValue(v, yaml.Marshal, yaml.Unmarshal)
 }
@@ -0,0 +1,108 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// ArrayKeysFunction is the function that a provider may implement to declare the properties that identify the
+// elements of a resource type's arrays of objects, such as the "name" of each container in a list of containers. The
+// function is called using the provider protocol's Invoke method with one argument, "type", the token of the
+// resource's type.
+//
+// It returns an object whose "keys" property maps the paths of arrays, as described by resource.ArrayKeys (e.g.
+// "spec.containers"), to the names of their elements' identifying properties. The elements of these arrays are matched
+// by key when a resource's changes are displayed, so that inserting or deleting an element shows only that element as
+// changed. Array keys affect only the display of changes, not whether a resource must be updated.
+//
+// Providers declare that they implement the function by setting supportsArrayKeys in their response to Configure.
+const ArrayKeysFunction tokens.ModuleMember = "pulumi:providers:arrayKeys"
+
+// ArrayKeys returns the array keys that the provider of the given step declares for the type of its resource, or nil
+// if the step does not update or replace a custom resource, or if its provider does not report ArrayKeysFunction as a
+// capability.
+func ArrayKeys(step Step) resource.ArrayKeys {
+	switch step.Op() {
+	case OpUpdate, OpReplace, OpCreateReplacement:
+	default:
+		return nil
+	}
+	// Provider resources are managed by the provider registry, which does not declare any array keys.
+	if step.Old() == nil || step.New() == nil || !step.New().Custom || providers.IsProviderType(step.Type()) {
+		return nil
+	}
+	prov, err := getProvider(step)
+	if err != nil {
+		return nil
+	}
+	return step.Plan().arrayKeys(prov, step.Type())
+}
+
+// arrayKeys returns the array keys that the given provider declares for the given resource type. Results are cached
+// for the lifetime of the plan.
+func (p *Plan) arrayKeys(prov plugin.Provider, t tokens.Type) resource.ArrayKeys {
+	if !plugin.GetCapabilities(prov).ArrayKeys {
+		return nil
+	}
+
+	p.arrayKeyLock.Lock()
+	defer p.arrayKeyLock.Unlock()
+
+	if p.arrayKeyCache == nil {
+		p.arrayKeyCache = make(map[plugin.Provider]map[tokens.Type]resource.ArrayKeys)
+	}
+	types, has := p.arrayKeyCache[prov]
+	if !has {
+		types = make(map[tokens.Type]resource.ArrayKeys)
+		p.arrayKeyCache[prov] = types
+	}
+	if keys, has := types[t]; has {
+		return keys
+	}
+
+	ret, failures, err := prov.Invoke(ArrayKeysFunction, resource.PropertyMap{
+		"type": resource.NewStringProperty(string(t)),
+	})
+	if err != nil {
+		// Array keys only affect the display of changes, so a provider that fails to declare them is not an error.
+		logging.V(7).Infof("provider %v failed to declare the array keys of %v: %v", prov.Pkg(), t, err)
+		types[t] = nil
+		return nil
+	}
+	if len(failures) > 0 {
+		logging.V(7).Infof("provider %v could not declare the array keys of %v: %v", prov.Pkg(), t, failures)
+	}
+
+	var keys resource.ArrayKeys
+	if obj, has := ret["keys"]; has && obj.IsObject() {
+		for path, key := range obj.ObjectValue() {
+			if !key.IsString() || key.StringValue() == "" {
+				logging.V(7).Infof("provider %v declared an invalid key for array %v of %v", prov.Pkg(), path, t)
+				continue
+			}
+			if keys == nil {
+				keys = make(resource.ArrayKeys)
+			}
+			keys[string(path)] = resource.PropertyKey(key.StringValue())
+		}
+	}
+	types[t] = keys
+	return keys
+}
@@ -0,0 +1,55 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// autoNameSuffixLength is the number of random hex digits that are appended to a resource's name to make its
+// physical name unique.
+const autoNameSuffixLength = 8
+
+// autoNameInputs returns the goal's inputs with its auto-named property, if it has one that the program did not set,
+// filled in. The name is taken from the given old inputs or, failing that, the old outputs, so that a resource keeps
+// its physical name across updates and refreshes; a resource without either gets a new name, made from its name and
+// a random suffix. A value that the program sets always takes precedence.
+func autoNameInputs(goal *resource.Goal, oldInputs, oldOutputs resource.PropertyMap) resource.PropertyMap {
+	key := goal.AutoName
+	if key == "" {
+		return goal.Properties
+	}
+	if v, has := goal.Properties[key]; has && !v.IsNull() {
+		return goal.Properties
+	}
+
+	var name resource.PropertyValue
+	if old, has := oldInputs[key]; has && old.IsString() {
+		name = old
+	} else if old, has := oldOutputs[key]; has && old.IsString() {
+		name = old
+	} else {
+		unique, err := resource.NewUniqueHex(string(goal.Name)+"-", autoNameSuffixLength, 0)
+		contract.AssertNoError(err)
+		name = resource.NewStringProperty(unique)
+		logging.V(7).Infof("auto-named property %v of %v: %v", key, goal.Name, unique)
+	}
+
+	inputs := goal.Properties.Copy()
+	inputs[key] = name
+	return inputs
+}
@@ -0,0 +1,240 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// BatchCreateFunction is the function that a provider may implement to create several resources of the same type with
+// one call, which is often much faster than creating them one at a time. It is called using the provider protocol's
+// Invoke method with two arguments: "type", the token of the resources' type, and "resources", an array of objects,
+// one for each resource, with the properties "urn", "news" (the resource's inputs), and "timeout" (the seconds that
+// the creation may take, or zero for no limit).
+//
+// It returns an object whose "results" property is an array with an object for each resource, in the same order. Each
+// object holds the created resource's "id" and "outputs". If a resource could not be created, its object instead holds
+// an "error" message, along with the resource's "id" and "outputs" if it was created but could not be initialized.
+// If the call fails, the creation of every resource in the batch fails. Only providers that set supportsBatchCreate in
+// their response to Configure are sent batches; the resources of other providers are each created by a call to Create.
+const BatchCreateFunction tokens.ModuleMember = "pulumi:providers:batchCreate"
+
+// batchWindow is the time for which a batch waits for more creations to join it before it is sent to its provider.
+const batchWindow = 20 * time.Millisecond
+
+// batchKey identifies the creations that may be batched together: those of resources of the same type that are
+// managed by the same provider.
+type batchKey struct {
+	prov plugin.Provider
+	t    tokens.Type
+}
+
+// batchedCreate is the creation of a single resource that is waiting for its batch to be sent.
+type batchedCreate struct {
+	urn     resource.URN
+	news    resource.PropertyMap
+	timeout float64
+	done    chan batchedCreateResult
+}
+
+// batchedCreateResult is the result of a batched creation, as Create would return it.
+type batchedCreateResult struct {
+	id     resource.ID
+	outs   resource.PropertyMap
+	status resource.Status
+	err    error
+}
+
+// createBatch is a batch of creations that are sent to their provider together.
+type createBatch struct {
+	key     batchKey
+	creates []*batchedCreate
+}
+
+// createBatcher gathers the creations of resources that run concurrently into batches, and sends each batch to its
+// provider's BatchCreateFunction. It is safe to use concurrently.
+type createBatcher struct {
+	size int // the maximum number of creations in a batch.
+
+	lock         sync.Mutex
+	pending      map[batchKey]*createBatch // the batches that are waiting to be sent.
+	batchesSent  int                       // the number of batches that have been sent, for logging.
+	batchedTotal int                       // the number of creations in those batches, for logging.
+}
+
+// create creates a resource using the given provider, as part of a batch if the plan batches creations.
+func (p *Plan) create(prov plugin.Provider, urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	if p.batcher == nil {
+		return prov.Create(urn, news, timeout)
+	}
+	return p.batcher.create(prov, urn, news, timeout)
+}
+
+func newCreateBatcher(size int) *createBatcher {
+	return &createBatcher{
+		size:    size,
+		pending: make(map[batchKey]*createBatch),
+	}
+}
+
+// create creates a resource using the given provider, as its Create method would, but as part of a batch if the
+// provider allows it.
+func (b *createBatcher) create(prov plugin.Provider, urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	// Provider resources are created by the provider registry, which does not batch them.
+	if providers.IsProviderType(urn.Type()) || !plugin.GetCapabilities(prov).BatchCreate {
+		return prov.Create(urn, news, timeout)
+	}
+
+	b.lock.Lock()
+	c := &batchedCreate{urn: urn, news: news, timeout: timeout, done: make(chan batchedCreateResult, 1)}
+	key := batchKey{prov: prov, t: urn.Type()}
+	batch := b.pending[key]
+	if batch == nil {
+		batch = &createBatch{key: key}
+		b.pending[key] = batch
+		time.AfterFunc(batchWindow, func() { b.flush(batch) })
+	}
+	batch.creates = append(batch.creates, c)
+	full := len(batch.creates) >= b.size
+	if full {
+		delete(b.pending, key)
+	}
+	b.lock.Unlock()
+
+	if full {
+		go b.send(batch)
+	}
+	r := <-c.done
+	return r.id, r.outs, r.status, r.err
+}
+
+// flush sends the given batch once its window has passed, unless it has already been sent because it filled up.
+func (b *createBatcher) flush(batch *createBatch) {
+	b.lock.Lock()
+	waiting := b.pending[batch.key] == batch
+	if waiting {
+		delete(b.pending, batch.key)
+	}
+	b.lock.Unlock()
+
+	if waiting {
+		b.send(batch)
+	}
+}
+
+// send sends the given batch to its provider, and delivers the result of each of its creations. If the provider fails
+// the batch as a whole, each of its creations fails.
+func (b *createBatcher) send(batch *createBatch) {
+	prov := batch.key.prov
+	if len(batch.creates) == 1 {
+		c := batch.creates[0]
+		id, outs, status, err := prov.Create(c.urn, c.news, c.timeout)
+		c.done <- batchedCreateResult{id: id, outs: outs, status: status, err: err}
+		return
+	}
+
+	resources := make([]resource.PropertyValue, len(batch.creates))
+	for i, c := range batch.creates {
+		resources[i] = resource.NewObjectProperty(resource.PropertyMap{
+			"urn":     resource.NewStringProperty(string(c.urn)),
+			"news":    resource.NewObjectProperty(c.news),
+			"timeout": resource.NewNumberProperty(c.timeout),
+		})
+	}
+	ret, failures, err := prov.Invoke(BatchCreateFunction, resource.PropertyMap{
+		"type":      resource.NewStringProperty(string(batch.key.t)),
+		"resources": resource.NewArrayProperty(resources),
+	})
+	// The provider may have created some of the resources before it failed, so their status is unknown.
+	if err != nil {
+		b.fail(batch, errors.Wrapf(err, "creating a batch of %d resources of type %v", len(batch.creates), batch.key.t))
+		return
+	}
+	if len(failures) > 0 {
+		b.fail(batch, errors.Errorf("creating a batch of %d resources of type %v failed: %v", len(batch.creates),
+			batch.key.t, failures[0].Reason))
+		return
+	}
+
+	b.lock.Lock()
+	b.batchesSent++
+	b.batchedTotal += len(batch.creates)
+	logging.V(7).Infof("provider %v created %d resources of type %v in a batch (%d batches, %d resources so far)",
+		prov.Pkg(), len(batch.creates), batch.key.t, b.batchesSent, b.batchedTotal)
+	b.lock.Unlock()
+
+	results := ret["results"]
+	if !results.IsArray() || len(results.ArrayValue()) != len(batch.creates) {
+		b.fail(batch, errors.Errorf("provider %v returned an invalid result for a batch of %d resources", prov.Pkg(),
+			len(batch.creates)))
+		return
+	}
+	for i, c := range batch.creates {
+		c.done <- batchedCreateResultOf(results.ArrayValue()[i])
+	}
+}
+
+// fail fails each of the creations in the given batch with the given error.
+func (b *createBatcher) fail(batch *createBatch, err error) {
+	for _, c := range batch.creates {
+		c.done <- batchedCreateResult{status: resource.StatusUnknown, err: err}
+	}
+}
+
+// batchedCreateResultOf converts the given element of a BatchCreateFunction's results into the result of a creation.
+func batchedCreateResultOf(v resource.PropertyValue) batchedCreateResult {
+	if !v.IsObject() {
+		return batchedCreateResult{status: resource.StatusUnknown,
+			err: errors.New("the provider returned an invalid result for the resource")}
+	}
+	obj := v.ObjectValue()
+
+	var r batchedCreateResult
+	if id := obj["id"]; id.IsString() {
+		r.id = resource.ID(id.StringValue())
+	}
+	if outs := obj["outputs"]; outs.IsObject() {
+		r.outs = outs.ObjectValue()
+	}
+	if msg := obj["error"]; msg.IsString() && msg.StringValue() != "" {
+		if r.id == "" {
+			// The resource was not created.
+			r.status, r.err = resource.StatusOK, errors.New(msg.StringValue())
+			return r
+		}
+		// The resource was created, but could not be initialized.
+		r.status, r.err = resource.StatusPartialFailure, &plugin.InitError{Reasons: []string{msg.StringValue()}}
+	}
+	if r.id == "" {
+		r.status, r.err = resource.StatusUnknown, errors.New("the provider returned an empty ID for the resource")
+	}
+	if r.outs == nil {
+		r.outs = resource.PropertyMap{}
+	}
+	return r
+}
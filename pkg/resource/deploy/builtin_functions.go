@@ -0,0 +1,206 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// builtinFunctionsModule is the module reserved for builtin functions. A function registered under the name "foo" is
+// invoked by programs using the token "pulumi:functions:foo".
+const builtinFunctionsModule = "pulumi:functions"
+
+// BuiltinFunctionParamType is the type of a single argument to a builtin function.
+type BuiltinFunctionParamType string
+
+// The set of types that a builtin function's arguments may have.
+const (
+	BuiltinAny    BuiltinFunctionParamType = "any"
+	BuiltinBool   BuiltinFunctionParamType = "boolean"
+	BuiltinNumber BuiltinFunctionParamType = "number"
+	BuiltinString BuiltinFunctionParamType = "string"
+	BuiltinArray  BuiltinFunctionParamType = "array"
+	BuiltinObject BuiltinFunctionParamType = "object"
+)
+
+// BuiltinFunctionParam describes a single argument to a builtin function.
+type BuiltinFunctionParam struct {
+	Name     resource.PropertyKey     // the name of the argument.
+	Type     BuiltinFunctionParamType // the type of the argument.
+	Optional bool                     // true if the argument may be omitted.
+}
+
+// BuiltinFunction is a function implemented in Go that may be invoked by Pulumi programs. The engine checks the
+// arguments passed by the program against Params before calling Func, so Func may assume that every required argument
+// is present and that every argument that is present has the expected type.
+type BuiltinFunction struct {
+	Params []BuiltinFunctionParam
+	Func   func(args resource.PropertyMap) (resource.PropertyMap, error)
+}
+
+var builtinFunctions = struct {
+	sync.RWMutex
+	fns map[tokens.ModuleMember]BuiltinFunction
+}{fns: make(map[tokens.ModuleMember]BuiltinFunction)}
+
+// BuiltinFunctionToken returns the token that programs use to invoke the builtin function with the given name.
+func BuiltinFunctionToken(name string) tokens.ModuleMember {
+	return tokens.ModuleMember(builtinFunctionsModule + tokens.TokenDelimiter + name)
+}
+
+// RegisterBuiltinFunction registers a builtin function under the given name. It is an error to register two functions
+// with the same name.
+func RegisterBuiltinFunction(name string, fn BuiltinFunction) error {
+	if name == "" {
+		return errors.New("builtin functions must have a name")
+	}
+	if fn.Func == nil {
+		return errors.Errorf("builtin function '%v' has no implementation", name)
+	}
+
+	tok := BuiltinFunctionToken(name)
+
+	builtinFunctions.Lock()
+	defer builtinFunctions.Unlock()
+	if _, has := builtinFunctions.fns[tok]; has {
+		return errors.Errorf("a builtin function named '%v' has already been registered", name)
+	}
+	builtinFunctions.fns[tok] = fn
+	return nil
+}
+
+// lookupBuiltinFunction returns the builtin function registered for the given token, if any.
+func lookupBuiltinFunction(tok tokens.ModuleMember) (BuiltinFunction, bool) {
+	builtinFunctions.RLock()
+	defer builtinFunctions.RUnlock()
+	fn, has := builtinFunctions.fns[tok]
+	return fn, has
+}
+
+// invoke checks the given arguments against the function's parameters and, if they are valid, calls the function.
+// Secret arguments are passed to the function as their plaintext values. If any argument was secret, each of the
+// function's outputs is marked secret.
+func (fn BuiltinFunction) invoke(args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+	if failures := fn.check(args); len(failures) > 0 {
+		return nil, failures, nil
+	}
+
+	outs, err := fn.Func(removeSecrets(resource.NewObjectProperty(args)).ObjectValue())
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.ContainsSecrets() {
+		for k, v := range outs {
+			if !v.IsSecret() {
+				outs[k] = resource.MakeSecret(v)
+			}
+		}
+	}
+	return outs, nil, nil
+}
+
+// removeSecrets returns a copy of the given value in which each secret is replaced by its element.
+func removeSecrets(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsSecret():
+		return removeSecrets(v.SecretValue().Element)
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = removeSecrets(e)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		obj := make(resource.PropertyMap, len(v.ObjectValue()))
+		for k, e := range v.ObjectValue() {
+			obj[k] = removeSecrets(e)
+		}
+		return resource.NewObjectProperty(obj)
+	default:
+		return v
+	}
+}
+
+// check returns a check failure for each argument that is missing, unexpected, or of the wrong type.
+func (fn BuiltinFunction) check(args resource.PropertyMap) []plugin.CheckFailure {
+	var failures []plugin.CheckFailure
+
+	known := make(map[resource.PropertyKey]bool)
+	for _, param := range fn.Params {
+		known[param.Name] = true
+
+		arg, has := args[param.Name]
+		if !has || arg.IsNull() {
+			if !param.Optional {
+				failures = append(failures, plugin.CheckFailure{
+					Property: param.Name,
+					Reason:   fmt.Sprintf("missing required argument \"%v\"", param.Name),
+				})
+			}
+			continue
+		}
+
+		if arg.IsComputed() || arg.IsOutput() {
+			failures = append(failures, plugin.CheckFailure{
+				Property: param.Name,
+				Reason:   fmt.Sprintf("argument \"%v\" must be known", param.Name),
+			})
+			continue
+		}
+
+		if !builtinParamTypeMatches(param.Type, arg) {
+			failures = append(failures, plugin.CheckFailure{
+				Property: param.Name,
+				Reason:   fmt.Sprintf("argument \"%v\" must be of type %v", param.Name, param.Type),
+			})
+		}
+	}
+
+	for _, k := range args.StableKeys() {
+		if !known[k] {
+			failures = append(failures, plugin.CheckFailure{
+				Property: k,
+				Reason:   fmt.Sprintf("unknown argument \"%v\"", k),
+			})
+		}
+	}
+
+	return failures
+}
+
+func builtinParamTypeMatches(typ BuiltinFunctionParamType, v resource.PropertyValue) bool {
+	v = removeSecrets(v)
+	switch typ {
+	case BuiltinBool:
+		return v.IsBool()
+	case BuiltinNumber:
+		return v.IsNumber()
+	case BuiltinString:
+		return v.IsString()
+	case BuiltinArray:
+		return v.IsArray()
+	case BuiltinObject:
+		return v.IsObject()
+	default:
+		return true
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestBuiltinFunctions(t *testing.T) {
+	err := RegisterBuiltinFunction("testRepeat", BuiltinFunction{
+		Params: []BuiltinFunctionParam{
+			{Name: "value", Type: BuiltinString},
+			{Name: "count", Type: BuiltinNumber, Optional: true},
+		},
+		Func: func(args resource.PropertyMap) (resource.PropertyMap, error) {
+			count := 2
+			if c, ok := args["count"]; ok {
+				count = int(c.NumberValue())
+			}
+			return resource.PropertyMap{
+				"result": resource.NewStringProperty(strings.Repeat(args["value"].StringValue(), count)),
+			}, nil
+		},
+	})
+	assert.NoError(t, err)
+
+	// Registering a second function with the same name fails.
+	err = RegisterBuiltinFunction("testRepeat", BuiltinFunction{
+		Func: func(args resource.PropertyMap) (resource.PropertyMap, error) { return nil, nil },
+	})
+	assert.Error(t, err)
+
+	p := newBuiltinProvider(nil)
+	tok := BuiltinFunctionToken("testRepeat")
+	assert.Equal(t, "pulumi:functions:testRepeat", string(tok))
+
+	outs, failures, err := p.Invoke(tok, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"value": "ab",
+		"count": 3,
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, "ababab", outs["result"].StringValue())
+
+	outs, failures, err = p.Invoke(tok, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"value": "ab",
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, "abab", outs["result"].StringValue())
+
+	// Secret arguments are passed to the function as plaintext, and make the function's outputs secret.
+	outs, failures, err = p.Invoke(tok, resource.PropertyMap{
+		"value": resource.MakeSecret(resource.NewStringProperty("ab")),
+		"count": resource.NewNumberProperty(3),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	if assert.True(t, outs["result"].IsSecret()) {
+		assert.Equal(t, "ababab", outs["result"].SecretValue().Element.StringValue())
+	}
+
+	// Missing, mistyped, and unknown arguments are all reported.
+	_, failures, err = p.Invoke(tok, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"count": "three",
+		"extra": true,
+	}))
+	assert.NoError(t, err)
+	if assert.Len(t, failures, 3) {
+		assert.Equal(t, resource.PropertyKey("value"), failures[0].Property)
+		assert.Equal(t, resource.PropertyKey("count"), failures[1].Property)
+		assert.Equal(t, resource.PropertyKey("extra"), failures[2].Property)
+	}
+
+	// Unknown functions are still rejected.
+	_, _, err = p.Invoke(BuiltinFunctionToken("testMissing"), resource.PropertyMap{})
+	assert.Error(t, err)
+}
@@ -146,6 +146,9 @@ const readStackResourceOutputs = "pulumi:pulumi:readStackResourceOutputs"
 
 func (p *builtinProvider) Invoke(tok tokens.ModuleMember,
 	args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+	if fn, ok := lookupBuiltinFunction(tok); ok {
+		return fn.invoke(args)
+	}
 	if tok != readStackResourceOutputs {
 		return nil, nil, errors.Errorf("unrecognized function name: '%v'", tok)
 	}
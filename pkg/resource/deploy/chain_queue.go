@@ -0,0 +1,70 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+// A pendingChain is a chain that has been submitted to the step executor but not yet handed to a worker.
+type pendingChain struct {
+	incomingChain
+	priority int // the priority of the chain, taken from its first step.
+	seq      int // the order in which the chain was submitted, which breaks ties between equal priorities.
+}
+
+// A chainQueue orders pending chains by descending priority, and then by the order in which they were submitted. It
+// implements heap.Interface.
+//
+// Every chain that the step executor receives is ready to execute, so the order in which the queue releases chains
+// never affects whether a resource's dependencies have completed before it is operated upon.
+type chainQueue []*pendingChain
+
+func (q chainQueue) Len() int { return len(q) }
+
+func (q chainQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q chainQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *chainQueue) Push(x interface{}) { *q = append(*q, x.(*pendingChain)) }
+
+func (q *chainQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	p := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return p
+}
+
+// chainPriority returns the priority of the given chain, which is the priority of the resource that its first step
+// operates on. A step that removes a resource uses the negation of the resource's priority, so that the resources
+// that are created first are deleted last.
+func chainPriority(c chain) int {
+	if len(c) == 0 {
+		return 0
+	}
+	res := c[0].Res()
+	if res == nil {
+		return 0
+	}
+	switch c[0].Op() {
+	case OpDelete, OpDeleteReplaced, OpReadDiscard, OpDiscardReplaced, OpRemovePendingReplace:
+		return -res.Priority
+	default:
+		return res.Priority
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// ContinuePendingOperations determines how the operations that were pending when a deployment of the given snapshot
+// was interrupted are to be reconciled, so that the deployment may be continued. It returns the URNs of the resources
+// that must be refreshed to learn whether their updates or deletes completed, and the operations that cannot be
+// reconciled against the live state of their resources: creates, whose resources' IDs were never recorded, and reads
+// and imports, which are simply run again. The snapshot is not changed.
+func ContinuePendingOperations(snap *Snapshot) (map[resource.URN]bool, []resource.Operation) {
+	if snap == nil || len(snap.PendingOperations) == 0 {
+		return nil, nil
+	}
+
+	refresh := make(map[resource.URN]bool)
+	var unreconciled []resource.Operation
+	for _, op := range snap.PendingOperations {
+		switch op.Type {
+		case resource.OperationTypeUpdating, resource.OperationTypeDeleting:
+			// The resource's old state is still in the snapshot; a refresh tells whether the operation completed.
+			refresh[op.Resource.URN] = true
+		default:
+			unreconciled = append(unreconciled, op)
+		}
+	}
+	return refresh, unreconciled
+}
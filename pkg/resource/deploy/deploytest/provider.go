@@ -38,6 +38,8 @@ type Provider struct {
 		ignoreChanges []string) (plugin.DiffResult, error)
 	ConfigureF func(news resource.PropertyMap) error
 
+	CapabilitiesF func() plugin.ProviderCapabilities
+
 	CheckF func(urn resource.URN,
 		olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error)
 	DiffF func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap,
@@ -101,6 +103,12 @@ func (prov *Provider) Configure(inputs resource.PropertyMap) error {
 	}
 	return prov.ConfigureF(inputs)
 }
+func (prov *Provider) Capabilities() plugin.ProviderCapabilities {
+	if prov.CapabilitiesF == nil {
+		return plugin.ProviderCapabilities{}
+	}
+	return prov.CapabilitiesF()
+}
 
 func (prov *Provider) Check(urn resource.URN,
 	olds, news resource.PropertyMap, _ bool) (resource.PropertyMap, []plugin.CheckFailure, error) {
@@ -16,7 +16,7 @@ package deploytest
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
@@ -41,6 +41,10 @@ type ResourceOptions struct {
 	Aliases             []resource.URN
 	ImportID            resource.ID
 	CustomTimeouts      *resource.CustomTimeouts
+	Priority            int
+	RetryPolicy         resource.RetryPolicy
+	AutoName            resource.PropertyKey
+	ReplaceOnChanges    []string
 }
 
 func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom bool,
@@ -106,6 +110,12 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		Aliases:              aliasStrings,
 		ImportId:             string(opts.ImportID),
 		CustomTimeouts:       &timeouts,
+		Priority:             int32(opts.Priority),
+		RetryAttempts:        int32(opts.RetryPolicy.Attempts),
+		RetryDelay:           opts.RetryPolicy.Delay,
+		RetryBackoff:         opts.RetryPolicy.Backoff,
+		AutoName:             string(opts.AutoName),
+		ReplaceOnChanges:     opts.ReplaceOnChanges,
 	}
 
 	// submit request
@@ -189,7 +199,5 @@ func (rm *ResourceMonitor) Invoke(tok tokens.ModuleMember, inputs resource.Prope
 }
 
 func prepareTestTimeout(timeout float64) string {
-	mins := int(timeout) / 60
-
-	return fmt.Sprintf("%dm", mins)
+	return time.Duration(timeout * float64(time.Second)).String()
 }
@@ -0,0 +1,123 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// DeprecationsFunction is the function that a provider may implement to report the resource types and properties
+// that it has deprecated. The function is called using the provider protocol's Invoke method with no arguments.
+//
+// It returns an object with two optional properties: "types", which maps the token of each deprecated resource type to
+// a message that describes what to use instead, and "properties", which maps the token of a resource type to an object
+// that maps the name of each deprecated property of that type to such a message.
+//
+// The engine asks only those providers that set supportsDeprecations in their response to Configure.
+const DeprecationsFunction tokens.ModuleMember = "pulumi:providers:getDeprecations"
+
+// providerDeprecations records the resource types and properties that a provider has deprecated.
+type providerDeprecations struct {
+	types      map[tokens.Type]string
+	properties map[tokens.Type]map[resource.PropertyKey]string
+}
+
+// loadProviderDeprecations asks the given provider for the resource types and properties that it has deprecated.
+// Providers that do not report DeprecationsFunction as a capability have not deprecated anything.
+func loadProviderDeprecations(prov plugin.Provider) *providerDeprecations {
+	deps := &providerDeprecations{
+		types:      make(map[tokens.Type]string),
+		properties: make(map[tokens.Type]map[resource.PropertyKey]string),
+	}
+	if !plugin.GetCapabilities(prov).Deprecations {
+		return deps
+	}
+
+	ret, failures, err := prov.Invoke(DeprecationsFunction, resource.PropertyMap{})
+	if err != nil || len(failures) > 0 {
+		logging.V(7).Infof("provider %v failed to report its deprecations: %v %v", prov.Pkg(), err, failures)
+		return deps
+	}
+
+	if types, has := ret["types"]; has && types.IsObject() {
+		for t, msg := range types.ObjectValue() {
+			if msg.IsString() {
+				deps.types[tokens.Type(t)] = msg.StringValue()
+			}
+		}
+	}
+	if props, has := ret["properties"]; has && props.IsObject() {
+		for t, typeProps := range props.ObjectValue() {
+			if !typeProps.IsObject() {
+				continue
+			}
+			msgs := make(map[resource.PropertyKey]string)
+			for k, msg := range typeProps.ObjectValue() {
+				if msg.IsString() {
+					msgs[k] = msg.StringValue()
+				}
+			}
+			deps.properties[tokens.Type(t)] = msgs
+		}
+	}
+	return deps
+}
+
+// issueDeprecations reports the use of any deprecated resource type or property by the given resource. Each notice is
+// only reported once per plan, no matter how many resources it applies to. Notices are reported as errors if the plan
+// treats deprecations as errors, and as warnings otherwise. It returns true if any notices apply to the resource.
+func (sg *stepGenerator) issueDeprecations(urn resource.URN, t tokens.Type, props resource.PropertyMap,
+	prov plugin.Provider) bool {
+
+	// Provider resources are managed by the provider registry, which does not deprecate anything.
+	if providers.IsProviderType(t) {
+		return false
+	}
+
+	deps, has := sg.deprecations[prov]
+	if !has {
+		deps = loadProviderDeprecations(prov)
+		sg.deprecations[prov] = deps
+	}
+
+	report := sg.plan.Diag().Warningf
+	if sg.opts.DeprecationErrors {
+		report = sg.plan.Diag().Errorf
+	}
+
+	deprecated := false
+	if msg, has := deps.types[t]; has {
+		deprecated = true
+		if key := string(t); !sg.reportedDeprecations[key] {
+			sg.reportedDeprecations[key] = true
+			report(diag.GetDeprecatedResourceTypeWarning(urn), t, msg)
+		}
+	}
+	for _, k := range props.StableKeys() {
+		if msg, has := deps.properties[t][k]; has && !props[k].IsNull() {
+			deprecated = true
+			if key := string(t) + "." + string(k); !sg.reportedDeprecations[key] {
+				sg.reportedDeprecations[key] = true
+				report(diag.GetDeprecatedResourcePropertyWarning(urn), k, t, msg)
+			}
+		}
+	}
+	return deprecated
+}
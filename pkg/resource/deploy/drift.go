@@ -0,0 +1,166 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// NonComparablePropertiesFunction is the function that a provider may implement to report the output properties of
+// a resource type whose values are expected to change on their own, such as timestamps that record when a resource was
+// last read, and which should therefore not be reported as drift when a resource is refreshed. The function is called
+// using the provider protocol's Invoke method with one argument, "type", the token of the resource's type.
+//
+// It returns an object whose "properties" property holds an array of property paths (e.g. "status.lastModified").
+// Differences at or under these paths are ignored when comparing a resource's refreshed outputs with its old outputs,
+// although the refreshed values are still recorded in the resource's state.
+//
+// A provider that implements the function sets supportsNonComparableProperties in its response to Configure.
+const NonComparablePropertiesFunction tokens.ModuleMember = "pulumi:providers:nonComparableProperties"
+
+// nonComparableProperties returns the paths of the output properties of the given resource type that the given
+// provider reports as non-comparable. Results are cached for the lifetime of the plan.
+func (p *Plan) nonComparableProperties(prov plugin.Provider, t tokens.Type) []resource.PropertyPath {
+	if !plugin.GetCapabilities(prov).NonComparableProperties {
+		return nil
+	}
+
+	p.nonComparableLock.Lock()
+	defer p.nonComparableLock.Unlock()
+
+	if p.nonComparable == nil {
+		p.nonComparable = make(map[plugin.Provider]map[tokens.Type][]resource.PropertyPath)
+	}
+	types, has := p.nonComparable[prov]
+	if !has {
+		types = make(map[tokens.Type][]resource.PropertyPath)
+		p.nonComparable[prov] = types
+	}
+	if paths, has := types[t]; has {
+		return paths
+	}
+
+	ret, failures, err := prov.Invoke(NonComparablePropertiesFunction, resource.PropertyMap{
+		"type": resource.NewStringProperty(string(t)),
+	})
+	if err != nil {
+		logging.V(7).Infof("provider %v failed to report the non-comparable properties of %v: %v", prov.Pkg(), t, err)
+		types[t] = nil
+		return nil
+	}
+	if len(failures) > 0 {
+		logging.V(7).Infof("provider %v could not report the non-comparable properties of %v: %v",
+			prov.Pkg(), t, failures)
+	}
+
+	var paths []resource.PropertyPath
+	if props, has := ret["properties"]; has && props.IsArray() {
+		for _, prop := range props.ArrayValue() {
+			if !prop.IsString() {
+				continue
+			}
+			path, err := resource.ParsePropertyPath(prop.StringValue())
+			if err != nil {
+				logging.V(7).Infof("provider %v reported an invalid non-comparable property %q of %v: %v",
+					prov.Pkg(), prop.StringValue(), t, err)
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	types[t] = paths
+	return paths
+}
+
+// diffRefreshedOutputs compares a resource's old outputs with the outputs that were read when it was refreshed. It
+// returns the keys of the top-level properties that drifted and a detailed diff that records each drifted property by
+// its path. Any differences at or under the given non-comparable paths are ignored.
+func diffRefreshedOutputs(olds, news resource.PropertyMap,
+	nonComparable []resource.PropertyPath) ([]resource.PropertyKey, map[string]plugin.PropertyDiff) {
+
+	d := &driftDiffer{
+		nonComparable: nonComparable,
+		keys:          make(map[resource.PropertyKey]bool),
+		detailedDiff:  make(map[string]plugin.PropertyDiff),
+	}
+	if diff := olds.Diff(news); diff != nil {
+		d.diffObjects(nil, *diff)
+	}
+
+	var diffs []resource.PropertyKey
+	for k := range d.keys {
+		diffs = append(diffs, k)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+	return diffs, d.detailedDiff
+}
+
+// driftDiffer flattens the diff between a resource's old and refreshed outputs into a detailed diff.
+type driftDiffer struct {
+	nonComparable []resource.PropertyPath        // the paths of properties whose differences are ignored.
+	keys          map[resource.PropertyKey]bool  // the top-level properties that drifted.
+	detailedDiff  map[string]plugin.PropertyDiff // the drifted properties, keyed by path.
+}
+
+func (d *driftDiffer) add(path resource.PropertyPath, kind plugin.DiffKind) {
+	for _, ignored := range d.nonComparable {
+		if ignored.Contains(path) {
+			return
+		}
+	}
+	d.keys[resource.PropertyKey(path[0].(string))] = true
+	d.detailedDiff[path.String()] = plugin.PropertyDiff{Kind: kind}
+}
+
+func (d *driftDiffer) diffObjects(path resource.PropertyPath, diff resource.ObjectDiff) {
+	for k := range diff.Adds {
+		d.add(appendPath(path, string(k)), plugin.DiffAdd)
+	}
+	for k := range diff.Deletes {
+		d.add(appendPath(path, string(k)), plugin.DiffDelete)
+	}
+	for k, update := range diff.Updates {
+		d.diffValues(appendPath(path, string(k)), update)
+	}
+}
+
+func (d *driftDiffer) diffValues(path resource.PropertyPath, diff resource.ValueDiff) {
+	switch {
+	case diff.Object != nil:
+		d.diffObjects(path, *diff.Object)
+	case diff.Array != nil:
+		for i := range diff.Array.Adds {
+			d.add(appendPath(path, i), plugin.DiffAdd)
+		}
+		for i := range diff.Array.Deletes {
+			d.add(appendPath(path, i), plugin.DiffDelete)
+		}
+		for i, update := range diff.Array.Updates {
+			d.diffValues(appendPath(path, i), update)
+		}
+	default:
+		d.add(path, plugin.DiffUpdate)
+	}
+}
+
+// appendPath returns a new path that locates the given element of the property located by the given path.
+func appendPath(path resource.PropertyPath, element interface{}) resource.PropertyPath {
+	return append(path[:len(path):len(path)], element)
+}
@@ -0,0 +1,87 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// sameExplanation records how the step generator compared a resource's old and new inputs, so that it can explain
+// why it decided that the resource was unchanged.
+type sameExplanation struct {
+	compared   []string // the input properties that were compared.
+	normalized []string // the input properties whose values the provider normalized before they were compared.
+	ignored    []string // the property paths whose changes were ignored.
+	reason     string   // how the comparison found no changes.
+}
+
+// recordInputs records the properties that are compared, and those whose values the provider normalized, given the
+// old and new inputs as the program supplied them and as they were normalized.
+func (e *sameExplanation) recordInputs(oldInputs, newInputs, normalizedOld, normalizedNew resource.PropertyMap) {
+	e.compared = propertyKeys(normalizedOld, normalizedNew)
+	normalized := make(map[string]bool)
+	for _, k := range append(changedProperties(oldInputs, normalizedOld), changedProperties(newInputs, normalizedNew)...) {
+		normalized[k] = true
+	}
+	for k := range normalized {
+		e.normalized = append(e.normalized, k)
+	}
+	sort.Strings(e.normalized)
+}
+
+// report issues an informational message that explains why the given resource is unchanged.
+func (e *sameExplanation) report(d diag.Sink, urn resource.URN) {
+	list := func(names []string) string {
+		if len(names) == 0 {
+			return "(none)"
+		}
+		return strings.Join(names, ", ")
+	}
+	d.Infof(diag.GetResourceUnchangedInfo(urn), e.reason, list(e.compared), list(e.normalized), list(e.ignored))
+}
+
+// propertyKeys returns the sorted names of the properties of any of the given maps, other than internal properties.
+func propertyKeys(maps ...resource.PropertyMap) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if name := string(k); !seen[name] && !strings.HasPrefix(name, "__") {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// changedProperties returns the sorted names of the properties whose values differ between the given maps, including
+// those that are only present in one of them.
+func changedProperties(before, after resource.PropertyMap) []string {
+	var changed []string
+	for _, k := range propertyKeys(before, after) {
+		b, hasBefore := before[resource.PropertyKey(k)]
+		a, hasAfter := after[resource.PropertyKey(k)]
+		if hasBefore != hasAfter || !b.DeepEquals(a) {
+			changed = append(changed, k)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,66 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// features is the set of feature flags known to the engine, mapped to their descriptions. Feature flags gate
+// experimental behavior: code that implements such behavior registers a flag for it, and then only enables the
+// behavior for plans whose options enable the flag (see Options.FeatureEnabled).
+var features = struct {
+	sync.RWMutex
+	descriptions map[string]string
+}{descriptions: make(map[string]string)}
+
+// RegisterFeature registers a feature flag with the given name and description. It is an error to register two
+// feature flags with the same name.
+func RegisterFeature(name, description string) error {
+	if name == "" {
+		return errors.New("feature flags must have a name")
+	}
+
+	features.Lock()
+	defer features.Unlock()
+	if _, has := features.descriptions[name]; has {
+		return errors.Errorf("a feature flag named '%v' has already been registered", name)
+	}
+	features.descriptions[name] = description
+	return nil
+}
+
+// IsKnownFeature returns true if a feature flag with the given name has been registered.
+func IsKnownFeature(name string) bool {
+	features.RLock()
+	defer features.RUnlock()
+	_, has := features.descriptions[name]
+	return has
+}
+
+// KnownFeatures returns the names of all registered feature flags, in sorted order.
+func KnownFeatures() []string {
+	features.RLock()
+	defer features.RUnlock()
+	var names []string
+	for name := range features.descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterFeature(t *testing.T) {
+	assert.False(t, IsKnownFeature("test-feature"))
+	assert.NoError(t, RegisterFeature("test-feature", "a feature used for testing"))
+	assert.True(t, IsKnownFeature("test-feature"))
+	assert.Contains(t, KnownFeatures(), "test-feature")
+
+	// Feature flags must be named, and may only be registered once.
+	assert.Error(t, RegisterFeature("", "a feature with no name"))
+	assert.Error(t, RegisterFeature("test-feature", "the same feature again"))
+
+	// Known or not, a feature is only enabled if the plan's options enable it.
+	opts := Options{Features: map[string]bool{"test-feature": true}}
+	assert.True(t, opts.FeatureEnabled("test-feature"))
+	assert.False(t, opts.FeatureEnabled("other-feature"))
+	assert.False(t, Options{}.FeatureEnabled("test-feature"))
+}
@@ -0,0 +1,71 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// NormalizeInputsFunction is the function that a provider may implement to normalize a resource's inputs before they
+// are diffed, e.g. by lowercasing values that the provider treats case-insensitively or by filling in defaults. The
+// function is called once per diff using the provider protocol's Invoke method with three arguments: "type", the token
+// of the resource's type, and "olds" and "news", the resource's old and new inputs.
+//
+// It returns an object whose "olds" and "news" properties hold the normalized inputs. If either property is missing,
+// those inputs are used as-is. Normalized inputs are only used to compute diffs: the inputs recorded in a resource's
+// state, and shown in previews, are always those that the program supplied.
+//
+// Providers that implement the function say so by setting supportsNormalizeInputs in their response to Configure.
+const NormalizeInputsFunction tokens.ModuleMember = "pulumi:providers:normalizeInputs"
+
+// normalizeInputs returns the given provider's normalized forms of the given old and new inputs for a resource of the
+// given type. If the provider does not implement NormalizeInputsFunction, or the inputs contain unknown values that
+// cannot be passed to it, the inputs are returned unchanged.
+func (sg *stepGenerator) normalizeInputs(urn resource.URN, t tokens.Type, olds, news resource.PropertyMap,
+	prov plugin.Provider) (resource.PropertyMap, resource.PropertyMap, error) {
+
+	// Provider resources are managed by the provider registry, which does not normalize anything.
+	if providers.IsProviderType(t) || !plugin.GetCapabilities(prov).NormalizeInputs || olds.ContainsUnknowns() ||
+		news.ContainsUnknowns() {
+		return olds, news, nil
+	}
+
+	ret, failures, err := prov.Invoke(NormalizeInputsFunction, resource.PropertyMap{
+		"type": resource.NewStringProperty(string(t)),
+		"olds": resource.NewObjectProperty(olds),
+		"news": resource.NewObjectProperty(news),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "normalizing the inputs of %v", urn)
+	}
+	if len(failures) > 0 {
+		logging.V(7).Infof("provider %v could not normalize the inputs of %v: %v", prov.Pkg(), urn, failures)
+		return olds, news, nil
+	}
+
+	normalized := func(key resource.PropertyKey, inputs resource.PropertyMap) resource.PropertyMap {
+		if v, has := ret[key]; has && v.IsObject() {
+			return v.ObjectValue()
+		}
+		return inputs
+	}
+	return normalized("olds", olds), normalized("news", news), nil
+}
@@ -17,6 +17,8 @@ package deploy
 import (
 	"context"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
@@ -53,6 +55,88 @@ type Options struct {
 	RefreshOnly       bool   // whether or not to exit after refreshing.
 	TrustDependencies bool   // whether or not to trust the resource dependency graph.
 	UseLegacyDiff     bool   // whether or not to use legacy diffing behavior.
+	RetainProtected   bool   // whether or not to retain, rather than fail to delete, protected resources.
+	ValidateOnly      bool   // whether or not to stop after checking each resource's inputs.
+	DisallowReplace   bool   // whether or not to fail rather than replace resources.
+	DeprecationErrors bool   // whether or not to fail rather than warn when deprecated types or properties are used.
+
+	// the degree of parallelism for the reads of a refresh, or zero to use Parallel. Reads have no side effects, so
+	// they may safely run with more parallelism than other resource operations.
+	ReadParallel int
+
+	// the maximum number of creations of resources of the same type that are sent to a provider at once, or <=1 to
+	// create each resource individually. Batches are only sent to providers that implement BatchCreateFunction.
+	BatchSize int
+
+	// the maximum number of resource operations that may run concurrently against each provider package.
+	ProviderParallel map[tokens.Package]int
+
+	// the slices of the stack to which the plan is restricted, if any. Resources outside of the slices keep their
+	// current state, and are not created if they do not exist.
+	Slices []Slice
+
+	// true if the resources outside of the Slices that do not exist are created, so that the resources in the slices
+	// may depend on them. The resources outside of the slices that exist still keep their current state.
+	SliceCreateMissing bool
+
+	// if non-nil, the only old resources that are refreshed before the plan executes. These resources are refreshed even
+	// if Refresh is false.
+	RefreshTargets map[resource.URN]bool
+
+	// if non-nil, the only resources that the plan may change, each of which is replaced whether or not its inputs
+	// have changed. Every other resource but the providers keeps its current state, and is not created if it does not
+	// exist.
+	ReplaceTargets map[resource.URN]bool
+
+	// the resources that depend on the ReplaceTargets, which the plan may also change, as they may be affected, but
+	// does not replace unless their changes require it.
+	DependentTargets map[resource.URN]bool
+
+	// if non-nil, the only old resources that may be deleted. Any other resources, and the resources that they depend
+	// on, are retained.
+	DeleteTargets map[resource.URN]bool
+
+	// the time limit for each create, update, or delete of a resource that does not declare a custom timeout for the
+	// operation, or zero for no limit.
+	ResourceTimeout time.Duration
+
+	// the time limits that override those of the operations of the resources that they match, whether or not the
+	// resources declare custom timeouts. Where several match a resource's operation, the last one applies.
+	ResourceTimeouts []ResourceTimeout
+
+	// the retry policy of the resources that do not override it. Any field that it does not set is taken from
+	// DefaultRetryPolicy.
+	RetryPolicy resource.RetryPolicy
+
+	// true to continue deleting resources after a delete fails. The resources that a resource which could not be
+	// deleted depends on are retained, and the resources that could not be deleted are reported once the plan completes.
+	ContinueOnError bool
+
+	// true to explain, for each resource that is unchanged, which of its properties were compared, normalized, and
+	// ignored.
+	ExplainSames bool
+
+	// the patterns of the URNs of the resources whose steps are logged in detail as debug diagnostics.
+	LogResources []resource.URNPattern
+
+	// the maximum number of lifecycle events kept in the history of each resource: zero for DefaultResourceHistory, or
+	// negative to keep none.
+	ResourceHistory int
+
+	// the ID with which the backend records this plan's update in the stack's history, if any. It is recorded in each
+	// lifecycle event of the resources that the plan changes.
+	UpdateID string
+
+	// true to take the inputs of each imported resource from its provider rather than from the source. The import of
+	// a resource whose inputs differ from those of the source otherwise fails.
+	AdoptImportedInputs bool
+
+	Features map[string]bool // the set of feature flags that are enabled for this plan.
+}
+
+// FeatureEnabled returns true if the feature flag with the given name is enabled.
+func (o Options) FeatureEnabled(name string) bool {
+	return o.Features[name]
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -69,6 +153,15 @@ func (o Options) InfiniteParallelism() bool {
 	return o.Parallel == math.MaxInt32
 }
 
+// ReadOptions returns the options with which the reads of a refresh are executed, which use the read parallelism, if
+// there is one, in place of the degree of parallelism.
+func (o Options) ReadOptions() Options {
+	if o.ReadParallel > 0 {
+		o.Parallel = o.ReadParallel
+	}
+	return o
+}
+
 // StepExecutorEvents is an interface that can be used to hook resource lifecycle events.
 type StepExecutorEvents interface {
 	OnResourceStepPre(step Step) (interface{}, error)
@@ -112,6 +205,17 @@ type Plan struct {
 	preview   bool                             // true if this plan is to be previewed rather than applied.
 	depGraph  *graph.DependencyGraph           // the dependency graph of the old snapshot
 	providers *providers.Registry              // the provider registry for this plan.
+
+	nonComparable     map[plugin.Provider]map[tokens.Type][]resource.PropertyPath // non-comparable properties by type.
+	nonComparableLock sync.Mutex                                                  // a lock that protects nonComparable.
+
+	arrayKeyCache map[plugin.Provider]map[tokens.Type]resource.ArrayKeys // array keys by type.
+	arrayKeyLock  sync.Mutex                                             // a lock that protects arrayKeyCache.
+
+	reads     map[readKey]plugin.ReadResult // the results of the provider reads made during this plan.
+	readsLock sync.Mutex                    // a lock that protects reads.
+
+	batcher *createBatcher // the batcher of resource creations, if they are batched.
 }
 
 // addDefaultProviders adds any necessary default provider definitions and references to the given snapshot. Version
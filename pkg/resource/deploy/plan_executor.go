@@ -16,6 +16,10 @@ package deploy
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
@@ -35,6 +39,9 @@ type planExecutor struct {
 
 	stepGen  *stepGenerator // step generator owned by this plan
 	stepExec *stepExecutor  // step executor owned by this plan
+
+	completions map[resource.URN]completionToken // tokens signalled when each registered resource's steps complete
+	waiting     sync.WaitGroup                   // chains waiting for the resources their providers reported
 }
 
 // reportExecResult issues an appropriate diagnostic depending on went wrong.
@@ -73,8 +80,12 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 		}
 	}()
 
+	if opts.BatchSize > 1 && !preview {
+		pe.plan.batcher = newCreateBatcher(opts.BatchSize)
+	}
+
 	// Before doing anything else, optionally refresh each resource in the base checkpoint.
-	if opts.Refresh {
+	if opts.Refresh || opts.RefreshTargets != nil {
 		if res := pe.refresh(callerCtx, opts, preview); res != nil {
 			return res
 		}
@@ -91,6 +102,7 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 
 	// Set up a step generator for this plan.
 	pe.stepGen = newStepGenerator(pe.plan, opts)
+	pe.completions = make(map[resource.URN]completionToken)
 
 	// Retire any pending deletes that are currently present in this plan.
 	if res := pe.retirePendingDeletes(callerCtx, opts, preview); res != nil {
@@ -102,7 +114,7 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 	ctx, cancel := context.WithCancel(callerCtx)
 
 	// Set up a step generator and executor for this plan.
-	pe.stepExec = newStepExecutor(ctx, cancel, pe.plan, opts, preview, false)
+	pe.stepExec = newStepExecutor(callerCtx, ctx, cancel, pe.plan, opts, preview, opts.ContinueOnError)
 
 	// We iterate the source in its own goroutine because iteration is blocking and we want the main loop to be able to
 	// respond to cancellation requests promptly.
@@ -152,6 +164,9 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 				}
 
 				if event.Event == nil {
+					// Every chain must be submitted before the step executor is told that there are no more.
+					pe.waiting.Wait()
+
 					deleteSteps := pe.stepGen.GenerateDeletes()
 					deletes := pe.stepGen.ScheduleDeletes(deleteSteps)
 
@@ -161,12 +176,23 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 					// This is not "true" delete parallelism, since there may be resources that could safely begin
 					// deleting but we won't until the previous set of deletes fully completes. This approximation is
 					// conservative, but correct.
+					//
+					// If we are continuing past failed deletes, any resources that a resource which could not be
+					// deleted depends on must be retained along with it.
+					retained := make(map[*resource.State]resource.URN)
 					for _, antichain := range deletes {
+						if opts.ContinueOnError {
+							antichain = pe.retainDependencies(antichain, retained)
+						}
+
 						logging.V(4).Infof("planExecutor.Execute(...): beginning delete antichain")
 						tok := pe.stepExec.ExecuteParallel(antichain)
 						tok.Wait(ctx)
 						logging.V(4).Infof("planExecutor.Execute(...): antichain complete")
 					}
+					if opts.ContinueOnError {
+						pe.reportUndeleted(retained)
+					}
 
 					// We're done here - signal completion so that the step executor knows to terminate.
 					pe.stepExec.SignalCompletion()
@@ -212,6 +238,87 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 	return res
 }
 
+// retainDependencies returns the steps of the given antichain of deletes that may run after the deletes that have
+// already failed. The steps that would delete resources that a resource which failed to delete, or which was retained,
+// depends on are removed, and their resources are added to the given map of retained resources along with the URN of
+// the resource that depends on each of them.
+func (pe *planExecutor) retainDependencies(deletes antichain,
+	retained map[*resource.State]resource.URN) antichain {
+
+	var undeleted []*resource.State
+	for _, failure := range pe.stepExec.Failures() {
+		if res := failure.step.Res(); res != nil {
+			undeleted = append(undeleted, res)
+		}
+	}
+	for res := range retained {
+		undeleted = append(undeleted, res)
+	}
+	if len(undeleted) == 0 {
+		return deletes
+	}
+
+	var steps antichain
+	for _, step := range deletes {
+		dependent, has := resource.URN(""), false
+		for _, res := range undeleted {
+			if pe.plan.depGraph.DependenciesOf(res)[step.Res()] {
+				dependent, has = res.URN, true
+				break
+			}
+		}
+		if has {
+			logging.V(4).Infof("planExecutor.retainDependencies(...): retaining %v, since %v was not deleted",
+				step.URN(), dependent)
+			retained[step.Res()] = dependent
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// reportUndeleted reports each resource that could not be deleted, along with the reason: either its delete failed,
+// or it was retained because a resource that depends on it was not deleted.
+func (pe *planExecutor) reportUndeleted(retained map[*resource.State]resource.URN) {
+	failures := pe.stepExec.Failures()
+	if len(failures) == 0 && len(retained) == 0 {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("%d resource(s) could not be deleted and remain in the stack's state:",
+		len(failures)+len(retained))}
+	for _, failure := range failures {
+		lines = append(lines, fmt.Sprintf("    - %s: %v", failure.step.URN(), failure.err))
+	}
+
+	var urns []resource.URN
+	dependents := make(map[resource.URN]resource.URN)
+	for res, dependent := range retained {
+		urns = append(urns, res.URN)
+		dependents[res.URN] = dependent
+	}
+	sort.Slice(urns, func(i, j int) bool { return urns[i] < urns[j] })
+	for _, urn := range urns {
+		lines = append(lines, fmt.Sprintf("    - %s: %s depends on it and was not deleted", urn, dependents[urn]))
+	}
+
+	pe.reportError("", errors.New(strings.Join(lines, "\n")))
+}
+
+// reportUnrefreshed reports the resources whose reads failed during a refresh in a single error, if there are any.
+func (pe *planExecutor) reportUnrefreshed(failures []stepFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("%d resource(s) could not be refreshed:", len(failures))}
+	for _, failure := range failures {
+		lines = append(lines, fmt.Sprintf("    - %s: %v", failure.step.URN(), failure.err))
+	}
+	pe.reportError("", errors.New(strings.Join(lines, "\n")))
+}
+
 // handleSingleEvent handles a single source event. For all incoming events, it produces a chain that needs
 // to be executed and schedules the chain for execution.
 func (pe *planExecutor) handleSingleEvent(event SourceEvent) result.Result {
@@ -236,10 +343,67 @@ func (pe *planExecutor) handleSingleEvent(event SourceEvent) result.Result {
 		return res
 	}
 
-	pe.stepExec.ExecuteSerial(steps)
+	for _, step := range steps {
+		pe.stepExec.logResource(step, "planned %v step", step.Op())
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+
+	// The program does not know of the dependencies that a resource's provider reported, so a resource may be
+	// registered before the resources that it depends on that way have been created or updated. Its steps must wait
+	// for theirs to complete, and are not run at all if theirs fail.
+	var deps []resource.URN
+	var toks []completionToken
+	if new := steps[0].New(); new != nil {
+		for _, dep := range new.ProviderDependencies {
+			if tok, has := pe.completions[dep]; has {
+				deps, toks = append(deps, dep), append(toks, tok)
+			}
+		}
+	}
+	if len(deps) == 0 {
+		pe.completions[steps[0].URN()] = pe.stepExec.ExecuteSerial(steps)
+		return nil
+	}
+
+	done := make(chan bool)
+	pe.completions[steps[0].URN()] = completionToken{channel: done}
+	pe.waiting.Add(1)
+	go func() {
+		defer pe.waiting.Done()
+		defer close(done)
+		for _, tok := range toks {
+			tok.Wait(pe.stepExec.ctx)
+		}
+		if dep, failed := pe.failedDependency(deps); failed {
+			err := errors.Errorf("this resource was not deployed, as its provider reported that it depends on %v, "+
+				"which failed", dep)
+			pe.stepExec.recordFailure(steps[0], err)
+			pe.plan.Diag().Errorf(diag.RawMessage(steps[0].URN(), err.Error()))
+			pe.stepExec.cancelDueToError()
+			return
+		}
+		pe.stepExec.ExecuteSerial(steps).Wait(pe.stepExec.ctx)
+	}()
 	return nil
 }
 
+// failedDependency returns the first of the given resources whose steps have failed, or were not run because those of
+// one of their own dependencies failed.
+func (pe *planExecutor) failedDependency(deps []resource.URN) (resource.URN, bool) {
+	failed := make(map[resource.URN]bool)
+	for _, failure := range pe.stepExec.Failures() {
+		failed[failure.step.URN()] = true
+	}
+	for _, dep := range deps {
+		if failed[dep] {
+			return dep, true
+		}
+	}
+	return "", false
+}
+
 // retirePendingDeletes deletes all resources that are pending deletion. Run before the start of a plan, this pass
 // ensures that the engine never sees any resources that are pending deletion from a previous plan.
 //
@@ -255,7 +419,7 @@ func (pe *planExecutor) retirePendingDeletes(callerCtx context.Context, opts Opt
 	logging.V(4).Infof("planExecutor.retirePendingDeletes(...): executing %d steps", len(steps))
 	ctx, cancel := context.WithCancel(callerCtx)
 
-	stepExec := newStepExecutor(ctx, cancel, pe.plan, opts, preview, false)
+	stepExec := newStepExecutor(callerCtx, ctx, cancel, pe.plan, opts, preview, false)
 	antichains := pe.stepGen.ScheduleDeletes(steps)
 	// Submit the deletes for execution and wait for them all to retire.
 	for _, antichain := range antichains {
@@ -290,18 +454,26 @@ func (pe *planExecutor) refresh(callerCtx context.Context, opts Options, preview
 		return nil
 	}
 
-	// Create a refresh step for each resource in the old snapshot.
-	steps := make([]Step, len(prev.Resources))
-	for i := range prev.Resources {
-		steps[i] = NewRefreshStep(pe.plan, prev.Resources[i], nil)
+	// Create a refresh step for each resource in the old snapshot that is to be refreshed.
+	steps := make([]Step, 0, len(prev.Resources))
+	refreshes := make(map[*resource.State]Step)
+	for _, old := range prev.Resources {
+		if opts.RefreshTargets != nil && !opts.RefreshTargets[old.URN] {
+			continue
+		}
+		step := NewRefreshStep(pe.plan, old, nil)
+		steps = append(steps, step)
+		refreshes[old] = step
 	}
 
-	// Fire up a worker pool and issue each refresh in turn.
+	// Fire up a worker pool and issue each refresh in turn. Reads are independent of each other, so a read that fails
+	// does not stop the others, and the failures are reported together once all of them have finished.
 	ctx, cancel := context.WithCancel(callerCtx)
-	stepExec := newStepExecutor(ctx, cancel, pe.plan, opts, preview, true)
+	stepExec := newStepExecutor(callerCtx, ctx, cancel, pe.plan, opts.ReadOptions(), preview, true)
 	stepExec.ExecuteParallel(steps)
 	stepExec.SignalCompletion()
 	stepExec.WaitForCompletion()
+	pe.reportUnrefreshed(stepExec.Failures())
 
 	// Rebuild this plan's map of old resources and dependency graph, stripping out any deleted resources and repairing
 	// dependency lists as necessary. Note that this updates the base snapshot _in memory_, so it is critical that any
@@ -331,12 +503,14 @@ func (pe *planExecutor) refresh(callerCtx context.Context, opts Options, preview
 	resources := make([]*resource.State, 0, len(prev.Resources))
 	referenceable := make(map[resource.URN]bool)
 	olds := make(map[resource.URN]*resource.State)
-	for _, s := range steps {
-		new := s.New()
-		if new == nil {
-			contract.Assert(s.Old().Custom)
-			contract.Assert(!providers.IsProviderType(s.Old().Type))
-			continue
+	for _, old := range prev.Resources {
+		new := old
+		if s, ok := refreshes[old]; ok {
+			if new = s.New(); new == nil {
+				contract.Assert(old.Custom)
+				contract.Assert(!providers.IsProviderType(old.Type))
+				continue
+			}
 		}
 
 		// Remove any deleted resources from this resource's dependency list.
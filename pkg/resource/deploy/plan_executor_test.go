@@ -0,0 +1,42 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestFailedDependency(t *testing.T) {
+	resA, resB, resC := newResource("a"), newResource("b"), newResource("c")
+	pe := &planExecutor{stepExec: &stepExecutor{}}
+
+	_, failed := pe.failedDependency([]resource.URN{resA.URN, resB.URN})
+	assert.False(t, failed)
+
+	// Once resB's steps have failed, the resources that depend on it are not run.
+	pe.stepExec.recordFailure(NewDeleteStep(nil, resB), errors.New("failed"))
+	dep, failed := pe.failedDependency([]resource.URN{resA.URN, resB.URN, resC.URN})
+	assert.True(t, failed)
+	assert.Equal(t, resB.URN, dep)
+
+	_, failed = pe.failedDependency([]resource.URN{resA.URN, resC.URN})
+	assert.False(t, failed)
+}
@@ -0,0 +1,150 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/retry"
+)
+
+// InProgressKey is the output property with which a provider reports that it returned from the creation or update of
+// a resource before that operation completed. Its value is a string token that identifies the operation, which the
+// engine passes to PollOperationFunction until the operation completes.
+const InProgressKey resource.PropertyKey = "__inProgress"
+
+// PollOperationFunction is the function that a provider implements to report the progress of an operation that it
+// completes asynchronously. The function is called using the provider protocol's Invoke method with three arguments:
+// "urn" and "id", which identify the resource, and "token", the token that identifies the operation.
+//
+// It returns an object with these optional properties: "done", which is true once the operation has completed;
+// "token", a new token with which to poll the operation next; "message", which describes the progress of an operation
+// that has not completed, or the failure of one that has; "failed", which is true if the operation failed; and
+// "outputs", the resource's outputs once the operation has completed.
+//
+// Only providers that set supportsPollOperation in their response to Configure may report operations in progress.
+const PollOperationFunction tokens.ModuleMember = "pulumi:providers:pollOperation"
+
+// pollDelay and pollMaxDelay bound the time between successive polls of an operation.
+var (
+	pollDelay    = time.Second
+	pollMaxDelay = 30 * time.Second
+)
+
+// pollOperation polls the operation that the given step's provider reported to be in progress, if any, until it
+// completes, and then replaces the outputs of the step's new state with those that the provider reports for it. The
+// polling stops, and the operation is assumed to be still in progress, if the context is canceled.
+//
+// The resource exists once the provider has returned its ID, so if the operation fails or cannot be followed to its
+// completion, the resource is recorded with the failure as an initialization error, and StatusPartialFailure is
+// returned. A later update tries again to bring the resource to its desired state, and a refresh or destroy finds it.
+func pollOperation(ctx context.Context, step Step) (resource.Status, error) {
+	new := step.New()
+	tokenValue, has := new.Outputs[InProgressKey]
+	if !has {
+		return resource.StatusOK, nil
+	}
+	if !tokenValue.IsString() {
+		return incompleteOperation(new, errors.Errorf(
+			"the provider of resource %v reported an in-progress operation without a token", step.URN()))
+	}
+	prov, err := getProvider(step)
+	if err != nil {
+		return incompleteOperation(new, err)
+	}
+	if !plugin.GetCapabilities(prov).PollOperation {
+		return incompleteOperation(new, errors.Errorf(
+			"the provider of resource %v reported an in-progress operation, but does not implement %v",
+			step.URN(), PollOperationFunction))
+	}
+
+	token := tokenValue.StringValue()
+	backoff := 1.5
+	done, data, err := retry.Until(ctx, retry.Acceptor{
+		Delay:    &pollDelay,
+		Backoff:  &backoff,
+		MaxDelay: &pollMaxDelay,
+		Accept: func(try int, nextRetryTime time.Duration) (bool, interface{}, error) {
+			logging.V(7).Infof("polling operation %v on resource %v (try %d)", token, step.URN(), try)
+			ret, failures, err := prov.Invoke(PollOperationFunction, resource.PropertyMap{
+				"urn":   resource.NewStringProperty(string(step.URN())),
+				"id":    resource.NewStringProperty(string(new.ID)),
+				"token": resource.NewStringProperty(token),
+			})
+			if err != nil {
+				return false, nil, errors.Wrapf(err, "polling the operation on resource %v", step.URN())
+			}
+			if len(failures) > 0 {
+				return false, nil, errors.Errorf("polling the operation on resource %v failed: %v",
+					step.URN(), failures[0].Reason)
+			}
+
+			if next, has := ret["token"]; has && next.IsString() {
+				token = next.StringValue()
+			}
+			if isDone, has := ret["done"]; has && isDone.IsBool() && isDone.BoolValue() {
+				return true, ret, nil
+			}
+			if msg, has := ret["message"]; has && msg.IsString() {
+				step.Plan().Ctx().StatusDiag.Infof(diag.RawMessage(step.URN(), msg.StringValue()))
+			}
+			return false, nil, nil
+		},
+	})
+	if err != nil {
+		return incompleteOperation(new, err)
+	}
+	if !done {
+		return incompleteOperation(new, errors.Errorf(
+			"stopped waiting for the %v of resource %v, which may still be in progress", step.Op(), step.URN()))
+	}
+
+	ret := data.(resource.PropertyMap)
+	if outs, has := ret["outputs"]; has && outs.IsObject() {
+		new.Outputs = outs.ObjectValue()
+	} else {
+		outs := new.Outputs.Copy()
+		delete(outs, InProgressKey)
+		new.Outputs = outs
+	}
+
+	if failed, has := ret["failed"]; has && failed.IsBool() && failed.BoolValue() {
+		msg := "the operation failed"
+		if m, has := ret["message"]; has && m.IsString() {
+			msg = m.StringValue()
+		}
+		new.InitErrors = []string{msg}
+		return resource.StatusPartialFailure, &plugin.InitError{Reasons: new.InitErrors}
+	}
+	return resource.StatusOK, nil
+}
+
+// incompleteOperation records the given error as an initialization error of the given state, whose operation is in
+// progress but could not be followed to its completion, and removes the operation's token from its outputs.
+func incompleteOperation(new *resource.State, err error) (resource.Status, error) {
+	outs := new.Outputs.Copy()
+	delete(outs, InProgressKey)
+	new.Outputs = outs
+	new.InitErrors = []string{err.Error()}
+	return resource.StatusPartialFailure, err
+}
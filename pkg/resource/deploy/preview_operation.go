@@ -0,0 +1,79 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// PreviewOperationFunction is the function that a provider may implement to predict the outputs that the creation or
+// update of a resource would produce, so that a preview can show the inputs of the resources that depend on it more
+// accurately. The function must not have any side effects. It is called during previews using the provider protocol's
+// Invoke method with these arguments: "operation", which is "create" or "update"; "urn", which identifies the
+// resource; "id" and "olds", the ID and current outputs of a resource that is to be updated; and "news", the
+// resource's new inputs. It is not called if the new inputs contain unknown values.
+//
+// It returns an object whose "outputs" property holds the predicted outputs. An output that the provider cannot
+// predict should be omitted, and is unknown during the preview. If the "outputs" property is missing, every output is
+// unknown, as it is for providers that do not implement the function. Only providers that set supportsPreviewOperation
+// in their response to Configure are asked.
+const PreviewOperationFunction tokens.ModuleMember = "pulumi:providers:previewOperation"
+
+// previewOperation asks the given provider to predict the outputs of the given operation on a resource, and returns
+// them, or nil if they cannot be predicted. olds and id are only used for updates.
+func (p *Plan) previewOperation(prov plugin.Provider, op StepOp, urn resource.URN, id resource.ID,
+	olds, news resource.PropertyMap) resource.PropertyMap {
+
+	// Provider resources are managed by the provider registry, which does not predict anything.
+	if providers.IsProviderType(urn.Type()) || news.ContainsUnknowns() || !plugin.GetCapabilities(prov).PreviewOperation {
+		return nil
+	}
+
+	args := resource.PropertyMap{
+		"urn":  resource.NewStringProperty(string(urn)),
+		"news": resource.NewObjectProperty(news),
+	}
+	switch op {
+	case OpCreate, OpCreateReplacement:
+		args["operation"] = resource.NewStringProperty("create")
+	case OpUpdate:
+		args["operation"] = resource.NewStringProperty("update")
+		args["id"] = resource.NewStringProperty(string(id))
+		args["olds"] = resource.NewObjectProperty(olds)
+	default:
+		return nil
+	}
+
+	ret, failures, err := prov.Invoke(PreviewOperationFunction, args)
+	if err != nil {
+		logging.V(7).Infof("provider %v failed to preview the %v of %v: %v", prov.Pkg(), op, urn, err)
+		return nil
+	}
+	if len(failures) > 0 {
+		logging.V(7).Infof("provider %v could not preview the %v of %v: %v", prov.Pkg(), op, urn, failures)
+		return nil
+	}
+
+	outputs, has := ret["outputs"]
+	if !has || !outputs.IsObject() {
+		return nil
+	}
+	logging.V(7).Infof("provider %v predicted the outputs of the %v of %v", prov.Pkg(), op, urn)
+	return outputs.ObjectValue()
+}
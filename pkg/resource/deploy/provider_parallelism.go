@@ -0,0 +1,72 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// ProviderParallelismConfigKey is the configuration key that a stack may use to limit the number of resource
+// operations that run concurrently against each provider. Its value is a comma-separated list of limits of the form
+// accepted by ParseProviderParallelism, e.g. "aws=5,gcp=10".
+var ProviderParallelismConfigKey = config.MustMakeKey("pulumi", "providerParallel")
+
+// ParseProviderParallelism parses a list of per-provider concurrency limits, each of the form "<package>=<n>", into a
+// map from provider package to limit. Later limits for the same package override earlier ones.
+func ParseProviderParallelism(specs []string) (map[tokens.Package]int, error) {
+	limits := make(map[tokens.Package]int)
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		eq := strings.Index(spec, "=")
+		if eq <= 0 {
+			return nil, errors.Errorf("invalid provider parallelism '%s': expected <package>=<n>", spec)
+		}
+		pkg, value := strings.TrimSpace(spec[:eq]), strings.TrimSpace(spec[eq+1:])
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, errors.Errorf("invalid provider parallelism '%s': %q is not a positive integer", spec, value)
+		}
+		limits[tokens.Package(pkg)] = n
+	}
+	return limits, nil
+}
+
+// stepProviderPackage returns the package of the provider that applies the given step, if the step's resource has a
+// provider.
+func stepProviderPackage(step Step) (tokens.Package, bool) {
+	state := step.New()
+	if state == nil {
+		state = step.Old()
+	}
+	if state == nil || state.Provider == "" {
+		return "", false
+	}
+	ref, err := providers.ParseReference(state.Provider)
+	if err != nil {
+		return "", false
+	}
+	return providers.GetProviderPackage(ref.URN().Type()), true
+}
@@ -0,0 +1,34 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestParseProviderParallelism(t *testing.T) {
+	limits, err := ParseProviderParallelism([]string{"aws=5", " gcp = 2 ", "", "aws=3"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[tokens.Package]int{"aws": 3, "gcp": 2}, limits)
+
+	for _, spec := range []string{"aws", "=5", "aws=", "aws=five", "aws=0", "aws=-1"} {
+		_, err = ParseProviderParallelism([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// readKey identifies a resource read by the provider that read it, so that two resources with the same URN and ID
+// that are managed by different providers are never confused.
+type readKey struct {
+	prov plugin.Provider
+	urn  resource.URN
+	id   resource.ID
+}
+
+// read reads the current state of a resource using the given provider, unless the same resource has already been
+// read successfully during this plan, in which case the earlier result is returned; this happens when an update that
+// refreshes its resources first runs a program that reads one of them. Reads that fail, even partially, are not
+// remembered, and nor are reads of resources that a step has since changed.
+func (p *Plan) read(prov plugin.Provider, urn resource.URN, id resource.ID,
+	inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+	key := readKey{prov: prov, urn: urn, id: id}
+	p.readsLock.Lock()
+	cached, has := p.reads[key]
+	p.readsLock.Unlock()
+	if has {
+		logging.V(7).Infof("using the result of an earlier read of %v (%v)", urn, id)
+		return copyReadResult(cached), resource.StatusOK, nil
+	}
+
+	result, rst, err := prov.Read(urn, id, inputs, state)
+	if err != nil {
+		return result, rst, err
+	}
+
+	p.readsLock.Lock()
+	if p.reads == nil {
+		p.reads = make(map[readKey]plugin.ReadResult)
+	}
+	p.reads[key] = copyReadResult(result)
+	p.readsLock.Unlock()
+	return result, rst, nil
+}
+
+// forgetReads discards the remembered reads of the resource with the given URN, after a step has changed it.
+func (p *Plan) forgetReads(urn resource.URN) {
+	p.readsLock.Lock()
+	defer p.readsLock.Unlock()
+	for key := range p.reads {
+		if key.urn == urn {
+			delete(p.reads, key)
+		}
+	}
+}
+
+// copyReadResult returns a copy of the given read result's property maps, so that a step that adds properties to, or
+// removes them from, a remembered result does not change it for the steps that use it later.
+func copyReadResult(result plugin.ReadResult) plugin.ReadResult {
+	var copied plugin.ReadResult
+	if result.Inputs != nil {
+		copied.Inputs = result.Inputs.Copy()
+	}
+	if result.Outputs != nil {
+		copied.Outputs = result.Outputs.Copy()
+	}
+	return copied
+}
@@ -0,0 +1,68 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestReadCache(t *testing.T) {
+	reads := map[resource.ID]int{}
+	newProvider := func() plugin.Provider {
+		return &deploytest.Provider{
+			ReadF: func(urn resource.URN, id resource.ID,
+				inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+				reads[id]++
+				return plugin.ReadResult{
+					Outputs: resource.PropertyMap{"id": resource.NewStringProperty(string(id))},
+				}, resource.StatusOK, nil
+			},
+		}
+	}
+	prov, other := newProvider(), newProvider()
+	urn := resource.NewURN("test", "test", "", "pkgA:m:typA", "resA")
+
+	p := &Plan{}
+	result, _, err := p.read(prov, urn, "id1", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id1", result.Outputs["id"].StringValue())
+
+	// A second read of the same resource uses the first one's result, which a step cannot change.
+	result.Outputs["id"] = resource.NewStringProperty("changed")
+	result, _, err = p.read(prov, urn, "id1", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id1", result.Outputs["id"].StringValue())
+	assert.Equal(t, 1, reads["id1"])
+
+	// A resource with a different ID, or that is managed by a different provider, is read again.
+	_, _, err = p.read(prov, urn, "id2", nil, nil)
+	assert.NoError(t, err)
+	_, _, err = p.read(other, urn, "id1", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[resource.ID]int{"id1": 2, "id2": 1}, reads)
+
+	// Once a resource has changed, it is read again.
+	p.forgetReads(urn)
+	_, _, err = p.read(prov, urn, "id1", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, reads["id1"])
+}
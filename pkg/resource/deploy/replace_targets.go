@@ -0,0 +1,119 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/graph"
+)
+
+// isTarget returns true if the resource with the given URN is a target of --target-replace, or a dependent of one that
+// the plan includes.
+func (sg *stepGenerator) isTarget(urn resource.URN) bool {
+	return sg.opts.ReplaceTargets[urn] || sg.opts.DependentTargets[urn]
+}
+
+// checkReplaceTargetDependencies fails if the given resource, which is a target of --target-replace or a dependent of
+// one, depends on any resources that were not created because they are not targets. It returns true if the resource
+// may proceed.
+func (sg *stepGenerator) checkReplaceTargetDependencies(urn resource.URN, goal *resource.Goal) bool {
+	var missing []string
+	for dep := range goalDependencies(goal) {
+		if sg.skipped[dep] {
+			missing = append(missing, string(dep))
+		}
+	}
+	if len(missing) == 0 {
+		return true
+	}
+	sort.Strings(missing)
+	sg.plan.Diag().Errorf(diag.RawMessage(urn,
+		"this resource depends on resources that are not targets of --target-replace and do not exist, so it cannot "+
+			"be deployed until they are created: "+strings.Join(missing, ", ")))
+	return false
+}
+
+// warnReplaceTargetDependents warns about the resources that depend on the given resource, which is a target of
+// --target-replace, but are not themselves targets. They keep their current state, so they may refer to the resource
+// that is replaced until they are updated.
+func (sg *stepGenerator) warnReplaceTargetDependents(old *resource.State) {
+	if sg.plan.depGraph == nil {
+		return
+	}
+
+	var outside []string
+	for _, dep := range sg.plan.depGraph.DependingOn(old) {
+		if !dep.Delete && !sg.isTarget(dep.URN) {
+			outside = append(outside, string(dep.URN))
+		}
+	}
+	if len(outside) == 0 {
+		return
+	}
+	sort.Strings(outside)
+	sg.plan.Diag().Warningf(diag.RawMessage(old.URN,
+		"resources that depend on this resource are not targets of --target-replace, so they keep their current "+
+			"state and may refer to the resource that is replaced until they are updated; pass --target-dependents "+
+			"to include them: "+strings.Join(outside, ", ")))
+}
+
+// ReplaceTargetDependents returns the set of the resources in the given snapshot that depend, directly or indirectly,
+// on any of the given targets but are not targets themselves, for use as the DependentTargets of a plan.
+func ReplaceTargetDependents(snap *Snapshot, targets map[resource.URN]bool) map[resource.URN]bool {
+	dependents := make(map[resource.URN]bool)
+	if snap == nil {
+		return dependents
+	}
+	dg := graph.NewDependencyGraph(snap.Resources)
+	for _, res := range snap.Resources {
+		if res.Delete || !targets[res.URN] {
+			continue
+		}
+		for _, dep := range dg.DependingOn(res) {
+			if !dep.Delete && !targets[dep.URN] {
+				dependents[dep.URN] = true
+			}
+		}
+	}
+	return dependents
+}
+
+// ReplaceTargetsOf returns the set of the given URNs, each of which must name a resource in the given snapshot that is
+// not pending deletion, for use as the ReplaceTargets of a plan.
+func ReplaceTargetsOf(snap *Snapshot, urns []resource.URN) (map[resource.URN]bool, error) {
+	existing := make(map[resource.URN]bool)
+	if snap != nil {
+		for _, res := range snap.Resources {
+			if !res.Delete {
+				existing[res.URN] = true
+			}
+		}
+	}
+
+	targets := make(map[resource.URN]bool)
+	for _, urn := range urns {
+		if !existing[urn] {
+			return nil, errors.Errorf("cannot replace '%v', as no resource with that URN exists in the stack", urn)
+		}
+		targets[urn] = true
+	}
+	return targets, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// ResourceDependenciesFunction is the function that a provider may implement to report dependencies of a resource
+// that its program does not express, such as a resource that must wait for the permissions that other resources grant
+// to take effect. It is called using the provider protocol's Invoke method with these arguments: "urn" and "news",
+// the resource's URN and checked inputs; and "resources", an array of objects with the "urn", "type", and "inputs" of
+// each resource of the provider's package that was registered earlier in the plan.
+//
+// It returns an object whose "dependencies" property is an array of the URNs of those resources that the resource
+// depends on. The resource's operations wait for theirs to complete, and the dependencies are recorded in the
+// resource's state along with those that its program declared. Only providers that set supportsResourceDependencies in
+// their response to Configure are asked.
+const ResourceDependenciesFunction tokens.ModuleMember = "pulumi:providers:resourceDependencies"
+
+// StrictResourceDependenciesFeature is the feature flag that fails a resource whose provider reports dependencies that
+// cannot be honored, rather than ignoring them with a warning.
+const StrictResourceDependenciesFeature = "strict-resource-dependencies"
+
+func init() {
+	contract.AssertNoError(RegisterFeature(StrictResourceDependenciesFeature,
+		"fail resources whose providers report dependencies that cannot be honored"))
+}
+
+// dependencyCandidate is a resource that was registered earlier in the plan, on which a later resource of the same
+// package may depend according to its provider.
+type dependencyCandidate struct {
+	urn    resource.URN
+	inputs resource.PropertyMap
+}
+
+// addProviderDependencies asks the given provider for the dependencies of the given new resource that its program
+// does not declare, and adds them to the resource's state. A reported dependency on a resource that was not a
+// candidate is ignored with a warning, unless StrictResourceDependenciesFeature is enabled, in which case it is
+// reported as an error and addProviderDependencies returns true.
+//
+// The candidates are the resources that were registered before the new resource, just as a program may only declare
+// dependencies on resources that it has already registered. Every dependency therefore points to an earlier resource,
+// so the dependencies that providers report can never form a cycle, either among themselves or with those that
+// programs declare. A reported dependency on a later resource, which might form one, is ignored like any other
+// resource that is not a candidate.
+func (sg *stepGenerator) addProviderDependencies(prov plugin.Provider, new *resource.State) (bool, error) {
+	// Only custom resources have providers.
+	if !new.Custom {
+		return false, nil
+	}
+	pkg := new.Type.Package()
+	candidates := sg.dependencyCandidates[pkg]
+	sg.dependencyCandidates[pkg] = append(candidates, dependencyCandidate{urn: new.URN, inputs: new.Inputs})
+	if prov == nil || providers.IsProviderType(new.Type) || len(candidates) == 0 ||
+		!plugin.GetCapabilities(prov).ResourceDependencies {
+		return false, nil
+	}
+
+	resources := make([]resource.PropertyValue, len(candidates))
+	candidateURNs := make(map[resource.URN]bool)
+	for i, c := range candidates {
+		candidateURNs[c.urn] = true
+		resources[i] = resource.NewObjectProperty(resource.PropertyMap{
+			"urn":    resource.NewStringProperty(string(c.urn)),
+			"type":   resource.NewStringProperty(string(c.urn.Type())),
+			"inputs": resource.NewObjectProperty(c.inputs),
+		})
+	}
+	ret, failures, err := prov.Invoke(ResourceDependenciesFunction, resource.PropertyMap{
+		"urn":       resource.NewStringProperty(string(new.URN)),
+		"news":      resource.NewObjectProperty(new.Inputs),
+		"resources": resource.NewArrayProperty(resources),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting the dependencies of resource %v from its provider", new.URN)
+	}
+	if len(failures) > 0 {
+		return false, errors.Errorf("getting the dependencies of resource %v from its provider failed: %v", new.URN,
+			failures[0].Reason)
+	}
+	deps := ret["dependencies"]
+	if !deps.IsArray() {
+		return false, nil
+	}
+
+	declared := make(map[resource.URN]bool)
+	for _, dep := range new.Dependencies {
+		declared[dep] = true
+	}
+	var ignored []string
+	for _, v := range deps.ArrayValue() {
+		if !v.IsString() {
+			continue
+		}
+		dep := resource.URN(v.StringValue())
+		switch {
+		case declared[dep]:
+			continue
+		case !candidateURNs[dep]:
+			ignored = append(ignored, string(dep))
+			continue
+		}
+		declared[dep] = true
+		new.ProviderDependencies = append(new.ProviderDependencies, dep)
+		new.Dependencies = append(new.Dependencies, dep)
+	}
+	if len(new.ProviderDependencies) > 0 {
+		logging.V(7).Infof("provider %v reported that %v depends on %v", prov.Pkg(), new.URN, new.ProviderDependencies)
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		if sg.opts.FeatureEnabled(StrictResourceDependenciesFeature) {
+			sg.plan.Diag().Errorf(diag.RawMessage(new.URN, "the provider reported dependencies on resources that "+
+				"were not registered before this one: "+strings.Join(ignored, ", ")))
+			return true, nil
+		}
+		sg.plan.Diag().Warningf(diag.RawMessage(new.URN, "ignoring the dependencies that the provider reported on "+
+			"resources that were not registered before this one: "+strings.Join(ignored, ", ")))
+	}
+	return false, nil
+}
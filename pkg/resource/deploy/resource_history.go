@@ -0,0 +1,68 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+// ResourceHistoryConfigKey is the configuration key that a stack may use to set the number of lifecycle events that
+// are kept in the history of each of its resources. A value of zero keeps no history.
+var ResourceHistoryConfigKey = config.MustMakeKey("pulumi", "resourceHistory")
+
+// DefaultResourceHistory is the number of lifecycle events that are kept in the history of each resource if the stack
+// does not configure it.
+const DefaultResourceHistory = 10
+
+// lifecycleEventOps maps the ops of the steps that are recorded in the histories of their resources to the names under
+// which they are recorded.
+var lifecycleEventOps = map[StepOp]string{
+	OpCreate:            "create",
+	OpUpdate:            "update",
+	OpCreateReplacement: "replace",
+	OpImport:            "import",
+	OpImportReplacement: "import",
+}
+
+// ResourceHistoryLength returns the maximum number of lifecycle events that are kept in each resource's history.
+func (o Options) ResourceHistoryLength() int {
+	switch {
+	case o.ResourceHistory == 0:
+		return DefaultResourceHistory
+	case o.ResourceHistory < 0:
+		return 0
+	default:
+		return o.ResourceHistory
+	}
+}
+
+// recordLifecycleEvent appends the given step, which has just succeeded, to the history of its new resource, if the
+// step changed the resource and is not part of a preview.
+func (se *stepExecutor) recordLifecycleEvent(step Step) {
+	op, has := lifecycleEventOps[step.Op()]
+	if !has || se.preview || step.New() == nil {
+		return
+	}
+
+	new := step.New()
+	new.History = resource.AppendLifecycleEvent(new.History, resource.LifecycleEvent{
+		Op:        op,
+		Time:      time.Now().UTC(),
+		Operation: se.opts.UpdateID,
+	}, se.opts.ResourceHistoryLength())
+}
@@ -0,0 +1,137 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// logsResource returns true if the steps of the resource with the given URN are to be logged in detail.
+func (se *stepExecutor) logsResource(urn resource.URN) bool {
+	return resource.MatchesAnyURNPattern(se.opts.LogResources, urn)
+}
+
+// logResource issues a debug diagnostic about the resource that the given step operates on, if its steps are to be
+// logged in detail. Unlike the step executor's own log, these diagnostics are part of the update's events, so that
+// they are displayed for just the resources that the user asked to see.
+func (se *stepExecutor) logResource(step Step, msg string, args ...interface{}) {
+	if !se.logsResource(step.URN()) {
+		return
+	}
+	se.plan.Diag().Debugf(diag.RawMessage(step.URN(), fmt.Sprintf(msg, args...)))
+}
+
+// logStepStart logs the beginning of the application of the given step.
+func (se *stepExecutor) logStepStart(step Step) {
+	if !se.logsResource(step.URN()) {
+		return
+	}
+
+	var details []string
+	if step.Old() != nil && step.Old().ID != "" {
+		details = append(details, fmt.Sprintf("id %v", step.Old().ID))
+	}
+	if step.Provider() != "" {
+		details = append(details, fmt.Sprintf("provider %v", step.Provider()))
+	}
+	if state := step.New(); state != nil {
+		details = append(details, fmt.Sprintf("inputs %v", propertyNames(state.Inputs)))
+	}
+	if diffs := stepDiffs(step); len(diffs) > 0 {
+		details = append(details, fmt.Sprintf("changed inputs %v", keyNames(diffs)))
+	}
+
+	what := "applying"
+	if se.preview {
+		what = "previewing"
+	}
+	msg := fmt.Sprintf("%v %v step", what, step.Op())
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+	se.logResource(step, "%s", msg)
+}
+
+// logStepEnd logs the result of the application of the given step, which took the given time.
+func (se *stepExecutor) logStepEnd(step Step, status resource.Status, elapsed time.Duration, err error) {
+	if !se.logsResource(step.URN()) {
+		return
+	}
+
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		se.logResource(step, "%v step failed after %v with status %v: %v", step.Op(), elapsed, statusName(status), err)
+		return
+	}
+
+	msg := fmt.Sprintf("%v step succeeded after %v", step.Op(), elapsed)
+	if state := step.New(); state != nil && !se.preview {
+		if state.ID != "" {
+			msg += fmt.Sprintf(" (id %v; outputs %v)", state.ID, propertyNames(state.Outputs))
+		} else {
+			msg += fmt.Sprintf(" (outputs %v)", propertyNames(state.Outputs))
+		}
+	}
+	se.logResource(step, "%s", msg)
+}
+
+// stepDiffs returns the properties whose changes caused the given step, if it records them.
+func stepDiffs(step Step) []resource.PropertyKey {
+	switch s := step.(type) {
+	case *UpdateStep:
+		return s.Diffs()
+	case *ReplaceStep:
+		return s.Diffs()
+	default:
+		return nil
+	}
+}
+
+// propertyNames returns a list of the names of the given properties. Only names are logged, since the values of a
+// resource's properties may be secret.
+func propertyNames(props resource.PropertyMap) string {
+	return keyNames(props.StableKeys())
+}
+
+// keyNames returns a sorted list of the given property keys.
+func keyNames(keys []resource.PropertyKey) string {
+	if len(keys) == 0 {
+		return "(none)"
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = string(k)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// statusName returns a description of the given step status.
+func statusName(status resource.Status) string {
+	switch status {
+	case resource.StatusOK:
+		return "ok"
+	case resource.StatusPartialFailure:
+		return "partial failure"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+// ResourceTimeoutsConfigKey is the configuration key that a stack may use to override the time limits of operations on
+// its resources without changing its program. Its value is a comma-separated list of timeouts of the form accepted by
+// ParseResourceTimeouts, e.g. "urn:pulumi:*::aws:rds/instance:Instance::*:create=40m".
+var ResourceTimeoutsConfigKey = config.MustMakeKey("pulumi", "resourceTimeouts")
+
+// TimeoutSource describes the setting from which the time limit of a resource operation was taken.
+type TimeoutSource string
+
+const (
+	// TimeoutSourceDefault is the default time limit of the operations of resources that do not declare one.
+	TimeoutSourceDefault TimeoutSource = "the default resource timeout"
+	// TimeoutSourceCode is a time limit declared by a resource's customTimeouts option.
+	TimeoutSourceCode TimeoutSource = "the timeout declared by its customTimeouts"
+	// TimeoutSourceConfig is a time limit set by the stack's configuration.
+	TimeoutSourceConfig TimeoutSource = "the timeout set by the stack's configuration"
+	// TimeoutSourceFlag is a time limit set on the command line.
+	TimeoutSourceFlag TimeoutSource = "the timeout set on the command line"
+)
+
+// ResourceTimeout overrides the time limit of an operation on the resources whose URNs match a pattern.
+type ResourceTimeout struct {
+	Pattern   resource.URNPattern // the pattern of the URNs of the resources to which the timeout applies.
+	Operation string              // the operation that the timeout limits: "create", "update", or "delete".
+	Timeout   time.Duration       // the time limit of the operation.
+	Source    TimeoutSource       // the setting from which the timeout was taken.
+}
+
+// ParseResourceTimeouts parses a list of resource operation timeouts, each of the form
+// "<urn pattern>:<operation>=<duration>", where the operation is "create", "update", or "delete", and the duration is
+// of the form accepted by time.ParseDuration, e.g. "10m". Each timeout records the given source.
+func ParseResourceTimeouts(specs []string, source TimeoutSource) ([]ResourceTimeout, error) {
+	var timeouts []ResourceTimeout
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		// URN patterns contain colons, so the operation follows the last colon before the last equals sign.
+		colon, eq := -1, strings.LastIndex(spec, "=")
+		if eq != -1 {
+			colon = strings.LastIndex(spec[:eq], ":")
+		}
+		if colon <= 0 {
+			return nil, errors.Errorf("invalid resource timeout '%s': expected <urn pattern>:<operation>=<duration>",
+				spec)
+		}
+		operation, value := strings.TrimSpace(spec[colon+1:eq]), strings.TrimSpace(spec[eq+1:])
+		switch operation {
+		case "create", "update", "delete":
+		default:
+			return nil, errors.Errorf("invalid resource timeout '%s': unknown operation '%s'; "+
+				"expected 'create', 'update', or 'delete'", spec, operation)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid resource timeout '%s': %q is not a positive duration, such as '10m'",
+				spec, value)
+		}
+		pattern, err := resource.ParseURNPattern(spec[:colon])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid resource timeout '%s'", spec)
+		}
+
+		timeouts = append(timeouts, ResourceTimeout{
+			Pattern:   pattern,
+			Operation: operation,
+			Timeout:   timeout,
+			Source:    source,
+		})
+	}
+	return timeouts, nil
+}
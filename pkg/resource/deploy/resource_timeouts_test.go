@@ -0,0 +1,46 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestParseResourceTimeouts(t *testing.T) {
+	timeouts, err := ParseResourceTimeouts([]string{
+		"urn:pulumi:*::aws:rds/instance:Instance::*:create=40m", "", " *::db:delete = 90s ",
+	}, TimeoutSourceConfig)
+	assert.NoError(t, err)
+	if assert.Len(t, timeouts, 2) {
+		assert.Equal(t, "urn:pulumi:*::aws:rds/instance:Instance::*", timeouts[0].Pattern.String())
+		assert.Equal(t, "create", timeouts[0].Operation)
+		assert.Equal(t, 40*time.Minute, timeouts[0].Timeout)
+		assert.Equal(t, TimeoutSourceConfig, timeouts[0].Source)
+
+		assert.True(t, timeouts[1].Pattern.Matches(resource.URN("urn:pulumi:dev::proj::pkg:m:typ::db")))
+		assert.Equal(t, "delete", timeouts[1].Operation)
+		assert.Equal(t, 90*time.Second, timeouts[1].Timeout)
+	}
+
+	for _, spec := range []string{"*", "*=10m", ":create=10m", "*:read=10m", "*:create=", "*:create=ten", "*:create=0"} {
+		_, err = ParseResourceTimeouts([]string{spec}, TimeoutSourceFlag)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// Slice is a named subset of a stack's resources. A plan that is restricted to one or more slices only creates,
+// updates, replaces, and deletes the resources in those slices; every other resource keeps its current state.
+//
+// A resource is in a slice if its URN matches one of the slice's patterns, if its type is in one of the slice's
+// modules or their submodules, if its "tags" input holds every one of the slice's tags, or if its parent is in the
+// slice. Provider resources are in every slice.
+type Slice struct {
+	Name      string                // the name of the slice.
+	Resources []resource.URNPattern // the patterns of the URNs of the resources in the slice.
+	Modules   []tokens.Module       // the modules whose resources are in the slice.
+	Tags      map[string]string     // the tags of the resources in the slice.
+}
+
+// matches returns true if the resource with the given URN and inputs matches one of the slice's rules.
+func (s *Slice) matches(urn resource.URN, inputs resource.PropertyMap) bool {
+	if resource.MatchesAnyURNPattern(s.Resources, urn) {
+		return true
+	}
+	mod := urn.Type().Module()
+	for _, m := range s.Modules {
+		if mod == m || strings.HasPrefix(string(mod), string(m)+"/") {
+			return true
+		}
+	}
+	if len(s.Tags) == 0 {
+		return false
+	}
+	tags := inputs["tags"]
+	if !tags.IsObject() {
+		return false
+	}
+	for k, v := range s.Tags {
+		tag := tags.ObjectValue()[resource.PropertyKey(k)]
+		if !tag.IsString() || tag.StringValue() != v {
+			return false
+		}
+	}
+	return true
+}
+
+// inSlices returns true if the resource with the given URN, inputs, and parent is in any of the given slices. sliced
+// records the resources that are known to be in the slices.
+func inSlices(slices []Slice, sliced map[resource.URN]bool, urn resource.URN, inputs resource.PropertyMap,
+	parent resource.URN) bool {
+
+	if len(slices) == 0 || providers.IsProviderType(urn.Type()) || (parent != "" && sliced[parent]) {
+		return true
+	}
+	for i := range slices {
+		if slices[i].matches(urn, inputs) {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceResources returns the set of the given snapshot's resources that are in any of the given slices.
+func SliceResources(snap *Snapshot, slices []Slice) map[resource.URN]bool {
+	sliced := make(map[resource.URN]bool)
+	if snap == nil {
+		return sliced
+	}
+	// The resources are stored in dependency order, so each resource's parent precedes it.
+	for _, res := range snap.Resources {
+		if inSlices(slices, sliced, res.URN, res.Inputs, res.Parent) {
+			sliced[res.URN] = true
+		}
+	}
+	return sliced
+}
+
+// goalDependencies returns the set of the resources that the given goal depends on, including its parent.
+func goalDependencies(goal *resource.Goal) map[resource.URN]bool {
+	deps := map[resource.URN]bool{}
+	for _, dep := range goal.Dependencies {
+		deps[dep] = true
+	}
+	for _, propDeps := range goal.PropertyDependencies {
+		for _, dep := range propDeps {
+			deps[dep] = true
+		}
+	}
+	if goal.Parent != "" {
+		deps[goal.Parent] = true
+	}
+	return deps
+}
+
+// keepResource returns the steps for a resource that the plan may not change, for the given reason: a same step that
+// keeps its current state, if it has an old state, old, or none, if it does not, in which case it is not created.
+func (sg *stepGenerator) keepResource(event RegisterResourceEvent, urn resource.URN, old, new *resource.State,
+	reason string) []Step {
+
+	if old != nil {
+		logging.V(7).Infof("Planner keeping '%v', which is %s", urn, reason)
+		kept := *old
+		kept.URN, kept.ID = urn, ""
+		sg.sames[urn] = true
+		return []Step{NewSameStep(sg.plan, event, old, &kept)}
+	}
+	logging.V(7).Infof("Planner skipping the creation of '%v', which is %s", urn, reason)
+	sg.plan.Diag().Infof(diag.RawMessage(urn, "this resource is not created, as it is "+reason))
+	sg.skipped[urn] = true
+	event.Done(&RegisterResult{State: new})
+	return nil
+}
+
+// checkSliceBoundary reports the resources outside of the plan's slices that the given resource, which is in them,
+// depends on. It warns about those that exist, which keep their current state, and fails if any do not exist, as they
+// have not been created unless the plan creates the missing resources outside of its slices. It returns true if the
+// resource may proceed.
+func (sg *stepGenerator) checkSliceBoundary(urn resource.URN, goal *resource.Goal) bool {
+	var outside, missing []string
+	for dep := range goalDependencies(goal) {
+		switch {
+		case sg.sliced[dep]:
+		case sg.skipped[dep]:
+			missing = append(missing, string(dep))
+		default:
+			outside = append(outside, string(dep))
+		}
+	}
+	sort.Strings(outside)
+	sort.Strings(missing)
+
+	if len(outside) > 0 {
+		sg.plan.Diag().Warningf(diag.RawMessage(urn,
+			"this resource depends on resources outside of the slice, which keep their current state: "+
+				strings.Join(outside, ", ")))
+	}
+	if len(missing) > 0 {
+		sg.plan.Diag().Errorf(diag.RawMessage(urn,
+			"this resource depends on resources outside of the slice that do not exist, so it cannot be deployed "+
+				"unless they are added to the slice or --slice-create-missing is passed: "+strings.Join(missing, ", ")))
+		return false
+	}
+	return true
+}
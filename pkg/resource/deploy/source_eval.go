@@ -673,8 +673,34 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 	protect := req.GetProtect()
 	deleteBeforeReplace := req.GetDeleteBeforeReplace()
 	ignoreChanges := req.GetIgnoreChanges()
+	replaceOnChanges := req.GetReplaceOnChanges()
 	id := resource.ID(req.GetImportId())
 	customTimeouts := req.GetCustomTimeouts()
+	priority := int(req.GetPriority())
+	retryPolicy := &resource.RetryPolicy{
+		Attempts: int(req.GetRetryAttempts()),
+		Delay:    req.GetRetryDelay(),
+		Backoff:  req.GetRetryBackoff(),
+	}
+	if retryPolicy.Attempts < 0 || retryPolicy.Delay < 0 || (retryPolicy.Backoff != 0 && retryPolicy.Backoff < 1) {
+		return nil, rpcerror.Newf(codes.InvalidArgument, "invalid retry policy for resource %s: the attempts and "+
+			"delay must not be negative, and the backoff must be at least 1", name)
+	}
+	autoName := resource.PropertyKey(req.GetAutoName())
+	if autoName != "" && !custom {
+		return nil, rpcerror.Newf(codes.InvalidArgument, "component resource %s cannot auto-name property %s; "+
+			"only custom resources have physical names", name, autoName)
+	}
+	if len(replaceOnChanges) > 0 && !custom {
+		return nil, rpcerror.Newf(codes.InvalidArgument, "component resource %s cannot be replaced on changes; "+
+			"only custom resources are replaced", name)
+	}
+	for _, path := range replaceOnChanges {
+		if _, err := resource.ParsePropertyPath(path); err != nil {
+			return nil, rpcerror.Newf(codes.InvalidArgument, "invalid replaceOnChanges path %q for resource %s: %v",
+				path, name, err)
+		}
+	}
 	var t tokens.Type
 
 	// Custom resources must have a three-part type so that we can 1) identify if they are providers and 2) retrieve the
@@ -775,14 +801,20 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 
 	logging.V(5).Infof(
 		"ResourceMonitor.RegisterResource received: t=%v, name=%v, custom=%v, #props=%v, parent=%v, protect=%v, "+
-			"provider=%v, deps=%v, deleteBeforeReplace=%v, ignoreChanges=%v, aliases=%v, customTimeouts=%v",
+			"provider=%v, deps=%v, deleteBeforeReplace=%v, ignoreChanges=%v, aliases=%v, customTimeouts=%v, priority=%v, "+
+			"retryPolicy=%+v, autoName=%v, replaceOnChanges=%v",
 		t, name, custom, len(props), parent, protect, provider, dependencies, deleteBeforeReplace, ignoreChanges,
-		aliases, timeouts)
+		aliases, timeouts, priority, *retryPolicy, autoName, replaceOnChanges)
 
 	// Send the goal state to the engine.
+	goal := resource.NewGoal(t, name, custom, props, parent, protect, dependencies, provider, nil,
+		propertyDependencies, deleteBeforeReplace, ignoreChanges, additionalSecretOutputs, aliases, id, &timeouts)
+	goal.Priority = priority
+	goal.RetryPolicy = *retryPolicy
+	goal.AutoName = autoName
+	goal.ReplaceOnChanges = replaceOnChanges
 	step := &registerResourceEvent{
-		goal: resource.NewGoal(t, name, custom, props, parent, protect, dependencies, provider, nil,
-			propertyDependencies, deleteBeforeReplace, ignoreChanges, additionalSecretOutputs, aliases, id, &timeouts),
+		goal: goal,
 		done: make(chan *RegisterResult),
 	}
 
@@ -0,0 +1,180 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/result"
+)
+
+// Import describes an existing resource that is to be imported into a stack.
+type Import struct {
+	Type tokens.Type  // the type token of the resource.
+	Name tokens.QName // the name of the resource.
+	ID   resource.ID  // the ID of the resource.
+}
+
+// NewImportSource returns a planning source that registers the stack's root resource followed by each of the given
+// resources, which are imported using their ID. Each resource is managed by the default provider for its package, and
+// is parented to the stack's root resource.
+func NewImportSource(project tokens.PackageName, stack tokens.QName, config plugin.ConfigSource,
+	imports []Import) Source {
+
+	return &importSource{project: project, stack: stack, config: config, imports: imports}
+}
+
+// An importSource registers a fixed list of resources to import.
+type importSource struct {
+	project tokens.PackageName
+	stack   tokens.QName
+	config  plugin.ConfigSource
+	imports []Import
+}
+
+func (src *importSource) Close() error                { return nil }
+func (src *importSource) Project() tokens.PackageName { return src.project }
+func (src *importSource) Info() interface{}           { return nil }
+
+func (src *importSource) Iterate(
+	ctx context.Context, opts Options, providers ProviderSource) (SourceIterator, result.Result) {
+
+	iter := &importSourceIterator{
+		src:     src,
+		regChan: make(chan *registerResourceEvent),
+		finChan: make(chan result.Result),
+		cancel:  make(chan bool),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			iter.close()
+		case <-iter.cancel:
+		}
+	}()
+	go iter.register()
+	return iter, nil
+}
+
+// importSourceIterator registers the resources of an importSource from its own goroutine, as the registration of
+// each resource's default provider must complete before the resource itself can be registered.
+type importSourceIterator struct {
+	src     *importSource
+	regChan chan *registerResourceEvent // the channel that contains resource registrations.
+	finChan chan result.Result          // the channel that communicates completion.
+	cancel  chan bool                   // closed when the iterator is closed or its context is canceled.
+	once    sync.Once
+	done    bool // set to true when all of the resources have been registered.
+}
+
+func (iter *importSourceIterator) close() {
+	iter.once.Do(func() { close(iter.cancel) })
+}
+
+func (iter *importSourceIterator) Close() error {
+	iter.close()
+	return nil
+}
+
+func (iter *importSourceIterator) Next() (SourceEvent, result.Result) {
+	if iter.done {
+		return nil, nil
+	}
+
+	select {
+	case reg := <-iter.regChan:
+		goal := reg.Goal()
+		logging.V(5).Infof("ImportSourceIterator produced a registration: t=%v,name=%v", goal.Type, goal.Name)
+		return reg, nil
+	case res := <-iter.finChan:
+		iter.done = true
+		return nil, res
+	case <-iter.cancel:
+		// The plan reports its own cancellation, so there is nothing more to say here.
+		iter.done = true
+		return nil, result.Bail()
+	}
+}
+
+// register sends a registration for the stack's root resource, then for each resource to import, preceded by the
+// registration of its default provider if that provider has not already been registered. Registrations are
+// abandoned once the iterator is canceled.
+func (iter *importSourceIterator) register() {
+	fin := func() result.Result {
+		// Register the root resource and wait for its URN, which parents each of the imported resources.
+		root, err := iter.registerAndWait(resource.NewGoal(resource.RootStackType,
+			tokens.QName(fmt.Sprintf("%s-%s", iter.src.project, iter.src.stack)), false, resource.PropertyMap{},
+			"", false, nil, "", nil, nil, false, nil, nil, nil, "", nil))
+		if err != nil {
+			return result.FromError(err)
+		}
+
+		defaults := &defaultProviders{
+			providers: make(map[string]providers.Reference),
+			config:    iter.src.config,
+			regChan:   iter.regChan,
+			cancel:    iter.cancel,
+		}
+		for _, imp := range iter.src.imports {
+			req := providers.NewProviderRequest(nil, imp.Type.Package())
+			ref, err := defaults.handleRequest(req)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			// Nothing waits for the import itself to complete, so its completion channel must not block.
+			event := &registerResourceEvent{
+				goal: resource.NewGoal(imp.Type, imp.Name, true, resource.PropertyMap{}, root.URN, false, nil,
+					ref.String(), nil, nil, false, nil, nil, nil, imp.ID, nil),
+				done: make(chan *RegisterResult, 1),
+			}
+			select {
+			case iter.regChan <- event:
+			case <-iter.cancel:
+				return result.FromError(context.Canceled)
+			}
+		}
+		return nil
+	}()
+
+	select {
+	case iter.finChan <- fin:
+	case <-iter.cancel:
+	}
+}
+
+// registerAndWait sends a registration for the given goal and returns the resulting state once it completes.
+func (iter *importSourceIterator) registerAndWait(goal *resource.Goal) (*resource.State, error) {
+	done := make(chan *RegisterResult)
+	select {
+	case iter.regChan <- &registerResourceEvent{goal: goal, done: done}:
+	case <-iter.cancel:
+		return nil, context.Canceled
+	}
+
+	select {
+	case res := <-done:
+		return res.State, nil
+	case <-iter.cancel:
+		return nil, context.Canceled
+	}
+}
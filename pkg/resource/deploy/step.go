@@ -15,8 +15,11 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -40,8 +43,9 @@ type Step interface {
 	// the step.
 	//
 	// The returned StepCompleteFunc, if not nil, must be called after committing the results of this step into
-	// the state of the deployment.
-	Apply(preview bool) (resource.Status, StepCompleteFunc, error) // applies or previews this step.
+	// the state of the deployment. If the given context has a deadline, the provider operation that the step performs
+	// is limited to the time left until it.
+	Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) // applies or previews this step.
 
 	Op() StepOp           // the operation performed by this step.
 	URN() resource.URN    // the resource URN (for before and after).
@@ -93,7 +97,7 @@ func (s *SameStep) New() *resource.State { return s.new }
 func (s *SameStep) Res() *resource.State { return s.new }
 func (s *SameStep) Logical() bool        { return true }
 
-func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *SameStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	// Retain the ID, and outputs:
 	s.new.ID = s.old.ID
 	s.new.Outputs = s.old.Outputs
@@ -176,7 +180,7 @@ func (s *CreateStep) Diffs() []resource.PropertyKey                { return s.di
 func (s *CreateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 func (s *CreateStep) Logical() bool                                { return !s.replacing }
 
-func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *CreateStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	var resourceError error
 	resourceStatus := resource.StatusOK
 	if !preview {
@@ -187,7 +191,8 @@ func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 				return resource.StatusOK, nil, err
 			}
 
-			id, outs, rst, err := prov.Create(s.URN(), s.new.Inputs, s.new.CustomTimeouts.Create)
+			id, outs, rst, err := s.plan.create(prov, s.URN(), s.new.Inputs,
+				operationTimeout(ctx, s.new.CustomTimeouts.Create))
 			if err != nil {
 				if rst != resource.StatusPartialFailure {
 					return rst, nil, err
@@ -207,6 +212,13 @@ func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 			s.new.ID = id
 			s.new.Outputs = outs
 		}
+	} else if s.new.Custom {
+		// Ask the provider to predict the outputs of the creation, if it can.
+		if prov, err := getProvider(s); err == nil {
+			if outs := s.plan.previewOperation(prov, s.Op(), s.URN(), "", nil, s.new.Inputs); outs != nil {
+				s.new.Outputs = outs
+			}
+		}
 	}
 
 	// Mark the old resource as pending deletion if necessary.
@@ -291,7 +303,7 @@ func (s *DeleteStep) New() *resource.State { return nil }
 func (s *DeleteStep) Res() *resource.State { return s.old }
 func (s *DeleteStep) Logical() bool        { return !s.replacing }
 
-func (s *DeleteStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *DeleteStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	// Refuse to delete protected resources.
 	if s.old.Protect {
 		return resource.StatusOK, nil,
@@ -307,7 +319,8 @@ func (s *DeleteStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 				return resource.StatusOK, nil, err
 			}
 
-			if rst, err := prov.Delete(s.URN(), s.old.ID, s.old.Outputs, s.old.CustomTimeouts.Delete); err != nil {
+			if rst, err := prov.Delete(s.URN(), s.old.ID, s.old.Outputs,
+				operationTimeout(ctx, s.old.CustomTimeouts.Delete)); err != nil {
 				return rst, nil, err
 			}
 		}
@@ -342,7 +355,7 @@ func (s *RemovePendingReplaceStep) New() *resource.State { return nil }
 func (s *RemovePendingReplaceStep) Res() *resource.State { return s.old }
 func (s *RemovePendingReplaceStep) Logical() bool        { return false }
 
-func (s *RemovePendingReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *RemovePendingReplaceStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	return resource.StatusOK, nil, nil
 }
 
@@ -399,7 +412,7 @@ func (s *UpdateStep) Logical() bool                                { return true
 func (s *UpdateStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *UpdateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 
-func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *UpdateStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	// Always propagate the ID, even in previews and refreshes.
 	s.new.ID = s.old.ID
 
@@ -415,7 +428,7 @@ func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 
 			// Update to the combination of the old "all" state, but overwritten with new inputs.
 			outs, rst, upderr := prov.Update(s.URN(), s.old.ID, s.old.Outputs, s.new.Inputs,
-				s.new.CustomTimeouts.Update, s.ignoreChanges)
+				operationTimeout(ctx, s.new.CustomTimeouts.Update), s.ignoreChanges)
 			if upderr != nil {
 				if rst != resource.StatusPartialFailure {
 					return rst, nil, upderr
@@ -432,6 +445,14 @@ func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 			// Now copy any output state back in case the update triggered cascading updates to other properties.
 			s.new.Outputs = outs
 		}
+	} else if s.new.Custom {
+		// Ask the provider to predict the outputs of the update, if it can.
+		if prov, err := getProvider(s); err == nil {
+			outs := s.plan.previewOperation(prov, s.Op(), s.URN(), s.old.ID, s.old.Outputs, s.new.Inputs)
+			if outs != nil {
+				s.new.Outputs = outs
+			}
+		}
 	}
 
 	// Finally, mark this operation as complete.
@@ -491,7 +512,7 @@ func (s *ReplaceStep) Diffs() []resource.PropertyKey                { return s.d
 func (s *ReplaceStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 func (s *ReplaceStep) Logical() bool                                { return true }
 
-func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *ReplaceStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	// If this is a pending delete, we should have marked the old resource for deletion in the CreateReplacement step.
 	contract.Assert(!s.pendingDelete || s.old.Delete)
 	return resource.StatusOK, func() {}, nil
@@ -569,7 +590,7 @@ func (s *ReadStep) New() *resource.State { return s.new }
 func (s *ReadStep) Res() *resource.State { return s.new }
 func (s *ReadStep) Logical() bool        { return !s.replacing }
 
-func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *ReadStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	urn := s.new.URN
 	id := s.new.ID
 
@@ -585,7 +606,7 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 			return resource.StatusOK, nil, err
 		}
 
-		result, rst, err := prov.Read(urn, id, nil, s.new.Inputs)
+		result, rst, err := s.plan.read(prov, urn, id, nil, s.new.Inputs)
 		if err != nil {
 			if rst != resource.StatusPartialFailure {
 				return rst, nil, err
@@ -619,10 +640,12 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 // resource by reading its current state from its provider plugin. These steps are not issued by the step generator;
 // instead, they are issued by the plan executor as the optional first step in plan execution.
 type RefreshStep struct {
-	plan *Plan           // the plan that produced this refresh
-	old  *resource.State // the old resource state, if one exists for this urn
-	new  *resource.State // the new resource state, to be used to query the provider
-	done chan<- bool     // the channel to use to signal completion, if any
+	plan         *Plan                          // the plan that produced this refresh
+	old          *resource.State                // the old resource state, if one exists for this urn
+	new          *resource.State                // the new resource state, to be used to query the provider
+	done         chan<- bool                    // the channel to use to signal completion, if any
+	diffs        []resource.PropertyKey         // the keys of the outputs that drifted, once the resource is read.
+	detailedDiff map[string]plugin.PropertyDiff // the outputs that drifted by path, once the resource is read.
 }
 
 // NewRefreshStep creates a new Refresh step.
@@ -648,19 +671,27 @@ func (s *RefreshStep) New() *resource.State { return s.new }
 func (s *RefreshStep) Res() *resource.State { return s.old }
 func (s *RefreshStep) Logical() bool        { return false }
 
+// Diffs returns the keys of the output properties whose values drifted from those in the old state, ignoring any that
+// the resource's provider reports as non-comparable. It is empty until the step has been applied.
+func (s *RefreshStep) Diffs() []resource.PropertyKey { return s.diffs }
+
+// DetailedDiff returns the output properties whose values drifted from those in the old state, keyed by path. As
+// with Diffs, non-comparable properties are excluded. It is nil until the step has read the resource.
+func (s *RefreshStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+
 // ResultOp returns the operation that corresponds to the change to this resource after reading its current state, if
 // any.
 func (s *RefreshStep) ResultOp() StepOp {
 	if s.new == nil {
 		return OpDelete
 	}
-	if s.new == s.old || s.old.Outputs.Diff(s.new.Outputs) == nil {
+	if s.new == s.old || len(s.diffs) == 0 {
 		return OpSame
 	}
 	return OpUpdate
 }
 
-func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *RefreshStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	var complete func()
 	if s.done != nil {
 		complete = func() { close(s.done) }
@@ -678,7 +709,7 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 	}
 
 	var initErrors []string
-	refreshed, rst, err := prov.Read(s.old.URN, s.old.ID, s.old.Inputs, s.old.Outputs)
+	refreshed, rst, err := s.plan.read(prov, s.old.URN, s.old.ID, s.old.Inputs, s.old.Outputs)
 	if err != nil {
 		if rst != resource.StatusPartialFailure {
 			return rst, nil, err
@@ -705,10 +736,10 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 	}
 
 	if outputs != nil {
-		s.new = resource.NewState(s.old.Type, s.old.URN, s.old.Custom, s.old.Delete, s.old.ID, inputs, outputs,
-			s.old.Parent, s.old.Protect, s.old.External, s.old.Dependencies, initErrors, s.old.Provider,
-			s.old.PropertyDependencies, s.old.PendingReplacement, s.old.AdditionalSecretOutputs, s.old.Aliases,
-			&s.old.CustomTimeouts)
+		s.new = s.old.Copy()
+		s.new.Inputs, s.new.Outputs, s.new.InitErrors = inputs, outputs, initErrors
+		s.diffs, s.detailedDiff = diffRefreshedOutputs(s.old.Outputs, outputs,
+			s.plan.nonComparableProperties(prov, s.old.Type))
 	} else {
 		s.new = nil
 	}
@@ -726,9 +757,13 @@ type ImportStep struct {
 	diffs         []resource.PropertyKey         // any keys that differed between the user's program and the actual state.
 	detailedDiff  map[string]plugin.PropertyDiff // the structured property diff.
 	ignoreChanges []string                       // a list of property paths to ignore when updating.
+	adopt         bool                           // true to adopt the inputs that the provider reads.
 }
 
-func NewImportStep(plan *Plan, reg RegisterResourceEvent, new *resource.State, ignoreChanges []string) Step {
+// NewImportStep returns a step that imports an existing resource. If adopt is true, the resource's inputs are those
+// that its provider reads, rather than those of the given state, which must then be empty.
+func NewImportStep(plan *Plan, reg RegisterResourceEvent, new *resource.State, ignoreChanges []string,
+	adopt bool) Step {
 	contract.Assert(new != nil)
 	contract.Assert(new.URN != "")
 	contract.Assert(new.ID != "")
@@ -741,11 +776,12 @@ func NewImportStep(plan *Plan, reg RegisterResourceEvent, new *resource.State, i
 		reg:           reg,
 		new:           new,
 		ignoreChanges: ignoreChanges,
+		adopt:         adopt,
 	}
 }
 
 func NewImportReplacementStep(plan *Plan, reg RegisterResourceEvent, original, new *resource.State,
-	ignoreChanges []string) Step {
+	ignoreChanges []string, adopt bool) Step {
 
 	contract.Assert(original != nil)
 	contract.Assert(new != nil)
@@ -762,6 +798,7 @@ func NewImportReplacementStep(plan *Plan, reg RegisterResourceEvent, original, n
 		new:           new,
 		replacing:     true,
 		ignoreChanges: ignoreChanges,
+		adopt:         adopt,
 	}
 }
 
@@ -783,7 +820,7 @@ func (s *ImportStep) Logical() bool                                { return !s.r
 func (s *ImportStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *ImportStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 
-func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *ImportStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	complete := func() { s.reg.Done(&RegisterResult{State: s.new}) }
 
 	// Read the current state of the resource to import. If the provider does not hand us back any inputs for the
@@ -792,7 +829,7 @@ func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 	if err != nil {
 		return resource.StatusOK, nil, err
 	}
-	read, rst, err := prov.Read(s.new.URN, s.new.ID, nil, nil)
+	read, rst, err := s.plan.read(prov, s.new.URN, s.new.ID, nil, nil)
 	if err != nil {
 		if initErr, isInitErr := err.(*plugin.InitError); isInitErr {
 			s.new.InitErrors = initErr.Reasons
@@ -813,9 +850,18 @@ func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 	// Magic up an old state so the frontend can display a proper diff. This state is the output of the just-executed
 	// `Read` combined with the resource identity and metadata from the desired state. This ensures that the only
 	// differences between the old and new states are between the inputs and outputs.
-	s.old = resource.NewState(s.new.Type, s.new.URN, s.new.Custom, false, s.new.ID, read.Inputs, read.Outputs,
-		s.new.Parent, s.new.Protect, false, s.new.Dependencies, s.new.InitErrors, s.new.Provider,
-		s.new.PropertyDependencies, false, nil, nil, &s.new.CustomTimeouts)
+	s.old = s.new.Copy()
+	s.old.Inputs, s.old.Outputs = read.Inputs, read.Outputs
+	s.old.AdditionalSecretOutputs, s.old.Aliases = nil, nil
+
+	// If we are adopting the provider's view of the resource, there are no user inputs to check.
+	if s.adopt {
+		s.new.Inputs = read.Inputs
+		if s.replacing {
+			s.original.Delete = true
+		}
+		return rst, complete, nil
+	}
 
 	// Check the user inputs using the provider inputs for defaults.
 	inputs, failures, err := prov.Check(s.new.URN, s.old.Inputs, s.new.Inputs, preview)
@@ -1006,5 +1052,18 @@ func getProvider(s Step) (plugin.Provider, error) {
 	if !ok {
 		return nil, errors.Errorf("unknown provider '%v' for resource %v", s.Provider(), s.URN())
 	}
+	if s.Plan().Ctx().ProviderDryRun && !plugin.GetCapabilities(provider).DryRun {
+		return nil, errors.Errorf("the provider of resource %v does not support dry-run mode", s.URN())
+	}
 	return provider, nil
 }
+
+// operationTimeout returns the time limit, in seconds, to give a provider for an operation: the time left until the
+// given context's deadline, if it has one, and otherwise the limit that the resource declares. A provider treats zero
+// as no limit, so a deadline that has already passed leaves the operation a millisecond.
+func operationTimeout(ctx context.Context, declared float64) float64 {
+	if deadline, ok := ctx.Deadline(); ok {
+		return math.Max(time.Until(deadline).Seconds(), 0.001)
+	}
+	return declared
+}
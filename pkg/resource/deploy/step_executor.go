@@ -15,14 +15,19 @@
 package deploy
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 )
@@ -31,6 +36,7 @@ const (
 	// Dummy workerID for synchronous operations.
 	synchronousWorkerID = -1
 	infiniteWorkerID    = -2
+	dispatcherWorkerID  = -3
 
 	// Utility constant for easy debugging.
 	stepExecutorLogLevel = 4
@@ -91,12 +97,25 @@ type stepExecutor struct {
 	pendingNews     sync.Map // Resources that have been created but are pending a RegisterResourceOutputs.
 	continueOnError bool     // True if we want to continue the plan after a step error.
 
-	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
-	incomingChains chan incomingChain // Incoming chains that we are to execute
+	workers         sync.WaitGroup       // WaitGroup tracking the worker goroutines that are owned by this step executor.
+	submittedChains chan []incomingChain // Batches of chains that have been submitted for execution
+	incomingChains  chan incomingChain   // Incoming chains that we are to execute, in order of priority
 
-	ctx      context.Context    // cancellation context for the current plan.
-	cancel   context.CancelFunc // CancelFunc that cancels the above context.
-	sawError atomic.Value       // atomic boolean indicating whether or not the step excecutor saw that there was an error.
+	providerSlots map[tokens.Package]chan struct{} // Semaphores limiting the concurrent steps for each provider package.
+
+	callerCtx context.Context    // the caller's cancellation context, which is only canceled if the plan is.
+	ctx       context.Context    // cancellation context for the current plan.
+	cancel    context.CancelFunc // CancelFunc that cancels the above context.
+	sawError  atomic.Value       // atomic boolean indicating whether or not the step executor has seen an error.
+
+	failures     []stepFailure // the steps that have failed, in the order in which they failed.
+	failuresLock sync.Mutex    // a lock that protects failures.
+}
+
+// stepFailure records a step that failed and the error with which it failed.
+type stepFailure struct {
+	step Step
+	err  error
 }
 
 //
@@ -108,16 +127,8 @@ type stepExecutor struct {
 // Execute submits a Chain for asynchronous execution. The execution of the chain will begin as soon as there
 // is a worker available to execute it.
 func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
-	// The select here is to avoid blocking on a send to se.incomingChains if a cancellation is pending.
-	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
-
 	completion := make(chan bool)
-	select {
-	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion}:
-	case <-se.ctx.Done():
-		close(completion)
-	}
-
+	se.submit([]incomingChain{{Chain: chain, CompletionChan: completion}})
 	return completionToken{channel: completion}
 }
 
@@ -126,11 +137,17 @@ func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
 func (se *stepExecutor) ExecuteParallel(antichain antichain) completionToken {
 	var wg sync.WaitGroup
 
-	// ExecuteParallel is implemented in terms of ExecuteSerial - it executes each step individually and waits for all
-	// of the steps to complete.
-	wg.Add(len(antichain))
-	for _, step := range antichain {
-		tok := se.ExecuteSerial(chain{step})
+	// ExecuteParallel executes each step as its own chain and waits for all of the steps to complete. The chains are
+	// submitted together so that their priorities decide the order in which they are handed to workers.
+	batch := make([]incomingChain, len(antichain))
+	for i, step := range antichain {
+		batch[i] = incomingChain{Chain: chain{step}, CompletionChan: make(chan bool)}
+	}
+	se.submit(batch)
+
+	wg.Add(len(batch))
+	for _, c := range batch {
+		tok := completionToken{channel: c.CompletionChan}
 		go func() {
 			defer wg.Done()
 			tok.Wait(se.ctx)
@@ -146,6 +163,19 @@ func (se *stepExecutor) ExecuteParallel(antichain antichain) completionToken {
 	return completionToken{channel: done}
 }
 
+// submit hands the given chains to the dispatcher, which queues them until a worker is available.
+func (se *stepExecutor) submit(batch []incomingChain) {
+	// The select here is to avoid blocking on a send to se.submittedChains if a cancellation is pending.
+	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
+	select {
+	case se.submittedChains <- batch:
+	case <-se.ctx.Done():
+		for _, c := range batch {
+			close(c.CompletionChan)
+		}
+	}
+}
+
 // ExecuteRegisterResourceOutputs services a RegisterResourceOutputsEvent synchronously on the calling goroutine.
 func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputsEvent) {
 	// Look up the final state in the pending registration list.
@@ -187,10 +217,24 @@ func (se *stepExecutor) Errored() bool {
 	return se.sawError.Load().(bool)
 }
 
+// Failures returns the steps that have failed so far, in the order in which they failed.
+func (se *stepExecutor) Failures() []stepFailure {
+	se.failuresLock.Lock()
+	defer se.failuresLock.Unlock()
+	return append([]stepFailure(nil), se.failures...)
+}
+
+// recordFailure records that the given step failed with the given error.
+func (se *stepExecutor) recordFailure(step Step, err error) {
+	se.failuresLock.Lock()
+	defer se.failuresLock.Unlock()
+	se.failures = append(se.failures, stepFailure{step: step, err: err})
+}
+
 // SignalCompletion signals to the stepExecutor that there are no more chains left to execute. All worker
 // threads will terminate as soon as they retire all of the work they are currently executing.
 func (se *stepExecutor) SignalCompletion() {
-	close(se.incomingChains)
+	close(se.submittedChains)
 }
 
 // WaitForCompletion blocks the calling goroutine until the step executor completes execution of all in-flight
@@ -218,8 +262,21 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 		default:
 		}
 
-		if err := se.executeStep(workerID, step); err != nil {
+		release, ok := se.acquireProviderSlot(workerID, step)
+		if !ok {
+			se.log(workerID, "step %v on %v canceled while waiting for its provider", step.Op(), step.URN())
+			return
+		}
+		err := se.executeStep(workerID, step)
+		release()
+
+		if err != nil {
 			se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
+			if se.continueOnError && providers.IsProviderType(step.Type()) && step.Op() != OpDelete {
+				// The resources that use a provider cannot be managed without it, so a provider that could not be
+				// registered stops the plan even if it would otherwise continue past failures.
+				se.cancel()
+			}
 			se.cancelDueToError()
 			if err != errStepApplyFailed {
 				// Step application errors are recorded by the OnResourceStepPost callback. This is confusing,
@@ -227,6 +284,7 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 				//
 				// The errStepApplyFailed sentinel signals that the error that failed this chain was a step apply
 				// error and that we shouldn't log it. Everything else should be logged to the diag system as usual.
+				se.recordFailure(step, err)
 				diagMsg := diag.RawMessage(step.URN(), err.Error())
 				se.plan.Diag().Errorf(diagMsg)
 			}
@@ -235,6 +293,32 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 	}
 }
 
+// acquireProviderSlot waits until the given step may run without exceeding the parallelism limit of its provider, if
+// any, and returns a function that must be called once the step has finished. It returns false if the plan is canceled
+// while waiting.
+func (se *stepExecutor) acquireProviderSlot(workerID int, step Step) (func(), bool) {
+	pkg, ok := stepProviderPackage(step)
+	if !ok {
+		return func() {}, true
+	}
+	slots, has := se.providerSlots[pkg]
+	if !has {
+		return func() {}, true
+	}
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		se.log(workerID, "step %v on %v waiting for provider %v", step.Op(), step.URN(), pkg)
+		select {
+		case slots <- struct{}{}:
+		case <-se.ctx.Done():
+			return nil, false
+		}
+	}
+	return func() { <-slots }, true
+}
+
 func (se *stepExecutor) cancelDueToError() {
 	se.sawError.Store(true)
 	if !se.continueOnError {
@@ -242,6 +326,20 @@ func (se *stepExecutor) cancelDueToError() {
 	}
 }
 
+// startStepSpan starts a tracing span that covers the application of the given step. The span is named after the
+// step's operation and resource so that time spent on individual resources can be found in a trace.
+func (se *stepExecutor) startStepSpan(step Step) opentracing.Span {
+	parent := context.Background()
+	if ctx := se.plan.Ctx(); ctx != nil {
+		parent = ctx.Request()
+	}
+	span, _ := opentracing.StartSpanFromContext(parent, fmt.Sprintf("pulumi-step %v %v", step.Op(), step.URN()),
+		opentracing.Tag{Key: "urn", Value: string(step.URN())},
+		opentracing.Tag{Key: "op", Value: string(step.Op())},
+		opentracing.Tag{Key: "preview", Value: se.preview})
+	return span
+}
+
 //
 // The next few functions are responsible for executing individual steps. The basic flow of step
 // execution is
@@ -268,9 +366,23 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
-	status, stepComplete, err := step.Apply(se.preview)
+	se.logStepStart(step)
+	span := se.startStepSpan(step)
+	start := time.Now()
+	status, stepComplete, err := se.applyStep(step)
+	span.Finish()
+	se.logStepEnd(step, status, time.Since(start), err)
+
+	// A step that may have changed a resource, even if it failed, makes any earlier read of the resource stale.
+	switch step.Op() {
+	case OpSame, OpRead, OpReadReplacement, OpRefresh, OpImport, OpImportReplacement:
+	default:
+		se.plan.forgetReads(step.URN())
+	}
 
 	if err == nil {
+		se.recordLifecycleEvent(step)
+
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
 		if step.Logical() && step.New() != nil {
 			if prior, has := se.pendingNews.Load(step.URN()); has {
@@ -308,12 +420,91 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 
 	if err != nil {
 		se.log(workerID, "step %v on %v failed with an error: %v", step.Op(), step.URN(), err)
+		se.recordFailure(step, err)
 		return errStepApplyFailed
 	}
 
 	return nil
 }
 
+// applyStep applies the given step. If the step creates, updates, or deletes a resource, the provider is given the
+// time limit for that operation, and the step fails if the operation does not finish within it. As the provider may
+// complete the operation regardless, the step waits for it to return. An operation that succeeds after its time limit
+// has passed is recorded as it would be otherwise, and only draws a warning.
+func (se *stepExecutor) applyStep(step Step) (resource.Status, StepCompleteFunc, error) {
+	if se.preview {
+		return step.Apply(se.callerCtx, se.preview)
+	}
+
+	timeout, source := stepTimeout(step, se.opts)
+	if timeout == 0 {
+		return se.applyAndPoll(se.callerCtx, step)
+	}
+	logging.V(4).Infof("the %v of %v is limited to %v by %s", step.Op(), step.URN(), timeout, source)
+
+	// Once the time limit passes, retries and the polling of an operation that the provider completes asynchronously
+	// stop as well.
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(se.callerCtx, deadline)
+	defer cancel()
+	status, complete, err := se.applyAndPoll(ctx, step)
+	if time.Now().Before(deadline) {
+		return status, complete, err
+	}
+
+	msg := fmt.Sprintf("%s of resource %s did not complete within %v, %s", step.Op(), step.URN(), timeout, source)
+	if err == nil {
+		se.plan.Diag().Warningf(diag.RawMessage(step.URN(), msg+", but succeeded"))
+		return status, complete, nil
+	}
+	return status, complete, errors.Wrap(err, msg)
+}
+
+// applyAndPoll applies the given step, retrying it if it fails transiently, and, if the step creates or updates a
+// resource and the provider reports that it has yet to complete that operation, polls the operation until it completes
+// or the given context is canceled. A resource whose operation is not known to have completed is recorded with an
+// initialization error.
+func (se *stepExecutor) applyAndPoll(ctx context.Context, step Step) (resource.Status, StepCompleteFunc, error) {
+	status, complete, err := se.applyWithRetries(ctx, step)
+	if err != nil {
+		return status, complete, err
+	}
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement, OpUpdate:
+		status, err = pollOperation(ctx, step)
+	}
+	return status, complete, err
+}
+
+// stepTimeout returns the time limit for the provider operation that the given step performs, if any, and the setting
+// from which it was taken. The timeouts in opts.ResourceTimeouts take precedence over those declared by the resource's
+// customTimeouts, with the last timeout that matches the resource winning, and opts.ResourceTimeout applies to those
+// operations that have no other limit.
+func stepTimeout(step Step, opts Options) (time.Duration, TimeoutSource) {
+	var operation string
+	var seconds float64
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement:
+		operation, seconds = "create", step.New().CustomTimeouts.Create
+	case OpUpdate:
+		operation, seconds = "update", step.New().CustomTimeouts.Update
+	case OpDelete, OpDeleteReplaced:
+		operation, seconds = "delete", step.Old().CustomTimeouts.Delete
+	default:
+		return 0, ""
+	}
+
+	for i := len(opts.ResourceTimeouts) - 1; i >= 0; i-- {
+		if t := opts.ResourceTimeouts[i]; t.Operation == operation && t.Pattern.Matches(step.URN()) {
+			return t.Timeout, t.Source
+		}
+	}
+	if seconds > 0 {
+		return time.Duration(seconds * float64(time.Second)), TimeoutSourceCode
+	}
+	return opts.ResourceTimeout, TimeoutSourceDefault
+}
+
 // log is a simple logging helper for the step executor.
 func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 	if logging.V(stepExecutorLogLevel) {
@@ -322,12 +513,55 @@ func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 	}
 }
 
+// dispatch queues the chains that are submitted to the step executor and hands them to workers as workers become
+// available, highest priority first. Chains of equal priority are handed out in the order in which they were submitted.
+// Once there are no more chains to submit and the queue is empty, dispatch closes se.incomingChains, which tells the
+// workers to exit.
+func (se *stepExecutor) dispatch() {
+	defer close(se.incomingChains)
+
+	var queue chainQueue
+	submitted, seq := se.submittedChains, 0
+	for submitted != nil || len(queue) > 0 {
+		var next chan incomingChain
+		var top incomingChain
+		if len(queue) > 0 {
+			next, top = se.incomingChains, queue[0].incomingChain
+		}
+
+		select {
+		case batch, ok := <-submitted:
+			if !ok {
+				submitted = nil
+				continue
+			}
+			for _, c := range batch {
+				heap.Push(&queue, &pendingChain{incomingChain: c, priority: chainPriority(c.Chain), seq: seq})
+				seq++
+			}
+		case next <- top:
+			p := heap.Pop(&queue).(*pendingChain)
+			if len(p.Chain) > 0 {
+				se.log(dispatcherWorkerID, "dispatched chain starting with %v on %v (priority %d, %d queued)",
+					p.Chain[0].Op(), p.Chain[0].URN(), p.priority, len(queue))
+			}
+		case <-se.ctx.Done():
+			se.log(dispatcherWorkerID, "dispatcher exiting due to cancellation, dropping %d queued chains", len(queue))
+			for _, p := range queue {
+				close(p.CompletionChan)
+			}
+			return
+		}
+	}
+}
+
 //
 // The step executor owns a number of goroutines that it considers to be "workers", responsible for
 // executing steps. By default, as we ease into the waters of parallelism, there is at most one worker
 // active.
 //
-// Workers continuously pull from se.incomingChains, executing chains as they are provided to the executor.
+// Workers continuously pull from se.incomingChains, executing chains as they are provided to the executor. The
+// dispatcher feeds se.incomingChains from the chains that have been submitted, ordered by priority.
 // There are two reasons why a worker would exit:
 //
 //  1. A worker exits if se.ctx is canceled. There are two ways that se.ctx gets canceled: first, if there is
@@ -380,19 +614,29 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 	}
 }
 
-func newStepExecutor(ctx context.Context, cancel context.CancelFunc, plan *Plan, opts Options,
+func newStepExecutor(callerCtx, ctx context.Context, cancel context.CancelFunc, plan *Plan, opts Options,
 	preview, continueOnError bool) *stepExecutor {
 	exec := &stepExecutor{
 		plan:            plan,
 		opts:            opts,
 		preview:         preview,
 		continueOnError: continueOnError,
+		submittedChains: make(chan []incomingChain),
 		incomingChains:  make(chan incomingChain),
+		callerCtx:       callerCtx,
 		ctx:             ctx,
 		cancel:          cancel,
 	}
 
 	exec.sawError.Store(false)
+	go exec.dispatch()
+
+	if len(opts.ProviderParallel) > 0 {
+		exec.providerSlots = make(map[tokens.Package]chan struct{})
+		for pkg, n := range opts.ProviderParallel {
+			exec.providerSlots[pkg] = make(chan struct{}, n)
+		}
+	}
 
 	// If we're being asked to run as parallel as possible, spawn a single worker that launches chain executions
 	// asynchronously.
@@ -44,7 +44,7 @@ type stepGenerator struct {
 	// events and report them all at once.
 	hasPolicyViolations bool
 
-	urns           map[resource.URN]bool            // set of URNs discovered for this plan
+	urns           map[resource.URN]*resource.Goal  // the goal first registered for each URN in this plan
 	reads          map[resource.URN]bool            // set of URNs read for this plan
 	deletes        map[resource.URN]bool            // set of URNs deleted in this plan
 	replaces       map[resource.URN]bool            // set of URNs replaced in this plan
@@ -58,6 +58,17 @@ type stepGenerator struct {
 	dependentReplaceKeys map[resource.URN][]resource.PropertyKey
 	// a map from old names (aliased URNs) to the new URN that aliased to them.
 	aliased map[resource.URN]resource.URN
+	// a map from providers to the resource types and properties that they have deprecated.
+	deprecations map[plugin.Provider]*providerDeprecations
+	// the set of deprecation notices that have already been reported.
+	reportedDeprecations map[string]bool
+	// the set of URNs in the plan's slices, if it is restricted to slices of the stack.
+	sliced map[resource.URN]bool
+	// the set of URNs outside of the plan's slices that were not created because they do not exist.
+	skipped map[resource.URN]bool
+	// the custom resources registered in this plan, by package, on which later resources may depend according to
+	// their providers.
+	dependencyCandidates map[tokens.Package][]dependencyCandidate
 }
 
 // GenerateReadSteps is responsible for producing one or more steps required to service
@@ -85,6 +96,9 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, res
 	)
 	old, hasOld := sg.plan.Olds()[urn]
 
+	// A resource that is read is read whether or not it is in the plan's slices.
+	sg.sliced[urn] = true
+
 	// If the snapshot has an old resource for this URN and it's not external, we're going
 	// to have to delete the old resource and conceptually replace it with the resource we
 	// are about to read.
@@ -130,44 +144,60 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 	goal := event.Goal()
 	// generate an URN for this new resource.
 	urn := sg.plan.generateURN(goal.Parent, goal.Type, goal.Name)
-	if sg.urns[urn] {
+	if first, has := sg.urns[urn]; has {
+		// The URN does not name the resource's parent, only its type, so the two registrations may be children of
+		// different resources; name both parents to help find them.
 		invalid = true
-		// TODO[pulumi/pulumi-framework#19]: improve this error message!
-		sg.plan.Diag().Errorf(diag.GetDuplicateResourceURNError(urn), urn)
+		sg.plan.Diag().Errorf(diag.GetDuplicateResourceURNError(urn), urn, first.Parent, goal.Parent)
+	} else {
+		sg.urns[urn] = goal
 	}
-	sg.urns[urn] = true
 
 	// Check for an old resource so that we can figure out if this is a create, delete, etc., and/or to diff.  We look
 	// up first by URN and then by any provided aliases.  If it is found using an alias, record that alias so that we do
-	// not delete the aliased resource later.
+	// not delete the aliased resource later.  It is an error for the URN and aliases to match more than one existing
+	// resource, as we would not know which of them this resource replaces.
 	var oldInputs resource.PropertyMap
 	var oldOutputs resource.PropertyMap
 	var old *resource.State
 	var hasOld bool
+	var oldURN resource.URN
 	for _, urnOrAlias := range append([]resource.URN{urn}, goal.Aliases...) {
-		old, hasOld = sg.plan.Olds()[urnOrAlias]
+		match, hasMatch := sg.plan.Olds()[urnOrAlias]
+		if !hasMatch || urnOrAlias == oldURN {
+			continue
+		}
 		if hasOld {
-			oldInputs = old.Inputs
-			oldOutputs = old.Outputs
-			if urnOrAlias != urn {
-				if previousAliasURN, alreadyAliased := sg.aliased[urnOrAlias]; alreadyAliased {
-					invalid = true
-					sg.plan.Diag().Errorf(diag.GetDuplicateResourceAliasError(urn), urnOrAlias, urn, previousAliasURN)
-				}
-				sg.aliased[urnOrAlias] = urn
-			}
+			invalid = true
+			sg.plan.Diag().Errorf(diag.GetAmbiguousResourceAliasError(urn), urn, oldURN, urnOrAlias)
 			break
 		}
+
+		old, hasOld, oldURN = match, true, urnOrAlias
+		oldInputs = old.Inputs
+		oldOutputs = old.Outputs
+		if urnOrAlias != urn {
+			if previousAliasURN, alreadyAliased := sg.aliased[urnOrAlias]; alreadyAliased {
+				invalid = true
+				sg.plan.Diag().Errorf(diag.GetDuplicateResourceAliasError(urn), urnOrAlias, urn, previousAliasURN)
+			}
+			sg.aliased[urnOrAlias] = urn
+		}
 	}
 
-	// Create the desired inputs from the goal state
-	inputs := goal.Properties
+	// Create the desired inputs from the goal state, keeping the resource's generated name if it has one.
+	inputs := autoNameInputs(goal, oldInputs, oldOutputs)
 	if hasOld {
 		// Set inputs back to their old values (if any) for any "ignored" properties
 		processedInputs, res := processIgnoreChanges(inputs, oldInputs, goal.IgnoreChanges)
 		if res != nil {
 			return nil, res
 		}
+
+		// Let the user know which of the ignored properties would otherwise have produced a change.
+		if ignored := changedPropertyPaths(inputs, oldInputs, goal.IgnoreChanges); len(ignored) > 0 {
+			sg.plan.Diag().Infof(diag.GetIgnoredPropertyChangesInfo(urn), strings.Join(ignored, ", "))
+		}
 		inputs = processedInputs
 	}
 
@@ -176,6 +206,50 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 	new := resource.NewState(goal.Type, urn, goal.Custom, false, "", inputs, nil, goal.Parent, goal.Protect, false,
 		goal.Dependencies, goal.InitErrors, goal.Provider, goal.PropertyDependencies, false,
 		goal.AdditionalSecretOutputs, goal.Aliases, &goal.CustomTimeouts)
+	new.DeleteBeforeReplace = goal.DeleteBeforeReplace
+	new.Priority = goal.Priority
+	new.RetryPolicy = goal.RetryPolicy
+	if hasOld {
+		new.History = old.History
+	}
+
+	// If the plan is restricted to slices of the stack, a resource outside of them keeps its current state, and is not
+	// created if it does not exist, unless the plan creates the missing resources outside of the slices.
+	if len(sg.opts.Slices) > 0 {
+		if !inSlices(sg.opts.Slices, sg.sliced, urn, goal.Properties, goal.Parent) {
+			if invalid {
+				return nil, result.Bail()
+			}
+			if hasOld || !sg.opts.SliceCreateMissing {
+				return sg.keepResource(event, urn, old, new, "outside of the slice"), nil
+			}
+			sg.plan.Diag().Infof(diag.RawMessage(urn,
+				"this resource is created, though it is outside of the slice, as it does not exist"))
+		}
+		sg.sliced[urn] = true
+		if !sg.checkSliceBoundary(urn, goal) {
+			invalid = true
+		}
+	}
+
+	// If the plan only replaces its targets, every other resource but the providers and the targets' dependents, if
+	// they are included, keeps its current state.
+	replaceTarget := sg.opts.ReplaceTargets[urn]
+	if sg.opts.ReplaceTargets != nil && !sg.isTarget(urn) && !providers.IsProviderType(goal.Type) {
+		if invalid {
+			return nil, result.Bail()
+		}
+		return sg.keepResource(event, urn, old, new, "not a target of --target-replace"), nil
+	}
+	if sg.isTarget(urn) {
+		if !replaceTarget {
+			sg.plan.Diag().Infof(diag.RawMessage(urn,
+				"this resource is included because it depends on a target of --target-replace"))
+		}
+		if !sg.checkReplaceTargetDependencies(urn, goal) {
+			invalid = true
+		}
+	}
 
 	// Is this thing a provider resource? If so, stash it - we might need it later when calculating replacement
 	// of resources that use this provider.
@@ -207,11 +281,11 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		new.ID = goal.ID
 		if isReplace := hasOld && !recreating; isReplace {
 			return []Step{
-				NewImportReplacementStep(sg.plan, event, old, new, goal.IgnoreChanges),
+				NewImportReplacementStep(sg.plan, event, old, new, goal.IgnoreChanges, sg.opts.AdoptImportedInputs),
 				NewReplaceStep(sg.plan, old, new, nil, nil, nil, true),
 			}, nil
 		}
-		return []Step{NewImportStep(sg.plan, event, new, goal.IgnoreChanges)}, nil
+		return []Step{NewImportStep(sg.plan, event, new, goal.IgnoreChanges, sg.opts.AdoptImportedInputs)}, nil
 	}
 
 	// Ensure the provider is okay with this resource and fetch the inputs to pass to subsequent methods.
@@ -223,7 +297,7 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		// invalid (they got deleted) so don't consider them. Similarly, if the old resource was External,
 		// don't consider those inputs since Pulumi does not own them.
 		if recreating || wasExternal {
-			inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns)
+			inputs, failures, err = prov.Check(urn, nil, autoNameInputs(goal, nil, nil), allowUnknowns)
 		} else {
 			inputs, failures, err = prov.Check(urn, oldInputs, inputs, allowUnknowns)
 		}
@@ -234,6 +308,17 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 			invalid = true
 		}
 		new.Inputs = inputs
+
+		if sg.issueDeprecations(urn, goal.Type, goal.Properties, prov) && sg.opts.DeprecationErrors {
+			invalid = true
+		}
+	}
+
+	// Add the dependencies of the resource that its provider knows of but its program does not declare.
+	if !invalid {
+		if invalid, err = sg.addProviderDependencies(prov, new); err != nil {
+			return nil, result.FromError(err)
+		}
 	}
 
 	// Get all Analyzers -- if any -- and give each a chance to inspect the resource too.
@@ -273,6 +358,17 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		return nil, result.Bail()
 	}
 
+	// If we're only validating the program, there's nothing more to do: the resource's inputs have been checked, so
+	// hand them back to the program without diffing them against the resource's old state.
+	if sg.opts.ValidateOnly {
+		if hasOld {
+			sg.sames[urn] = true
+			return []Step{NewSameStep(sg.plan, event, old, new)}, nil
+		}
+		sg.creates[urn] = true
+		return []Step{NewCreateStep(sg.plan, event, new)}, nil
+	}
+
 	// There are four cases we need to consider when figuring out what to do with this resource.
 	//
 	// Case 1: recreating
@@ -295,6 +391,10 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		delete(sg.deletes, urn)
 		sg.replaces[urn] = true
 		keys := sg.dependentReplaceKeys[urn]
+
+		// The resource has already been deleted, so there is no point in refusing to replace it now: the replacement
+		// of the resource it depends upon was checked before anything was deleted.
+		sg.warnReplacement(urn, describeReplaceKeys(keys), true /*deleteBeforeReplace*/, false /*requested*/)
 		return []Step{
 			NewReplaceStep(sg.plan, old, new, nil, nil, nil, false),
 			NewCreateReplacementStep(sg.plan, event, old, new, keys, nil, nil, false),
@@ -333,7 +433,12 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 	//    be replaced, we do so. If it does not, we update the resource in place.
 	if hasOld {
 		contract.Assert(old != nil)
-		diff, err := sg.diff(urn, old, new, oldInputs, oldOutputs, inputs, prov, allowUnknowns, goal.IgnoreChanges)
+		var explanation *sameExplanation
+		if sg.opts.ExplainSames {
+			explanation = &sameExplanation{ignored: goal.IgnoreChanges}
+		}
+		diff, err := sg.diff(urn, old, new, oldInputs, oldOutputs, inputs, prov, allowUnknowns, goal.IgnoreChanges,
+			explanation)
 		if err != nil {
 			// If the plugin indicated that the diff is unavailable, assume that the resource will be updated and
 			// report the message contained in the error.
@@ -351,9 +456,22 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 				"unrecognized diff state for %s: %d", urn, diff.Changes)
 		}
 
+		// The program may require the resource to be replaced when some of its properties change, even if its
+		// provider could update them in place.
+		var replaced []string
+		diff, replaced = applyReplaceOnChanges(diff, inputs, oldInputs, goal.ReplaceOnChanges)
+		if len(replaced) > 0 {
+			sg.plan.Diag().Infof(diag.GetReplaceOnChangesInfo(urn), strings.Join(replaced, ", "))
+		}
+
+		// A target of --target-replace is replaced even if nothing about it has changed.
+		if replaceTarget {
+			diff.Changes = plugin.DiffSome
+		}
+
 		// If there were changes, check for a replacement vs. an in-place update.
 		if diff.Changes == plugin.DiffSome {
-			if diff.Replace() {
+			if diff.Replace() || replaceTarget {
 				// If the goal state specified an ID, issue an error: the replacement will change the ID, and is
 				// therefore incompatible with the goal state.
 				if goal.ID != "" {
@@ -372,7 +490,8 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 				// had assumed that we were going to carry them over from the old resource, which is no longer true.
 				if prov != nil {
 					var failures []plugin.CheckFailure
-					inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns)
+					// A replacement is a new physical resource, so it gets a new generated name, too.
+					inputs, failures, err = prov.Check(urn, nil, autoNameInputs(goal, nil, nil), allowUnknowns)
 					if err != nil {
 						return nil, result.FromError(err)
 					} else if issueCheckErrors(sg.plan, new, urn, failures) {
@@ -398,9 +517,27 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 				//       until pulumi/pulumi#624 is resolved, we cannot safely perform this operation on resources
 				//       that have dependent resources (we try to delete the resource while they refer to it).
 				//
-				// The provider is responsible for requesting which of these two modes to use.
+				// The provider is responsible for requesting which of these two modes to use, although the program
+				// may require that a resource always be deleted before it is replaced with the deleteBeforeReplace
+				// resource option, e.g. because the resource's name must be unique. That option is recorded in the
+				// resource's state.
+
+				// A replacement that was requested with --target-replace is allowed even if others are not.
+				reason := describeReplaceKeys(diff.ReplaceKeys)
+				if replaceTarget {
+					reason = "--target-replace"
+				}
+				deleteBeforeReplace := diff.DeleteBeforeReplace || goal.DeleteBeforeReplace
+				if sg.opts.DisallowReplace && !sg.plan.preview && !replaceTarget {
+					sg.plan.Diag().Errorf(diag.GetResourceReplacementNotAllowedError(urn), reason)
+					return nil, result.Bail()
+				}
+				sg.warnReplacement(urn, reason, deleteBeforeReplace, goal.DeleteBeforeReplace)
+				if replaceTarget {
+					sg.warnReplaceTargetDependents(old)
+				}
 
-				if diff.DeleteBeforeReplace || goal.DeleteBeforeReplace {
+				if deleteBeforeReplace {
 					logging.V(7).Infof("Planner decided to delete-before-replacement for resource '%v'", urn)
 					contract.Assert(sg.plan.depGraph != nil)
 
@@ -427,6 +564,11 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 								continue
 							}
 
+							// A resource that is not a target of --target-replace keeps its current state.
+							if sg.opts.ReplaceTargets != nil && !sg.isTarget(dependentResource.URN) {
+								continue
+							}
+
 							sg.dependentReplaceKeys[dependentResource.URN] = toReplace[i].keys
 
 							logging.V(7).Infof("Planner decided to delete '%v' due to dependence on condemned resource '%v'",
@@ -478,6 +620,9 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		if logging.V(7) {
 			logging.V(7).Infof("Planner decided not to update '%v' (same) (inputs=%v)", urn, new.Inputs)
 		}
+		if explanation != nil {
+			explanation.report(sg.plan.Diag(), urn)
+		}
 		return []Step{NewSameStep(sg.plan, event, old, new)}, nil
 	}
 
@@ -489,12 +634,51 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 	return []Step{NewCreateStep(sg.plan, event, new)}, nil
 }
 
+// warnReplacement lets the user know that the given resource will be replaced, for the given reason, and whether the
+// replacement may cause downtime. requested is true if the resource's own deleteBeforeReplace option was set.
+func (sg *stepGenerator) warnReplacement(urn resource.URN, reason string, deleteBeforeReplace bool, requested bool) {
+	if deleteBeforeReplace && requested {
+		sg.plan.Diag().Warningf(diag.GetResourceReplacementWarning(urn), reason,
+			"Its deleteBeforeReplace option requires it to be deleted before its replacement is created, "+
+				"which may cause downtime.")
+	} else if deleteBeforeReplace {
+		sg.plan.Diag().Warningf(diag.GetResourceReplacementWarning(urn), reason,
+			"It will be deleted before its replacement is created, which may cause downtime.")
+	} else {
+		sg.plan.Diag().Infof(diag.GetResourceReplacementWarning(urn), reason,
+			"Its replacement will be created before it is deleted, so no downtime is expected.")
+	}
+}
+
+// describeReplaceKeys describes the properties that caused a replacement.
+func describeReplaceKeys(keys []resource.PropertyKey) string {
+	if len(keys) == 0 {
+		return "changes to its inputs or provider"
+	}
+
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = string(k)
+	}
+	return "changes to " + strings.Join(names, ", ")
+}
+
 func (sg *stepGenerator) GenerateDeletes() []Step {
 	// To compute the deletion list, we must walk the list of old resources *backwards*.  This is because the list is
 	// stored in dependency order, and earlier elements are possibly leaf nodes for later elements.  We must not delete
 	// dependencies prior to their dependent nodes.
+	// Validation never deletes anything.
+	if sg.opts.ValidateOnly {
+		return nil
+	}
+
 	var dels []Step
 	if prev := sg.plan.prev; prev != nil {
+		var retained map[resource.URN]bool
+		if sg.opts.RetainProtected || sg.opts.DeleteTargets != nil {
+			retained = sg.retainResources()
+		}
+
 		for i := len(prev.Resources) - 1; i >= 0; i-- {
 			// If this resource is explicitly marked for deletion or wasn't seen at all, delete it.
 			res := prev.Resources[i]
@@ -529,6 +713,8 @@ func (sg *stepGenerator) GenerateDeletes() []Step {
 				logging.V(7).Infof("Planner decided to delete '%v' due to replacement", res.URN)
 				sg.deletes[res.URN] = true
 				dels = append(dels, NewDeleteReplacementStep(sg.plan, res, false))
+			} else if retained[res.URN] {
+				logging.V(7).Infof("Planner retaining '%v' due to protection", res.URN)
 			} else if _, aliased := sg.aliased[res.URN]; !sg.sames[res.URN] && !sg.updates[res.URN] && !sg.replaces[res.URN] &&
 				!sg.reads[res.URN] && !aliased {
 				// NOTE: we deliberately do not check sg.deletes here, as it is possible for us to issue multiple
@@ -546,9 +732,67 @@ func (sg *stepGenerator) GenerateDeletes() []Step {
 	return dels
 }
 
+// retainResources computes the set of old resources that must not be deleted: those that are protected, if protected
+// resources are to be retained; those that are not targeted for deletion, if only some resources may be deleted; and
+// the resources that any of these depend on. It reports the protected and targeted resources that will be retained.
+func (sg *stepGenerator) retainResources() map[resource.URN]bool {
+	retained := make(map[resource.URN]bool)
+	var protected, targeted []string
+
+	// The old resources are stored in dependency order, so walking them backwards guarantees that we visit every
+	// resource that depends on a given resource before we visit the resource itself.
+	prev := sg.plan.prev.Resources
+	for i := len(prev) - 1; i >= 0; i-- {
+		res := prev[i]
+		if res.Delete {
+			continue
+		}
+		switch {
+		case sg.opts.RetainProtected && res.Protect:
+			retained[res.URN] = true
+			protected = append(protected, string(res.URN))
+		case sg.opts.DeleteTargets == nil:
+			// All unprotected resources may be deleted.
+		case !sg.opts.DeleteTargets[res.URN]:
+			retained[res.URN] = true
+		case retained[res.URN]:
+			targeted = append(targeted, string(res.URN))
+		}
+		if !retained[res.URN] {
+			continue
+		}
+
+		for _, dep := range res.Dependencies {
+			retained[dep] = true
+		}
+		if res.Parent != "" {
+			retained[res.Parent] = true
+		}
+		if res.Provider != "" {
+			ref, err := providers.ParseReference(res.Provider)
+			contract.Assert(err == nil)
+			retained[ref.URN()] = true
+		}
+	}
+
+	if len(protected) > 0 {
+		sg.plan.Diag().Warningf(diag.GetResourcesRetainedDueToProtectionWarning(""),
+			len(protected), strings.Join(protected, "\n    "))
+	}
+	if len(targeted) > 0 {
+		sg.plan.Diag().Warningf(diag.GetTargetedResourcesRetainedWarning(""),
+			len(targeted), strings.Join(targeted, "\n    "))
+	}
+	return retained
+}
+
 // GeneratePendingDeletes generates delete steps for all resources that are pending deletion. This function should be
 // called at the start of a plan in order to find all resources that are pending deletion from the prevous plan.
 func (sg *stepGenerator) GeneratePendingDeletes() []Step {
+	if sg.opts.ValidateOnly {
+		return nil
+	}
+
 	var dels []Step
 	if prev := sg.plan.prev; prev != nil {
 		logging.V(7).Infof("stepGenerator.GeneratePendingDeletes(): scanning previous snapshot for pending deletes")
@@ -577,14 +821,14 @@ func (sg *stepGenerator) GeneratePendingDeletes() []Step {
 //
 // The algorithm for decomposing a poset into antichains is:
 //  1. While there exist elements in the poset,
-//    1a. There must exist at least one "maximal" element of the poset. Let E_max be those elements.
-//    2a. Remove all elements E_max from the poset. E_max is an antichain.
-//    3a. Goto 1.
+//     1a. There must exist at least one "maximal" element of the poset. Let E_max be those elements.
+//     2a. Remove all elements E_max from the poset. E_max is an antichain.
+//     3a. Goto 1.
 //
 // Translated to our dependency graph:
 //  1. While the set of condemned resources is not empty:
-//    1a. Remove all resources with no outgoing edges from the graph and add them to the current antichain.
-//    2a. Goto 1.
+//     1a. Remove all resources with no outgoing edges from the graph and add them to the current antichain.
+//     2a. Goto 1.
 //
 // The resulting list of antichains is a list of list of steps that can be safely executed in parallel. Since we must
 // process deletes in reverse (so we don't delete resources upon which other resources depend), we reverse the list and
@@ -730,9 +974,11 @@ func (sg *stepGenerator) providerChanged(urn resource.URN, old, new *resource.St
 }
 
 // diff returns a DiffResult for the given resource.
+//
+// If explanation is non-nil, the properties that were compared, and how they were compared, are recorded in it.
 func (sg *stepGenerator) diff(urn resource.URN, old, new *resource.State, oldInputs, oldOutputs,
 	newInputs resource.PropertyMap, prov plugin.Provider, allowUnknowns bool,
-	ignoreChanges []string) (plugin.DiffResult, error) {
+	ignoreChanges []string, explanation *sameExplanation) (plugin.DiffResult, error) {
 
 	// Before diffing the resource, diff the provider field. If the provider field changes, we may or may
 	// not need to replace the resource.
@@ -743,9 +989,28 @@ func (sg *stepGenerator) diff(urn resource.URN, old, new *resource.State, oldInp
 		return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"provider"}}, nil
 	}
 
+	// Compare the provider's normalized forms of the old and new inputs, so that values that only differ in ways that
+	// the provider considers insignificant are not reported as changes. The inputs in the resource's state are left
+	// as the program supplied them.
+	if prov != nil {
+		normalizedOld, normalizedNew, err := sg.normalizeInputs(urn, new.Type, oldInputs, newInputs, prov)
+		if err != nil {
+			return plugin.DiffResult{}, err
+		}
+		if explanation != nil {
+			explanation.recordInputs(oldInputs, newInputs, normalizedOld, normalizedNew)
+		}
+		oldInputs, newInputs = normalizedOld, normalizedNew
+	} else if explanation != nil {
+		explanation.recordInputs(oldInputs, newInputs, oldInputs, newInputs)
+	}
+
 	// Apply legacy diffing behavior if requested. In this mode, if the provider-calculated inputs for a resource did
 	// not change, then the resource is considered to have no diff between its desired and actual state.
 	if sg.opts.UseLegacyDiff && oldInputs.DeepEquals(newInputs) {
+		if explanation != nil {
+			explanation.reason = "its inputs are the same as those of its last update (using legacy diffing)"
+		}
 		return plugin.DiffResult{Changes: plugin.DiffNone}, nil
 	}
 
@@ -753,11 +1018,17 @@ func (sg *stepGenerator) diff(urn resource.URN, old, new *resource.State, oldInp
 	// "diffs exist" result.
 	if prov == nil {
 		if oldInputs.DeepEquals(newInputs) {
+			if explanation != nil {
+				explanation.reason = "its inputs are the same as those of its last update"
+			}
 			return plugin.DiffResult{Changes: plugin.DiffNone}, nil
 		}
 		return plugin.DiffResult{Changes: plugin.DiffSome}, nil
 	}
 
+	if explanation != nil {
+		explanation.reason = "its provider's diff reported no changes to its inputs"
+	}
 	return diffResource(urn, old.ID, oldInputs, oldOutputs, newInputs, prov, allowUnknowns, ignoreChanges)
 }
 
@@ -841,6 +1112,72 @@ func processIgnoreChanges(inputs, oldInputs resource.PropertyMap,
 	return ignoredInputs.ObjectValue(), nil
 }
 
+// changedPropertyPaths returns the subset of the given property paths whose values differ between inputs and
+// oldInputs, e.g. the ignoreChanges paths for which ignoring changes actually suppressed a diff.
+func changedPropertyPaths(inputs, oldInputs resource.PropertyMap, paths []string) []string {
+	var changed []string
+	for _, p := range paths {
+		path, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			continue
+		}
+
+		oldValue, hasOld := path.Get(resource.NewObjectProperty(oldInputs))
+		newValue, hasNew := path.Get(resource.NewObjectProperty(inputs))
+		if hasOld != hasNew || (hasOld && !oldValue.DeepEquals(newValue)) {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// applyReplaceOnChanges turns the given diff into a replacement if any of the given replaceOnChanges property paths
+// changed, even if the resource's provider could update them in place, and returns the paths that changed. Each of
+// them becomes a replace key, and any entry for it in the detailed diff becomes a replacement.
+func applyReplaceOnChanges(diff plugin.DiffResult, inputs, oldInputs resource.PropertyMap,
+	replaceOnChanges []string) (plugin.DiffResult, []string) {
+
+	triggered := changedPropertyPaths(inputs, oldInputs, replaceOnChanges)
+	if len(triggered) == 0 {
+		return diff, nil
+	}
+
+	diff.Changes = plugin.DiffSome
+	replaceKeys := make(map[resource.PropertyKey]bool)
+	for _, k := range diff.ReplaceKeys {
+		replaceKeys[k] = true
+	}
+	for _, p := range triggered {
+		if k := resource.PropertyKey(p); !replaceKeys[k] {
+			diff.ReplaceKeys, replaceKeys[k] = append(diff.ReplaceKeys, k), true
+		}
+	}
+
+	if diff.DetailedDiff != nil {
+		detailedDiff := make(map[string]plugin.PropertyDiff)
+		for k, v := range diff.DetailedDiff {
+			detailedDiff[k] = v
+		}
+		for _, p := range triggered {
+			d, has := detailedDiff[p]
+			if !has {
+				d = plugin.PropertyDiff{Kind: plugin.DiffUpdate, InputDiff: true}
+			}
+			switch d.Kind {
+			case plugin.DiffAdd:
+				d.Kind = plugin.DiffAddReplace
+			case plugin.DiffDelete:
+				d.Kind = plugin.DiffDeleteReplace
+			case plugin.DiffUpdate:
+				d.Kind = plugin.DiffUpdateReplace
+			}
+			detailedDiff[p] = d
+		}
+		diff.DetailedDiff = detailedDiff
+	}
+	return diff, triggered
+}
+
 func (sg *stepGenerator) loadResourceProvider(
 	urn resource.URN, custom bool, provider string, typ tokens.Type) (plugin.Provider, result.Result) {
 
@@ -970,7 +1307,7 @@ func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
 	return &stepGenerator{
 		plan:                 plan,
 		opts:                 opts,
-		urns:                 make(map[resource.URN]bool),
+		urns:                 make(map[resource.URN]*resource.Goal),
 		reads:                make(map[resource.URN]bool),
 		creates:              make(map[resource.URN]bool),
 		sames:                make(map[resource.URN]bool),
@@ -981,5 +1318,11 @@ func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
 		providers:            make(map[resource.URN]*resource.State),
 		dependentReplaceKeys: make(map[resource.URN][]resource.PropertyKey),
 		aliased:              make(map[resource.URN]resource.URN),
+		deprecations:         make(map[plugin.Provider]*providerDeprecations),
+		sliced:               make(map[resource.URN]bool),
+		skipped:              make(map[resource.URN]bool),
+
+		dependencyCandidates: make(map[tokens.Package][]dependencyCandidate),
+		reportedDeprecations: make(map[string]bool),
 	}
 }
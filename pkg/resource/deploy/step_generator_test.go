@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -113,3 +114,66 @@ func TestIgnoreChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedIgnoredProperties(t *testing.T) {
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": "foo",
+			"c": "bar",
+		},
+		"d": 42,
+	})
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": "foo",
+			"c": "baz",
+		},
+		"e": true,
+	})
+
+	changed := changedPropertyPaths(news, olds, []string{"a.b", "a.c", "d", "e", "f"})
+	assert.Equal(t, []string{"a.c", "d", "e"}, changed)
+}
+
+func TestApplyReplaceOnChanges(t *testing.T) {
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": map[string]interface{}{"b": "foo", "c": "bar"},
+		"d": "baz",
+	})
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": map[string]interface{}{"b": "qux", "c": "bar"},
+		"d": "quux",
+	})
+
+	// Changes to paths that are not listed leave the diff alone.
+	diff := plugin.DiffResult{Changes: plugin.DiffSome, ChangedKeys: []resource.PropertyKey{"a", "d"}}
+	applied, triggered := applyReplaceOnChanges(diff, news, olds, []string{"a.c"})
+	assert.Empty(t, triggered)
+	assert.Equal(t, diff, applied)
+
+	// Changes to listed paths are replacements, even if the provider reported no changes.
+	applied, triggered = applyReplaceOnChanges(plugin.DiffResult{Changes: plugin.DiffNone}, news, olds,
+		[]string{"a.b", "a.c"})
+	assert.Equal(t, []string{"a.b"}, triggered)
+	assert.Equal(t, plugin.DiffSome, applied.Changes)
+	assert.Equal(t, []resource.PropertyKey{"a.b"}, applied.ReplaceKeys)
+	assert.True(t, applied.Replace())
+
+	// A detailed diff marks the listed paths as replacements.
+	diff = plugin.DiffResult{
+		Changes:     plugin.DiffSome,
+		ReplaceKeys: []resource.PropertyKey{"d"},
+		DetailedDiff: map[string]plugin.PropertyDiff{
+			"a.b": {Kind: plugin.DiffUpdate},
+			"d":   {Kind: plugin.DiffUpdateReplace},
+		},
+	}
+	applied, triggered = applyReplaceOnChanges(diff, news, olds, []string{"a.b", "d"})
+	assert.Equal(t, []string{"a.b", "d"}, triggered)
+	assert.Equal(t, []resource.PropertyKey{"d", "a.b"}, applied.ReplaceKeys)
+	assert.Equal(t, map[string]plugin.PropertyDiff{
+		"a.b": {Kind: plugin.DiffUpdateReplace},
+		"d":   {Kind: plugin.DiffUpdateReplace},
+	}, applied.DetailedDiff)
+	assert.Equal(t, plugin.DiffUpdate, diff.DetailedDiff["a.b"].Kind)
+}
@@ -0,0 +1,103 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+)
+
+// DefaultRetryPolicy is the retry policy of the resources that do not override it, when the plan's options do not
+// provide one. It does not retry operations at all.
+var DefaultRetryPolicy = resource.RetryPolicy{Attempts: 1, Delay: 1, Backoff: 2}
+
+// applyWithRetries applies the given step and, if the provider reports that the operation it performs failed
+// transiently without changing the resource, retries the step according to the retry policy of the resource. The
+// retries stop, and the last failure is returned, if the context is canceled.
+func (se *stepExecutor) applyWithRetries(ctx context.Context, step Step) (resource.Status, StepCompleteFunc, error) {
+	policy, retryable := stepRetryPolicy(step, se.opts.RetryPolicy.Merge(DefaultRetryPolicy))
+	delay := time.Duration(policy.Delay * float64(time.Second))
+	for attempt := 1; ; attempt++ {
+		status, complete, err := step.Apply(ctx, se.preview)
+		if err == nil || !retryable || attempt >= policy.Attempts ||
+			status != resource.StatusOK || !isTransientError(err) {
+			return status, complete, err
+		}
+
+		logging.V(stepExecutorLogLevel).Infof(
+			"StepExecutor: step %v on %v failed transiently on attempt %d of %d, retrying in %v "+
+				"(delay=%vs, backoff=%v): %v", step.Op(), step.URN(), attempt, policy.Attempts, delay,
+			policy.Delay, policy.Backoff, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return status, complete, err
+		}
+		delay = time.Duration(float64(delay) * policy.Backoff)
+	}
+}
+
+// stepRetryPolicy returns the retry policy for the provider operation that the given step performs, which is that of
+// the resource it operates on merged with the given defaults, and whether the operation may be retried at all. Reads
+// and deletions may always be retried. Creations and updates are not idempotent, so they are only retried if the
+// provider reports that they fail transiently only when they did not change the resource.
+func stepRetryPolicy(step Step, defaults resource.RetryPolicy) (resource.RetryPolicy, bool) {
+	var res *resource.State
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement, OpUpdate, OpRead, OpReadReplacement:
+		res = step.New()
+	case OpDelete, OpDeleteReplaced, OpRefresh:
+		res = step.Old()
+	default:
+		return defaults, false
+	}
+	if res == nil || !res.Custom {
+		return defaults, false
+	}
+
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement, OpUpdate:
+		prov, err := getProvider(step)
+		if err != nil || !plugin.GetCapabilities(prov).CreateAndUpdateRetries {
+			return defaults, false
+		}
+	}
+	return res.RetryPolicy.Merge(defaults), true
+}
+
+// isTransientError returns true if the given error is one with which a provider reports that an operation failed
+// for a reason that may not recur, e.g. because its cloud was briefly unavailable or throttled the request.
+func isTransientError(err error) bool {
+	rpcErr, ok := rpcerror.FromError(errors.Cause(err))
+	if !ok {
+		return false
+	}
+	switch rpcErr.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
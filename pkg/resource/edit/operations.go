@@ -79,6 +79,36 @@ func UnprotectResource(_ *deploy.Snapshot, res *resource.State) error {
 	return nil
 }
 
+// ReplaceResource replaces a given resource in the snapshot with a new state for that resource. If the snapshot was
+// valid before the replacement but would not be valid after it, the snapshot is left unchanged and an error describing
+// the problem is returned.
+func ReplaceResource(snapshot *deploy.Snapshot, oldRes, newRes *resource.State) error {
+	contract.Require(snapshot != nil, "snapshot")
+	contract.Require(oldRes != nil, "oldRes")
+	contract.Require(newRes != nil, "newRes")
+
+	idx := -1
+	for i, res := range snapshot.Resources {
+		if res == oldRes {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return errors.Errorf("resource %q is not present in the snapshot", oldRes.URN)
+	}
+
+	wasValid := snapshot.VerifyIntegrity() == nil
+	snapshot.Resources[idx] = newRes
+	if wasValid {
+		if err := snapshot.VerifyIntegrity(); err != nil {
+			snapshot.Resources[idx] = oldRes
+			return err
+		}
+	}
+	return nil
+}
+
 // LocateResource returns all resources in the given shapshot that have the given URN.
 func LocateResource(snap *deploy.Snapshot, urn resource.URN) []*resource.State {
 	contract.Require(snap != nil, "snap")
@@ -150,3 +180,85 @@ func RenameStack(snap *deploy.Snapshot, newName tokens.QName) error {
 
 	return nil
 }
+
+// ReplaceProvider changes the provider of each resource in the snapshot that uses the old provider, and for which the
+// given filter, if any, returns true, to the new provider. Both providers must be defined in the snapshot, and must be
+// providers for the same package. If the new provider is defined after a resource that is changed to use it, the
+// provider is moved to precede the resource. The number of resources that were changed is returned; if an error is
+// returned, the snapshot is left unchanged.
+func ReplaceProvider(snap *deploy.Snapshot, oldRef, newRef providers.Reference,
+	filter func(*resource.State) bool) (int, error) {
+
+	contract.Require(snap != nil, "snap")
+
+	if err := snap.VerifyIntegrity(); err != nil {
+		return 0, errors.Wrap(err, "checkpoint is invalid")
+	}
+	if oldRef.String() == newRef.String() {
+		return 0, errors.New("the old and new providers must differ")
+	}
+
+	findProvider := func(ref providers.Reference) (int, error) {
+		for i, res := range snap.Resources {
+			if res.URN == ref.URN() && res.ID == ref.ID() && !res.Delete && providers.IsProviderType(res.Type) {
+				return i, nil
+			}
+		}
+		return -1, errors.Errorf("provider %q does not exist in the current state", ref)
+	}
+	oldIdx, err := findProvider(oldRef)
+	if err != nil {
+		return 0, err
+	}
+	newIdx, err := findProvider(newRef)
+	if err != nil {
+		return 0, err
+	}
+	newProvider := snap.Resources[newIdx]
+	if oldPkg, newPkg := providers.GetProviderPackage(snap.Resources[oldIdx].Type),
+		providers.GetProviderPackage(newProvider.Type); oldPkg != newPkg {
+		return 0, errors.Errorf("provider %q is for package %s, not %s", newRef, newPkg, oldPkg)
+	}
+
+	var changed []int
+	for i, res := range snap.Resources {
+		if res.Provider == oldRef.String() && (filter == nil || filter(res)) {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	// If the new provider is defined after the first resource that will use it, it must be moved before that resource,
+	// which is only possible if the resources that the provider itself depends on are defined before it, too.
+	resources := snap.Resources
+	if first := changed[0]; newIdx > first {
+		defined := make(map[resource.URN]bool)
+		for _, res := range snap.Resources[:first] {
+			defined[res.URN] = true
+		}
+		requires := newProvider.Dependencies
+		if newProvider.Parent != "" {
+			requires = append([]resource.URN{newProvider.Parent}, requires...)
+		}
+		for _, urn := range requires {
+			if !defined[urn] {
+				return 0, errors.Errorf("provider %q cannot be moved before resource %q, which uses it, "+
+					"because the provider depends on %q", newRef, snap.Resources[first].URN, urn)
+			}
+		}
+
+		resources = make([]*resource.State, 0, len(snap.Resources))
+		resources = append(resources, snap.Resources[:first]...)
+		resources = append(resources, newProvider)
+		resources = append(resources, snap.Resources[first:newIdx]...)
+		resources = append(resources, snap.Resources[newIdx+1:]...)
+	}
+
+	for _, i := range changed {
+		snap.Resources[i].Provider = newRef.String()
+	}
+	snap.Resources = resources
+	return len(changed), nil
+}
@@ -260,3 +260,92 @@ func TestLocateResourceExact(t *testing.T) {
 	assert.Len(t, resList, 1)
 	assert.Contains(t, resList, a)
 }
+
+func TestReplaceResource(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	snap := NewSnapshot([]*resource.State{
+		pA,
+		a,
+		b,
+	})
+
+	// Editing a resource's properties is fine.
+	edited := *a
+	edited.Outputs = resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	err := ReplaceResource(snap, a, &edited)
+	assert.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, &edited, b}, snap.Resources)
+
+	// Renaming a resource that another resource depends upon is not, and the snapshot is left unchanged.
+	renamed := edited
+	renamed.URN = resource.NewURN("test", "test", "", renamed.Type, "renamed")
+	err = ReplaceResource(snap, &edited, &renamed)
+	assert.Error(t, err)
+	assert.Equal(t, []*resource.State{pA, &edited, b}, snap.Resources)
+
+	// Neither is replacing a resource that is not in the snapshot.
+	err = ReplaceResource(snap, a, &renamed)
+	assert.Error(t, err)
+}
+
+func TestReplaceProvider(t *testing.T) {
+	ref := func(p *resource.State) providers.Reference {
+		r, err := providers.NewReference(p.URN, p.ID)
+		assert.NoError(t, err)
+		return r
+	}
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	c := NewResource("c", pA)
+	pB := NewProviderResource("a", "p2", "1")
+	pC := NewProviderResource("c", "p3", "2")
+	snap := NewSnapshot([]*resource.State{pA, a, b, c, pB, pC})
+
+	// Only the resources that match the filter are changed. The new provider is moved before them.
+	n, err := ReplaceProvider(snap, ref(pA), ref(pB), func(res *resource.State) bool { return res != a })
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, ref(pA).String(), a.Provider)
+	assert.Equal(t, ref(pB).String(), b.Provider)
+	assert.Equal(t, ref(pB).String(), c.Provider)
+	assert.Equal(t, []*resource.State{pA, a, pB, b, c, pC}, snap.Resources)
+	assert.NoError(t, snap.VerifyIntegrity())
+
+	// Without a filter, every resource that uses the old provider is changed.
+	n, err = ReplaceProvider(snap, ref(pA), ref(pB), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, ref(pB).String(), a.Provider)
+	assert.Equal(t, []*resource.State{pA, pB, a, b, c, pC}, snap.Resources)
+
+	// Both providers must exist, and must be for the same package.
+	_, err = ReplaceProvider(snap, ref(pB), ref(pC), nil)
+	assert.EqualError(t, err, `provider "`+ref(pC).String()+`" is for package c, not a`)
+	missing, err := providers.NewReference(pB.URN, "missing")
+	assert.NoError(t, err)
+	_, err = ReplaceProvider(snap, ref(pB), missing, nil)
+	assert.Error(t, err)
+	assert.Equal(t, ref(pB).String(), a.Provider)
+}
+
+func TestReplaceProviderDependencies(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	pB := NewProviderResource("a", "p2", "1", a.URN)
+	snap := NewSnapshot([]*resource.State{pA, a, pB})
+
+	oldRef, err := providers.NewReference(pA.URN, pA.ID)
+	assert.NoError(t, err)
+	newRef, err := providers.NewReference(pB.URN, pB.ID)
+	assert.NoError(t, err)
+
+	// A resource cannot use a provider that depends on it, and the snapshot is left unchanged.
+	_, err = ReplaceProvider(snap, oldRef, newRef, nil)
+	assert.Error(t, err)
+	assert.Equal(t, oldRef.String(), a.Provider)
+	assert.Equal(t, []*resource.State{pA, a, pB}, snap.Resources)
+}
@@ -0,0 +1,62 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// NewHost returns a plugin host that serves every resource provider from the given mocks, and defers to the given
+// host for language and analyzer plugins. Resource plugins are neither required nor loaded.
+func NewHost(host plugin.Host, mocks *Mocks) plugin.Host {
+	return &mockHost{Host: host, mocks: mocks}
+}
+
+// IsMocked returns true if the given host serves resource providers from mocks.
+func IsMocked(host plugin.Host) bool {
+	_, ok := host.(*mockHost)
+	return ok
+}
+
+type mockHost struct {
+	plugin.Host
+	mocks *Mocks
+}
+
+func (h *mockHost) Provider(pkg tokens.Package, version *semver.Version) (plugin.Provider, error) {
+	return &provider{pkg: pkg, version: version, mocks: h.mocks}, nil
+}
+
+func (h *mockHost) CloseProvider(p plugin.Provider) error {
+	if _, ok := p.(*provider); ok {
+		return nil
+	}
+	return h.Host.CloseProvider(p)
+}
+
+func (h *mockHost) EnsurePlugins(plugins []workspace.PluginInfo, kinds plugin.Flags) error {
+	return h.Host.EnsurePlugins(plugins, kinds&^plugin.ResourcePlugins)
+}
+
+func (h *mockHost) GetRequiredPlugins(info plugin.ProgInfo, kinds plugin.Flags) ([]workspace.PluginInfo, error) {
+	if kinds&^plugin.ResourcePlugins == 0 {
+		return nil, nil
+	}
+	return h.Host.GetRequiredPlugins(info, kinds&^plugin.ResourcePlugins)
+}
@@ -0,0 +1,119 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mock satisfies the operations of resource providers with canned state, so that a stack's program can be
+// previewed and updated without contacting any cloud, e.g. in the stack's tests.
+package mock
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// A Call records a single operation that a mock provider was asked to perform.
+type Call struct {
+	Method string               // the provider method that was called, e.g. "Create".
+	URN    resource.URN         // the URN of the resource operated upon, if any.
+	ID     resource.ID          // the ID of the resource operated upon, if any.
+	Token  tokens.ModuleMember  // the function that was invoked, if any.
+	Inputs resource.PropertyMap // the inputs of the resource, or the arguments of the function.
+}
+
+// Mocks satisfies the operations of every resource provider. A resource's outputs are its inputs, overlaid with the
+// canned outputs for its type, if any; a function returns the canned result for its token, if any, and otherwise
+// returns nothing. Every call that is made to a mock provider is recorded, and may be retrieved using Calls.
+type Mocks struct {
+	Resources map[tokens.Type]resource.PropertyMap         // the canned outputs of resources, by type.
+	Invokes   map[tokens.ModuleMember]resource.PropertyMap // the canned results of functions, by token.
+
+	calls []Call
+	lock  sync.Mutex
+}
+
+// fixtures is the format of a mock fixtures file.
+type fixtures struct {
+	Resources map[string]map[string]interface{} `json:"resources" yaml:"resources"`
+	Invokes   map[string]map[string]interface{} `json:"invokes" yaml:"invokes"`
+}
+
+// Load reads canned outputs and function results from the fixtures file at the given path, which is decoded as JSON
+// or YAML according to its extension. The file maps each resource type under "resources" to the outputs of resources
+// of that type, and each function token under "invokes" to the function's result, e.g.
+// {"resources": {"aws:s3/bucket:Bucket": {"arn": "arn:aws:s3:::logs"}}, "invokes": {"aws:index/getRegion:getRegion":
+// {"name": "us-west-2"}}}.
+func Load(path string) (*Mocks, error) {
+	m, _ := encoding.Detect(path)
+	if m == nil {
+		return nil, errors.Errorf("could not read mock fixtures %s: expected a .json or .yaml file", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading mock fixtures")
+	}
+	var f fixtures
+	if err = m.Unmarshal(b, &f); err != nil {
+		return nil, errors.Wrapf(err, "could not read mock fixtures %s", path)
+	}
+
+	mocks := &Mocks{
+		Resources: make(map[tokens.Type]resource.PropertyMap),
+		Invokes:   make(map[tokens.ModuleMember]resource.PropertyMap),
+	}
+	for t, outputs := range f.Resources {
+		if _, err := tokens.ParseTypeToken(t); err != nil {
+			return nil, errors.Wrapf(err, "mock fixtures %s", path)
+		}
+		mocks.Resources[tokens.Type(t)] = resource.NewPropertyMapFromMap(outputs)
+	}
+	for tok, result := range f.Invokes {
+		if strings.Count(tok, ":") != 2 {
+			return nil, errors.Errorf("mock fixtures %s: '%s' is not a valid function token", path, tok)
+		}
+		mocks.Invokes[tokens.ModuleMember(tok)] = resource.NewPropertyMapFromMap(result)
+	}
+	return mocks, nil
+}
+
+// Calls returns the calls that have been made to the mock providers so far, in the order in which they were made.
+func (m *Mocks) Calls() []Call {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// record records the given call.
+func (m *Mocks) record(c Call) {
+	logging.V(7).Infof("mock provider call: %s(urn=%v, id=%v, token=%v)", c.Method, c.URN, c.ID, c.Token)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, c)
+}
+
+// outputs returns the outputs of a resource of the given type with the given inputs.
+func (m *Mocks) outputs(t tokens.Type, inputs resource.PropertyMap) resource.PropertyMap {
+	outputs := inputs.Copy()
+	for k, v := range m.Resources[t] {
+		outputs[k] = v
+	}
+	return outputs
+}
@@ -0,0 +1,114 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-fixtures")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "fixtures.yaml")
+	err = ioutil.WriteFile(path, []byte(`resources:
+  aws:s3/bucket:Bucket:
+    arn: arn:aws:s3:::logs
+    tags:
+      env: test
+invokes:
+  aws:index/getRegion:getRegion:
+    name: us-west-2
+`), 0600)
+	assert.NoError(t, err)
+
+	mocks, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"arn": resource.NewStringProperty("arn:aws:s3:::logs"),
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"env": resource.NewStringProperty("test"),
+		}),
+	}, mocks.Resources["aws:s3/bucket:Bucket"])
+	assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("us-west-2")},
+		mocks.Invokes["aws:index/getRegion:getRegion"])
+
+	err = ioutil.WriteFile(path, []byte("resources:\n  bucket: {}\n"), 0600)
+	assert.NoError(t, err)
+	_, err = Load(path)
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(dir, "fixtures.txt"))
+	assert.Error(t, err)
+}
+
+func TestProvider(t *testing.T) {
+	mocks := &Mocks{
+		Resources: map[tokens.Type]resource.PropertyMap{
+			"aws:s3/bucket:Bucket": {"arn": resource.NewStringProperty("arn:aws:s3:::logs")},
+		},
+		Invokes: map[tokens.ModuleMember]resource.PropertyMap{
+			"aws:index/getRegion:getRegion": {"name": resource.NewStringProperty("us-west-2")},
+		},
+	}
+	host := NewHost(nil, mocks)
+	assert.True(t, IsMocked(host))
+	prov, err := host.Provider("aws", nil)
+	assert.NoError(t, err)
+
+	// A resource's outputs are its inputs overlaid with the canned outputs for its type.
+	urn := resource.NewURN("test", "proj", "", "aws:s3/bucket:Bucket", "logs")
+	inputs := resource.PropertyMap{
+		"acl": resource.NewStringProperty("private"),
+		"arn": resource.NewStringProperty("ignored"),
+	}
+	id, outputs, status, err := prov.Create(urn, inputs, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.StatusOK, status)
+	assert.Equal(t, resource.ID("logs-id"), id)
+	assert.Equal(t, resource.PropertyMap{
+		"acl": resource.NewStringProperty("private"),
+		"arn": resource.NewStringProperty("arn:aws:s3:::logs"),
+	}, outputs)
+
+	// A function returns its canned result, if any; the functions that the engine invokes are not recorded.
+	result, _, err := prov.Invoke("aws:index/getRegion:getRegion", resource.PropertyMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", result["name"].StringValue())
+	result, _, err = prov.Invoke("aws:index/getZones:getZones", resource.PropertyMap{})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	_, _, err = prov.Invoke("pulumi:providers:getDeprecations", resource.PropertyMap{})
+	assert.NoError(t, err)
+
+	_, err = prov.Delete(urn, id, outputs, 0)
+	assert.NoError(t, err)
+
+	var methods []string
+	for _, c := range mocks.Calls() {
+		methods = append(methods, c.Method)
+	}
+	assert.Equal(t, []string{"Create", "Invoke", "Invoke", "Delete"}, methods)
+	assert.Equal(t, inputs, mocks.Calls()[0].Inputs)
+}
@@ -0,0 +1,119 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// engineFunctionPrefix prefixes the tokens of the functions that the engine itself invokes to query a provider's
+// capabilities. These are not calls that a program makes, so they are not recorded.
+const engineFunctionPrefix = "pulumi:providers:"
+
+// provider is a mock provider for a single package.
+type provider struct {
+	pkg     tokens.Package
+	version *semver.Version
+	mocks   *Mocks
+}
+
+func (p *provider) Close() error                         { return nil }
+func (p *provider) Pkg() tokens.Package                  { return p.pkg }
+func (p *provider) SignalCancellation() error            { return nil }
+func (p *provider) Configure(resource.PropertyMap) error { return nil }
+
+func (p *provider) GetPluginInfo() (workspace.PluginInfo, error) {
+	return workspace.PluginInfo{Name: string(p.pkg), Kind: workspace.ResourcePlugin, Version: p.version}, nil
+}
+
+func (p *provider) CheckConfig(urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, []plugin.CheckFailure, error) {
+	return news, nil, nil
+}
+
+func (p *provider) DiffConfig(urn resource.URN, olds, news resource.PropertyMap, allowUnknowns bool,
+	ignoreChanges []string) (plugin.DiffResult, error) {
+	return plugin.DiffResult{}, nil
+}
+
+func (p *provider) Check(urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, []plugin.CheckFailure, error) {
+	return news, nil, nil
+}
+
+// Diff reports that it does not know whether the resource has changed, so that the engine compares its inputs.
+func (p *provider) Diff(urn resource.URN, id resource.ID, olds resource.PropertyMap, news resource.PropertyMap,
+	allowUnknowns bool, ignoreChanges []string) (plugin.DiffResult, error) {
+	return plugin.DiffResult{}, nil
+}
+
+func (p *provider) Create(urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	id := resource.ID(fmt.Sprintf("%s-id", urn.Name()))
+	p.mocks.record(Call{Method: "Create", URN: urn, ID: id, Inputs: news})
+	return id, p.mocks.outputs(urn.Type(), news), resource.StatusOK, nil
+}
+
+// Read returns the given state unchanged. If there is no state, as when a resource is imported or read by its ID, it
+// returns the canned outputs for the resource's type.
+func (p *provider) Read(urn resource.URN, id resource.ID,
+	inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+
+	p.mocks.record(Call{Method: "Read", URN: urn, ID: id, Inputs: inputs})
+	if state == nil {
+		if inputs == nil {
+			inputs = resource.PropertyMap{}
+		}
+		state = p.mocks.outputs(urn.Type(), inputs)
+	}
+	return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+}
+
+func (p *provider) Update(urn resource.URN, id resource.ID, olds resource.PropertyMap, news resource.PropertyMap,
+	timeout float64, ignoreChanges []string) (resource.PropertyMap, resource.Status, error) {
+
+	p.mocks.record(Call{Method: "Update", URN: urn, ID: id, Inputs: news})
+	return p.mocks.outputs(urn.Type(), news), resource.StatusOK, nil
+}
+
+func (p *provider) Delete(urn resource.URN, id resource.ID, props resource.PropertyMap,
+	timeout float64) (resource.Status, error) {
+
+	p.mocks.record(Call{Method: "Delete", URN: urn, ID: id, Inputs: props})
+	return resource.StatusOK, nil
+}
+
+func (p *provider) Invoke(tok tokens.ModuleMember,
+	args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+	if strings.HasPrefix(string(tok), engineFunctionPrefix) {
+		return resource.PropertyMap{}, nil, nil
+	}
+
+	p.mocks.record(Call{Method: "Invoke", Token: tok, Inputs: args})
+	if result, has := p.mocks.Invokes[tok]; has {
+		return result.Copy(), nil, nil
+	}
+	return resource.PropertyMap{}, nil, nil
+}
@@ -31,6 +31,10 @@ type Context struct {
 	Host       Host      // the host that can be used to fetch providers.
 	Pwd        string    // the working directory to spawn all plugins in.
 
+	// ProviderDryRun is true if resource provider plugins are to be asked not to call cloud APIs. Operations on the
+	// resources of providers that do not support dry-run mode fail.
+	ProviderDryRun bool
+
 	tracingSpan opentracing.Span // the OpenTracing span to parent requests within.
 }
 
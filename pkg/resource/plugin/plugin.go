@@ -214,9 +214,12 @@ func execPlugin(bin string, pluginArgs []string, pwd string) (*plugin, error) {
 		if logging.Verbose > 0 {
 			args = append(args, "-v="+strconv.Itoa(logging.Verbose))
 		}
+		if logging.LogDir != "" {
+			args = append(args, "-log_dir="+logging.LogDir)
+		}
 	}
-	// Always flow tracing settings.
-	if cmdutil.TracingEndpoint != "" {
+	// Always flow tracing settings, unless tracing data is being written to a file that plugins would overwrite.
+	if cmdutil.TracingEndpoint != "" && !cmdutil.TracingToFile {
 		args = append(args, "--tracing", cmdutil.TracingEndpoint)
 	}
 	args = append(args, pluginArgs...)
@@ -23,6 +23,12 @@ import (
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
+// ProviderDryRunVariable is the configuration variable that is set to "true" when a resource provider plugin is
+// configured to run in dry-run mode. A provider that supports the mode runs its logic for every operation as it
+// normally would, but does not call the cloud APIs that would create, read, update, or delete real resources, and
+// returns outputs as if it had. Providers report that they support the mode in their response to Configure.
+const ProviderDryRunVariable = "pulumi:dryRun"
+
 // Provider presents a simple interface for orchestrating resource create, read, update, and delete operations.  Each
 // provider understands how to handle all of the resource types within a single package.
 //
@@ -82,6 +88,43 @@ type Provider interface {
 	SignalCancellation() error
 }
 
+// CapabilityReporter is implemented by providers that report the optional parts of the provider protocol that they
+// implement. A provider that does not implement it is treated as implementing none of them.
+type CapabilityReporter interface {
+	// Capabilities returns the optional parts of the provider protocol that this provider reported when it was
+	// configured. It waits for the provider to be configured, and reports nothing if configuration failed or if the
+	// provider's configuration is not known.
+	Capabilities() ProviderCapabilities
+}
+
+// ProviderCapabilities records the optional functions and behaviors of the provider protocol that a provider
+// implements, as it reports in its response to Configure. The engine only relies on those that a provider reports.
+type ProviderCapabilities struct {
+	NormalizeInputs         bool // true if the provider normalizes resource inputs before they are diffed.
+	Deprecations            bool // true if the provider reports the resource types and properties it deprecated.
+	NonComparableProperties bool // true if the provider reports the outputs that change on their own.
+	ArrayKeys               bool // true if the provider declares the keys of the elements of arrays.
+	PollOperation           bool // true if the provider completes some operations asynchronously.
+	PreviewOperation        bool // true if the provider predicts the outputs of operations during previews.
+	BatchCreate             bool // true if the provider creates several resources of a type in one call.
+	ResourceDependencies    bool // true if the provider reports dependencies between resources.
+
+	// CreateAndUpdateRetries is true if the provider fails creations and updates transiently only when they did not
+	// change the resource, so that they may be retried.
+	CreateAndUpdateRetries bool
+
+	// DryRun is true if the provider honors ProviderDryRunVariable.
+	DryRun bool
+}
+
+// GetCapabilities returns the capabilities that the given provider reports, or none if it is not a CapabilityReporter.
+func GetCapabilities(p Provider) ProviderCapabilities {
+	if reporter, ok := p.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return ProviderCapabilities{}
+}
+
 // CheckFailure indicates that a call to check failed; it contains the property and reason for the failure.
 type CheckFailure struct {
 	Property resource.PropertyKey // the property that failed checking.
@@ -58,6 +58,7 @@ type provider struct {
 	cfgknown      bool                             // true if all configuration values are known.
 	cfgdone       chan bool                        // closed when configuration has completed.
 	acceptSecrets bool                             // true if this provider plugin can consume strongly typed secret.
+	capabilities  ProviderCapabilities             // the optional functions that this provider plugin implements.
 }
 
 // NewProvider attempts to bind to a given package's resource plugin and then creates a gRPC connection to it.  If the
@@ -397,6 +398,10 @@ func (p *provider) Configure(inputs resource.PropertyMap) error {
 		}
 	}
 
+	if p.ctx.ProviderDryRun {
+		config[ProviderDryRunVariable] = "true"
+	}
+
 	// Spawn the configure to happen in parallel.  This ensures that we remain responsive elsewhere that might
 	// want to make forward progress, even as the configure call is happening.
 	go func() {
@@ -411,12 +416,32 @@ func (p *provider) Configure(inputs resource.PropertyMap) error {
 		}
 		// Acquire the lock, publish the results, and notify any waiters.
 		p.cfgknown, p.acceptSecrets, p.cfgerr = true, resp.GetAcceptSecrets(), err
+		p.capabilities = ProviderCapabilities{
+			NormalizeInputs:         resp.GetSupportsNormalizeInputs(),
+			Deprecations:            resp.GetSupportsDeprecations(),
+			NonComparableProperties: resp.GetSupportsNonComparableProperties(),
+			ArrayKeys:               resp.GetSupportsArrayKeys(),
+			PollOperation:           resp.GetSupportsPollOperation(),
+			PreviewOperation:        resp.GetSupportsPreviewOperation(),
+			BatchCreate:             resp.GetSupportsBatchCreate(),
+			ResourceDependencies:    resp.GetSupportsResourceDependencies(),
+			CreateAndUpdateRetries:  resp.GetSupportsCreateAndUpdateRetries(),
+			DryRun:                  resp.GetSupportsDryRun(),
+		}
 		close(p.cfgdone)
 	}()
 
 	return nil
 }
 
+// Capabilities returns the optional functions that this provider reported when it was configured.
+func (p *provider) Capabilities() ProviderCapabilities {
+	if err := p.ensureConfigured(); err != nil || !p.cfgknown {
+		return ProviderCapabilities{}
+	}
+	return p.capabilities
+}
+
 // Check validates that the given property bag is valid for a resource of the given type.
 func (p *provider) Check(urn resource.URN,
 	olds, news resource.PropertyMap, allowUnknowns bool) (resource.PropertyMap, []CheckFailure, error) {
@@ -88,6 +88,11 @@ type ArrayDiff struct {
 	Deletes map[int]PropertyValue // elements deleted in the new.
 	Sames   map[int]PropertyValue // elements the same in both.
 	Updates map[int]ValueDiff     // elements that have changed in the new.
+
+	// Key, if set, is the property that identifies the elements of the arrays, which were matched by its value rather
+	// than by their positions. The indices above are then positions in a merged sequence holding the new elements in
+	// order, with each deleted element following the old element that preceded it.
+	Key PropertyKey
 }
 
 // Len computes the length of this array, taking into account adds, deletes, sames, and updates.
@@ -119,8 +124,27 @@ func (diff *ArrayDiff) Len() int {
 // IgnoreKeyFunc is the callback type for Diff's ignore option.
 type IgnoreKeyFunc func(key PropertyKey) bool
 
+// ArrayKeys maps the paths of arrays of objects to the names of the properties that identify their elements. A path
+// names the properties that lead to an array from the root of a property map, joined by dots and without any array
+// indices: "rules" names a top-level array, and "rules.targets" names the "targets" array of each of its elements.
+type ArrayKeys map[string]PropertyKey
+
 // Diff returns a diffset by comparing the property map to another; it returns nil if there are no diffs.
 func (props PropertyMap) Diff(other PropertyMap, ignoreKeys ...IgnoreKeyFunc) *ObjectDiff {
+	return props.diff(other, "", nil, ignoreKeys)
+}
+
+// DiffWithArrayKeys is like Diff, but matches the elements of the arrays named by the given keys by the values of
+// their identifying properties rather than by their positions, so that inserting, deleting, or reordering elements
+// does not show every element that follows as changed. Reordering the elements of such an array is not a change. An
+// array whose elements are not all objects with distinct string or number values for their keys is diffed by position.
+func (props PropertyMap) DiffWithArrayKeys(other PropertyMap, keys ArrayKeys,
+	ignoreKeys ...IgnoreKeyFunc) *ObjectDiff {
+	return props.diff(other, "", keys, ignoreKeys)
+}
+
+// diff implements Diff for a property map at the given path.
+func (props PropertyMap) diff(other PropertyMap, path string, keys ArrayKeys, ignoreKeys []IgnoreKeyFunc) *ObjectDiff {
 	adds := make(PropertyMap)
 	deletes := make(PropertyMap)
 	sames := make(PropertyMap)
@@ -145,7 +169,7 @@ func (props PropertyMap) Diff(other PropertyMap, ignoreKeys ...IgnoreKeyFunc) *O
 			// If a new exists, use it; for output properties, however, ignore differences.
 			if new.IsOutput() {
 				sames[k] = old
-			} else if diff := old.Diff(new, ignoreKeys...); diff != nil {
+			} else if diff := old.diff(new, joinArrayKeyPath(path, k), keys, ignoreKeys); diff != nil {
 				if !old.HasValue() {
 					adds[k] = new
 				} else if !new.HasValue() {
@@ -187,9 +211,23 @@ func (props PropertyMap) Diff(other PropertyMap, ignoreKeys ...IgnoreKeyFunc) *O
 
 // Diff returns a diff by comparing a single property value to another; it returns nil if there are no diffs.
 func (v PropertyValue) Diff(other PropertyValue, ignoreKeys ...IgnoreKeyFunc) *ValueDiff {
+	return v.diff(other, "", nil, ignoreKeys)
+}
+
+// diff implements Diff for a property value at the given path.
+func (v PropertyValue) diff(other PropertyValue, path string, keys ArrayKeys, ignoreKeys []IgnoreKeyFunc) *ValueDiff {
 	if v.IsArray() && other.IsArray() {
 		old := v.ArrayValue()
 		new := other.ArrayValue()
+		if key, has := keys[path]; has {
+			if diff, ok := diffArrayByKey(old, new, key, path, keys); ok {
+				if diff == nil {
+					return nil
+				}
+				return &ValueDiff{Old: v, New: other, Array: diff}
+			}
+		}
+
 		// If any elements exist in the new array but not the old, track them as adds.
 		adds := make(map[int]PropertyValue)
 		for i := len(old); i < len(new); i++ {
@@ -204,7 +242,7 @@ func (v PropertyValue) Diff(other PropertyValue, ignoreKeys ...IgnoreKeyFunc) *V
 		sames := make(map[int]PropertyValue)
 		updates := make(map[int]ValueDiff)
 		for i := 0; i < len(old) && i < len(new); i++ {
-			if diff := old[i].Diff(new[i]); diff != nil {
+			if diff := old[i].diff(new[i], path, keys, nil); diff != nil {
 				updates[i] = *diff
 			} else {
 				sames[i] = old[i]
@@ -228,7 +266,7 @@ func (v PropertyValue) Diff(other PropertyValue, ignoreKeys ...IgnoreKeyFunc) *V
 	if v.IsObject() && other.IsObject() {
 		old := v.ObjectValue()
 		new := other.ObjectValue()
-		if diff := old.Diff(new, ignoreKeys...); diff != nil {
+		if diff := old.diff(new, path, keys, ignoreKeys); diff != nil {
 			return &ValueDiff{
 				Old:    v,
 				New:    other,
@@ -245,6 +283,96 @@ func (v PropertyValue) Diff(other PropertyValue, ignoreKeys ...IgnoreKeyFunc) *V
 	return &ValueDiff{Old: v, New: other}
 }
 
+// diffArrayByKey diffs two arrays whose elements are identified by the given key, as described by DiffWithArrayKeys.
+// It returns nil if the arrays hold the same elements, and false if their elements cannot be matched by key.
+func diffArrayByKey(old, new []PropertyValue, key PropertyKey, path string, keys ArrayKeys) (*ArrayDiff, bool) {
+	oldKeys, oldIndices, ok := arrayElementKeys(old, key)
+	if !ok {
+		return nil, false
+	}
+	newKeys, newIndices, ok := arrayElementKeys(new, key)
+	if !ok {
+		return nil, false
+	}
+
+	diff := &ArrayDiff{
+		Adds:    make(map[int]PropertyValue),
+		Deletes: make(map[int]PropertyValue),
+		Sames:   make(map[int]PropertyValue),
+		Updates: make(map[int]ValueDiff),
+		Key:     key,
+	}
+	n := 0
+
+	// deleteFrom records the run of deleted old elements that starts at the given index.
+	deleteFrom := func(i int) {
+		for ; i < len(old); i++ {
+			if _, matched := newIndices[oldKeys[i]]; matched {
+				return
+			}
+			diff.Deletes[n] = old[i]
+			n++
+		}
+	}
+
+	deleteFrom(0)
+	for j, elem := range new {
+		i, matched := oldIndices[newKeys[j]]
+		if !matched {
+			diff.Adds[n] = elem
+			n++
+			continue
+		}
+
+		if update := old[i].diff(elem, path, keys, nil); update != nil {
+			diff.Updates[n] = *update
+		} else {
+			diff.Sames[n] = elem
+		}
+		n++
+		deleteFrom(i + 1)
+	}
+
+	if len(diff.Adds) == 0 && len(diff.Deletes) == 0 && len(diff.Updates) == 0 {
+		return nil, true
+	}
+	return diff, true
+}
+
+// arrayElementKeys returns the value of the given key of each of the given elements, along with the index of each
+// value. It returns false if any element is not an object with a string or number value for the key, or if any two
+// elements share a value.
+func arrayElementKeys(elems []PropertyValue, key PropertyKey) ([]interface{}, map[interface{}]int, bool) {
+	values := make([]interface{}, len(elems))
+	indices := make(map[interface{}]int, len(elems))
+	for i, elem := range elems {
+		if !elem.IsObject() {
+			return nil, nil, false
+		}
+		switch k := elem.ObjectValue()[key]; {
+		case k.IsString():
+			values[i] = k.StringValue()
+		case k.IsNumber():
+			values[i] = k.NumberValue()
+		default:
+			return nil, nil, false
+		}
+		if _, has := indices[values[i]]; has {
+			return nil, nil, false
+		}
+		indices[values[i]] = i
+	}
+	return values, indices, true
+}
+
+// joinArrayKeyPath returns the ArrayKeys path of the property with the given key within the object at the given path.
+func joinArrayKeyPath(path string, key PropertyKey) string {
+	if path == "" {
+		return string(key)
+	}
+	return path + "." + string(key)
+}
+
 // DeepEquals returns true if this property map is deeply equal to the other property map; and false otherwise.
 func (props PropertyMap) DeepEquals(other PropertyMap) bool {
 	// If any in props either doesn't exist, or is of a different value, return false.
@@ -186,6 +186,58 @@ func TestArrayPropertyValueDiffs(t *testing.T) {
 	assert.NotNil(t, d6)
 }
 
+func TestArrayPropertyValueDiffsByKey(t *testing.T) {
+	t.Parallel()
+	elem := func(name string, port int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "port": port}
+	}
+	keys := ArrayKeys{"rules": "name"}
+	olds := NewPropertyMapFromMap(map[string]interface{}{
+		"rules": []interface{}{elem("a", 1), elem("b", 2), elem("c", 3)},
+	})
+
+	// reordering is not a change:
+	reordered := NewPropertyMapFromMap(map[string]interface{}{
+		"rules": []interface{}{elem("c", 3), elem("a", 1), elem("b", 2)},
+	})
+	assert.Nil(t, olds.DiffWithArrayKeys(reordered, keys))
+	assert.NotNil(t, olds.Diff(reordered))
+
+	// insert one, delete one, update one:
+	news := NewPropertyMapFromMap(map[string]interface{}{
+		"rules": []interface{}{elem("z", 0), elem("a", 1), elem("c", 4)},
+	})
+	d1 := olds.DiffWithArrayKeys(news, keys)
+	assert.NotNil(t, d1)
+	a1 := d1.Updates["rules"].Array
+	assert.NotNil(t, a1)
+	assert.Equal(t, PropertyKey("name"), a1.Key)
+	assert.Equal(t, 4, a1.Len())
+	assert.Equal(t, map[int]PropertyValue{0: news["rules"].ArrayValue()[0]}, a1.Adds)
+	assert.Equal(t, map[int]PropertyValue{1: olds["rules"].ArrayValue()[0]}, a1.Sames)
+	assert.Equal(t, map[int]PropertyValue{2: olds["rules"].ArrayValue()[1]}, a1.Deletes)
+	assert.Equal(t, 1, len(a1.Updates))
+	assert.Equal(t, NewNumberProperty(4), a1.Updates[3].New.ObjectValue()["port"])
+
+	// arrays whose elements cannot be matched by key are diffed by position:
+	dup := NewPropertyMapFromMap(map[string]interface{}{
+		"rules": []interface{}{elem("c", 3), elem("c", 3), elem("b", 2)},
+	})
+	d2 := olds.DiffWithArrayKeys(dup, keys)
+	assert.NotNil(t, d2)
+	assert.Equal(t, PropertyKey(""), d2.Updates["rules"].Array.Key)
+
+	// keys apply to nested arrays by path:
+	nestedOlds := NewPropertyMapFromMap(map[string]interface{}{
+		"spec": map[string]interface{}{"rules": []interface{}{elem("a", 1), elem("b", 2)}},
+	})
+	nestedNews := NewPropertyMapFromMap(map[string]interface{}{
+		"spec": map[string]interface{}{"rules": []interface{}{elem("b", 2), elem("a", 1)}},
+	})
+	assert.Nil(t, nestedOlds.DiffWithArrayKeys(nestedNews, ArrayKeys{"spec.rules": "name"}))
+	assert.NotNil(t, nestedOlds.DiffWithArrayKeys(nestedNews, keys))
+}
+
 func TestObjectPropertyValueDiffs(t *testing.T) {
 	t.Parallel()
 	// no diffs:
@@ -101,6 +101,57 @@ func ParsePropertyPath(path string) (PropertyPath, error) {
 	return PropertyPath(elements), nil
 }
 
+// String returns the string form of the PropertyPath, which ParsePropertyPath parses back into an equal path. Names
+// that are not valid identifiers are quoted.
+func (p PropertyPath) String() string {
+	var sb strings.Builder
+	for i, element := range p {
+		switch element := element.(type) {
+		case int:
+			sb.WriteString("[" + strconv.Itoa(element) + "]")
+		case string:
+			if isPropertyName(element) {
+				if i > 0 {
+					sb.WriteByte('.')
+				}
+				sb.WriteString(element)
+			} else {
+				sb.WriteString(`["` + strings.Replace(element, `"`, `\"`, -1) + `"]`)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Contains returns true if the given path is equal to this path or is nested inside of the property that it locates.
+func (p PropertyPath) Contains(other PropertyPath) bool {
+	if len(other) < len(p) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isPropertyName returns true if the given string is a propertyName according to the property path grammar.
+func isPropertyName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '$':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // Get attempts to get the value located by the PropertyPath inside the given PropertyValue. If any component of the
 // path does not exist, this function will return (NullPropertyValue, false).
 func (p PropertyPath) Get(v PropertyValue) (PropertyValue, bool) {
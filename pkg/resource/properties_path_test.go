@@ -162,3 +162,33 @@ func TestPropertyPath(t *testing.T) {
 		})
 	}
 }
+
+func TestPropertyPathString(t *testing.T) {
+	cases := map[string]PropertyPath{
+		"root":                        {"root"},
+		"root.nested":                 {"root", "nested"},
+		"root.array[0].nested":        {"root", "array", 0, "nested"},
+		`root["key with a ."]`:        {"root", "key with a ."},
+		`["root key"][100]`:           {"root key", 100},
+		`root["key with \"quotes\""]`: {"root", `key with "quotes"`},
+		`["0"].$ok_1`:                 {"0", "$ok_1"},
+	}
+	for expected, path := range cases {
+		t.Run(expected, func(t *testing.T) {
+			assert.Equal(t, expected, path.String())
+
+			parsed, err := ParsePropertyPath(path.String())
+			assert.NoError(t, err)
+			assert.Equal(t, path, parsed)
+		})
+	}
+}
+
+func TestPropertyPathContains(t *testing.T) {
+	path := PropertyPath{"root", "array", 0}
+	assert.True(t, path.Contains(PropertyPath{"root", "array", 0}))
+	assert.True(t, path.Contains(PropertyPath{"root", "array", 0, "nested"}))
+	assert.False(t, path.Contains(PropertyPath{"root", "array"}))
+	assert.False(t, path.Contains(PropertyPath{"root", "array", 1}))
+	assert.False(t, path.Contains(PropertyPath{"root", "other", 0}))
+}
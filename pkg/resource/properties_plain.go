@@ -0,0 +1,142 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	// PlainUnknown is the value that Plain uses in place of computed and output values, whose values are not known.
+	PlainUnknown = "[unknown]"
+	// PlainSecret is the value that Plain uses in place of secret values that are not shown.
+	PlainSecret = "[secret]"
+	// PlainCycle is the value that Plain uses in place of an object or array that contains itself.
+	PlainCycle = "[cycle]"
+)
+
+// Plain returns a tree of plain Go values that represents the given property value and is suitable for serializing as
+// JSON, e.g. in debugging dumps. Objects become maps from strings to values, arrays become slices, and null, bool,
+// number, and string values become nil, bools, float64s, and strings, respectively. Unlike Mappable, Plain never
+// returns values of this package's types: unknown values are replaced by PlainUnknown, assets and archives by a
+// description of their contents, and secrets by PlainSecret or, if showSecrets is true, by their plaintext values. An
+// object or array that contains itself is replaced by PlainCycle where it recurs.
+func (v PropertyValue) Plain(showSecrets bool) interface{} {
+	return plainValue(v, showSecrets, make(map[uintptr]bool))
+}
+
+// Plain returns a map of plain Go values that represents the given property map. See PropertyValue.Plain for details.
+func (props PropertyMap) Plain(showSecrets bool) map[string]interface{} {
+	return plainValue(NewObjectProperty(props), showSecrets, make(map[uintptr]bool)).(map[string]interface{})
+}
+
+// plainValue implements Plain. The visiting set holds the identities of the objects and arrays that enclose v, which
+// are used to detect cycles.
+func plainValue(v PropertyValue, showSecrets bool, visiting map[uintptr]bool) interface{} {
+	switch {
+	case v.IsNull():
+		return nil
+	case v.IsBool():
+		return v.BoolValue()
+	case v.IsNumber():
+		return v.NumberValue()
+	case v.IsString():
+		return v.StringValue()
+	case v.IsComputed() || v.IsOutput():
+		return PlainUnknown
+	case v.IsSecret():
+		if !showSecrets {
+			return PlainSecret
+		}
+		return plainValue(v.SecretValue().Element, showSecrets, visiting)
+	case v.IsAsset():
+		return plainAsset(v.AssetValue())
+	case v.IsArchive():
+		return plainArchive(v.ArchiveValue())
+	case v.IsArray():
+		arr := v.ArrayValue()
+		if len(arr) == 0 {
+			return []interface{}{}
+		}
+		id := reflect.ValueOf(arr).Pointer()
+		if visiting[id] {
+			return PlainCycle
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		elems := make([]interface{}, len(arr))
+		for i, elem := range arr {
+			elems[i] = plainValue(elem, showSecrets, visiting)
+		}
+		return elems
+	case v.IsObject():
+		obj := v.ObjectValue()
+		id := reflect.ValueOf(obj).Pointer()
+		if obj != nil {
+			if visiting[id] {
+				return PlainCycle
+			}
+			visiting[id] = true
+			defer delete(visiting, id)
+		}
+
+		props := make(map[string]interface{}, len(obj))
+		for k, elem := range obj {
+			props[string(k)] = plainValue(elem, showSecrets, visiting)
+		}
+		return props
+	default:
+		return fmt.Sprintf("%v", v.V)
+	}
+}
+
+// plainAsset describes the given asset by its hash and the source of its contents.
+func plainAsset(a *Asset) map[string]interface{} {
+	desc := map[string]interface{}{"hash": a.Hash}
+	switch {
+	case a.IsText():
+		desc["text"] = a.Text
+	case a.IsPath():
+		desc["path"] = a.Path
+	case a.IsURI():
+		desc["uri"] = a.URI
+	}
+	return map[string]interface{}{"asset": desc}
+}
+
+// plainArchive describes the given archive by its hash and the source of its contents.
+func plainArchive(a *Archive) map[string]interface{} {
+	desc := map[string]interface{}{"hash": a.Hash}
+	switch {
+	case a.IsAssets():
+		assets := make(map[string]interface{}, len(a.Assets))
+		for name, elem := range a.Assets {
+			switch elem := elem.(type) {
+			case *Asset:
+				assets[name] = plainAsset(elem)
+			case *Archive:
+				assets[name] = plainArchive(elem)
+			}
+		}
+		desc["assets"] = assets
+	case a.IsPath():
+		desc["path"] = a.Path
+	case a.IsURI():
+		desc["uri"] = a.URI
+	}
+	return map[string]interface{}{"archive": desc}
+}
@@ -0,0 +1,89 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlain(t *testing.T) {
+	text, err := NewTextAsset("hello")
+	assert.NoError(t, err)
+
+	props := PropertyMap{
+		"null":   NewNullProperty(),
+		"bool":   NewBoolProperty(true),
+		"number": NewNumberProperty(42),
+		"string": NewStringProperty("foo"),
+		"array": NewArrayProperty([]PropertyValue{
+			NewStringProperty("bar"),
+			MakeComputed(NewStringProperty("")),
+		}),
+		"object": NewObjectProperty(PropertyMap{
+			"output": NewOutputProperty(Output{Element: NewNumberProperty(1)}),
+			"secret": MakeSecret(NewStringProperty("hunter2")),
+		}),
+		"asset": NewAssetProperty(text),
+	}
+
+	expected := map[string]interface{}{
+		"null":   nil,
+		"bool":   true,
+		"number": 42.0,
+		"string": "foo",
+		"array":  []interface{}{"bar", PlainUnknown},
+		"object": map[string]interface{}{
+			"output": PlainUnknown,
+			"secret": PlainSecret,
+		},
+		"asset": map[string]interface{}{
+			"asset": map[string]interface{}{"hash": text.Hash, "text": "hello"},
+		},
+	}
+	assert.Equal(t, expected, props.Plain(false))
+
+	expected["object"].(map[string]interface{})["secret"] = "hunter2"
+	assert.Equal(t, expected, props.Plain(true))
+
+	// The result must be serializable as JSON.
+	_, err = json.Marshal(props.Plain(true))
+	assert.NoError(t, err)
+}
+
+func TestPlainCycles(t *testing.T) {
+	shared := PropertyMap{"x": NewNumberProperty(1)}
+	cyclic := PropertyMap{
+		"a": NewObjectProperty(shared),
+		"b": NewObjectProperty(shared),
+	}
+	cyclic["self"] = NewObjectProperty(cyclic)
+
+	arr := []PropertyValue{NewStringProperty("first"), {}}
+	arr[1] = NewArrayProperty(arr)
+	cyclic["array"] = NewArrayProperty(arr)
+
+	plain := cyclic.Plain(false)
+
+	// Values that appear more than once without forming a cycle are repeated.
+	assert.Equal(t, map[string]interface{}{"x": 1.0}, plain["a"])
+	assert.Equal(t, map[string]interface{}{"x": 1.0}, plain["b"])
+
+	// Values that contain themselves are replaced where they recur.
+	assert.Equal(t, PlainCycle, plain["self"])
+	assert.Equal(t, []interface{}{"first", PlainCycle}, plain["array"])
+}
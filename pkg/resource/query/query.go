@@ -0,0 +1,384 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package query implements a small expression language for selecting resources from a snapshot by their type, name,
+// and properties. It is designed to be used by tools that search a stack's state without running its program.
+//
+// An expression is made up of predicates combined with "and" and "or", where "and" binds more tightly than "or" and
+// parentheses may be used for grouping. The supported predicates are
+//
+//	field = value         the field's value is equal to the given value ("==" may also be used)
+//	field ^= value        the field's value starts with the given value
+//	exists(field)         the field has a non-null value
+//
+// A field is one of "urn", "type", "name", "id", "parent", "provider", or "protect", or a property path rooted at
+// "inputs" or "outputs", e.g. "outputs.tags.Name" or "inputs.rules[0].port". A value is either a word that contains
+// no whitespace, parentheses, or quotes, or a double-quoted string literal.
+//
+// Values are compared with the text of strings, the decimal representation of numbers, and "true" or "false" for
+// booleans. Secret values never match an equality or prefix predicate.
+package query
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// Query is a predicate over resources.
+type Query interface {
+	// Matches returns true if the given resource satisfies the query.
+	Matches(res *resource.State) bool
+}
+
+// Field names a value that can be read from a resource: either one of its attributes or one of its properties.
+type Field struct {
+	name string                // the attribute name, or "inputs" or "outputs" for properties.
+	path resource.PropertyPath // the path of the property, if this field names a property.
+}
+
+// ParseField parses the given field name.
+func ParseField(s string) (Field, error) {
+	switch s {
+	case "urn", "type", "name", "id", "parent", "provider", "protect":
+		return Field{name: s}, nil
+	}
+
+	for _, root := range []string{"inputs", "outputs"} {
+		if strings.HasPrefix(s, root) && len(s) > len(root) && (s[len(root)] == '.' || s[len(root)] == '[') {
+			path, err := resource.ParsePropertyPath(s[len(root):])
+			if err != nil {
+				return Field{}, errors.Wrapf(err, "invalid property path '%s'", s)
+			}
+			return Field{name: root, path: path}, nil
+		}
+	}
+
+	return Field{}, errors.Errorf("unknown field '%s': expected urn, type, name, id, parent, provider, protect, "+
+		"or a property path beginning with inputs or outputs", s)
+}
+
+// Get returns the value of the field for the given resource, or false if the resource has no such value.
+func (f Field) Get(res *resource.State) (resource.PropertyValue, bool) {
+	switch f.name {
+	case "urn":
+		return resource.NewStringProperty(string(res.URN)), true
+	case "type":
+		return resource.NewStringProperty(string(res.Type)), true
+	case "name":
+		return resource.NewStringProperty(string(res.URN.Name())), true
+	case "id":
+		return resource.NewStringProperty(string(res.ID)), res.ID != ""
+	case "parent":
+		return resource.NewStringProperty(string(res.Parent)), res.Parent != ""
+	case "provider":
+		return resource.NewStringProperty(res.Provider), res.Provider != ""
+	case "protect":
+		return resource.NewBoolProperty(res.Protect), true
+	case "inputs":
+		return f.path.Get(resource.NewObjectProperty(res.Inputs))
+	case "outputs":
+		return f.path.Get(resource.NewObjectProperty(res.Outputs))
+	default:
+		return resource.PropertyValue{}, false
+	}
+}
+
+// Parse parses the given query expression.
+func Parse(expr string) (Query, error) {
+	p := &parser{tokens: lex(expr)}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		if tok.kind == errorToken {
+			return nil, errors.New(tok.text)
+		}
+		return nil, errors.Errorf("unexpected '%s' in query", tok.text)
+	}
+	return q, nil
+}
+
+// Filter returns the resources that satisfy the given query, in their original order.
+func Filter(q Query, resources []*resource.State) []*resource.State {
+	var matches []*resource.State
+	for _, res := range resources {
+		if q.Matches(res) {
+			matches = append(matches, res)
+		}
+	}
+	return matches
+}
+
+type andQuery struct{ left, right Query }
+
+func (q andQuery) Matches(res *resource.State) bool {
+	return q.left.Matches(res) && q.right.Matches(res)
+}
+
+type orQuery struct{ left, right Query }
+
+func (q orQuery) Matches(res *resource.State) bool {
+	return q.left.Matches(res) || q.right.Matches(res)
+}
+
+type existsQuery struct{ field Field }
+
+func (q existsQuery) Matches(res *resource.State) bool {
+	v, ok := q.field.Get(res)
+	return ok && !v.IsNull()
+}
+
+type compareQuery struct {
+	field  Field
+	value  string
+	prefix bool
+}
+
+func (q compareQuery) Matches(res *resource.State) bool {
+	v, ok := q.field.Get(res)
+	if !ok {
+		return false
+	}
+	s, ok := comparableString(v)
+	if !ok {
+		return false
+	}
+	if q.prefix {
+		return strings.HasPrefix(s, q.value)
+	}
+	return s == q.value
+}
+
+// comparableString returns the text that a query value is compared against for the given property value, or false if
+// the value cannot be compared.
+func comparableString(v resource.PropertyValue) (string, bool) {
+	switch {
+	case v.IsString():
+		return v.StringValue(), true
+	case v.IsNumber():
+		return strconv.FormatFloat(v.NumberValue(), 'f', -1, 64), true
+	case v.IsBool():
+		return strconv.FormatBool(v.BoolValue()), true
+	default:
+		return "", false
+	}
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	stringToken
+	punctToken
+	errorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex splits a query expression into tokens. Lexical errors are returned as error tokens so that the parser can
+// report them where they occur.
+func lex(expr string) []token {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(' || c == ')':
+			tokens, i = append(tokens, token{kind: punctToken, text: string(c)}), i+1
+		case c == '=':
+			// Both "=" and "==" test for equality.
+			tokens, i = append(tokens, token{kind: punctToken, text: "="}), i+1
+			if i < len(expr) && expr[i] == '=' {
+				i++
+			}
+		case c == '^' && i+1 < len(expr) && expr[i+1] == '=':
+			tokens, i = append(tokens, token{kind: punctToken, text: "^="}), i+2
+		case c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return append(tokens, token{kind: errorToken, text: "missing closing quote in query"})
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return append(tokens, token{kind: errorToken, text: "invalid string " + expr[i:j+1] + " in query"})
+			}
+			tokens, i = append(tokens, token{kind: stringToken, text: s}), j+1
+		default:
+			// Words end at whitespace, parentheses, or operators. Quotes are allowed within brackets so that property
+			// paths such as outputs.tags["Name"] are a single word.
+			j, depth := i, 0
+			for ; j < len(expr); j++ {
+				d := expr[j]
+				if depth == 0 && (unicode.IsSpace(rune(d)) || d == '(' || d == ')' || d == '=' || d == '"' ||
+					(d == '^' && j+1 < len(expr) && expr[j+1] == '=')) {
+					break
+				}
+				switch d {
+				case '[':
+					depth++
+				case ']':
+					depth--
+				}
+			}
+			tokens, i = append(tokens, token{kind: wordToken, text: expr[i:j]}), j
+		}
+	}
+	return tokens
+}
+
+type parser struct {
+	tokens []token
+}
+
+func (p *parser) peek() (token, bool) {
+	if len(p.tokens) == 0 {
+		return token{}, false
+	}
+	return p.tokens[0], true
+}
+
+func (p *parser) next() (token, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return token{}, errors.New("unexpected end of query")
+	}
+	if tok.kind == errorToken {
+		return token{}, errors.New(tok.text)
+	}
+	p.tokens = p.tokens[1:]
+	return tok, nil
+}
+
+// isKeyword returns true if the next token is the given keyword.
+func (p *parser) isKeyword(keyword string) bool {
+	tok, ok := p.peek()
+	return ok && tok.kind == wordToken && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) expect(punct string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != punctToken || tok.text != punct {
+		return errors.Errorf("expected '%s' but found '%s' in query", punct, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Query, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.tokens = p.tokens[1:]
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orQuery{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Query, error) {
+	left, err := p.parsePredicate()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.tokens = p.tokens[1:]
+		right, err := p.parsePredicate()
+		if err != nil {
+			return nil, err
+		}
+		left = andQuery{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePredicate() (Query, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tok.kind == punctToken && tok.text == "(":
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return q, nil
+	case tok.kind == wordToken && strings.EqualFold(tok.text, "exists"):
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		name, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		field, err := ParseField(name.text)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return existsQuery{field: field}, nil
+	case tok.kind == wordToken:
+		field, err := ParseField(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		op, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if op.kind != punctToken || (op.text != "=" && op.text != "^=") {
+			return nil, errors.Errorf("expected '=' or '^=' after '%s' but found '%s' in query", tok.text, op.text)
+		}
+		value, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if value.kind == punctToken {
+			return nil, errors.Errorf("expected a value after '%s %s' but found '%s' in query",
+				tok.text, op.text, value.text)
+		}
+		return compareQuery{field: field, value: value.text, prefix: op.text == "^="}, nil
+	default:
+		return nil, errors.Errorf("unexpected '%s' in query", tok.text)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func newResource(typ tokens.Type, name string, outputs resource.PropertyMap) *resource.State {
+	urn := resource.NewURN("stack", "project", "", typ, tokens.QName(name))
+	return &resource.State{Type: typ, URN: urn, Custom: true, ID: resource.ID(name + "-id"), Outputs: outputs}
+}
+
+func TestQuery(t *testing.T) {
+	bucketA := newResource("aws:s3/bucket:Bucket", "bucketA", resource.PropertyMap{
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"Name": resource.NewStringProperty("logs"),
+		}),
+		"versioning": resource.NewBoolProperty(true),
+	})
+	bucketB := newResource("aws:s3/bucket:Bucket", "bucketB", resource.PropertyMap{
+		"versioning": resource.NewBoolProperty(false),
+	})
+	instance := newResource("aws:ec2/instance:Instance", "web", resource.PropertyMap{
+		"port":     resource.NewNumberProperty(8080),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+	})
+	resources := []*resource.State{bucketA, bucketB, instance}
+
+	cases := []struct {
+		expr     string
+		expected []*resource.State
+	}{
+		{`type = aws:s3/bucket:Bucket`, []*resource.State{bucketA, bucketB}},
+		{`type == "aws:s3/bucket:Bucket"`, []*resource.State{bucketA, bucketB}},
+		{`type ^= aws:ec2`, []*resource.State{instance}},
+		{`name=web`, []*resource.State{instance}},
+		{`exists(outputs.tags)`, []*resource.State{bucketA}},
+		{`outputs.tags["Name"] = logs`, []*resource.State{bucketA}},
+		{`outputs.versioning = false`, []*resource.State{bucketB}},
+		{`outputs.port = 8080`, []*resource.State{instance}},
+		{`type ^= aws:s3 and outputs.versioning = true or name = web`, []*resource.State{bucketA, instance}},
+		{`type ^= aws:s3 AND (outputs.versioning = false OR name = web)`, []*resource.State{bucketB}},
+		{`exists(outputs.missing) or id = bucket`, nil},
+
+		// Secrets exist, but never match.
+		{`exists(outputs.password)`, []*resource.State{instance}},
+		{`outputs.password = hunter2`, nil},
+		{`outputs.password ^= h`, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			q, err := Parse(c.expr)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, Filter(q, resources))
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`type`,
+		`type =`,
+		`type = )`,
+		`type < a`,
+		`(type = a`,
+		`type = a)`,
+		`type = a and`,
+		`exists(type`,
+		`exists type`,
+		`color = red`,
+		`type = "unterminated`,
+		`inputs.a[b] = c`,
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -37,9 +37,13 @@ type Goal struct {
 	Aliases                 []URN                 // additional URNs that should be aliased to this resource.
 	ID                      ID                    // the expected ID of the resource, if any.
 	CustomTimeouts          CustomTimeouts        // an optional config object for resource options
+	Priority                int                   // the priority of this resource's operations among unordered ones.
+	RetryPolicy             RetryPolicy           // how operations on this resource that fail transiently are retried.
+	AutoName                PropertyKey           // an optional input property to fill with a generated unique name.
+	ReplaceOnChanges        []string              // a list of property paths whose changes require a replacement.
 }
 
-// NewGoal allocates a new resource goal state.
+// NewGoal allocates a new resource goal state. The goal's other settings, such as its priority, are set on the result.
 func NewGoal(t tokens.Type, name tokens.QName, custom bool, props PropertyMap,
 	parent URN, protect bool, dependencies []URN, provider string, initErrors []string,
 	propertyDependencies map[PropertyKey][]URN, deleteBeforeReplace bool, ignoreChanges []string,
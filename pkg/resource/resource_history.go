@@ -0,0 +1,38 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"time"
+)
+
+// LifecycleEvent records an operation that changed a resource, such as its creation, update, or replacement.
+type LifecycleEvent struct {
+	Op        string    `json:"op" yaml:"op"`                                   // the operation, e.g. "create".
+	Time      time.Time `json:"time" yaml:"time"`                               // the time at which it completed.
+	Operation string    `json:"operation,omitempty" yaml:"operation,omitempty"` // the ID of the update that did it.
+}
+
+// AppendLifecycleEvent returns a copy of the given history with the given event appended to it, keeping only the
+// latest max events.
+func AppendLifecycleEvent(history []LifecycleEvent, event LifecycleEvent, max int) []LifecycleEvent {
+	if max <= 0 {
+		return nil
+	}
+	if len(history) >= max {
+		history = history[len(history)-max+1:]
+	}
+	return append(append(make([]LifecycleEvent, 0, len(history)+1), history...), event)
+}
@@ -0,0 +1,38 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendLifecycleEvent(t *testing.T) {
+	create, update, replace := LifecycleEvent{Op: "create"}, LifecycleEvent{Op: "update"}, LifecycleEvent{Op: "replace"}
+
+	history := AppendLifecycleEvent(nil, create, 2)
+	assert.Equal(t, []LifecycleEvent{create}, history)
+	history = AppendLifecycleEvent(history, update, 2)
+	assert.Equal(t, []LifecycleEvent{create, update}, history)
+
+	// The oldest events are dropped to keep the history to its maximum length, and the given history is not changed.
+	trimmed := AppendLifecycleEvent(history, replace, 2)
+	assert.Equal(t, []LifecycleEvent{update, replace}, trimmed)
+	assert.Equal(t, []LifecycleEvent{create, update}, history)
+
+	assert.Equal(t, []LifecycleEvent{replace}, AppendLifecycleEvent(history, replace, 1))
+	assert.Nil(t, AppendLifecycleEvent(history, replace, 0))
+}
@@ -42,9 +42,15 @@ type State struct {
 	AdditionalSecretOutputs []PropertyKey         // an additional set of outputs that should be treated as secrets.
 	Aliases                 []URN                 // TODO
 	CustomTimeouts          CustomTimeouts        // A config block that will be used to configure timeouts for CRUD operations
+	DeleteBeforeReplace     bool                  // true if this resource must be deleted before it is replaced.
+	Priority                int                   // the priority of this resource's operations among unordered ones.
+	RetryPolicy             RetryPolicy           // how operations on this resource that fail transiently are retried.
+	History                 []LifecycleEvent      // the latest operations that changed this resource, oldest first.
+	ProviderDependencies    []URN                 // the dependencies that its provider reported, also in Dependencies.
 }
 
-// NewState creates a new resource value from existing resource state information.
+// NewState creates a new resource value from existing resource state information. The resource's other settings, such
+// as its retry policy, are set on the result.
 func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 	inputs PropertyMap, outputs PropertyMap, parent URN, protect bool,
 	external bool, dependencies []URN, initErrors []string, provider string,
@@ -81,3 +87,10 @@ func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 
 	return s
 }
+
+// Copy returns a shallow copy of the resource state, including the settings that NewState does not take. Callers that
+// rebuild a state from an existing one should copy it and replace the fields that change, so that no setting is lost.
+func (s *State) Copy() *State {
+	copy := *s
+	return &copy
+}
@@ -0,0 +1,42 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+// RetryPolicy controls how an operation on a resource that fails transiently is retried. A zero field is not set by
+// the policy, and takes its value from the defaults with which the policy is merged.
+type RetryPolicy struct {
+	Attempts int     `json:"attempts,omitempty" yaml:"attempts,omitempty"` // the maximum number of attempts.
+	Delay    float64 `json:"delay,omitempty" yaml:"delay,omitempty"`       // the seconds to wait before the first retry.
+	Backoff  float64 `json:"backoff,omitempty" yaml:"backoff,omitempty"`   // the factor by which each delay grows.
+}
+
+// IsZero returns true if the policy sets none of its fields.
+func (p RetryPolicy) IsZero() bool {
+	return p == RetryPolicy{}
+}
+
+// Merge returns the policy with each of its unset fields taken from the given defaults.
+func (p RetryPolicy) Merge(defaults RetryPolicy) RetryPolicy {
+	if p.Attempts == 0 {
+		p.Attempts = defaults.Attempts
+	}
+	if p.Delay == 0 {
+		p.Delay = defaults.Delay
+	}
+	if p.Backoff == 0 {
+		p.Backoff = defaults.Backoff
+	}
+	return p
+}
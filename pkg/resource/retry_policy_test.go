@@ -0,0 +1,31 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyMerge(t *testing.T) {
+	defaults := RetryPolicy{Attempts: 1, Delay: 1, Backoff: 2}
+
+	assert.True(t, RetryPolicy{}.IsZero())
+	assert.Equal(t, defaults, RetryPolicy{}.Merge(defaults))
+	assert.Equal(t, RetryPolicy{Attempts: 5, Delay: 1, Backoff: 2}, RetryPolicy{Attempts: 5}.Merge(defaults))
+	assert.Equal(t, RetryPolicy{Attempts: 1, Delay: 0.5, Backoff: 3},
+		RetryPolicy{Delay: 0.5, Backoff: 3}.Merge(defaults))
+}
@@ -261,6 +261,10 @@ func SerializeResource(res *resource.State, enc config.Encrypter) (apitype.Resou
 		}
 		outputs = soutp
 	}
+	var retryPolicy *resource.RetryPolicy
+	if !res.RetryPolicy.IsZero() {
+		retryPolicy = &res.RetryPolicy
+	}
 
 	return apitype.ResourceV3{
 		URN:                     res.URN,
@@ -281,6 +285,11 @@ func SerializeResource(res *resource.State, enc config.Encrypter) (apitype.Resou
 		AdditionalSecretOutputs: res.AdditionalSecretOutputs,
 		Aliases:                 res.Aliases,
 		CustomTimeouts:          &res.CustomTimeouts,
+		DeleteBeforeReplace:     res.DeleteBeforeReplace,
+		Priority:                res.Priority,
+		RetryPolicy:             retryPolicy,
+		History:                 res.History,
+		ProviderDependencies:    res.ProviderDependencies,
 	}, nil
 }
 
@@ -383,10 +392,18 @@ func DeserializeResource(res apitype.ResourceV3, dec config.Decrypter) (*resourc
 		return nil, err
 	}
 
-	return resource.NewState(
+	state := resource.NewState(
 		res.Type, res.URN, res.Custom, res.Delete, res.ID,
 		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider,
-		res.PropertyDependencies, res.PendingReplacement, res.AdditionalSecretOutputs, res.Aliases, res.CustomTimeouts), nil
+		res.PropertyDependencies, res.PendingReplacement, res.AdditionalSecretOutputs, res.Aliases, res.CustomTimeouts)
+	state.DeleteBeforeReplace = res.DeleteBeforeReplace
+	state.Priority = res.Priority
+	if res.RetryPolicy != nil {
+		state.RetryPolicy = *res.RetryPolicy
+	}
+	state.History = res.History
+	state.ProviderDependencies = res.ProviderDependencies
+	return state, nil
 }
 
 func DeserializeOperation(op apitype.OperationV2, dec config.Decrypter) (resource.Operation, error) {
@@ -0,0 +1,73 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// URNPattern is a pattern that matches URNs. In a pattern, '*' matches any number of characters and '?' matches a
+// single character; every other character matches itself.
+type URNPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// ParseURNPattern parses the given URN pattern.
+func ParseURNPattern(pattern string) (URNPattern, error) {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return URNPattern{}, errors.Wrapf(err, "invalid URN pattern %q", pattern)
+	}
+	return URNPattern{pattern: pattern, re: re}, nil
+}
+
+// ParseURNPatterns parses each of the given URN patterns.
+func ParseURNPatterns(patterns []string) ([]URNPattern, error) {
+	var result []URNPattern
+	for _, p := range patterns {
+		pattern, err := ParseURNPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pattern)
+	}
+	return result, nil
+}
+
+// Matches returns true if the given URN matches the pattern.
+func (p URNPattern) Matches(urn URN) bool {
+	return p.re != nil && p.re.MatchString(string(urn))
+}
+
+func (p URNPattern) String() string {
+	return p.pattern
+}
+
+// MatchesAnyURNPattern returns true if the given URN matches any of the given patterns.
+func MatchesAnyURNPattern(patterns []URNPattern, urn URN) bool {
+	for _, p := range patterns {
+		if p.Matches(urn) {
+			return true
+		}
+	}
+	return false
+}
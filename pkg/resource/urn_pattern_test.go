@@ -0,0 +1,48 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURNPattern(t *testing.T) {
+	urn := URN("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs")
+
+	for pattern, expected := range map[string]bool{
+		string(urn):                             true,
+		"urn:pulumi:dev::proj::*::logs":         true,
+		"*::logs":                               true,
+		"*::log?":                               true,
+		"*::lo?":                                false,
+		"urn:pulumi:dev::proj::aws:s3/bucket:*": true,
+		"urn:pulumi:prod::*":                    false,
+		"*:Bucket::logs.old":                    false,
+		"*(logs)":                               false,
+	} {
+		p, err := ParseURNPattern(pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, p.Matches(urn), pattern)
+		assert.Equal(t, pattern, p.String())
+	}
+
+	patterns, err := ParseURNPatterns([]string{"*::web", "*::logs"})
+	assert.NoError(t, err)
+	assert.True(t, MatchesAnyURNPattern(patterns, urn))
+	assert.False(t, MatchesAnyURNPattern(patterns[:1], urn))
+	assert.False(t, MatchesAnyURNPattern(nil, urn))
+}
@@ -67,7 +67,7 @@ func Untgz(tarball []byte, dir string) error {
 			}
 
 			// Expand files into the target directory.
-			dst, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			dst, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return errors.Wrapf(err, "opening file %s for untar", path)
 			}
@@ -82,3 +82,63 @@ func Untgz(tarball []byte, dir string) error {
 
 	return nil
 }
+
+// Tgz creates a .tar.gz file that contains the files in the given directory, which may be expanded by Untgz. The
+// paths of the files in the archive are relative to the directory, and their permissions are preserved.
+func Tgz(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gzw)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return errors.Errorf("unexpected file type %s (%v)", rel, info.Mode())
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return errors.Wrapf(err, "tarring %s", rel)
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err = w.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "tarring %s", rel)
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrapf(err, "opening file %s for tar", path)
+		}
+		defer contract.IgnoreClose(f)
+		if _, err = io.Copy(w, f); err != nil {
+			return errors.Wrapf(err, "tarring file %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, errors.Wrap(err, "tarring")
+	}
+	if err = gzw.Close(); err != nil {
+		return nil, errors.Wrap(err, "zipping")
+	}
+	return buf.Bytes(), nil
+}
@@ -90,3 +90,10 @@ func InitDiag(opts diag.FormatOptions) {
 	contract.Assertf(snk == nil, "Cannot initialize diagnostics sink more than once")
 	snk = diag.DefaultSink(os.Stdout, os.Stderr, opts)
 }
+
+// SetDiag replaces the sink that Diag returns, e.g. so that a program that embeds Pulumi's commands can deliver their
+// diagnostics to its own logging system using diag.CallbackSink.
+func SetDiag(sink diag.Sink) {
+	contract.Require(sink != nil, "sink")
+	snk = sink
+}
@@ -28,6 +28,17 @@ import (
 	"github.com/pulumi/pulumi/pkg/util/result"
 )
 
+// ExitCodeError is an error that causes the command that returns it to exit with the given code, rather than the
+// standard error exit code, so that scripts can tell it apart from other failures.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (err ExitCodeError) Error() string {
+	return err.Err.Error()
+}
+
 // DetailedError extracts a detailed error message, including stack trace, if there is one.
 func DetailedError(err error) string {
 	msg := errorMessage(err)
@@ -135,6 +146,9 @@ func RunResultFunc(run func(cmd *cobra.Command, args []string) result.Result) fu
 				logging.V(3).Infof(DetailedError(err))
 			}
 
+			if codeErr, ok := err.(ExitCodeError); ok {
+				exitErrorCodef(codeErr.Code, strings.Replace(msg, "%", "%%", -1))
+			}
 			ExitError(msg)
 		}
 	}
@@ -26,6 +26,10 @@ import (
 
 // TracingEndpoint is the Zipkin-compatible tracing endpoint where tracing data will be sent.
 var TracingEndpoint string
+
+// TracingToFile is true if tracing data is being written to a local file rather than sent to an endpoint.
+var TracingToFile bool
+
 var TracingRootSpan opentracing.Span
 
 var traceCloser io.Closer
@@ -44,22 +48,30 @@ func InitTracing(name, rootSpanName, tracingEndpoint string) {
 	// Store the tracing endpoint
 	TracingEndpoint = tracingEndpoint
 
-	// Jaeger tracer can be initialized with a transport that will
-	// report tracing Spans to a Zipkin backend
-	transport, err := zipkin.NewHTTPTransport(
-		tracingEndpoint,
-		zipkin.HTTPBatchSize(1),
-		zipkin.HTTPLogger(jaeger.StdLogger),
-	)
-	if err != nil {
-		log.Fatalf("Cannot initialize HTTP transport: %v", err)
+	// If the endpoint names a file, collect spans in memory and write them to that file when tracing is closed.
+	// Otherwise, the Jaeger tracer can be initialized with a transport that will report tracing Spans to a Zipkin
+	// backend.
+	var reporter jaeger.Reporter
+	if path, ok := tracingFilePath(tracingEndpoint); ok {
+		TracingToFile = true
+		reporter = newFileReporter(path, name)
+	} else {
+		transport, err := zipkin.NewHTTPTransport(
+			tracingEndpoint,
+			zipkin.HTTPBatchSize(1),
+			zipkin.HTTPLogger(jaeger.StdLogger),
+		)
+		if err != nil {
+			log.Fatalf("Cannot initialize HTTP transport: %v", err)
+		}
+		reporter = jaeger.NewRemoteReporter(transport)
 	}
 
 	// create Jaeger tracer
 	tracer, closer := jaeger.NewTracer(
 		name,
 		jaeger.NewConstSampler(true), // sample all traces
-		jaeger.NewRemoteReporter(transport))
+		reporter)
 
 	// Store the closer so that we can flush the Jaeger span cache on process exit
 	traceCloser = closer
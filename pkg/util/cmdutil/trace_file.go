@@ -0,0 +1,136 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdutil
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"sync"
+
+	jaeger "github.com/uber/jaeger-client-go"
+	z "github.com/uber/jaeger-client-go/thrift-gen/zipkincore"
+
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// zipkinSpan is a span in the Zipkin v2 JSON format, which can be loaded by Zipkin, Jaeger, and other trace viewers.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// fileReporter is a jaeger.Reporter that collects finished spans in memory and writes them to a file as a Zipkin v2
+// JSON array when it is closed.
+type fileReporter struct {
+	path        string
+	serviceName string
+
+	m     sync.Mutex
+	spans []zipkinSpan
+}
+
+// tracingFilePath returns the path named by the given tracing endpoint if it is of the form "file:<path>".
+func tracingFilePath(endpoint string) (string, bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	if u.Opaque != "" {
+		return u.Opaque, true
+	}
+	return u.Path, true
+}
+
+func newFileReporter(path, serviceName string) *fileReporter {
+	return &fileReporter{path: path, serviceName: serviceName}
+}
+
+func (r *fileReporter) Report(span *jaeger.Span) {
+	zs := jaeger.BuildZipkinThrift(span)
+
+	s := zipkinSpan{
+		TraceID:       fmt.Sprintf("%016x", uint64(zs.TraceID)),
+		ID:            fmt.Sprintf("%016x", uint64(zs.ID)),
+		Name:          zs.Name,
+		LocalEndpoint: zipkinEndpoint{ServiceName: r.serviceName},
+	}
+	if zs.ParentID != nil {
+		s.ParentID = fmt.Sprintf("%016x", uint64(*zs.ParentID))
+	}
+	if zs.Timestamp != nil {
+		s.Timestamp = *zs.Timestamp
+	}
+	if zs.Duration != nil {
+		s.Duration = *zs.Duration
+	}
+	for _, a := range zs.BinaryAnnotations {
+		if v, ok := binaryAnnotationValue(a); ok {
+			if s.Tags == nil {
+				s.Tags = make(map[string]string)
+			}
+			s.Tags[a.Key] = v
+		}
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+func (r *fileReporter) Close() {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	spans := r.spans
+	if spans == nil {
+		spans = []zipkinSpan{}
+	}
+	b, err := json.MarshalIndent(spans, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(r.path, b, 0600)
+	}
+	if err != nil {
+		logging.Warningf("could not write trace to %v: %v", r.path, err)
+	}
+}
+
+// binaryAnnotationValue returns the string form of the given annotation's value, if it has a type that tags use.
+func binaryAnnotationValue(a *z.BinaryAnnotation) (string, bool) {
+	switch a.AnnotationType {
+	case z.AnnotationType_STRING:
+		return string(a.Value), true
+	case z.AnnotationType_BOOL:
+		return strconv.FormatBool(len(a.Value) == 1 && a.Value[0] == 1), true
+	case z.AnnotationType_I64:
+		if len(a.Value) == 8 {
+			return strconv.FormatInt(int64(binary.BigEndian.Uint64(a.Value)), 10), true
+		}
+	}
+	return "", false
+}
@@ -16,7 +16,9 @@ package gitutil
 
 import (
 	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -24,10 +26,13 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/fsutil"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
 
@@ -231,6 +236,61 @@ func getMatchedGroupsFromRegex(regex *regexp.Regexp, remoteURL string) map[strin
 	return groups
 }
 
+// GitExtractRevision writes the files of the given revision of a repository, which may be any revision that git
+// understands, such as a branch, tag, or commit hash, to the directory at path. The repository's working tree is not
+// changed. It returns the hash of the commit that the revision names.
+func GitExtractRevision(repo *git.Repository, revision string, path string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrapf(err, "resolving revision '%s'", revision)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrapf(err, "reading commit %s", hash)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrapf(err, "reading the tree of commit %s", hash)
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		dest := filepath.Join(path, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return err
+		}
+		if f.Mode == filemode.Symlink {
+			target, err := f.Contents()
+			if err != nil {
+				return errors.Wrapf(err, "reading %s", f.Name)
+			}
+			return os.Symlink(target, dest)
+		}
+
+		perm := os.FileMode(0644)
+		if f.Mode == filemode.Executable {
+			perm = 0755
+		}
+		r, err := f.Reader()
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", f.Name)
+		}
+		defer contract.IgnoreClose(r)
+		w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(w, r); err != nil {
+			contract.IgnoreClose(w)
+			return errors.Wrapf(err, "writing %s", dest)
+		}
+		return w.Close()
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return *hash, nil
+}
+
 // GitCloneAndCheckoutCommit clones the Git repository and checkouts the specified commit.
 func GitCloneAndCheckoutCommit(url string, commit plumbing.Hash, path string) error {
 	repo, err := git.PlainClone(path, false, &git.CloneOptions{
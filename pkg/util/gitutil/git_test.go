@@ -15,6 +15,7 @@
 package gitutil
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -264,3 +265,36 @@ func TestTryGetVCSInfoFromSSHRemote(t *testing.T) {
 		assert.Equal(t, test.WantVCSInfo, got)
 	}
 }
+
+func TestGitExtractRevision(t *testing.T) {
+	e := ptesting.NewEnvironment(t)
+	defer e.DeleteIfNotFailed()
+
+	// Create local test repository.
+	repoPath := filepath.Join(e.RootPath, "repo")
+	err := os.MkdirAll(repoPath, os.ModePerm)
+	assert.NoError(e, err, "making repo dir %s", repoPath)
+	e.CWD = repoPath
+	createTestRepo(e)
+
+	repo, err := GetGitRepository(repoPath)
+	assert.NoError(t, err)
+
+	// The revision before the last has the first two commits' files, but not the last's.
+	dest := filepath.Join(e.RootPath, "extracted")
+	hash, err := GitExtractRevision(repo, "HEAD~1", dest)
+	assert.NoError(t, err)
+	assert.False(t, hash.IsZero())
+	b, err := ioutil.ReadFile(filepath.Join(dest, "foo", "bar.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo-bar.md", string(b))
+	_, err = os.Stat(filepath.Join(dest, "content"))
+	assert.True(t, os.IsNotExist(err))
+
+	// The working tree is unchanged.
+	_, err = os.Stat(filepath.Join(repoPath, "content", "foo", "bar.md"))
+	assert.NoError(t, err)
+
+	_, err = GitExtractRevision(repo, "no-such-branch", filepath.Join(e.RootPath, "missing"))
+	assert.Error(t, err)
+}
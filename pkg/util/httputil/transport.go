@@ -0,0 +1,128 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TransportOptions configures the transport that is shared by the clients that Client returns.
+type TransportOptions struct {
+	CACertFile     string // a PEM file of CA certificates to trust in addition to the system's.
+	ClientCertFile string // a PEM file holding a certificate to present to servers that request one.
+	ClientKeyFile  string // a PEM file holding the private key of the client certificate.
+}
+
+var (
+	transport     http.RoundTripper = &tlsErrorTransport{base: newTransport(nil)}
+	transportLock sync.RWMutex
+)
+
+// ConfigureTransport replaces the transport that is shared by the clients that Client returns with one that uses the
+// given options. The transport always honors the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+func ConfigureTransport(opts TransportOptions) error {
+	tlsConfig := &tls.Config{}
+
+	if opts.CACertFile != "" {
+		pem, err := ioutil.ReadFile(opts.CACertFile)
+		if err != nil {
+			return errors.Wrap(err, "reading CA certificates")
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.Errorf("no PEM-encoded CA certificates were found in %s", opts.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case opts.ClientCertFile != "" && opts.ClientKeyFile != "":
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return errors.Wrap(err, "loading client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case opts.ClientCertFile != "" || opts.ClientKeyFile != "":
+		return errors.New("a client certificate and its key must be specified together")
+	}
+
+	transportLock.Lock()
+	defer transportLock.Unlock()
+	transport = &tlsErrorTransport{base: newTransport(tlsConfig)}
+	return nil
+}
+
+// Transport returns the shared transport.
+func Transport() http.RoundTripper {
+	transportLock.RLock()
+	defer transportLock.RUnlock()
+	return transport
+}
+
+// Client returns a client that uses the shared transport and the given timeout, if any, for its requests.
+func Client(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Transport(), Timeout: timeout}
+}
+
+// newTransport returns a transport with the same settings as http.DefaultTransport, using the given TLS configuration.
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// tlsErrorTransport is a transport that explains the TLS errors of the requests that it makes.
+type tlsErrorTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tlsErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && isTLSError(err) {
+		return nil, errors.Errorf("could not establish a secure connection to %s: %v; if this endpoint, or a proxy "+
+			"in front of it, uses a certificate issued by a private certificate authority, pass that authority's "+
+			"certificate using --ca-cert", req.URL.Host, err)
+	}
+	return resp, err
+}
+
+// isTLSError returns true if the given error is the result of a failure to verify a server's certificate or to
+// complete a TLS handshake. The crypto packages' errors are matched by their prefixes, since the transport may wrap
+// them in other errors.
+func isTLSError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "x509: ") || strings.Contains(msg, "tls: ")
+}
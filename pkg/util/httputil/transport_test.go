@@ -0,0 +1,67 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTransportCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	defer func() { assert.NoError(t, ConfigureTransport(TransportOptions{})) }()
+
+	// The test server's certificate is self-signed, so requests fail until it is trusted, with an error that names
+	// the endpoint and explains how to trust it.
+	_, err := Client(0).Get(server.URL)
+	if assert.Error(t, err) {
+		u, uerr := url.Parse(server.URL)
+		assert.NoError(t, uerr)
+		assert.Contains(t, err.Error(), "could not establish a secure connection to "+u.Host)
+		assert.Contains(t, err.Error(), "--ca-cert")
+	}
+
+	dir, err := ioutil.TempDir("", "ca-cert")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caCert := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(caCert, certPEM, 0600))
+	assert.NoError(t, ConfigureTransport(TransportOptions{CACertFile: caCert}))
+
+	resp, err := Client(0).Get(server.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	// Files without certificates, and client certificates without keys, are rejected.
+	notPEM := filepath.Join(dir, "not.pem")
+	assert.NoError(t, ioutil.WriteFile(notPEM, []byte("not a certificate"), 0600))
+	assert.Error(t, ConfigureTransport(TransportOptions{CACertFile: notPEM}))
+	assert.Error(t, ConfigureTransport(TransportOptions{ClientCertFile: caCert}))
+}
@@ -40,12 +40,38 @@ type Filter interface {
 var LogToStderr = false // true if logging is being redirected to stderr.
 var Verbose = 0         // >0 if verbose logging is enabled at a particular level.
 var LogFlow = false     // true to flow logging settings to child processes.
+var LogDir = ""         // the directory that log files are written to, if not the default.
 
 var rwLock sync.RWMutex
 var filters []Filter
 
-func V(level glog.Level) glog.Verbose {
-	return glog.V(level)
+// VerboseLogger logs messages only if the requested verbosity level is enabled. Like the other logging functions in
+// this package, it applies the global filters to each message before logging it.
+type VerboseLogger glog.Verbose
+
+// Info is equivalent to the global Info function, guarded by the value of v.
+func (v VerboseLogger) Info(args ...interface{}) {
+	if v {
+		glog.InfoDepth(1, FilterString(fmt.Sprint(args...)))
+	}
+}
+
+// Infoln is equivalent to the global Infoln function, guarded by the value of v.
+func (v VerboseLogger) Infoln(args ...interface{}) {
+	if v {
+		glog.InfoDepth(1, FilterString(fmt.Sprintln(args...)))
+	}
+}
+
+// Infof is equivalent to the global Infof function, guarded by the value of v.
+func (v VerboseLogger) Infof(format string, args ...interface{}) {
+	if v {
+		glog.InfoDepth(1, FilterString(fmt.Sprintf(format, args...)))
+	}
+}
+
+func V(level glog.Level) VerboseLogger {
+	return VerboseLogger(glog.V(level))
 }
 
 func Errorf(format string, args ...interface{}) {
@@ -66,10 +92,17 @@ func Flush() {
 
 // InitLogging ensures the logging library has been initialized with the given settings.
 func InitLogging(logToStderr bool, verbose int, logFlow bool) {
+	InitLoggingToDir(logToStderr, verbose, logFlow, "")
+}
+
+// InitLoggingToDir is like InitLogging, but also writes log files to the given directory, if it is not empty, rather
+// than to the default one.
+func InitLoggingToDir(logToStderr bool, verbose int, logFlow bool, logDir string) {
 	// Remember the settings in case someone inquires.
 	LogToStderr = logToStderr
 	Verbose = verbose
 	LogFlow = logFlow
+	LogDir = logDir
 
 	// glog uses golang's built in flags package to set configuration values, which is incompatible with how
 	// we use cobra. In order to accommodate this, we call flag.CommandLine.Parse() with an empty array and
@@ -86,6 +119,10 @@ func InitLogging(logToStderr bool, verbose int, logFlow bool) {
 		err := flag.Lookup("v").Value.Set(strconv.Itoa(verbose))
 		assertNoError(err)
 	}
+	if logDir != "" {
+		err := flag.Lookup("log_dir").Value.Set(logDir)
+		assertNoError(err)
+	}
 }
 
 func assertNoError(err error) {
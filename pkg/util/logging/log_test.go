@@ -25,11 +25,13 @@ func TestInitLogging(t *testing.T) {
 	prevLog := LogToStderr
 	prevV := Verbose
 	prevFlow := LogFlow
+	prevDir := LogDir
 	InitLogging(true, 9, true)
-	InitLogging(prevLog, prevV, prevFlow)
+	InitLoggingToDir(prevLog, prevV, prevFlow, prevDir)
 	assert.Equal(t, prevLog, LogToStderr)
 	assert.Equal(t, prevV, Verbose)
 	assert.Equal(t, prevFlow, LogFlow)
+	assert.Equal(t, prevDir, LogDir)
 }
 
 func TestFilter(t *testing.T) {
@@ -0,0 +1,220 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics emits counters and timers to a StatsD server or to a Prometheus pushgateway.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// Labels are the names and values of the dimensions of a metric.
+type Labels map[string]string
+
+// With returns a copy of the labels to which the given name and value have been added.
+func (l Labels) With(name, value string) Labels {
+	result := make(Labels, len(l)+1)
+	for k, v := range l {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
+// Sink receives metrics. Metric names use underscores to separate their words, as Prometheus requires.
+type Sink interface {
+	// Count adds the given value to the named counter.
+	Count(name string, value int64, labels Labels)
+	// Time records a duration of the named timer.
+	Time(name string, d time.Duration, labels Labels)
+	// Flush sends any metrics that the sink has buffered.
+	Flush() error
+}
+
+// New returns a sink that sends metrics to the given address, which is either of the form statsd://HOST:PORT, for a
+// StatsD server, or an HTTP or HTTPS URL, for a Prometheus pushgateway. If the URL has no path, the metrics are pushed
+// under the job "pulumi".
+func New(addr string) (Sink, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing metrics address %q", addr)
+	}
+
+	switch u.Scheme {
+	case "statsd", "udp":
+		conn, err := net.Dial("udp", u.Host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "connecting to StatsD server %s", u.Host)
+		}
+		return &statsdSink{conn: conn}, nil
+	case "http", "https":
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/metrics/job/pulumi"
+		}
+		return &pushgatewaySink{
+			endpoint: u.String(),
+			counters: make(map[string]float64),
+			gauges:   make(map[string]float64),
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported metrics address %q; expected statsd://HOST:PORT or the URL of a "+
+			"Prometheus pushgateway", addr)
+	}
+}
+
+// sortedLabels returns the names of the given labels in sorted order, so that a metric's labels are always written in
+// the same order.
+func sortedLabels(labels Labels) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// statsdSink sends each metric to a StatsD server as soon as it is recorded. Labels are sent as DogStatsD tags.
+type statsdSink struct {
+	conn net.Conn
+}
+
+func (s *statsdSink) send(name, value, kind string, labels Labels) {
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s:%s|%s", name, value, kind)
+	for i, label := range sortedLabels(labels) {
+		if i == 0 {
+			line.WriteString("|#")
+		} else {
+			line.WriteString(",")
+		}
+		fmt.Fprintf(&line, "%s:%s", label, labels[label])
+	}
+
+	// Metrics are sent on a best-effort basis: a lost datagram should never fail an update.
+	if _, err := s.conn.Write([]byte(line.String())); err != nil {
+		logging.V(7).Infof("could not send metric %s: %v", name, err)
+	}
+}
+
+func (s *statsdSink) Count(name string, value int64, labels Labels) {
+	s.send(name, fmt.Sprintf("%d", value), "c", labels)
+}
+
+func (s *statsdSink) Time(name string, d time.Duration, labels Labels) {
+	s.send(name, fmt.Sprintf("%d", d.Nanoseconds()/int64(time.Millisecond)), "ms", labels)
+}
+
+func (s *statsdSink) Flush() error {
+	return nil
+}
+
+// pushgatewaySink buffers metrics in memory and pushes them to a Prometheus pushgateway when it is flushed. Counters
+// are pushed as counter samples, and timers as gauges, in seconds, whose names are suffixed with "_seconds".
+type pushgatewaySink struct {
+	endpoint string
+	counters map[string]float64 // the value of each counter, by its sample name.
+	gauges   map[string]float64 // the value of each gauge, by its sample name.
+	lock     sync.Mutex
+}
+
+// sampleName returns the name of a sample of the given metric in the Prometheus exposition format.
+func sampleName(name string, labels Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	var sample strings.Builder
+	sample.WriteString(name)
+	sample.WriteString("{")
+	for i, label := range sortedLabels(labels) {
+		if i > 0 {
+			sample.WriteString(",")
+		}
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[label])
+		fmt.Fprintf(&sample, "%s=\"%s\"", label, value)
+	}
+	sample.WriteString("}")
+	return sample.String()
+}
+
+func (s *pushgatewaySink) Count(name string, value int64, labels Labels) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.counters[sampleName(name, labels)] += float64(value)
+}
+
+func (s *pushgatewaySink) Time(name string, d time.Duration, labels Labels) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.gauges[sampleName(name+"_seconds", labels)] = d.Seconds()
+}
+
+func (s *pushgatewaySink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.counters) == 0 && len(s.gauges) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	writeSamples(&body, "counter", s.counters)
+	writeSamples(&body, "gauge", s.gauges)
+
+	resp, err := httputil.Client(10*time.Second).Post(s.endpoint, "text/plain; version=0.0.4", &body)
+	if err != nil {
+		return errors.Wrap(err, "pushing metrics")
+	}
+	contract.IgnoreClose(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%d HTTP error pushing metrics to %s", resp.StatusCode, s.endpoint)
+	}
+
+	s.counters, s.gauges = make(map[string]float64), make(map[string]float64)
+	return nil
+}
+
+// writeSamples writes the given samples, grouped by metric, in the Prometheus text exposition format.
+func writeSamples(w *bytes.Buffer, kind string, samples map[string]float64) {
+	names := make([]string, 0, len(samples))
+	for name := range samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lastMetric := ""
+	for _, name := range names {
+		metric := name
+		if i := strings.IndexByte(name, '{'); i != -1 {
+			metric = name[:i]
+		}
+		if metric != lastMetric {
+			fmt.Fprintf(w, "# TYPE %s %s\n", metric, kind)
+			lastMetric = metric
+		}
+		fmt.Fprintf(w, "%s %v\n", name, samples[name])
+	}
+}
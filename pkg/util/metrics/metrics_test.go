@@ -0,0 +1,97 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	sink, err := New("statsd://" + conn.LocalAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	sink.Count("pulumi_operations", 1, Labels{"stack": "dev", "operation": "update"})
+	assert.Equal(t, "pulumi_operations:1|c|#operation:update,stack:dev", read())
+
+	sink.Time("pulumi_operation_duration", 1500*time.Millisecond, nil)
+	assert.Equal(t, "pulumi_operation_duration:1500|ms", read())
+
+	assert.NoError(t, sink.Flush())
+}
+
+func TestPushgateway(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	sink, err := New(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Nothing is pushed until the sink is flushed, and counters accumulate until then.
+	labels := Labels{"stack": "dev"}
+	sink.Count("pulumi_operations", 1, labels.With("result", "succeeded"))
+	sink.Count("pulumi_operations", 2, labels.With("result", "succeeded"))
+	sink.Count("pulumi_operations", 1, labels.With("result", "failed"))
+	sink.Time("pulumi_operation_duration", 2*time.Second, labels)
+	assert.Equal(t, "", body)
+
+	assert.NoError(t, sink.Flush())
+	assert.Equal(t, "/metrics/job/pulumi", path)
+	assert.Equal(t, "# TYPE pulumi_operations counter\n"+
+		"pulumi_operations{result=\"failed\",stack=\"dev\"} 1\n"+
+		"pulumi_operations{result=\"succeeded\",stack=\"dev\"} 3\n"+
+		"# TYPE pulumi_operation_duration_seconds gauge\n"+
+		"pulumi_operation_duration_seconds{stack=\"dev\"} 2\n", body)
+
+	// A flushed sink starts afresh.
+	body = ""
+	assert.NoError(t, sink.Flush())
+	assert.Equal(t, "", body)
+}
+
+func TestNewUnsupported(t *testing.T) {
+	_, err := New("localhost:8125")
+	assert.Error(t, err)
+}
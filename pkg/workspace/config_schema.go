@@ -0,0 +1,250 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+// ProjectConfigType declares a configuration key that a project's program reads.
+type ProjectConfigType struct {
+	// Type is the type of the key's value: "string" (the default), "integer", "number", or "boolean".
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Description is an optional description of the key.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Required may be set to true to indicate that every stack must set the key.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Secret may be set to true to indicate that the key's value must be encrypted.
+	Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// Minimum is the optional least value of an "integer" or "number" key.
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	// Maximum is the optional greatest value of an "integer" or "number" key.
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	// Pattern is an optional regular expression that the whole value of a "string" key must match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Enum optionally lists the values that the key may have.
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+}
+
+// ConfigProblem is a problem with a stack's configuration that ValidateConfig found.
+type ConfigProblem struct {
+	Key     config.Key // the key whose value has the problem.
+	Message string     // a description of the problem.
+	Warning bool       // true if the problem does not make the configuration invalid, e.g. an undeclared key.
+}
+
+func (p ConfigProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// ValidateConfig checks the given configuration of one of the project's stacks against the project's config schema, and
+// returns the problems that it finds, ordered by key: the keys that are required but not set, the secret keys whose
+// values are not encrypted, and the values that are not of their declared types or that break their keys' rules
+// (minimum, maximum, pattern, or enum), each of which make the configuration invalid, and the keys in the project's
+// namespace that the schema does not declare, which are only reported as warnings. The decrypter is used to check the
+// types of secret values. If the project has no config schema, there are no problems.
+func (proj *Project) ValidateConfig(cfg config.Map, dec config.Decrypter) ([]ConfigProblem, error) {
+	if len(proj.ConfigSchema) == 0 {
+		return nil, nil
+	}
+
+	var problems []ConfigProblem
+	declared := make(map[config.Key]bool)
+	for name, typ := range proj.ConfigSchema {
+		key, err := proj.configSchemaKey(name)
+		if err != nil {
+			return nil, err
+		}
+		declared[key] = true
+
+		check, err := configValueChecker(typ)
+		if err != nil {
+			return nil, errors.Wrapf(err, "config schema key '%s'", name)
+		}
+
+		v, has := cfg[key]
+		if !has {
+			if typ.Required {
+				problems = append(problems, ConfigProblem{Key: key, Message: "is required, but is not set"})
+			}
+			continue
+		}
+		if typ.Secret && !v.Secure() && !v.Reference() {
+			problems = append(problems, ConfigProblem{Key: key,
+				Message: "must be a secret; set it using 'pulumi config set --secret'"})
+			continue
+		}
+		s, err := v.Value(dec)
+		if err != nil {
+			problems = append(problems, ConfigProblem{Key: key, Message: fmt.Sprintf("could not be decrypted: %v", err)})
+			continue
+		}
+		if err = check(s); err != nil {
+			problems = append(problems, ConfigProblem{Key: key, Message: err.Error()})
+		}
+	}
+
+	for key := range cfg {
+		if key.Namespace() == string(proj.Name) && !declared[key] {
+			problems = append(problems, ConfigProblem{Key: key, Message: "is not declared in the project's config schema",
+				Warning: true})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Key.String() < problems[j].Key.String()
+	})
+	return problems, nil
+}
+
+// configSchemaKey returns the key that the given name in the project's config schema declares. A name without a
+// namespace is in the project's namespace, as with 'pulumi config set'.
+func (proj *Project) configSchemaKey(name string) (config.Key, error) {
+	if !strings.Contains(name, ":") {
+		return config.MustMakeKey(string(proj.Name), name), nil
+	}
+	key, err := config.ParseKey(name)
+	if err != nil {
+		return config.Key{}, errors.Wrapf(err, "config schema key '%s'", name)
+	}
+	return key, nil
+}
+
+// configValueChecker returns a function that checks that a configuration value is of the given key's type, and obeys
+// its rules.
+func configValueChecker(typ ProjectConfigType) (func(string) error, error) {
+	checkType, err := configTypeChecker(typ.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	numeric := typ.Type == "integer" || typ.Type == "number"
+	if (typ.Minimum != nil || typ.Maximum != nil) && !numeric {
+		return nil, errors.New("'minimum' and 'maximum' apply only to 'integer' and 'number' keys")
+	}
+	if typ.Minimum != nil && typ.Maximum != nil && *typ.Minimum > *typ.Maximum {
+		return nil, errors.Errorf("the minimum, %v, is greater than the maximum, %v", *typ.Minimum, *typ.Maximum)
+	}
+	var pattern *regexp.Regexp
+	if typ.Pattern != "" {
+		if typ.Type != "" && typ.Type != "string" {
+			return nil, errors.New("'pattern' applies only to 'string' keys")
+		}
+		if pattern, err = regexp.Compile("^(?:" + typ.Pattern + ")$"); err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern '%s'", typ.Pattern)
+		}
+	}
+	for _, v := range typ.Enum {
+		if err = checkType(v); err != nil {
+			return nil, errors.Errorf("enum value %q is not of the key's type", v)
+		}
+	}
+
+	return func(s string) error {
+		if err := checkType(s); err != nil {
+			return err
+		}
+		if numeric {
+			// The type check has already made sure that the value parses.
+			n, _ := strconv.ParseFloat(s, 64)
+			if typ.Minimum != nil && n < *typ.Minimum {
+				return errors.Errorf("must be at least %v, but is %s", *typ.Minimum, s)
+			}
+			if typ.Maximum != nil && n > *typ.Maximum {
+				return errors.Errorf("must be at most %v, but is %s", *typ.Maximum, s)
+			}
+		}
+		if pattern != nil && !pattern.MatchString(s) {
+			return errors.Errorf("must match the pattern '%s', but is %q", typ.Pattern, s)
+		}
+		if len(typ.Enum) > 0 && !configEnumContains(typ, s) {
+			return errors.Errorf("must be one of %s, but is %q", quotedList(typ.Enum), s)
+		}
+		return nil
+	}, nil
+}
+
+// configEnumContains returns true if the given value is one of the key's enum values. Numbers are compared by value,
+// so that "8080" and "8080.0" are the same number.
+func configEnumContains(typ ProjectConfigType, s string) bool {
+	for _, v := range typ.Enum {
+		if v == s {
+			return true
+		}
+		if typ.Type == "integer" || typ.Type == "number" {
+			n, err1 := strconv.ParseFloat(s, 64)
+			m, err2 := strconv.ParseFloat(v, 64)
+			if err1 == nil && err2 == nil && n == m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// quotedList returns the given values, quoted, as an English list, e.g. "'a', 'b', or 'c'".
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + v + "'"
+	}
+	switch len(quoted) {
+	case 1:
+		return quoted[0]
+	case 2:
+		return quoted[0] + " or " + quoted[1]
+	default:
+		return strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1]
+	}
+}
+
+// configTypeChecker returns a function that checks that a configuration value is of the given type.
+func configTypeChecker(typ string) (func(string) error, error) {
+	switch typ {
+	case "", "string":
+		return func(string) error { return nil }, nil
+	case "integer":
+		return func(s string) error {
+			if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+				return errors.Errorf("must be an integer, but is %q", s)
+			}
+			return nil
+		}, nil
+	case "number":
+		return func(s string) error {
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return errors.Errorf("must be a number, but is %q", s)
+			}
+			return nil
+		}, nil
+	case "boolean":
+		return func(s string) error {
+			if s != "true" && s != "false" {
+				return errors.Errorf("must be 'true' or 'false', but is %q", s)
+			}
+			return nil
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown type '%s': expected 'string', 'integer', 'number', or 'boolean'", typ)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestValidateConfig(t *testing.T) {
+	proj := &Project{
+		Name: "proj",
+		ConfigSchema: map[string]ProjectConfigType{
+			"name":       {Required: true},
+			"port":       {Type: "integer"},
+			"ratio":      {Type: "number"},
+			"enabled":    {Type: "boolean"},
+			"password":   {Secret: true},
+			"aws:region": {Required: true},
+		},
+	}
+	key := func(name string) config.Key {
+		return config.MustMakeKey("proj", name)
+	}
+
+	// A configuration that satisfies the schema has no problems, even if it sets keys in other namespaces.
+	valid := config.Map{
+		key("name"):                         config.NewValue("web"),
+		key("port"):                         config.NewValue("8080"),
+		key("ratio"):                        config.NewValue("0.5"),
+		key("enabled"):                      config.NewValue("true"),
+		key("password"):                     config.NewSecureValue("hunter2"),
+		config.MustMakeKey("aws", "region"): config.NewValue("us-west-2"),
+		config.MustMakeKey("gcp", "zone"):   config.NewValue("us-central1-a"),
+	}
+	problems, err := proj.ValidateConfig(valid, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	invalid := config.Map{
+		key("port"):     config.NewValue("http"),
+		key("ratio"):    config.NewValue("half"),
+		key("enabled"):  config.NewValue("yes"),
+		key("password"): config.NewValue("hunter2"),
+		key("extra"):    config.NewValue("value"),
+	}
+	problems, err = proj.ValidateConfig(invalid, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigProblem{
+		{Key: config.MustMakeKey("aws", "region"), Message: "is required, but is not set"},
+		{Key: key("enabled"), Message: `must be 'true' or 'false', but is "yes"`},
+		{Key: key("extra"), Message: "is not declared in the project's config schema", Warning: true},
+		{Key: key("name"), Message: "is required, but is not set"},
+		{Key: key("password"), Message: "must be a secret; set it using 'pulumi config set --secret'"},
+		{Key: key("port"), Message: `must be an integer, but is "http"`},
+		{Key: key("ratio"), Message: `must be a number, but is "half"`},
+	}, problems)
+
+	// A project without a schema accepts any configuration.
+	problems, err = (&Project{Name: "proj"}).ValidateConfig(invalid, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// An unknown type is an error in the schema.
+	proj.ConfigSchema["port"] = ProjectConfigType{Type: "int"}
+	_, err = proj.ValidateConfig(valid, config.NopDecrypter)
+	assert.Error(t, err)
+}
+
+func TestValidateConfigRules(t *testing.T) {
+	min, max := float64(1), float64(65535)
+	proj := &Project{
+		Name: "proj",
+		ConfigSchema: map[string]ProjectConfigType{
+			"port":  {Type: "integer", Minimum: &min, Maximum: &max},
+			"ratio": {Type: "number", Maximum: &min},
+			"name":  {Pattern: "[a-z][a-z0-9-]*"},
+			"size":  {Enum: []string{"small", "medium", "large"}},
+			"tls":   {Type: "integer", Enum: []string{"80", "443"}},
+		},
+	}
+	key := func(name string) config.Key {
+		return config.MustMakeKey("proj", name)
+	}
+
+	valid := config.Map{
+		key("port"):  config.NewValue("65535"),
+		key("ratio"): config.NewValue("0.5"),
+		key("name"):  config.NewValue("web-1"),
+		key("size"):  config.NewValue("medium"),
+		key("tls"):   config.NewValue("443"),
+	}
+	problems, err := proj.ValidateConfig(valid, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	invalid := config.Map{
+		key("port"):  config.NewValue("0"),
+		key("ratio"): config.NewValue("1.5"),
+		key("name"):  config.NewValue("Web 1"),
+		key("size"):  config.NewValue("huge"),
+		key("tls"):   config.NewValue("8443"),
+	}
+	problems, err = proj.ValidateConfig(invalid, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigProblem{
+		{Key: key("name"), Message: `must match the pattern '[a-z][a-z0-9-]*', but is "Web 1"`},
+		{Key: key("port"), Message: "must be at least 1, but is 0"},
+		{Key: key("ratio"), Message: "must be at most 1, but is 1.5"},
+		{Key: key("size"), Message: `must be one of 'small', 'medium', or 'large', but is "huge"`},
+		{Key: key("tls"), Message: `must be one of '80' or '443', but is "8443"`},
+	}, problems)
+
+	// A value that is not of its key's type is reported as such, rather than as breaking its key's rules.
+	problems, err = proj.ValidateConfig(config.Map{key("port"): config.NewValue("http")}, config.NopDecrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigProblem{{Key: key("port"), Message: `must be an integer, but is "http"`}}, problems)
+
+	// Rules that do not fit their keys' types are errors in the schema.
+	for _, typ := range []ProjectConfigType{
+		{Minimum: &min},
+		{Type: "integer", Minimum: &max, Maximum: &min},
+		{Type: "boolean", Pattern: "true"},
+		{Pattern: "("},
+		{Type: "integer", Enum: []string{"one"}},
+	} {
+		proj.ConfigSchema["port"] = typ
+		_, err = proj.ValidateConfig(valid, config.NopDecrypter)
+		assert.Error(t, err)
+	}
+}
@@ -55,6 +55,8 @@ const (
 
 	// ProjectFile is the base name of a project file.
 	ProjectFile = "Pulumi"
+	// PluginLockFile is the name of the file, next to the project file, that records the checksum of each plugin.
+	PluginLockFile = "Pulumi.lock.json"
 	// RepoFile is the name of the file that holds information specific to the entire repository.
 	RepoFile = "settings.json"
 	// WorkspaceFile is the name of the file that holds workspace information.
@@ -177,13 +179,43 @@ func isMarkupFile(path string, expect string) bool {
 	return false
 }
 
-// GetCachedVersionFilePath returns the location where the CLI caches information from pulumi.com on the newest
-// available version of the CLI
-func GetCachedVersionFilePath() (string, error) {
+// PulumiHomeEnvVar is the environment variable that, if set, relocates the directory in which the CLI stores its
+// credentials, plugins, policy packs, templates, and workspace settings. If it is not set, the directory is ~/.pulumi.
+// The directory also stands in for ~ in the URL of a local backend, so that file://~ keeps its state there.
+const PulumiHomeEnvVar = "PULUMI_HOME"
+
+// GetPulumiHomeDir returns the directory in which the CLI stores its own files. A directory set using PulumiHomeEnvVar
+// is created if it does not exist.
+func GetPulumiHomeDir() (string, error) {
+	if dir := os.Getenv(PulumiHomeEnvVar); dir != "" {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving %s", PulumiHomeEnvVar)
+		}
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return "", errors.Wrapf(err, "creating %s directory %s", PulumiHomeEnvVar, dir)
+		}
+		return dir, nil
+	}
+
 	user, err := user.Current()
+	if user == nil || err != nil {
+		return "", errors.Wrap(err, "getting user home directory")
+	}
+	return filepath.Join(user.HomeDir, BookkeepingDir), nil
+}
+
+// GetPulumiPath returns the path of the given file or directory within the CLI's home directory.
+func GetPulumiPath(elem ...string) (string, error) {
+	home, err := GetPulumiHomeDir()
 	if err != nil {
 		return "", err
 	}
+	return filepath.Join(append([]string{home}, elem...)...), nil
+}
 
-	return filepath.Join(user.HomeDir, BookkeepingDir, CachedVersionFile), nil
+// GetCachedVersionFilePath returns the location where the CLI caches information from pulumi.com on the newest
+// available version of the CLI
+func GetCachedVersionFilePath() (string, error) {
+	return GetPulumiPath(CachedVersionFile)
 }
@@ -0,0 +1,59 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPulumiHomeDirOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv(PulumiHomeEnvVar, os.Getenv(PulumiHomeEnvVar))
+
+	// Without an override, the home directory is ~/.pulumi.
+	assert.NoError(t, os.Unsetenv(PulumiHomeEnvVar))
+	home, err := GetPulumiHomeDir()
+	assert.NoError(t, err)
+	assert.Equal(t, BookkeepingDir, filepath.Base(home))
+
+	// An override is created if it does not exist, and every path is derived from it.
+	override := filepath.Join(dir, "nested", "home")
+	assert.NoError(t, os.Setenv(PulumiHomeEnvVar, override))
+	home, err = GetPulumiHomeDir()
+	assert.NoError(t, err)
+	assert.Equal(t, override, home)
+	info, err := os.Stat(override)
+	if assert.NoError(t, err) {
+		assert.True(t, info.IsDir())
+	}
+
+	pluginDir, err := GetPluginDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(override, PluginDir), pluginDir)
+	policyDir, err := GetPolicyDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(override, PolicyDir), policyDir)
+	versionFile, err := GetCachedVersionFilePath()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(override, CachedVersionFile), versionFile)
+}
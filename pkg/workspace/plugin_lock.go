@@ -0,0 +1,167 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// PluginLock records the checksum of each plugin that a project uses, so that a plugin whose contents change
+// unexpectedly is detected before it is run.
+type PluginLock struct {
+	Plugins []LockedPlugin `json:"plugins"`
+}
+
+// LockedPlugin is the checksum of a single plugin within a PluginLock.
+type LockedPlugin struct {
+	Kind     PluginKind `json:"kind"`
+	Name     string     `json:"name"`
+	Version  string     `json:"version,omitempty"`
+	Checksum string     `json:"checksum"`
+}
+
+// LoadPluginLock reads a plugin lock file. If the file does not exist, nil is returned.
+func LoadPluginLock(path string) (*PluginLock, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lock PluginLock
+	if err = json.Unmarshal(b, &lock); err != nil {
+		return nil, errors.Wrapf(err, "could not read plugin lock file %s", path)
+	}
+	return &lock, nil
+}
+
+// Save writes the plugin lock to the given file.
+func (lock *PluginLock) Save(path string) error {
+	b, err := json.MarshalIndent(lock, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// LockPlugins computes the checksum of each of the given plugins, all of which must be installed. Language plugins are
+// skipped, as they are distributed with the CLI rather than installed separately.
+func LockPlugins(plugins []PluginInfo) (*PluginLock, error) {
+	lock := &PluginLock{Plugins: []LockedPlugin{}}
+	for _, plugin := range plugins {
+		if plugin.Kind == LanguagePlugin {
+			continue
+		}
+
+		_, path, err := GetPluginPath(plugin.Kind, plugin.Name, plugin.Version)
+		if err != nil {
+			return nil, err
+		} else if path == "" {
+			return nil, errors.Errorf("%s plugin %s is not installed", plugin.Kind, plugin)
+		}
+		checksum, err := pluginChecksum(path)
+		if err != nil {
+			return nil, err
+		}
+
+		lock.Plugins = append(lock.Plugins, LockedPlugin{
+			Kind:     plugin.Kind,
+			Name:     plugin.Name,
+			Version:  lockedVersion(plugin),
+			Checksum: checksum,
+		})
+	}
+
+	sort.Slice(lock.Plugins, func(i, j int) bool {
+		pi, pj := lock.Plugins[i], lock.Plugins[j]
+		if pi.Kind != pj.Kind {
+			return pi.Kind < pj.Kind
+		} else if pi.Name != pj.Name {
+			return pi.Name < pj.Name
+		}
+		return pi.Version < pj.Version
+	})
+	return lock, nil
+}
+
+// Verify checks that each of the given plugins that is recorded in the lock is installed and has the recorded
+// checksum. Plugins that are not recorded in the lock are not checked.
+func (lock *PluginLock) Verify(plugins []PluginInfo) error {
+	for _, plugin := range plugins {
+		locked, has := lock.find(plugin)
+		if !has {
+			continue
+		}
+
+		_, path, err := GetPluginPath(plugin.Kind, plugin.Name, plugin.Version)
+		if err != nil {
+			return err
+		} else if path == "" {
+			return errors.Errorf("%s plugin %s is not installed", plugin.Kind, plugin)
+		}
+		checksum, err := pluginChecksum(path)
+		if err != nil {
+			return err
+		}
+		if checksum != locked.Checksum {
+			return errors.Errorf("%s plugin %s does not match the plugin lock file: expected checksum %s, found %s",
+				plugin.Kind, plugin, locked.Checksum, checksum)
+		}
+	}
+	return nil
+}
+
+func (lock *PluginLock) find(plugin PluginInfo) (LockedPlugin, bool) {
+	version := lockedVersion(plugin)
+	for _, locked := range lock.Plugins {
+		if locked.Kind == plugin.Kind && locked.Name == plugin.Name && locked.Version == version {
+			return locked, true
+		}
+	}
+	return LockedPlugin{}, false
+}
+
+func lockedVersion(plugin PluginInfo) string {
+	if plugin.Version == nil {
+		return ""
+	}
+	return plugin.Version.String()
+}
+
+// pluginChecksum returns the SHA-256 checksum of the plugin binary at the given path.
+func pluginChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer contract.IgnoreClose(f)
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
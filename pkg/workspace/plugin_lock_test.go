@@ -0,0 +1,84 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin binaries on $PATH require an executable suffix on Windows")
+	}
+
+	// Plugins on $PATH take precedence over installed plugins, so put a fake plugin there.
+	dir, err := ioutil.TempDir("", "pulumi-plugin-lock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	assert.NoError(t, os.Setenv("PATH", dir))
+
+	bin := filepath.Join(dir, "pulumi-resource-fake")
+	assert.NoError(t, ioutil.WriteFile(bin, []byte("v1"), 0700))
+
+	v1 := semver.MustParse("1.0.0")
+	plugins := []PluginInfo{
+		{Name: "fake", Kind: ResourcePlugin, Version: &v1},
+		{Name: "nodejs", Kind: LanguagePlugin},
+	}
+
+	// Locking skips language plugins and records the checksum of each other plugin.
+	lock, err := LockPlugins(plugins)
+	assert.NoError(t, err)
+	if assert.Len(t, lock.Plugins, 1) {
+		assert.Equal(t, "fake", lock.Plugins[0].Name)
+		assert.Equal(t, "1.0.0", lock.Plugins[0].Version)
+		assert.Contains(t, lock.Plugins[0].Checksum, "sha256:")
+	}
+
+	// The lock round-trips through a file.
+	path := filepath.Join(dir, PluginLockFile)
+	assert.NoError(t, lock.Save(path))
+	loaded, err := LoadPluginLock(path)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, loaded)
+
+	missing, err := LoadPluginLock(filepath.Join(dir, "missing.json"))
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// Unchanged plugins verify, as do plugins that are not locked.
+	v2 := semver.MustParse("2.0.0")
+	assert.NoError(t, loaded.Verify(append(plugins, PluginInfo{Name: "fake", Kind: ResourcePlugin, Version: &v2})))
+
+	// Changed plugins do not, and the error names the plugin and both checksums.
+	original := lock.Plugins[0].Checksum
+	assert.NoError(t, ioutil.WriteFile(bin, []byte("v2"), 0700))
+	err = loaded.Verify(plugins)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "fake-1.0.0")
+		assert.Contains(t, err.Error(), original)
+		changed, cerr := LockPlugins(plugins)
+		assert.NoError(t, cerr)
+		assert.Contains(t, err.Error(), changed.Plugins[0].Checksum)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// PluginPathEnvVar is the environment variable that may override the binaries that are used for resource provider
+// plugins. It holds a list of NAME=PATH pairs, separated by the OS's path list separator (":" or ";"), each of which
+// names a provider and the path of the binary to launch for it in place of any installed version of the plugin.
+const PluginPathEnvVar = "PULUMI_PLUGIN_PATH"
+
+var (
+	pluginPathOverrides     map[string]string // the overrides set by SetPluginPathOverride.
+	pluginPathOverridesLock sync.RWMutex      // a lock that protects pluginPathOverrides.
+)
+
+// ParsePluginPathOverride parses an override of the binary for a resource provider plugin, of the form NAME=PATH, and
+// returns the provider's name and the absolute path of the binary, which must exist.
+func ParsePluginPathOverride(s string) (string, string, error) {
+	eq := strings.Index(s, "=")
+	if eq <= 0 || eq == len(s)-1 {
+		return "", "", errors.Errorf("expected a plugin path of the form NAME=PATH, got %q", s)
+	}
+	name, path := s[:eq], s[eq+1:]
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "checking the binary for the %s plugin", name)
+	}
+	if info.IsDir() {
+		return "", "", errors.Errorf("the binary for the %s plugin, %s, is a directory", name, path)
+	}
+	return name, path, nil
+}
+
+// SetPluginPathOverride launches the binary at the given path in place of any installed version of the named
+// resource provider plugin. Overrides set this way take precedence over those in PluginPathEnvVar.
+func SetPluginPathOverride(name, path string) {
+	pluginPathOverridesLock.Lock()
+	defer pluginPathOverridesLock.Unlock()
+
+	if pluginPathOverrides == nil {
+		pluginPathOverrides = make(map[string]string)
+	}
+	pluginPathOverrides[name] = path
+}
+
+// getPluginPathOverride returns the path of the binary that overrides the given plugin, if any.
+func getPluginPathOverride(kind PluginKind, name string) (string, bool, error) {
+	if kind != ResourcePlugin {
+		return "", false, nil
+	}
+
+	pluginPathOverridesLock.RLock()
+	path, has := pluginPathOverrides[name]
+	pluginPathOverridesLock.RUnlock()
+	if has {
+		return path, true, nil
+	}
+
+	for _, override := range filepath.SplitList(os.Getenv(PluginPathEnvVar)) {
+		if !strings.HasPrefix(override, name+"=") {
+			continue
+		}
+		_, path, err := ParsePluginPathOverride(override)
+		if err != nil {
+			return "", false, errors.Wrapf(err, "reading %s", PluginPathEnvVar)
+		}
+		return path, true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginPathOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugin-override")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fromEnv, fromFlag := filepath.Join(dir, "from-env"), filepath.Join(dir, "from-flag")
+	for _, path := range []string{fromEnv, fromFlag} {
+		assert.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0700))
+	}
+
+	defer os.Setenv(PluginPathEnvVar, os.Getenv(PluginPathEnvVar))
+	defer func() { pluginPathOverrides = nil }()
+
+	// Overrides apply to resource providers, whatever their version, but not to other kinds of plugins.
+	v := semver.MustParse("1.2.3")
+	assert.NoError(t, os.Setenv(PluginPathEnvVar, "other=/nonexistent"+string(os.PathListSeparator)+"myprovider="+fromEnv))
+	_, path, err := GetPluginPath(ResourcePlugin, "myprovider", &v)
+	assert.NoError(t, err)
+	assert.Equal(t, fromEnv, path)
+	_, path, _ = GetPluginPath(AnalyzerPlugin, "myprovider", nil)
+	assert.NotEqual(t, fromEnv, path)
+
+	// Overrides that have been set explicitly take precedence over the environment.
+	name, path, err := ParsePluginPathOverride("myprovider=" + fromFlag)
+	assert.NoError(t, err)
+	SetPluginPathOverride(name, path)
+	_, path, err = GetPluginPath(ResourcePlugin, "myprovider", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, fromFlag, path)
+
+	// An override for a missing binary is an error.
+	_, _, err = GetPluginPath(ResourcePlugin, "other", nil)
+	assert.Error(t, err)
+
+	for _, bad := range []string{"myprovider", "=" + fromFlag, "myprovider=", "myprovider=" + dir} {
+		_, _, err = ParsePluginPathOverride(bad)
+		assert.Error(t, err, bad)
+	}
+}
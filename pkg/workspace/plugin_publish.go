@@ -0,0 +1,141 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
+)
+
+// PluginManifest describes a published plugin tarball. It is uploaded alongside the tarball, with the same URL
+// followed by ".json".
+type PluginManifest struct {
+	Kind     PluginKind `json:"kind"`
+	Name     string     `json:"name"`
+	Version  string     `json:"version"`
+	OS       string     `json:"os"`
+	Arch     string     `json:"arch"`
+	Checksum string     `json:"checksum"` // the SHA-256 checksum of the tarball, as a hex string.
+}
+
+// PluginVersionExistsError is returned by Publish if the plugin's server already has a tarball for its version.
+type PluginVersionExistsError struct {
+	Plugin PluginInfo
+}
+
+func (err PluginVersionExistsError) Error() string {
+	return fmt.Sprintf("%s plugin %s has already been published", err.Plugin.Kind, err.Plugin)
+}
+
+// Publish uploads a tarball of this plugin for the current OS and architecture, along with its manifest, to the
+// plugin's server, at the URL from which Download fetches it. The server must accept PUT requests. If the server
+// already has a tarball for this version of the plugin, a PluginVersionExistsError is returned unless force is true,
+// in which case the existing tarball is replaced.
+func (info PluginInfo) Publish(tarball []byte, force bool) (*PluginManifest, error) {
+	contract.Require(info.Version != nil, "info.Version")
+
+	endpoint, err := info.downloadURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		exists, err := pluginTarballExists(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, PluginVersionExistsError{Plugin: info}
+		}
+	}
+
+	checksum := sha256.Sum256(tarball)
+	manifest := &PluginManifest{
+		Kind:     info.Kind,
+		Name:     info.Name,
+		Version:  info.Version.String(),
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Checksum: hex.EncodeToString(checksum[:]),
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	// Upload the tarball first, so that a manifest is never published without the tarball it describes.
+	if err = putPluginFile(endpoint, "application/gzip", tarball); err != nil {
+		return nil, err
+	}
+	if err = putPluginFile(endpoint+".json", "application/json", manifestJSON); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// pluginTarballExists returns true if the server has a plugin tarball at the given URL.
+func pluginTarballExists(endpoint string) (bool, error) {
+	req, err := http.NewRequest("HEAD", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", pluginUserAgent())
+
+	resp, err := httputil.DoWithRetry(req, httputil.Client(0))
+	if err != nil {
+		return false, err
+	}
+	contract.IgnoreClose(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return true, nil
+	default:
+		return false, errors.Errorf("%d HTTP error checking for plugin at %s", resp.StatusCode, endpoint)
+	}
+}
+
+// putPluginFile uploads the given contents to the given URL.
+func putPluginFile(endpoint, contentType string, contents []byte) error {
+	req, err := http.NewRequest("PUT", endpoint, bytes.NewReader(contents))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", pluginUserAgent())
+
+	resp, err := httputil.DoWithRetry(req, httputil.Client(0))
+	if err != nil {
+		return err
+	}
+	contract.IgnoreClose(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%d HTTP error uploading plugin to %s", resp.StatusCode, endpoint)
+	}
+	return nil
+}
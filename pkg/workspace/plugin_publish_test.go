@@ -0,0 +1,83 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishPlugin(t *testing.T) {
+	var lock sync.Mutex
+	files := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch r.Method {
+		case "HEAD", "GET":
+			contents, has := files[r.URL.Path]
+			if !has {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := w.Write(contents)
+			assert.NoError(t, err)
+		case "PUT":
+			contents, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			files[r.URL.Path] = contents
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	version := semver.MustParse("1.2.3")
+	info := PluginInfo{Kind: ResourcePlugin, Name: "test", Version: &version, ServerURL: server.URL}
+
+	manifest, err := info.Publish([]byte("tarball"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.Equal(t, "db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0", manifest.Checksum)
+
+	// The tarball can be downloaded from where it was published, and its manifest is alongside it.
+	tarball, _, err := info.Download()
+	assert.NoError(t, err)
+	contents, err := ioutil.ReadAll(tarball)
+	assert.NoError(t, err)
+	assert.Equal(t, "tarball", string(contents))
+
+	endpoint, err := info.downloadURL()
+	assert.NoError(t, err)
+	var published PluginManifest
+	assert.NoError(t, json.Unmarshal(files[endpoint[len(server.URL):]+".json"], &published))
+	assert.Equal(t, *manifest, published)
+
+	// Publishing the same version again fails unless it is forced.
+	_, err = info.Publish([]byte("new tarball"), false)
+	assert.Equal(t, PluginVersionExistsError{Plugin: info}, err)
+
+	_, err = info.Publish([]byte("new tarball"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "new tarball", string(files[endpoint[len(server.URL):]]))
+}
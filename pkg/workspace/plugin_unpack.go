@@ -0,0 +1,114 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/archive"
+)
+
+// ReadPluginManifest reads the manifest of a published plugin tarball from the given file.
+func ReadPluginManifest(file string) (*PluginManifest, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var manifest PluginManifest
+	if err = json.Unmarshal(b, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "reading plugin manifest %s", file)
+	}
+	if manifest.Checksum == "" {
+		return nil, errors.Errorf("plugin manifest %s does not record a checksum", file)
+	}
+	return &manifest, nil
+}
+
+// VerifyPluginTarball checks that the SHA-256 checksum of the given plugin tarball is the given hex string.
+func VerifyPluginTarball(tarball []byte, checksum string) error {
+	sum := sha256.Sum256(tarball)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, checksum) {
+		return errors.Errorf("the tarball's SHA-256 checksum is %s, but %s was expected; the tarball is corrupt or "+
+			"is not the one that was published", actual, checksum)
+	}
+	return nil
+}
+
+// UnpackPluginTarball extracts the files in the given plugin tarball, which should already have been verified, into
+// the given directory, creating it if necessary. The tarball is read in full before anything is extracted, so that a
+// corrupt tarball leaves the directory untouched. Unless force is true, the directory must be empty.
+func UnpackPluginTarball(tarball []byte, dir string, force bool) error {
+	if err := checkPluginTarball(tarball); err != nil {
+		return errors.Wrap(err, "the tarball is corrupt")
+	}
+
+	if !force {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(entries) > 0 {
+			return errors.Errorf("%s is not empty; pass --force to unpack into it anyway", dir)
+		}
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrapf(err, "creating %s", dir)
+	}
+	return archive.Untgz(tarball, dir)
+}
+
+// checkPluginTarball reads the given tarball in full, and returns an error if it is not a well-formed tarball of
+// files and directories that are all inside the directory into which it is extracted.
+func checkPluginTarball(tarball []byte) error {
+	gzr, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		return err
+	}
+	r := tar.NewReader(gzr)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("%s is outside of the tarball's directory", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+		case tar.TypeReg:
+			if _, err = io.Copy(ioutil.Discard, r); err != nil {
+				return errors.Wrapf(err, "reading %s", header.Name)
+			}
+		default:
+			return errors.Errorf("unexpected file type %s (%v)", header.Name, header.Typeflag)
+		}
+	}
+}
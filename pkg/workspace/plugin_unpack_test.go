@@ -0,0 +1,89 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/util/archive"
+)
+
+func TestUnpackPluginTarball(t *testing.T) {
+	src, err := ioutil.TempDir("", "plugin-src")
+	assert.NoError(t, err)
+	defer os.RemoveAll(src)
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "lib"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "pulumi-resource-test"), []byte("binary"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "lib", "index.js"), []byte("source"), 0600))
+
+	tarball, err := archive.Tgz(src)
+	assert.NoError(t, err)
+	sum := sha256.Sum256(tarball)
+	checksum := hex.EncodeToString(sum[:])
+
+	// A tarball only verifies against its own checksum.
+	assert.NoError(t, VerifyPluginTarball(tarball, checksum))
+	corrupt := append([]byte(nil), tarball...)
+	corrupt[len(corrupt)/2] ^= 0xff
+	assert.Error(t, VerifyPluginTarball(corrupt, checksum))
+
+	dir, err := ioutil.TempDir("", "plugin-dst")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dst := filepath.Join(dir, "unpacked")
+
+	assert.NoError(t, UnpackPluginTarball(tarball, dst, false))
+	contents, err := ioutil.ReadFile(filepath.Join(dst, "lib", "index.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, "source", string(contents))
+
+	// A directory that is not empty is only unpacked into if forced.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dst, "lib", "index.js"), []byte("edited source"), 0600))
+	assert.Error(t, UnpackPluginTarball(tarball, dst, false))
+	assert.NoError(t, UnpackPluginTarball(tarball, dst, true))
+	contents, err = ioutil.ReadFile(filepath.Join(dst, "lib", "index.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, "source", string(contents))
+
+	// Corrupt tarballs, and tarballs whose files are outside of their directory, are not unpacked at all.
+	empty := filepath.Join(dir, "empty")
+	assert.Error(t, UnpackPluginTarball([]byte("not a tarball"), empty, false))
+	assert.Error(t, UnpackPluginTarball(tarball[:len(tarball)-16], empty, false))
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gzw)
+	assert.NoError(t, w.WriteHeader(&tar.Header{Name: "../escaped", Typeflag: tar.TypeReg, Mode: 0600, Size: 1}))
+	_, err = w.Write([]byte("x"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, gzw.Close())
+	assert.Error(t, UnpackPluginTarball(buf.Bytes(), empty, false))
+
+	_, err = os.Stat(empty)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "escaped"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -21,7 +21,6 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
-	"os/user"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -170,20 +169,51 @@ func (info *PluginInfo) SetFileMetadata(path string) error {
 
 // Download fetches an io.ReadCloser for this plugin and also returns the size of the response (if known).
 func (info PluginInfo) Download() (io.ReadCloser, int64, error) {
+	endpoint, err := info.downloadURL()
+	if err != nil {
+		return nil, -1, err
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	req.Header.Set("User-Agent", pluginUserAgent())
+
+	resp, err := httputil.DoWithRetry(req, httputil.Client(0))
+	if err != nil {
+		return nil, -1, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, -1, errors.Errorf("%d HTTP error fetching plugin from %s", resp.StatusCode, endpoint)
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// pluginUserAgent returns the user agent that the CLI uses when downloading or publishing plugins.
+func pluginUserAgent() string {
+	return fmt.Sprintf("pulumi-cli/1 (%s; %s)", version.Version, runtime.GOOS)
+}
+
+// downloadURL returns the URL from which the tarball of this plugin for the current OS and architecture is downloaded.
+func (info PluginInfo) downloadURL() (string, error) {
 	// Figure out the OS/ARCH pair for the download URL.
 	var os string
 	switch runtime.GOOS {
 	case "darwin", "linux", "windows":
 		os = runtime.GOOS
 	default:
-		return nil, -1, errors.Errorf("unsupported plugin OS: %s", runtime.GOOS)
+		return "", errors.Errorf("unsupported plugin OS: %s", runtime.GOOS)
 	}
 	var arch string
 	switch runtime.GOARCH {
 	case "amd64":
 		arch = runtime.GOARCH
 	default:
-		return nil, -1, errors.Errorf("unsupported plugin architecture: %s", runtime.GOARCH)
+		return "", errors.Errorf("unsupported plugin architecture: %s", runtime.GOARCH)
 	}
 
 	// If the plugin has a server, associated with it, download from there.  Otherwise use the "default" location, which
@@ -193,25 +223,7 @@ func (info PluginInfo) Download() (io.ReadCloser, int64, error) {
 		serverURL = "https://api.pulumi.com/releases/plugins"
 	}
 
-	endpoint := fmt.Sprintf("%s/pulumi-%s-%s-v%s-%s-%s.tar.gz", serverURL, info.Kind, info.Name, info.Version, os, arch)
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, -1, err
-	}
-
-	userAgent := fmt.Sprintf("pulumi-cli/1 (%s; %s)", version.Version, runtime.GOOS)
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := httputil.DoWithRetry(req, http.DefaultClient)
-	if err != nil {
-		return nil, -1, err
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, -1, errors.Errorf("%d HTTP error fetching plugin from %s", resp.StatusCode, endpoint)
-	}
-
-	return resp.Body, resp.ContentLength, nil
+	return fmt.Sprintf("%s/pulumi-%s-%s-v%s-%s-%s.tar.gz", serverURL, info.Kind, info.Name, info.Version, os, arch), nil
 }
 
 // Install installs a plugin's tarball into the cache.  It validates that plugin names are in the expected format.
@@ -351,11 +363,7 @@ func HasPluginGTE(plug PluginInfo) (bool, error) {
 
 // GetPolicyDir returns the directory in which policies on the current machine are managed.
 func GetPolicyDir() (string, error) {
-	u, err := user.Current()
-	if u == nil || err != nil {
-		return "", errors.Wrapf(err, "getting user home directory")
-	}
-	return filepath.Join(u.HomeDir, BookkeepingDir, PolicyDir), nil
+	return GetPulumiPath(PolicyDir)
 }
 
 // GetPolicyPath finds a PolicyPack by its name version, as well as a bool marked true if the path
@@ -383,11 +391,7 @@ func GetPolicyPath(name, version string) (string, bool, error) {
 
 // GetPluginDir returns the directory in which plugins on the current machine are managed.
 func GetPluginDir() (string, error) {
-	u, err := user.Current()
-	if u == nil || err != nil {
-		return "", errors.Wrapf(err, "getting user home directory")
-	}
-	return filepath.Join(u.HomeDir, BookkeepingDir, PluginDir), nil
+	return GetPulumiPath(PluginDir)
 }
 
 // GetPlugins returns a list of installed plugins.
@@ -426,8 +430,18 @@ func GetPlugins() ([]PluginInfo, error) {
 
 // GetPluginPath finds a plugin's path by its kind, name, and optional version.  It will match the latest version that
 // is >= the version specified.  If no version is supplied, the latest plugin for that given kind/name pair is loaded,
-// using standard semver sorting rules.  A plugin may be overridden entirely by placing it on your $PATH.
+// using standard semver sorting rules.  A plugin may be overridden entirely by placing it on your $PATH, and the binary
+// for a resource provider may also be overridden using SetPluginPathOverride or PluginPathEnvVar.
 func GetPluginPath(kind PluginKind, name string, version *semver.Version) (string, string, error) {
+	// If the plugin's binary has been overridden, use it, whatever its version.  This supports plugin development.
+	if path, has, err := getPluginPathOverride(kind, name); err != nil {
+		return "", "", err
+	} else if has {
+		logging.V(3).Infof("GetPluginPath(%s, %s, %v): using overridden plugin binary %s, in place of any "+
+			"installed version", kind, name, version, path)
+		return "", path, nil
+	}
+
 	// If we have a version of the plugin on its $PATH, use it.  This supports development scenarios.
 	filename := (&PluginInfo{Kind: kind, Name: name, Version: version}).FilePrefix()
 	if path, err := exec.LookPath(filename); err == nil {
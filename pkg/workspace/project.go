@@ -88,6 +88,14 @@ type Project struct {
 	// Config indicates where to store the Pulumi.<stack-name>.yaml files, combined with the folder Pulumi.yaml is in.
 	Config string `json:"config,omitempty" yaml:"config,omitempty"`
 
+	// ConfigSchema optionally declares the configuration keys that the project's program reads, by name, so that a
+	// stack's configuration can be validated before the program is run.
+	ConfigSchema map[string]ProjectConfigType `json:"configSchema,omitempty" yaml:"configSchema,omitempty"`
+
+	// Slices optionally names subsets of the project's stacks' resources, so that an operation may be restricted to
+	// the resources that a team owns using the '--slice' flag.
+	Slices map[string]ProjectSlice `json:"slices,omitempty" yaml:"slices,omitempty"`
+
 	// Template is an optional template manifest, if this project is a template.
 	Template *ProjectTemplate `json:"template,omitempty" yaml:"template,omitempty"`
 
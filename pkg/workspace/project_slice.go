@@ -0,0 +1,29 @@
+// Copyright 2016-2019, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+// ProjectSlice declares a named subset of a stack's resources. A resource is in the slice if it matches any of the
+// slice's rules, or if its parent is in the slice.
+type ProjectSlice struct {
+	// Description is an optional description of the slice.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Resources lists the patterns of the URNs of the resources in the slice, in which '*' matches any number of
+	// characters and '?' matches a single character.
+	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// Modules lists the modules, such as 'aws:s3', whose resources, and whose submodules' resources, are in the slice.
+	Modules []string `json:"modules,omitempty" yaml:"modules,omitempty"`
+	// Tags are the tags that a resource's 'tags' input must hold for the resource to be in the slice.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/user"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -383,11 +382,7 @@ func GetTemplateDir() (string, error) {
 
 	// Use the classic template directory if there is no override.
 	if dir == "" {
-		u, err := user.Current()
-		if u == nil || err != nil {
-			return "", errors.Wrap(err, "getting user home directory")
-		}
-		dir = filepath.Join(u.HomeDir, BookkeepingDir, TemplateDir)
+		return GetPulumiPath(TemplateDir)
 	}
 
 	return dir, nil
@@ -295,6 +295,12 @@ func (ctx *Context) RegisterResource(
 			DeleteBeforeReplace:  inputs.deleteBeforeReplace,
 			ImportId:             inputs.importID,
 			CustomTimeouts:       inputs.customTimeouts,
+			Priority:             inputs.priority,
+			RetryAttempts:        inputs.retryPolicy.attempts,
+			RetryDelay:           inputs.retryPolicy.delay,
+			RetryBackoff:         inputs.retryPolicy.backoff,
+			AutoName:             inputs.autoName,
+			ReplaceOnChanges:     inputs.replaceOnChanges,
 		})
 		if err != nil {
 			logging.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
@@ -416,6 +422,17 @@ type resourceInputs struct {
 	deleteBeforeReplace bool
 	importID            string
 	customTimeouts      *pulumirpc.RegisterResourceRequest_CustomTimeouts
+	priority            int32
+	retryPolicy         retryPolicy
+	autoName            string
+	replaceOnChanges    []string
+}
+
+// retryPolicy is the retry policy of a resource, in the form in which it is sent to the engine.
+type retryPolicy struct {
+	attempts int32
+	delay    float64
+	backoff  float64
 }
 
 // prepareResourceInputs prepares the inputs for a resource operation, shared between read and register.
@@ -474,6 +491,10 @@ func (ctx *Context) prepareResourceInputs(props map[string]interface{}, opts ...
 		deleteBeforeReplace: deleteBeforeReplace,
 		importID:            string(importID),
 		customTimeouts:      timeouts,
+		priority:            ctx.getPriority(opts...),
+		retryPolicy:         ctx.getRetryPolicy(opts...),
+		autoName:            ctx.getAutoName(opts...),
+		replaceOnChanges:    ctx.getReplaceOnChanges(opts...),
 	}, nil
 }
 
@@ -496,6 +517,54 @@ func (ctx *Context) getTimeouts(opts ...ResourceOpt) *pulumirpc.RegisterResource
 	return &timeouts
 }
 
+// getPriority returns the priority of a resource from an array of options, the last of which takes precedence.
+func (ctx *Context) getPriority(opts ...ResourceOpt) int32 {
+	var priority int32
+	for _, opt := range opts {
+		if opt.Priority != 0 {
+			priority = int32(opt.Priority)
+		}
+	}
+	return priority
+}
+
+// getRetryPolicy returns the retry policy of a resource from an array of options, the last of which takes precedence.
+func (ctx *Context) getRetryPolicy(opts ...ResourceOpt) retryPolicy {
+	var policy retryPolicy
+	for _, opt := range opts {
+		if opt.RetryPolicy != nil {
+			policy = retryPolicy{
+				attempts: int32(opt.RetryPolicy.Attempts),
+				delay:    opt.RetryPolicy.Delay,
+				backoff:  opt.RetryPolicy.Backoff,
+			}
+		}
+	}
+	return policy
+}
+
+// getAutoName returns the auto-named property of a resource from an array of options, the last of which takes
+// precedence.
+func (ctx *Context) getAutoName(opts ...ResourceOpt) string {
+	var autoName string
+	for _, opt := range opts {
+		if opt.AutoName != "" {
+			autoName = opt.AutoName
+		}
+	}
+	return autoName
+}
+
+// getReplaceOnChanges returns the property paths whose changes require a resource's replacement from an array of
+// options, which are combined.
+func (ctx *Context) getReplaceOnChanges(opts ...ResourceOpt) []string {
+	var paths []string
+	for _, opt := range opts {
+		paths = append(paths, opt.ReplaceOnChanges...)
+	}
+	return paths
+}
+
 // getOpts returns a set of resource options from an array of them. This includes the parent URN, any dependency URNs,
 // a boolean indicating whether the resource is to be protected, and the URN and ID of the resource's provider, if any.
 func (ctx *Context) getOpts(opts ...ResourceOpt) (URN, []URN, bool, string, bool, ID, error) {
@@ -69,6 +69,19 @@ type ResourceOpt struct {
 	Import ID
 	// CustomTimeouts is an optional configuration block used for CRUD operations
 	CustomTimeouts *CustomTimeouts
+	// Priority orders this resource's operations among those of resources that do not depend on one another; higher
+	// priorities are performed first. It never overrides a dependency.
+	Priority int
+	// RetryPolicy controls how this resource's operations that fail transiently are retried, in place of the engine's
+	// default policy.
+	RetryPolicy *RetryPolicy
+	// AutoName is the name of an input property that the engine fills with a unique name, the resource's name followed
+	// by a random suffix, if the property is not set. The generated name is kept in the stack's state, so it does not
+	// change on later updates.
+	AutoName string
+	// ReplaceOnChanges lists the paths of the input properties whose changes require this resource to be replaced,
+	// rather than updated in place, even if its provider could update them in place.
+	ReplaceOnChanges []string
 }
 
 // InvokeOpt contains optional settings that control an invoke's behavior.
@@ -82,3 +95,14 @@ type CustomTimeouts struct {
 	Update string
 	Delete string
 }
+
+// RetryPolicy controls how a resource's operations that fail transiently are retried. A zero field takes its value
+// from the engine's default policy.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times to attempt each operation, including the first attempt.
+	Attempts int
+	// Delay is the number of seconds to wait before the first retry of an operation.
+	Delay float64
+	// Backoff is the factor by which the delay between retries of an operation grows after each retry.
+	Backoff float64
+}
@@ -300,7 +300,17 @@ proto.pulumirpc.ConfigureResponse.prototype.toObject = function(opt_includeInsta
  */
 proto.pulumirpc.ConfigureResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    acceptsecrets: jspb.Message.getFieldWithDefault(msg, 1, false)
+    acceptsecrets: jspb.Message.getFieldWithDefault(msg, 1, false),
+    supportsnormalizeinputs: jspb.Message.getFieldWithDefault(msg, 2, false),
+    supportsdeprecations: jspb.Message.getFieldWithDefault(msg, 3, false),
+    supportsnoncomparableproperties: jspb.Message.getFieldWithDefault(msg, 4, false),
+    supportsarraykeys: jspb.Message.getFieldWithDefault(msg, 5, false),
+    supportspolloperation: jspb.Message.getFieldWithDefault(msg, 6, false),
+    supportspreviewoperation: jspb.Message.getFieldWithDefault(msg, 7, false),
+    supportsbatchcreate: jspb.Message.getFieldWithDefault(msg, 8, false),
+    supportsresourcedependencies: jspb.Message.getFieldWithDefault(msg, 9, false),
+    supportscreateandupdateretries: jspb.Message.getFieldWithDefault(msg, 10, false),
+    supportsdryrun: jspb.Message.getFieldWithDefault(msg, 11, false)
   };
 
   if (includeInstance) {
@@ -341,6 +351,46 @@ proto.pulumirpc.ConfigureResponse.deserializeBinaryFromReader = function(msg, re
       var value = /** @type {boolean} */ (reader.readBool());
       msg.setAcceptsecrets(value);
       break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsnormalizeinputs(value);
+      break;
+    case 3:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsdeprecations(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsnoncomparableproperties(value);
+      break;
+    case 5:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsarraykeys(value);
+      break;
+    case 6:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportspolloperation(value);
+      break;
+    case 7:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportspreviewoperation(value);
+      break;
+    case 8:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsbatchcreate(value);
+      break;
+    case 9:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsresourcedependencies(value);
+      break;
+    case 10:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportscreateandupdateretries(value);
+      break;
+    case 11:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setSupportsdryrun(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -377,6 +427,76 @@ proto.pulumirpc.ConfigureResponse.serializeBinaryToWriter = function(message, wr
       f
     );
   }
+  f = message.getSupportsnormalizeinputs();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getSupportsdeprecations();
+  if (f) {
+    writer.writeBool(
+      3,
+      f
+    );
+  }
+  f = message.getSupportsnoncomparableproperties();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
+  f = message.getSupportsarraykeys();
+  if (f) {
+    writer.writeBool(
+      5,
+      f
+    );
+  }
+  f = message.getSupportspolloperation();
+  if (f) {
+    writer.writeBool(
+      6,
+      f
+    );
+  }
+  f = message.getSupportspreviewoperation();
+  if (f) {
+    writer.writeBool(
+      7,
+      f
+    );
+  }
+  f = message.getSupportsbatchcreate();
+  if (f) {
+    writer.writeBool(
+      8,
+      f
+    );
+  }
+  f = message.getSupportsresourcedependencies();
+  if (f) {
+    writer.writeBool(
+      9,
+      f
+    );
+  }
+  f = message.getSupportscreateandupdateretries();
+  if (f) {
+    writer.writeBool(
+      10,
+      f
+    );
+  }
+  f = message.getSupportsdryrun();
+  if (f) {
+    writer.writeBool(
+      11,
+      f
+    );
+  }
 };
 
 
@@ -397,6 +517,176 @@ proto.pulumirpc.ConfigureResponse.prototype.setAcceptsecrets = function(value) {
 };
 
 
+/**
+ * optional bool supportsNormalizeInputs = 2;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsnormalizeinputs = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsnormalizeinputs = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * optional bool supportsDeprecations = 3;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsdeprecations = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 3, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsdeprecations = function(value) {
+  jspb.Message.setProto3BooleanField(this, 3, value);
+};
+
+
+/**
+ * optional bool supportsNonComparableProperties = 4;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsnoncomparableproperties = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsnoncomparableproperties = function(value) {
+  jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+/**
+ * optional bool supportsArrayKeys = 5;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsarraykeys = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 5, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsarraykeys = function(value) {
+  jspb.Message.setProto3BooleanField(this, 5, value);
+};
+
+
+/**
+ * optional bool supportsPollOperation = 6;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportspolloperation = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 6, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportspolloperation = function(value) {
+  jspb.Message.setProto3BooleanField(this, 6, value);
+};
+
+
+/**
+ * optional bool supportsPreviewOperation = 7;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportspreviewoperation = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 7, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportspreviewoperation = function(value) {
+  jspb.Message.setProto3BooleanField(this, 7, value);
+};
+
+
+/**
+ * optional bool supportsBatchCreate = 8;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsbatchcreate = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 8, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsbatchcreate = function(value) {
+  jspb.Message.setProto3BooleanField(this, 8, value);
+};
+
+
+/**
+ * optional bool supportsResourceDependencies = 9;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsresourcedependencies = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 9, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsresourcedependencies = function(value) {
+  jspb.Message.setProto3BooleanField(this, 9, value);
+};
+
+
+/**
+ * optional bool supportsCreateAndUpdateRetries = 10;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportscreateandupdateretries = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 10, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportscreateandupdateretries = function(value) {
+  jspb.Message.setProto3BooleanField(this, 10, value);
+};
+
+
+/**
+ * optional bool supportsDryRun = 11;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConfigureResponse.prototype.getSupportsdryrun = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 11, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.ConfigureResponse.prototype.setSupportsdryrun = function(value) {
+  jspb.Message.setProto3BooleanField(this, 11, value);
+};
+
+
 
 /**
  * Generated by JsPbCodeGenerator.
@@ -998,7 +998,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<number>}
  * @const
  */
-proto.pulumirpc.RegisterResourceRequest.repeatedFields_ = [7,12,14,15];
+proto.pulumirpc.RegisterResourceRequest.repeatedFields_ = [7,12,14,15,23];
 
 
 
@@ -1045,7 +1045,13 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     additionalsecretoutputsList: jspb.Message.getRepeatedField(msg, 14),
     aliasesList: jspb.Message.getRepeatedField(msg, 15),
     importid: jspb.Message.getFieldWithDefault(msg, 16, ""),
-    customtimeouts: (f = msg.getCustomtimeouts()) && proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(includeInstance, f)
+    customtimeouts: (f = msg.getCustomtimeouts()) && proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(includeInstance, f),
+    priority: jspb.Message.getFieldWithDefault(msg, 18, 0),
+    retryattempts: jspb.Message.getFieldWithDefault(msg, 19, 0),
+    retrydelay: +jspb.Message.getFieldWithDefault(msg, 20, 0.0),
+    retrybackoff: +jspb.Message.getFieldWithDefault(msg, 21, 0.0),
+    autoname: jspb.Message.getFieldWithDefault(msg, 22, ""),
+    replaceonchangesList: jspb.Message.getRepeatedField(msg, 23)
   };
 
   if (includeInstance) {
@@ -1154,6 +1160,30 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader);
       msg.setCustomtimeouts(value);
       break;
+    case 18:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setPriority(value);
+      break;
+    case 19:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setRetryattempts(value);
+      break;
+    case 20:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setRetrydelay(value);
+      break;
+    case 21:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setRetrybackoff(value);
+      break;
+    case 22:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAutoname(value);
+      break;
+    case 23:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addReplaceonchanges(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1301,6 +1331,48 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter
     );
   }
+  f = message.getPriority();
+  if (f !== 0) {
+    writer.writeInt32(
+      18,
+      f
+    );
+  }
+  f = message.getRetryattempts();
+  if (f !== 0) {
+    writer.writeInt32(
+      19,
+      f
+    );
+  }
+  f = message.getRetrydelay();
+  if (f !== 0.0) {
+    writer.writeDouble(
+      20,
+      f
+    );
+  }
+  f = message.getRetrybackoff();
+  if (f !== 0.0) {
+    writer.writeDouble(
+      21,
+      f
+    );
+  }
+  f = message.getAutoname();
+  if (f.length > 0) {
+    writer.writeString(
+      22,
+      f
+    );
+  }
+  f = message.getReplaceonchangesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      23,
+      f
+    );
+  }
 };
 
 
@@ -2015,6 +2087,110 @@ proto.pulumirpc.RegisterResourceRequest.prototype.hasCustomtimeouts = function()
 };
 
 
+/**
+ * optional int32 priority = 18;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getPriority = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 18, 0));
+};
+
+
+/** @param {number} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setPriority = function(value) {
+  jspb.Message.setProto3IntField(this, 18, value);
+};
+
+
+/**
+ * optional int32 retryAttempts = 19;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetryattempts = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 19, 0));
+};
+
+
+/** @param {number} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetryattempts = function(value) {
+  jspb.Message.setProto3IntField(this, 19, value);
+};
+
+
+/**
+ * optional double retryDelay = 20;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetrydelay = function() {
+  return /** @type {number} */ (+jspb.Message.getFieldWithDefault(this, 20, 0.0));
+};
+
+
+/** @param {number} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetrydelay = function(value) {
+  jspb.Message.setProto3FloatField(this, 20, value);
+};
+
+
+/**
+ * optional double retryBackoff = 21;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetrybackoff = function() {
+  return /** @type {number} */ (+jspb.Message.getFieldWithDefault(this, 21, 0.0));
+};
+
+
+/** @param {number} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetrybackoff = function(value) {
+  jspb.Message.setProto3FloatField(this, 21, value);
+};
+
+
+/**
+ * optional string autoName = 22;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getAutoname = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 22, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setAutoname = function(value) {
+  jspb.Message.setProto3StringField(this, 22, value);
+};
+
+
+/**
+ * repeated string replaceOnChanges = 23;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getReplaceonchangesList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 23));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setReplaceonchangesList = function(value) {
+  jspb.Message.setField(this, 23, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addReplaceonchanges = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 23, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearReplaceonchangesList = function() {
+  this.setReplaceonchangesList([]);
+};
+
+
 
 /**
  * Generated by JsPbCodeGenerator.
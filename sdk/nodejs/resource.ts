@@ -444,6 +444,11 @@ export interface ResourceOptions {
      * Ignore changes to any of the specified properties.
      */
     ignoreChanges?: string[];
+    /**
+     * Replace this resource, rather than updating it in place, when any of the specified properties change, even if
+     * its provider could update them in place.
+     */
+    replaceOnChanges?: string[];
     /**
      * An optional version, corresponding to the version of the provider plugin that should be used when operating on
      * this resource. This version overrides the version information inferred from the current package and should
@@ -466,6 +471,16 @@ export interface ResourceOptions {
      * An optional customTimeouts configuration block.
      */
     customTimeouts?: CustomTimeouts;
+    /**
+     * An optional priority that orders this resource's operations among those of resources that do not depend on one
+     * another. Resources with higher priorities are operated upon first. A priority never overrides a dependency.
+     */
+    priority?: number;
+    /**
+     * An optional retryPolicy configuration block, which controls how operations on this resource that fail
+     * transiently are retried, in place of the engine's default policy.
+     */
+    retryPolicy?: RetryPolicy;
 
     // !!! IMPORTANT !!! If you add a new field to this type, make sure to add test that verifies
     // that mergeOptions works properly for it.
@@ -486,6 +501,21 @@ export interface CustomTimeouts {
     delete?: string;
 }
 
+export interface RetryPolicy {
+    /**
+     * The optional maximum number of times to attempt each operation, including the first attempt.
+     */
+    attempts?: number;
+    /**
+     * The optional number of seconds to wait before the first retry of an operation.
+     */
+    delay?: number;
+    /**
+     * The optional factor by which the delay between retries of an operation grows after each retry.
+     */
+    backoff?: number;
+}
+
 /**
  * CustomResourceOptions is a bag of optional settings that control a custom resource's behavior.
  */
@@ -514,6 +544,13 @@ export interface CustomResourceOptions extends ResourceOptions {
      */
     import?: ID;
 
+    /**
+     * The name of an input property to fill with a unique name if it is not set: the resource's name followed by a
+     * random suffix. The name is generated when the resource is created and recorded in the stack's state, so it does
+     * not change on later updates or refreshes. Setting the property explicitly overrides the generated name.
+     */
+    autoName?: string;
+
     // !!! IMPORTANT !!! If you add a new field to this type, make sure to add test that verifies
     // that mergeOptions works properly for it.
 }
@@ -191,6 +191,8 @@ export function registerResource(res: Resource, t: string, name: string, custom:
         req.setAdditionalsecretoutputsList((<any>opts).additionalSecretOutputs || []);
         req.setAliasesList(resop.aliases);
         req.setImportid(resop.import || "");
+        req.setAutoname((<any>opts).autoName || "");
+        req.setReplaceonchangesList(opts.replaceOnChanges || []);
 
         const customTimeouts = new resproto.RegisterResourceRequest.CustomTimeouts();
         if (opts.customTimeouts != null) {
@@ -199,6 +201,12 @@ export function registerResource(res: Resource, t: string, name: string, custom:
             customTimeouts.setDelete(opts.customTimeouts.delete);
         }
         req.setCustomtimeouts(customTimeouts);
+        req.setPriority(opts.priority || 0);
+        if (opts.retryPolicy) {
+            req.setRetryattempts(opts.retryPolicy.attempts || 0);
+            req.setRetrydelay(opts.retryPolicy.delay || 0);
+            req.setRetrybackoff(opts.retryPolicy.backoff || 0);
+        }
 
         const propertyDependencies = req.getPropertydependenciesMap();
         for (const [key, resourceURNs] of resop.propertyToDirectDependencyURNs) {
@@ -141,10 +141,20 @@ func (m *ConfigureRequest) GetAcceptSecrets() bool {
 }
 
 type ConfigureResponse struct {
-	AcceptSecrets        bool     `protobuf:"varint,1,opt,name=acceptSecrets" json:"acceptSecrets,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	AcceptSecrets                   bool     `protobuf:"varint,1,opt,name=acceptSecrets" json:"acceptSecrets,omitempty"`
+	SupportsNormalizeInputs         bool     `protobuf:"varint,2,opt,name=supportsNormalizeInputs" json:"supportsNormalizeInputs,omitempty"`
+	SupportsDeprecations            bool     `protobuf:"varint,3,opt,name=supportsDeprecations" json:"supportsDeprecations,omitempty"`
+	SupportsNonComparableProperties bool     `protobuf:"varint,4,opt,name=supportsNonComparableProperties" json:"supportsNonComparableProperties,omitempty"`
+	SupportsArrayKeys               bool     `protobuf:"varint,5,opt,name=supportsArrayKeys" json:"supportsArrayKeys,omitempty"`
+	SupportsPollOperation           bool     `protobuf:"varint,6,opt,name=supportsPollOperation" json:"supportsPollOperation,omitempty"`
+	SupportsPreviewOperation        bool     `protobuf:"varint,7,opt,name=supportsPreviewOperation" json:"supportsPreviewOperation,omitempty"`
+	SupportsBatchCreate             bool     `protobuf:"varint,8,opt,name=supportsBatchCreate" json:"supportsBatchCreate,omitempty"`
+	SupportsResourceDependencies    bool     `protobuf:"varint,9,opt,name=supportsResourceDependencies" json:"supportsResourceDependencies,omitempty"`
+	SupportsCreateAndUpdateRetries  bool     `protobuf:"varint,10,opt,name=supportsCreateAndUpdateRetries" json:"supportsCreateAndUpdateRetries,omitempty"`
+	SupportsDryRun                  bool     `protobuf:"varint,11,opt,name=supportsDryRun" json:"supportsDryRun,omitempty"`
+	XXX_NoUnkeyedLiteral            struct{} `json:"-"`
+	XXX_unrecognized                []byte   `json:"-"`
+	XXX_sizecache                   int32    `json:"-"`
 }
 
 func (m *ConfigureResponse) Reset()         { *m = ConfigureResponse{} }
@@ -178,6 +188,76 @@ func (m *ConfigureResponse) GetAcceptSecrets() bool {
 	return false
 }
 
+func (m *ConfigureResponse) GetSupportsNormalizeInputs() bool {
+	if m != nil {
+		return m.SupportsNormalizeInputs
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsDeprecations() bool {
+	if m != nil {
+		return m.SupportsDeprecations
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsNonComparableProperties() bool {
+	if m != nil {
+		return m.SupportsNonComparableProperties
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsArrayKeys() bool {
+	if m != nil {
+		return m.SupportsArrayKeys
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsPollOperation() bool {
+	if m != nil {
+		return m.SupportsPollOperation
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsPreviewOperation() bool {
+	if m != nil {
+		return m.SupportsPreviewOperation
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsBatchCreate() bool {
+	if m != nil {
+		return m.SupportsBatchCreate
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsResourceDependencies() bool {
+	if m != nil {
+		return m.SupportsResourceDependencies
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsCreateAndUpdateRetries() bool {
+	if m != nil {
+		return m.SupportsCreateAndUpdateRetries
+	}
+	return false
+}
+
+func (m *ConfigureResponse) GetSupportsDryRun() bool {
+	if m != nil {
+		return m.SupportsDryRun
+	}
+	return false
+}
+
 // ConfigureErrorMissingKeys is sent as a Detail on an error returned from `ResourceProvider.Configure`.
 type ConfigureErrorMissingKeys struct {
 	MissingKeys          []*ConfigureErrorMissingKeys_MissingKey `protobuf:"bytes,1,rep,name=missingKeys" json:"missingKeys,omitempty"`
@@ -1708,80 +1788,92 @@ var _ResourceProvider_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("provider.proto", fileDescriptor_provider_31a5eda36dbe30f0) }
 
 var fileDescriptor_provider_31a5eda36dbe30f0 = []byte{
-	// 1196 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x72, 0xe3, 0x44,
-	0x10, 0x5e, 0xd9, 0x8a, 0x13, 0xb7, 0x1d, 0xaf, 0x77, 0x80, 0xc4, 0xab, 0xcd, 0x21, 0x25, 0x38,
-	0x04, 0x28, 0x1c, 0x2a, 0x7b, 0x80, 0xdd, 0xda, 0x2d, 0x48, 0x62, 0x07, 0x52, 0xd9, 0xfc, 0xa0,
-	0x10, 0x7e, 0x4e, 0x8b, 0x22, 0x8d, 0x1d, 0x55, 0x14, 0x49, 0x48, 0x23, 0x53, 0xe1, 0xcc, 0x81,
-	0x07, 0xe0, 0xc2, 0x43, 0x50, 0x54, 0xf1, 0x04, 0xdc, 0x79, 0x06, 0x1e, 0x81, 0x77, 0x60, 0xfe,
-	0x24, 0xcf, 0xc4, 0x4e, 0xd6, 0x49, 0x6d, 0xc1, 0x6d, 0x5a, 0xdd, 0xd3, 0x3f, 0xdf, 0x74, 0x7f,
-	0x33, 0x82, 0x56, 0x92, 0xc6, 0xa3, 0xc0, 0xc7, 0x69, 0x97, 0x2e, 0x48, 0x8c, 0xea, 0x49, 0x1e,
-	0xe6, 0x17, 0x41, 0x9a, 0x78, 0x56, 0x33, 0x09, 0xf3, 0x61, 0x10, 0x09, 0x85, 0xf5, 0x68, 0x18,
-	0xc7, 0xc3, 0x10, 0xaf, 0x73, 0xe9, 0x34, 0x1f, 0xac, 0xe3, 0x8b, 0x84, 0x5c, 0x4a, 0xe5, 0xca,
-	0x55, 0x65, 0x46, 0xd2, 0xdc, 0x23, 0x42, 0x6b, 0xff, 0x63, 0x40, 0x7b, 0x3b, 0x8e, 0x06, 0xc1,
-	0x30, 0x4f, 0xb1, 0x83, 0xbf, 0xcf, 0x71, 0x46, 0xd0, 0xe7, 0x50, 0x1f, 0xb9, 0x69, 0xe0, 0x9e,
-	0x86, 0x38, 0xeb, 0x18, 0xab, 0xd5, 0xb5, 0xc6, 0xc6, 0x7b, 0xdd, 0x32, 0x78, 0xf7, 0xaa, 0x7d,
-	0xf7, 0xab, 0xc2, 0xb8, 0x1f, 0x91, 0xf4, 0xd2, 0x19, 0x6f, 0x46, 0xef, 0x83, 0xe9, 0xa6, 0xc3,
-	0xac, 0x53, 0x59, 0x35, 0xa8, 0x93, 0xe5, 0xae, 0xc8, 0xa5, 0x5b, 0xe4, 0xd2, 0x3d, 0xe6, 0xb9,
-	0x38, 0xdc, 0x08, 0xbd, 0x03, 0x8b, 0xae, 0xe7, 0xe1, 0x84, 0x1c, 0x63, 0x2f, 0xc5, 0x24, 0xeb,
-	0x54, 0xe9, 0xae, 0x05, 0x47, 0xff, 0x68, 0x3d, 0x83, 0x96, 0x1e, 0x0f, 0xb5, 0xa1, 0x7a, 0x8e,
-	0x2f, 0x69, 0xa2, 0xc6, 0x5a, 0xdd, 0x61, 0x4b, 0xf4, 0x26, 0xcc, 0x8d, 0xdc, 0x30, 0xc7, 0x3c,
-	0x6e, 0xdd, 0x11, 0xc2, 0xd3, 0xca, 0xc7, 0x86, 0xfd, 0x04, 0x1e, 0x28, 0xe9, 0x67, 0x49, 0x1c,
-	0x65, 0x78, 0x32, 0xb0, 0x31, 0x25, 0xb0, 0xfd, 0x87, 0x01, 0x0f, 0xcb, 0xbd, 0xfd, 0x34, 0x8d,
-	0xd3, 0xfd, 0x20, 0xcb, 0x82, 0x68, 0xb8, 0x87, 0x2f, 0x33, 0xf4, 0x05, 0x34, 0x2e, 0xc6, 0xa2,
-	0x44, 0x6d, 0x7d, 0x1a, 0x6a, 0x57, 0xb7, 0x76, 0xc7, 0x6b, 0x47, 0xf5, 0x61, 0x6d, 0x01, 0x8c,
-	0x55, 0x08, 0x81, 0x19, 0xb9, 0x17, 0x58, 0x96, 0xc9, 0xd7, 0x68, 0x15, 0x1a, 0x3e, 0xce, 0xbc,
-	0x34, 0x48, 0x48, 0x10, 0x47, 0xb2, 0x5a, 0xf5, 0x93, 0xfd, 0x93, 0x01, 0x8b, 0xbb, 0xd1, 0x28,
-	0x3e, 0x2f, 0x0f, 0x97, 0xa2, 0x45, 0xe2, 0xf3, 0x02, 0x2d, 0xba, 0xbc, 0xdd, 0x21, 0x59, 0xb0,
-	0x50, 0xb4, 0x25, 0x3f, 0x9f, 0xba, 0x53, 0xca, 0xa8, 0x03, 0xf3, 0x23, 0x9c, 0x66, 0x2c, 0x15,
-	0x93, 0xab, 0x0a, 0xd1, 0x1e, 0x41, 0xab, 0xc8, 0x42, 0x62, 0xbe, 0x0e, 0x35, 0x8a, 0x6a, 0x9e,
-	0x46, 0x3c, 0x93, 0x1b, 0xc2, 0x4a, 0x33, 0xf4, 0x18, 0x16, 0x06, 0x6e, 0x10, 0x52, 0x00, 0x59,
-	0xa6, 0x55, 0xbe, 0x45, 0x41, 0xf7, 0x0c, 0x7b, 0xe7, 0x3b, 0x42, 0xef, 0x94, 0x86, 0xf6, 0x8f,
-	0xd0, 0xe4, 0x1a, 0xa5, 0xf8, 0x22, 0x24, 0x2d, 0x9e, 0xb9, 0xa5, 0xc5, 0xc7, 0xa1, 0xff, 0xea,
-	0xe2, 0x99, 0x11, 0x33, 0x8e, 0xf0, 0x0f, 0xa2, 0x31, 0x6f, 0x32, 0x66, 0x46, 0x76, 0x0e, 0x8b,
-	0x32, 0xf6, 0xb8, 0xe4, 0x20, 0x4a, 0x72, 0xd9, 0x5f, 0x37, 0x95, 0x2c, 0xcc, 0xee, 0x56, 0xf2,
-	0x96, 0x2c, 0x59, 0x6a, 0xe4, 0x81, 0x25, 0x38, 0x25, 0xc5, 0x88, 0x94, 0x32, 0x5a, 0x62, 0x87,
-	0xe0, 0x66, 0x65, 0xeb, 0x48, 0xc9, 0xfe, 0xdd, 0x80, 0x46, 0x2f, 0x18, 0x0c, 0x0a, 0xd8, 0x5a,
-	0x50, 0x09, 0x7c, 0xb9, 0x9b, 0xae, 0x0a, 0x18, 0x2b, 0x93, 0x30, 0x56, 0x6f, 0x03, 0xa3, 0x39,
-	0x03, 0x8c, 0x6c, 0x38, 0x83, 0x61, 0x14, 0xa7, 0x78, 0xfb, 0xcc, 0x8d, 0x86, 0x14, 0x89, 0x39,
-	0x8a, 0x44, 0xdd, 0xd1, 0x3f, 0xda, 0x7f, 0x1a, 0xd0, 0x3c, 0x92, 0x65, 0xb1, 0xcc, 0xd1, 0x87,
-	0x60, 0x9e, 0x07, 0x91, 0x48, 0xba, 0xb5, 0xb1, 0xa2, 0xe0, 0xa6, 0x9a, 0x75, 0xf7, 0xa8, 0x8d,
-	0xc3, 0x2d, 0xd1, 0x0a, 0xd4, 0x39, 0xee, 0xec, 0x3b, 0x2f, 0x6d, 0xc1, 0x19, 0x7f, 0xb0, 0xbf,
-	0x03, 0x93, 0xd9, 0xa2, 0x79, 0xa8, 0x6e, 0xf6, 0x7a, 0xed, 0x7b, 0xe8, 0x3e, 0x34, 0xe8, 0xe2,
-	0xa5, 0xd3, 0x3f, 0x7a, 0xb1, 0xb9, 0xdd, 0x6f, 0x1b, 0x08, 0xa0, 0xd6, 0xeb, 0xbf, 0xe8, 0x7f,
-	0xd9, 0x6f, 0x57, 0xe8, 0xb0, 0xb6, 0xc4, 0xba, 0xd4, 0x57, 0x99, 0xfe, 0xe4, 0xa8, 0xb7, 0x49,
-	0xf5, 0x26, 0xd3, 0x8b, 0x75, 0xa9, 0x9f, 0xb3, 0xff, 0xae, 0x42, 0x53, 0x80, 0x2e, 0xfb, 0x85,
-	0x9e, 0x5c, 0x8a, 0x93, 0xd0, 0xf5, 0x24, 0x0b, 0xd3, 0x93, 0x2b, 0x64, 0x36, 0x6a, 0x19, 0x11,
-	0x04, 0x5d, 0xe1, 0xaa, 0x42, 0xa4, 0x85, 0xbf, 0xe1, 0xe3, 0x10, 0x13, 0xbc, 0x85, 0x07, 0x31,
-	0x23, 0x39, 0xbe, 0x43, 0x72, 0xe9, 0x34, 0x15, 0x7a, 0x0e, 0xf3, 0x9e, 0xc4, 0xd6, 0xe4, 0x68,
-	0xbd, 0xad, 0xa0, 0xa5, 0x66, 0xc4, 0x05, 0x89, 0xb8, 0x53, 0xec, 0x61, 0x64, 0xeb, 0xd3, 0xef,
-	0xc5, 0xc1, 0x08, 0x01, 0xed, 0x43, 0xd3, 0xc7, 0x84, 0xf6, 0x20, 0xf6, 0x39, 0xa0, 0x35, 0xde,
-	0xbf, 0xef, 0x5e, 0xeb, 0x59, 0xb1, 0x15, 0xb7, 0x88, 0xb6, 0x1d, 0xad, 0xc1, 0xfd, 0x33, 0x37,
-	0x53, 0xad, 0x3a, 0xf3, 0xbc, 0xa2, 0xab, 0x9f, 0xad, 0x6f, 0xe0, 0xc1, 0x84, 0xb3, 0x29, 0x57,
-	0xc4, 0x07, 0xea, 0x15, 0xa1, 0x0f, 0x96, 0xda, 0x20, 0xea, 0xdd, 0xf1, 0x5c, 0x0c, 0x85, 0x04,
-	0x80, 0xfa, 0x6c, 0xf6, 0x76, 0x77, 0x76, 0x5e, 0x9e, 0x1c, 0xec, 0x1d, 0x1c, 0x7e, 0x7d, 0x40,
-	0x5b, 0x62, 0x11, 0xea, 0xfc, 0xcb, 0xc1, 0xe1, 0x01, 0x6b, 0x88, 0x42, 0x3c, 0x3e, 0xdc, 0xa7,
-	0x3d, 0x61, 0x13, 0xca, 0x07, 0x74, 0xbe, 0x08, 0xbe, 0x9e, 0x8c, 0x3e, 0x02, 0x90, 0xb3, 0x19,
-	0xe0, 0x57, 0x52, 0x92, 0x62, 0xca, 0xda, 0x81, 0x04, 0x17, 0x38, 0xce, 0x09, 0x3f, 0x68, 0xc3,
-	0x29, 0x44, 0xfb, 0x5b, 0x68, 0x15, 0x51, 0x65, 0x5b, 0x5d, 0x1d, 0xe6, 0xbb, 0x06, 0xb5, 0x7f,
-	0xa5, 0x2c, 0xe1, 0x60, 0xd7, 0x9f, 0x9d, 0x25, 0xf4, 0x50, 0xd5, 0xd9, 0xeb, 0x1b, 0x53, 0xa7,
-	0x39, 0x13, 0x75, 0xda, 0x3f, 0x53, 0x3e, 0x10, 0xb9, 0xbd, 0xe6, 0xaa, 0x95, 0x54, 0xaa, 0xb3,
-	0xa5, 0xf2, 0x17, 0xbd, 0x82, 0x4f, 0x12, 0x5f, 0x39, 0xf8, 0xff, 0x93, 0x4e, 0x95, 0x4e, 0x99,
-	0xd3, 0x3a, 0x65, 0x92, 0x68, 0x6b, 0xd3, 0x88, 0x76, 0x97, 0x32, 0x97, 0x2c, 0x46, 0x22, 0xab,
-	0x23, 0x69, 0xcc, 0xde, 0x3f, 0xec, 0x6d, 0xd2, 0xe3, 0x7c, 0xf4, 0x1f, 0x74, 0x90, 0x52, 0xb7,
-	0xa9, 0x4f, 0xc8, 0x6f, 0x06, 0x2c, 0xf3, 0x37, 0x19, 0xad, 0x28, 0xce, 0x53, 0x0f, 0xef, 0x46,
-	0x01, 0xd9, 0xe1, 0x04, 0xf2, 0xfa, 0xba, 0x86, 0x86, 0x17, 0x77, 0x2b, 0x4b, 0x9a, 0xf3, 0xb5,
-	0x14, 0x6f, 0xdd, 0xda, 0x1b, 0xbf, 0xd4, 0xa0, 0x5d, 0xa4, 0x7a, 0x54, 0x3c, 0xbd, 0xb6, 0xa0,
-	0xc1, 0x6f, 0x7d, 0xf1, 0xca, 0x44, 0x13, 0xef, 0x04, 0x89, 0xb0, 0xd5, 0x99, 0x54, 0x88, 0x63,
-	0xb4, 0xef, 0xa1, 0x4f, 0x00, 0x38, 0xbf, 0x09, 0x17, 0x4b, 0x13, 0x54, 0x2d, 0x3c, 0x2c, 0x5f,
-	0x43, 0xe1, 0xd4, 0x01, 0xfd, 0x6f, 0x28, 0x5f, 0xb9, 0xe8, 0xd1, 0x0d, 0x7f, 0x0c, 0xd6, 0xca,
-	0x74, 0xa5, 0x92, 0x4a, 0x4d, 0xbc, 0x17, 0x91, 0x9a, 0xb0, 0xf6, 0x90, 0xb5, 0x1e, 0x4e, 0xd1,
-	0x94, 0x0e, 0x9e, 0xc1, 0x1c, 0x2f, 0xef, 0x6e, 0x48, 0x3c, 0x01, 0x93, 0xdf, 0x3a, 0x77, 0xc0,
-	0x80, 0x66, 0x2e, 0xf8, 0x56, 0xcb, 0x5c, 0x23, 0x7e, 0x2d, 0x73, 0x9d, 0x9c, 0x45, 0x6c, 0x46,
-	0x5c, 0x5a, 0x6c, 0x85, 0x65, 0xb5, 0xd8, 0x2a, 0xc3, 0x89, 0xd8, 0x62, 0x36, 0xb5, 0xd8, 0x1a,
-	0xf7, 0x68, 0xb1, 0xf5, 0x41, 0xe6, 0xa8, 0xd5, 0xc4, 0x40, 0x6a, 0x0e, 0xb4, 0x19, 0xb5, 0x96,
-	0x26, 0xfa, 0xb3, 0xcf, 0xfe, 0x36, 0xe9, 0xee, 0xa7, 0xb4, 0x74, 0x37, 0xf2, 0x70, 0x88, 0xae,
-	0xb1, 0xb9, 0x61, 0xef, 0xa7, 0xb0, 0xf8, 0x19, 0x26, 0x47, 0xfc, 0xaf, 0x76, 0x37, 0x1a, 0xc4,
-	0xd7, 0xba, 0x78, 0x4b, 0xbd, 0xa8, 0x4b, 0x73, 0xfb, 0xde, 0x69, 0x8d, 0x1b, 0x3e, 0xfe, 0x37,
-	0x00, 0x00, 0xff, 0xff, 0x37, 0xd1, 0x77, 0xc4, 0x36, 0x0f, 0x00, 0x00,
+	// 1391 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xc4, 0x58, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x36, 0x25, 0x5a, 0xb6, 0x46, 0x3f, 0x51, 0xb6, 0x49, 0x4c, 0x33, 0x46, 0x6b, 0xb0, 0x45,
+	0xe1, 0xfe, 0xc9, 0x81, 0x53, 0xa0, 0x69, 0x90, 0x20, 0xb5, 0x2d, 0xb9, 0x31, 0x92, 0xd8, 0x2e,
+	0x93, 0xf4, 0xe7, 0x94, 0x32, 0xe4, 0x4a, 0x26, 0x4c, 0x91, 0xec, 0x72, 0xa9, 0x40, 0x39, 0xf7,
+	0xd0, 0x07, 0xe8, 0xa5, 0x0f, 0x51, 0x14, 0xe8, 0x13, 0xf4, 0xde, 0x67, 0xe8, 0x23, 0xf4, 0xdc,
+	0x6b, 0xb1, 0xbb, 0x5c, 0x6a, 0x69, 0x49, 0xb6, 0x6c, 0x04, 0xed, 0x8d, 0xbb, 0xdf, 0xcc, 0xce,
+	0xcf, 0xce, 0x7c, 0x3b, 0x12, 0x34, 0x63, 0x12, 0x0d, 0x7d, 0x0f, 0x93, 0x76, 0x4c, 0x22, 0x1a,
+	0xa1, 0x6a, 0x9c, 0x06, 0xe9, 0xc0, 0x27, 0xb1, 0x6b, 0xd6, 0xe3, 0x20, 0xed, 0xfb, 0xa1, 0x00,
+	0xcc, 0x9b, 0xfd, 0x28, 0xea, 0x07, 0x78, 0x93, 0xaf, 0x5e, 0xa6, 0xbd, 0x4d, 0x3c, 0x88, 0xe9,
+	0x28, 0x03, 0xd7, 0x4e, 0x83, 0x09, 0x25, 0xa9, 0x4b, 0x05, 0x6a, 0xfd, 0xad, 0x41, 0x6b, 0x37,
+	0x0a, 0x7b, 0x7e, 0x3f, 0x25, 0xd8, 0xc6, 0x3f, 0xa4, 0x38, 0xa1, 0xe8, 0x21, 0x54, 0x87, 0x0e,
+	0xf1, 0x9d, 0x97, 0x01, 0x4e, 0x0c, 0x6d, 0xbd, 0xbc, 0x51, 0xdb, 0xfa, 0xb0, 0x9d, 0x1b, 0x6f,
+	0x9f, 0x96, 0x6f, 0x7f, 0x2d, 0x85, 0xbb, 0x21, 0x25, 0x23, 0x7b, 0xac, 0x8c, 0x3e, 0x02, 0xdd,
+	0x21, 0xfd, 0xc4, 0x28, 0xad, 0x6b, 0x1b, 0xb5, 0xad, 0x95, 0xb6, 0xf0, 0xa5, 0x2d, 0x7d, 0x69,
+	0x3f, 0xe5, 0xbe, 0xd8, 0x5c, 0x08, 0xbd, 0x07, 0x0d, 0xc7, 0x75, 0x71, 0x4c, 0x9f, 0x62, 0x97,
+	0x60, 0x9a, 0x18, 0xe5, 0x75, 0x6d, 0x63, 0xd9, 0x2e, 0x6e, 0x9a, 0xf7, 0xa0, 0x59, 0xb4, 0x87,
+	0x5a, 0x50, 0x3e, 0xc1, 0x23, 0x43, 0x5b, 0xd7, 0x36, 0xaa, 0x36, 0xfb, 0x44, 0xd7, 0x60, 0x71,
+	0xe8, 0x04, 0x29, 0xe6, 0x76, 0xab, 0xb6, 0x58, 0xdc, 0x2d, 0xdd, 0xd1, 0xac, 0x7f, 0x74, 0xb8,
+	0xaa, 0xf8, 0x9f, 0xc4, 0x51, 0x98, 0xe0, 0x49, 0xcb, 0xda, 0x14, 0xcb, 0xe8, 0x0e, 0xac, 0x24,
+	0x69, 0x1c, 0x47, 0x84, 0x26, 0x07, 0x11, 0x19, 0x38, 0x81, 0xff, 0x1a, 0xef, 0x87, 0x71, 0x4a,
+	0x45, 0x7c, 0xcb, 0xf6, 0x2c, 0x18, 0x6d, 0xc1, 0x35, 0x09, 0x75, 0x70, 0x4c, 0xb0, 0xeb, 0x50,
+	0x3f, 0x0a, 0x65, 0x80, 0x53, 0x31, 0xf4, 0x10, 0xde, 0x19, 0x1f, 0x17, 0xee, 0x46, 0x83, 0xd8,
+	0x21, 0x2c, 0xe8, 0x23, 0x12, 0xc5, 0x98, 0x50, 0x1f, 0x27, 0x86, 0xce, 0xd5, 0xcf, 0x13, 0x43,
+	0x1f, 0xc3, 0x55, 0x29, 0xb2, 0x4d, 0x88, 0x33, 0x7a, 0x84, 0x47, 0x89, 0xb1, 0xc8, 0x75, 0x27,
+	0x01, 0xf4, 0x29, 0x5c, 0x97, 0x9b, 0x47, 0x51, 0x10, 0x1c, 0xc6, 0x98, 0x70, 0x8f, 0x8c, 0x0a,
+	0xd7, 0x98, 0x0e, 0xa2, 0xbb, 0x60, 0xe4, 0x00, 0xc1, 0x43, 0x1f, 0xbf, 0x1a, 0x2b, 0x2e, 0x71,
+	0xc5, 0x99, 0x38, 0xba, 0x05, 0x6f, 0x49, 0x6c, 0xc7, 0xa1, 0xee, 0xf1, 0x2e, 0xc1, 0x0e, 0xc5,
+	0xc6, 0x32, 0x57, 0x9b, 0x06, 0xa1, 0x1d, 0x58, 0x93, 0xdb, 0x36, 0x4e, 0xa2, 0x94, 0xb8, 0xb8,
+	0x83, 0x63, 0x1c, 0x7a, 0x38, 0x74, 0x59, 0x62, 0xaa, 0x5c, 0xf5, 0x4c, 0x19, 0xb4, 0x07, 0x6f,
+	0x4b, 0x5c, 0x9c, 0xba, 0x1d, 0x7a, 0xcf, 0x63, 0xcf, 0xa1, 0xd8, 0xc6, 0x94, 0xb0, 0x53, 0x80,
+	0x9f, 0x72, 0x8e, 0x14, 0x7a, 0x1f, 0x9a, 0xf9, 0xfd, 0x91, 0x91, 0x9d, 0x86, 0x46, 0x8d, 0xeb,
+	0x9d, 0xda, 0xb5, 0x7e, 0xd7, 0x60, 0x35, 0xaf, 0xbc, 0x2e, 0x21, 0x11, 0x79, 0xe2, 0x27, 0x89,
+	0x1f, 0xf6, 0x79, 0xd6, 0xbf, 0x82, 0xda, 0x60, 0xbc, 0xcc, 0x9a, 0x6e, 0x73, 0x5a, 0xd3, 0x9d,
+	0x56, 0x6d, 0x8f, 0xbf, 0x6d, 0xf5, 0x0c, 0x73, 0x07, 0x60, 0x0c, 0x21, 0x04, 0x7a, 0xe8, 0x0c,
+	0x70, 0xd6, 0x25, 0xfc, 0x1b, 0xad, 0x43, 0xcd, 0xc3, 0x89, 0x4b, 0xfc, 0x98, 0xdf, 0x93, 0x68,
+	0x16, 0x75, 0xcb, 0xfa, 0x51, 0x83, 0xc6, 0x7e, 0x38, 0x8c, 0x4e, 0x72, 0x6e, 0x68, 0x41, 0x99,
+	0x46, 0x27, 0xb2, 0xd9, 0x68, 0x74, 0x72, 0xb1, 0x1e, 0x37, 0x61, 0x59, 0xb2, 0x1a, 0xaf, 0xfe,
+	0xaa, 0x9d, 0xaf, 0x91, 0x01, 0x4b, 0x43, 0x4c, 0x12, 0xe6, 0x8a, 0xce, 0x21, 0xb9, 0xb4, 0x86,
+	0xd0, 0x94, 0x5e, 0x64, 0x1d, 0xbb, 0x09, 0x15, 0x82, 0x69, 0x4a, 0x42, 0x43, 0x3b, 0xdb, 0x6c,
+	0x26, 0x86, 0x6e, 0xc3, 0x72, 0xcf, 0xf1, 0x83, 0x94, 0x60, 0xe6, 0x69, 0x99, 0xab, 0x28, 0xd9,
+	0x3d, 0xc6, 0xee, 0xc9, 0x9e, 0xc0, 0xed, 0x5c, 0xd0, 0x7a, 0x0d, 0x75, 0x8e, 0x28, 0xc1, 0x4b,
+	0x93, 0x55, 0x9b, 0x7d, 0xb2, 0xe0, 0xa3, 0xc0, 0x3b, 0x3f, 0x78, 0x26, 0xc4, 0x84, 0x43, 0xfc,
+	0x4a, 0xb4, 0xfd, 0x59, 0xc2, 0x4c, 0xc8, 0x4a, 0xa1, 0x91, 0xd9, 0x1e, 0x87, 0xec, 0x0b, 0xb6,
+	0x39, 0x2f, 0x64, 0x21, 0x76, 0xb9, 0x90, 0x77, 0xa0, 0xae, 0x22, 0xd9, 0x85, 0xc5, 0x98, 0x50,
+	0xc9, 0xb0, 0xf9, 0x1a, 0xdd, 0x60, 0x97, 0xe0, 0x24, 0x79, 0xe9, 0x64, 0x2b, 0xeb, 0x37, 0x0d,
+	0x6a, 0x1d, 0xbf, 0xd7, 0x93, 0x69, 0x6b, 0x42, 0xc9, 0xf7, 0x32, 0xed, 0x92, 0xef, 0xc9, 0x34,
+	0x96, 0x26, 0xd3, 0x58, 0xbe, 0x48, 0x1a, 0xf5, 0x39, 0xd2, 0xc8, 0xa8, 0xdd, 0xef, 0x87, 0x11,
+	0xc1, 0xbb, 0xc7, 0x4e, 0xd8, 0xc7, 0x8c, 0xf8, 0xca, 0x1b, 0x55, 0xbb, 0xb8, 0x69, 0xfd, 0xa1,
+	0x41, 0x3d, 0x63, 0xcc, 0x11, 0xf3, 0x1c, 0xdd, 0x02, 0xfd, 0xc4, 0x0f, 0x85, 0xd3, 0xcd, 0xad,
+	0x35, 0x25, 0x6f, 0xaa, 0x58, 0xfb, 0x91, 0x1f, 0x7a, 0x36, 0x97, 0x44, 0x6b, 0x50, 0xe5, 0x79,
+	0x67, 0xfb, 0xd9, 0x7b, 0x30, 0xde, 0xb0, 0xbe, 0x07, 0x9d, 0xc9, 0xa2, 0x25, 0x28, 0x6f, 0x77,
+	0x3a, 0xad, 0x05, 0x74, 0x05, 0x6a, 0xdb, 0x9d, 0xce, 0x0b, 0xbb, 0x7b, 0xf4, 0x78, 0x7b, 0xb7,
+	0xdb, 0xd2, 0x10, 0x40, 0xa5, 0xd3, 0x7d, 0xdc, 0x7d, 0xd6, 0x6d, 0x95, 0x10, 0x82, 0xa6, 0xf8,
+	0xce, 0xf1, 0x32, 0xc3, 0x9f, 0x1f, 0x75, 0xb6, 0x9f, 0x75, 0x5b, 0x3a, 0xc3, 0xc5, 0x77, 0x8e,
+	0x2f, 0x5a, 0x7f, 0x95, 0xa1, 0x2e, 0x92, 0x9e, 0xd5, 0x8b, 0x09, 0xcb, 0x04, 0xc7, 0x81, 0xe3,
+	0x66, 0x8f, 0x78, 0xd5, 0xce, 0xd7, 0xac, 0xd5, 0x12, 0x2a, 0xde, 0xf7, 0x12, 0x87, 0xe4, 0x92,
+	0x91, 0xb1, 0x87, 0x03, 0x4c, 0xf1, 0x0e, 0xee, 0x45, 0xec, 0x89, 0xe4, 0x1a, 0xd9, 0x4b, 0x35,
+	0x0d, 0x42, 0xf7, 0x61, 0xc9, 0xcd, 0x72, 0xab, 0xf3, 0x6c, 0xbd, 0xab, 0x64, 0x4b, 0xf5, 0x88,
+	0x2f, 0xb2, 0x8c, 0xdb, 0x52, 0x87, 0xbd, 0xd5, 0x9e, 0xdf, 0xeb, 0xc9, 0x8b, 0x11, 0x0b, 0xf4,
+	0x04, 0xea, 0x1e, 0xa6, 0x8e, 0x1f, 0x60, 0x8f, 0x27, 0xb4, 0xc2, 0xeb, 0xf7, 0x83, 0x99, 0x27,
+	0x2b, 0xb2, 0x62, 0x08, 0x29, 0xa8, 0xa3, 0x0d, 0xb8, 0x72, 0xec, 0x24, 0xaa, 0x54, 0xf6, 0x2a,
+	0x9d, 0xde, 0x36, 0xbf, 0x85, 0xab, 0x13, 0x87, 0x4d, 0x99, 0x30, 0x3e, 0x51, 0x27, 0x8c, 0x62,
+	0x63, 0xa9, 0x05, 0xa2, 0x8e, 0x1e, 0xf7, 0xa1, 0xa6, 0x24, 0x00, 0xb5, 0xa0, 0xde, 0xd9, 0xdf,
+	0xdb, 0x7b, 0xf1, 0xfc, 0xe0, 0xd1, 0xc1, 0xe1, 0x37, 0x07, 0xad, 0x05, 0xd4, 0x80, 0x2a, 0xdf,
+	0x39, 0x38, 0x3c, 0x60, 0x05, 0x21, 0x97, 0x4f, 0x0f, 0x9f, 0x74, 0x5b, 0x25, 0x8b, 0x42, 0x43,
+	0xbc, 0x40, 0xb3, 0xc9, 0xe8, 0x33, 0x80, 0x78, 0x3c, 0x1d, 0x9c, 0x43, 0x49, 0x8a, 0x28, 0x2b,
+	0x07, 0xea, 0x0f, 0x70, 0x94, 0x52, 0x7e, 0xd1, 0x9a, 0x2d, 0x97, 0xd6, 0x77, 0xd0, 0x94, 0x56,
+	0xb3, 0xb2, 0x3a, 0xdd, 0xcc, 0x97, 0x35, 0x6a, 0xfd, 0xa2, 0x41, 0xcd, 0xc6, 0x8e, 0x37, 0x3f,
+	0x4b, 0x14, 0x4d, 0x95, 0xe7, 0x8f, 0x6f, 0x4c, 0x9d, 0xfa, 0x5c, 0xd4, 0x69, 0xfd, 0xa4, 0x41,
+	0x5d, 0xf8, 0xf6, 0x86, 0xa3, 0x56, 0x5c, 0x29, 0xcf, 0xe7, 0xca, 0x9f, 0x1a, 0x34, 0xe4, 0xc4,
+	0xf1, 0xff, 0xd3, 0xa9, 0x52, 0x29, 0x8b, 0x85, 0x4a, 0x99, 0x24, 0xda, 0xca, 0x34, 0xa2, 0xdd,
+	0x87, 0xa6, 0x0c, 0x26, 0xcb, 0x6c, 0x31, 0x93, 0xda, 0xfc, 0xf5, 0xc3, 0x66, 0x93, 0x0e, 0xe7,
+	0xa3, 0xff, 0xa0, 0x82, 0x94, 0xb8, 0xf5, 0x62, 0x87, 0xfc, 0xaa, 0xc1, 0x0a, 0x9f, 0xc9, 0xe4,
+	0x94, 0xb9, 0x1f, 0xfa, 0x74, 0x8f, 0x13, 0xc8, 0x9b, 0xab, 0x1a, 0x03, 0x96, 0xc4, 0xdb, 0xca,
+	0x9c, 0xe6, 0x7c, 0x9d, 0x2d, 0x2f, 0x5c, 0xda, 0x5b, 0x3f, 0x57, 0xa0, 0x25, 0x5d, 0x3d, 0x92,
+	0xa3, 0xd7, 0x0e, 0xd4, 0xf8, 0xab, 0x2f, 0xa6, 0x4c, 0x34, 0x31, 0x27, 0x64, 0x19, 0x36, 0x8d,
+	0x49, 0x40, 0x5c, 0xa3, 0xb5, 0x80, 0x1e, 0x00, 0x70, 0x7e, 0x13, 0x47, 0xdc, 0x98, 0xa0, 0x6a,
+	0x71, 0xc2, 0xca, 0x0c, 0x0a, 0xb7, 0x16, 0xd8, 0xcf, 0xce, 0x7c, 0xca, 0x45, 0x37, 0xcf, 0xf8,
+	0xc1, 0x69, 0xae, 0x4d, 0x07, 0x15, 0x57, 0x2a, 0x62, 0x5e, 0x44, 0xaa, 0xc3, 0x85, 0x41, 0xd6,
+	0x5c, 0x9d, 0x82, 0xe4, 0x07, 0xdc, 0x83, 0x45, 0x1e, 0xde, 0xe5, 0x32, 0xf1, 0x39, 0xe8, 0xfc,
+	0xd5, 0xb9, 0x44, 0x0e, 0x1e, 0x40, 0x25, 0xfb, 0x8d, 0x53, 0x30, 0xa0, 0x12, 0xbf, 0xb9, 0x3a,
+	0x05, 0x51, 0x6d, 0x33, 0xe2, 0x2a, 0xd8, 0x56, 0x58, 0xd6, 0x5c, 0x99, 0xd8, 0x57, 0x6d, 0x8b,
+	0xde, 0x2c, 0xd8, 0x2e, 0x70, 0x8f, 0xb9, 0x3a, 0x05, 0x51, 0xb2, 0x56, 0x11, 0x0d, 0x59, 0x38,
+	0xa0, 0xd0, 0xa3, 0xe6, 0x8d, 0x89, 0xfa, 0xec, 0xb2, 0x3f, 0x2b, 0xac, 0x05, 0x74, 0x17, 0x2a,
+	0xbb, 0x4e, 0xe8, 0xe2, 0x00, 0xcd, 0x90, 0x39, 0x43, 0xf7, 0x0b, 0x68, 0x7c, 0x89, 0xe9, 0x11,
+	0xff, 0x53, 0x64, 0x3f, 0xec, 0x45, 0x33, 0x8f, 0xb8, 0xae, 0x3e, 0xd4, 0xb9, 0xb8, 0xb5, 0xf0,
+	0xb2, 0xc2, 0x05, 0x6f, 0xff, 0x3b, 0x00, 0xc9, 0xd5, 0x16, 0xdc, 0x75, 0x11, 0x00, 0x00,
 }
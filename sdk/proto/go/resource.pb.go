@@ -288,6 +288,12 @@ type RegisterResourceRequest struct {
 	Aliases                 []string                                                 `protobuf:"bytes,15,rep,name=aliases" json:"aliases,omitempty"`
 	ImportId                string                                                   `protobuf:"bytes,16,opt,name=importId" json:"importId,omitempty"`
 	CustomTimeouts          *RegisterResourceRequest_CustomTimeouts                  `protobuf:"bytes,17,opt,name=customTimeouts" json:"customTimeouts,omitempty"`
+	Priority                int32                                                    `protobuf:"varint,18,opt,name=priority" json:"priority,omitempty"`
+	RetryAttempts           int32                                                    `protobuf:"varint,19,opt,name=retryAttempts" json:"retryAttempts,omitempty"`
+	RetryDelay              float64                                                  `protobuf:"fixed64,20,opt,name=retryDelay" json:"retryDelay,omitempty"`
+	RetryBackoff            float64                                                  `protobuf:"fixed64,21,opt,name=retryBackoff" json:"retryBackoff,omitempty"`
+	AutoName                string                                                   `protobuf:"bytes,22,opt,name=autoName" json:"autoName,omitempty"`
+	ReplaceOnChanges        []string                                                 `protobuf:"bytes,23,rep,name=replaceOnChanges" json:"replaceOnChanges,omitempty"`
 	XXX_NoUnkeyedLiteral    struct{}                                                 `json:"-"`
 	XXX_unrecognized        []byte                                                   `json:"-"`
 	XXX_sizecache           int32                                                    `json:"-"`
@@ -436,6 +442,48 @@ func (m *RegisterResourceRequest) GetCustomTimeouts() *RegisterResourceRequest_C
 	return nil
 }
 
+func (m *RegisterResourceRequest) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *RegisterResourceRequest) GetRetryAttempts() int32 {
+	if m != nil {
+		return m.RetryAttempts
+	}
+	return 0
+}
+
+func (m *RegisterResourceRequest) GetRetryDelay() float64 {
+	if m != nil {
+		return m.RetryDelay
+	}
+	return 0
+}
+
+func (m *RegisterResourceRequest) GetRetryBackoff() float64 {
+	if m != nil {
+		return m.RetryBackoff
+	}
+	return 0
+}
+
+func (m *RegisterResourceRequest) GetAutoName() string {
+	if m != nil {
+		return m.AutoName
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest) GetReplaceOnChanges() []string {
+	if m != nil {
+		return m.ReplaceOnChanges
+	}
+	return nil
+}
+
 // PropertyDependencies describes the resources that a particular property depends on.
 type RegisterResourceRequest_PropertyDependencies struct {
 	Urns                 []string `protobuf:"bytes,1,rep,name=urns" json:"urns,omitempty"`
@@ -875,57 +923,63 @@ var _ResourceMonitor_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("resource.proto", fileDescriptor_resource_9e442c1601c8b0e8) }
 
 var fileDescriptor_resource_9e442c1601c8b0e8 = []byte{
-	// 826 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x6e, 0xeb, 0x44,
-	0x14, 0xbe, 0x49, 0xee, 0xcd, 0xcf, 0x49, 0x6f, 0x5a, 0xe6, 0x56, 0x8d, 0xaf, 0x41, 0xe5, 0x62,
-	0x58, 0x14, 0x16, 0x29, 0x2d, 0x8b, 0x16, 0x84, 0x84, 0x44, 0x29, 0x52, 0x17, 0x15, 0xe0, 0xb2,
-	0x00, 0x24, 0x90, 0x1c, 0xfb, 0x34, 0x35, 0x75, 0x3c, 0xc3, 0x78, 0x1c, 0x29, 0x3b, 0xde, 0x84,
-	0x57, 0xe1, 0x39, 0x78, 0x0c, 0x9e, 0x80, 0xf9, 0xf1, 0x84, 0xf8, 0x27, 0x4d, 0x60, 0x95, 0x39,
-	0x3f, 0x73, 0x3c, 0xf3, 0x7d, 0xdf, 0x39, 0x13, 0x18, 0x71, 0xcc, 0x68, 0xce, 0x43, 0x9c, 0x30,
-	0x4e, 0x05, 0x25, 0x03, 0x96, 0x27, 0xf9, 0x3c, 0xe6, 0x2c, 0x74, 0xdf, 0x9e, 0x51, 0x3a, 0x4b,
-	0xf0, 0x54, 0x07, 0xa6, 0xf9, 0xfd, 0x29, 0xce, 0x99, 0x58, 0x9a, 0x3c, 0xf7, 0x9d, 0x6a, 0x30,
-	0x13, 0x3c, 0x0f, 0x45, 0x11, 0x1d, 0xc9, 0x9f, 0x45, 0x1c, 0x21, 0x37, 0xb6, 0x77, 0x02, 0x47,
-	0x77, 0x39, 0x63, 0x94, 0x8b, 0xec, 0x6b, 0x0c, 0x44, 0xce, 0xd1, 0xc7, 0xdf, 0x72, 0xcc, 0x04,
-	0x19, 0x41, 0x3b, 0x8e, 0x9c, 0xd6, 0x9b, 0xd6, 0xc9, 0xc0, 0x97, 0x2b, 0xef, 0x53, 0x18, 0xd7,
-	0x32, 0x33, 0x46, 0xd3, 0x0c, 0xc9, 0x31, 0xc0, 0x43, 0x90, 0x15, 0x51, 0xbd, 0xa5, 0xef, 0xaf,
-	0x79, 0xbc, 0xbf, 0xdb, 0xf0, 0xca, 0xc7, 0x20, 0xf2, 0x8b, 0x1b, 0x6d, 0xf8, 0x04, 0x21, 0xf0,
-	0x5c, 0x2c, 0x19, 0x3a, 0x6d, 0xed, 0xd1, 0x6b, 0xe5, 0x4b, 0x83, 0x39, 0x3a, 0x1d, 0xe3, 0x53,
-	0x6b, 0x72, 0x04, 0x5d, 0x16, 0x70, 0x4c, 0x85, 0xf3, 0x5c, 0x7b, 0x0b, 0x8b, 0x5c, 0x00, 0xc8,
-	0x5b, 0x31, 0xe4, 0x22, 0xc6, 0xcc, 0x79, 0x21, 0x63, 0xc3, 0xf3, 0xf1, 0xc4, 0xe0, 0x31, 0xb1,
-	0x78, 0x4c, 0xee, 0x34, 0x1e, 0xfe, 0x5a, 0x2a, 0xf1, 0x60, 0x2f, 0x42, 0x86, 0x69, 0x84, 0x69,
-	0xa8, 0xb6, 0x76, 0xdf, 0x74, 0x64, 0xd9, 0x92, 0x8f, 0xb8, 0xd0, 0xb7, 0xd8, 0x39, 0x3d, 0xfd,
-	0xd9, 0x95, 0x4d, 0x1c, 0xe8, 0x2d, 0x90, 0x67, 0x31, 0x4d, 0x9d, 0xbe, 0x0e, 0x59, 0x93, 0x7c,
-	0x00, 0x2f, 0x83, 0x30, 0x44, 0x26, 0xee, 0x30, 0xe4, 0x28, 0x32, 0x67, 0xa0, 0xd1, 0x29, 0x3b,
-	0xc9, 0x25, 0x8c, 0x83, 0x28, 0x8a, 0x85, 0xdc, 0x11, 0x24, 0xc6, 0xf9, 0x4d, 0x2e, 0x58, 0x2e,
-	0xf3, 0x41, 0x1f, 0x65, 0x53, 0x58, 0x7d, 0x39, 0x48, 0xe2, 0x20, 0x93, 0x87, 0x1e, 0xea, 0x4c,
-	0x6b, 0x7a, 0x01, 0x1c, 0x96, 0x31, 0x2f, 0xc8, 0x3a, 0x80, 0x4e, 0xce, 0xd3, 0x02, 0x75, 0xb5,
-	0xac, 0xc0, 0xd6, 0xde, 0x19, 0x36, 0xef, 0xaf, 0x1e, 0x8c, 0x7d, 0x9c, 0xc5, 0x99, 0x40, 0x5e,
-	0xe5, 0xd6, 0x72, 0xd9, 0x6a, 0xe0, 0xb2, 0xdd, 0xc8, 0x65, 0xa7, 0xc4, 0xa5, 0xf4, 0x87, 0x79,
-	0x26, 0xe8, 0x5c, 0x73, 0xdc, 0xf7, 0x0b, 0x8b, 0x9c, 0x42, 0x97, 0x4e, 0x7f, 0xc5, 0x50, 0x6c,
-	0xe3, 0xb7, 0x48, 0x53, 0x08, 0xa9, 0x90, 0xda, 0xd1, 0xd5, 0x95, 0xac, 0x59, 0x63, 0xbd, 0xb7,
-	0x85, 0xf5, 0x7e, 0x85, 0x75, 0x06, 0x87, 0x05, 0x18, 0xcb, 0xaf, 0xd6, 0xeb, 0x0c, 0x64, 0x9d,
-	0xe1, 0xf9, 0xe7, 0x93, 0x55, 0xc3, 0x4e, 0x36, 0x80, 0x34, 0xf9, 0xb6, 0x61, 0xfb, 0x75, 0x2a,
-	0xf8, 0xd2, 0x6f, 0xac, 0x4c, 0x3e, 0x86, 0x57, 0x11, 0x26, 0x28, 0xf0, 0x4b, 0xbc, 0xa7, 0xaa,
-	0x01, 0x59, 0x12, 0x84, 0x28, 0x35, 0xa2, 0xee, 0xd5, 0x14, 0x5a, 0x57, 0xe6, 0xb0, 0xa6, 0xcc,
-	0x78, 0x96, 0xca, 0xd4, 0xab, 0x87, 0x20, 0x9d, 0xc9, 0x63, 0xef, 0xe9, 0xeb, 0x97, 0x9d, 0x75,
-	0xfd, 0xbe, 0xfc, 0x8f, 0xfa, 0x1d, 0xed, 0xac, 0xdf, 0xfd, 0x92, 0x7e, 0x15, 0xf2, 0xf1, 0x5c,
-	0x8d, 0x8f, 0x9b, 0xc8, 0x39, 0x30, 0xc8, 0x5b, 0x9b, 0xfc, 0x08, 0x23, 0x23, 0x87, 0xef, 0xe3,
-	0x39, 0x52, 0xf5, 0x99, 0xb7, 0xb4, 0x18, 0xce, 0x76, 0xc0, 0xfc, 0xaa, 0xb4, 0xd1, 0xaf, 0x14,
-	0x72, 0x3f, 0x82, 0xc3, 0x26, 0x56, 0x94, 0x76, 0x65, 0xaf, 0x64, 0x52, 0xcf, 0xea, 0x94, 0x7a,
-	0xed, 0xfe, 0x00, 0xa3, 0x72, 0x35, 0xad, 0x5a, 0x2e, 0xa7, 0xa3, 0xd5, 0x7d, 0x61, 0x29, 0x7f,
-	0xce, 0x22, 0xe5, 0x37, 0xda, 0x2f, 0x2c, 0xe5, 0x37, 0xac, 0x59, 0xf5, 0x1b, 0xcb, 0xfd, 0xbd,
-	0x05, 0xaf, 0x37, 0x8a, 0x43, 0xb5, 0xf0, 0x23, 0x2e, 0x6d, 0x0b, 0xcb, 0x25, 0xb9, 0x85, 0x17,
-	0x8b, 0x20, 0xc9, 0xb1, 0xe8, 0xde, 0x8b, 0xff, 0xa9, 0x3d, 0xdf, 0x54, 0xf9, 0xac, 0x7d, 0xd9,
-	0xf2, 0xfe, 0x68, 0x81, 0x53, 0xdf, 0xbb, 0x71, 0x88, 0x98, 0x59, 0xde, 0x5e, 0xcd, 0xf2, 0x7f,
-	0xfb, 0xb4, 0xb3, 0x5b, 0x9f, 0x4a, 0x28, 0x32, 0x11, 0x4c, 0x13, 0xb4, 0x0d, 0x6f, 0x2c, 0xa5,
-	0x10, 0xb3, 0x52, 0x13, 0x5d, 0x2b, 0xa4, 0x30, 0x3d, 0x84, 0xe3, 0xea, 0x01, 0x0b, 0x59, 0xd9,
-	0x21, 0x54, 0x3f, 0xe6, 0x19, 0xf4, 0x68, 0xa1, 0xcc, 0x2d, 0x83, 0xce, 0xe6, 0x9d, 0xff, 0xd9,
-	0x81, 0x7d, 0x5b, 0xff, 0x96, 0xa6, 0xb1, 0xa0, 0x9c, 0xfc, 0x04, 0xfb, 0x95, 0xc7, 0x90, 0xbc,
-	0xb7, 0x86, 0x79, 0xf3, 0x93, 0xea, 0x7a, 0x4f, 0xa5, 0x18, 0x64, 0xbd, 0x67, 0xe4, 0x0b, 0xe8,
-	0xde, 0xa4, 0x0b, 0xfa, 0x28, 0xaf, 0xbe, 0x96, 0x6f, 0x5c, 0xb6, 0xd2, 0xeb, 0x86, 0xc8, 0xaa,
-	0xc0, 0x77, 0xb0, 0xb7, 0x3e, 0xf9, 0xc9, 0x71, 0x49, 0x0d, 0xb5, 0x67, 0xd8, 0x7d, 0x77, 0x63,
-	0x7c, 0x55, 0xf2, 0x67, 0x38, 0xa8, 0x42, 0x4d, 0xbc, 0xed, 0x22, 0x73, 0xdf, 0x7f, 0x32, 0x67,
-	0x55, 0xfe, 0x97, 0xfa, 0x3b, 0x62, 0x07, 0xc4, 0x87, 0x4f, 0x54, 0x28, 0xb3, 0xed, 0x1e, 0xd5,
-	0xa8, 0xbc, 0x56, 0xff, 0x8b, 0xbc, 0x67, 0xd3, 0xae, 0xf6, 0x7c, 0xf2, 0x4f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x37, 0xd0, 0x44, 0x0d, 0x54, 0x09, 0x00, 0x00,
+	// 914 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x72, 0x23, 0xb5,
+	0x13, 0xde, 0xb1, 0x37, 0x8e, 0xdd, 0xc9, 0x3a, 0xf9, 0x29, 0xf9, 0xd9, 0xda, 0x81, 0x0a, 0x66,
+	0xe0, 0x60, 0xf6, 0xe0, 0xb0, 0xe1, 0xb0, 0x0b, 0x45, 0x15, 0xc5, 0xfe, 0xa1, 0x6a, 0x0f, 0xcb,
+	0xc2, 0x84, 0x03, 0x50, 0x05, 0x55, 0xca, 0x4c, 0xc7, 0x3b, 0x64, 0x3c, 0x12, 0x92, 0x26, 0x55,
+	0x73, 0xe3, 0x4d, 0xb8, 0xf2, 0x18, 0x3c, 0x13, 0x4f, 0x40, 0x49, 0x1a, 0x19, 0x8f, 0xc7, 0x4e,
+	0x02, 0x37, 0xf5, 0xd7, 0xad, 0x1e, 0xe9, 0xfb, 0xba, 0x5b, 0x03, 0x43, 0x89, 0x8a, 0x97, 0x32,
+	0xc1, 0x99, 0x90, 0x5c, 0x73, 0x32, 0x10, 0x65, 0x5e, 0x2e, 0x32, 0x29, 0x92, 0xf0, 0x9d, 0x39,
+	0xe7, 0xf3, 0x1c, 0x4f, 0xad, 0xe3, 0xa2, 0xbc, 0x3c, 0xc5, 0x85, 0xd0, 0x95, 0x8b, 0x0b, 0xdf,
+	0x5d, 0x77, 0x2a, 0x2d, 0xcb, 0x44, 0xd7, 0xde, 0xa1, 0x90, 0xfc, 0x3a, 0x4b, 0x51, 0x3a, 0x3b,
+	0x9a, 0xc2, 0xe8, 0xbc, 0x14, 0x82, 0x4b, 0xad, 0xbe, 0x42, 0xa6, 0x4b, 0x89, 0x31, 0xfe, 0x5a,
+	0xa2, 0xd2, 0x64, 0x08, 0x9d, 0x2c, 0xa5, 0xc1, 0x24, 0x98, 0x0e, 0xe2, 0x4e, 0x96, 0x46, 0x9f,
+	0xc2, 0xb8, 0x15, 0xa9, 0x04, 0x2f, 0x14, 0x92, 0x13, 0x80, 0xb7, 0x4c, 0xd5, 0x5e, 0xbb, 0xa5,
+	0x1f, 0xaf, 0x20, 0xd1, 0x5f, 0x1d, 0x38, 0x8a, 0x91, 0xa5, 0x71, 0x7d, 0xa3, 0x2d, 0x9f, 0x20,
+	0x04, 0xee, 0xeb, 0x4a, 0x20, 0xed, 0x58, 0xc4, 0xae, 0x0d, 0x56, 0xb0, 0x05, 0xd2, 0xae, 0xc3,
+	0xcc, 0x9a, 0x8c, 0xa0, 0x27, 0x98, 0xc4, 0x42, 0xd3, 0xfb, 0x16, 0xad, 0x2d, 0xf2, 0x04, 0x40,
+	0x48, 0x2e, 0x50, 0xea, 0x0c, 0x15, 0xdd, 0x99, 0x04, 0xd3, 0xbd, 0xb3, 0xf1, 0xcc, 0xf1, 0x31,
+	0xf3, 0x7c, 0xcc, 0xce, 0x2d, 0x1f, 0xf1, 0x4a, 0x28, 0x89, 0x60, 0x3f, 0x45, 0x81, 0x45, 0x8a,
+	0x45, 0x62, 0xb6, 0xf6, 0x26, 0xdd, 0xe9, 0x20, 0x6e, 0x60, 0x24, 0x84, 0xbe, 0xe7, 0x8e, 0xee,
+	0xda, 0xcf, 0x2e, 0x6d, 0x42, 0x61, 0xf7, 0x1a, 0xa5, 0xca, 0x78, 0x41, 0xfb, 0xd6, 0xe5, 0x4d,
+	0xf2, 0x21, 0x3c, 0x60, 0x49, 0x82, 0x42, 0x9f, 0x63, 0x22, 0x51, 0x2b, 0x3a, 0xb0, 0xec, 0x34,
+	0x41, 0xf2, 0x14, 0xc6, 0x2c, 0x4d, 0x33, 0x9d, 0xf1, 0x82, 0xe5, 0x0e, 0x7c, 0x53, 0x6a, 0x51,
+	0x6a, 0x45, 0xc1, 0x1e, 0x65, 0x9b, 0xdb, 0x7c, 0x99, 0xe5, 0x19, 0x53, 0xa8, 0xe8, 0x9e, 0x8d,
+	0xf4, 0x66, 0xc4, 0xe0, 0xb8, 0xc9, 0x79, 0x2d, 0xd6, 0x21, 0x74, 0x4b, 0x59, 0xd4, 0xac, 0x9b,
+	0xe5, 0x1a, 0x6d, 0x9d, 0x3b, 0xd3, 0x16, 0xfd, 0x31, 0x80, 0x71, 0x8c, 0xf3, 0x4c, 0x69, 0x94,
+	0xeb, 0xda, 0x7a, 0x2d, 0x83, 0x0d, 0x5a, 0x76, 0x36, 0x6a, 0xd9, 0x6d, 0x68, 0x39, 0x82, 0x5e,
+	0x52, 0x2a, 0xcd, 0x17, 0x56, 0xe3, 0x7e, 0x5c, 0x5b, 0xe4, 0x14, 0x7a, 0xfc, 0xe2, 0x17, 0x4c,
+	0xf4, 0x6d, 0xfa, 0xd6, 0x61, 0x86, 0x21, 0xe3, 0x32, 0x3b, 0x7a, 0x36, 0x93, 0x37, 0x5b, 0xaa,
+	0xef, 0xde, 0xa2, 0x7a, 0x7f, 0x4d, 0x75, 0x01, 0xc7, 0x35, 0x19, 0xd5, 0x8b, 0xd5, 0x3c, 0x83,
+	0x49, 0x77, 0xba, 0x77, 0xf6, 0xf9, 0x6c, 0xd9, 0xb0, 0xb3, 0x2d, 0x24, 0xcd, 0xbe, 0xd9, 0xb0,
+	0xfd, 0x65, 0xa1, 0x65, 0x15, 0x6f, 0xcc, 0x4c, 0x3e, 0x86, 0xa3, 0x14, 0x73, 0xd4, 0xf8, 0x0c,
+	0x2f, 0xb9, 0xc4, 0x18, 0x45, 0xce, 0x12, 0xa4, 0x60, 0xef, 0xb5, 0xc9, 0xb5, 0x5a, 0x99, 0x7b,
+	0xad, 0xca, 0xcc, 0xe6, 0x05, 0x97, 0xf8, 0xfc, 0x2d, 0x2b, 0xe6, 0xa8, 0xe8, 0xbe, 0xbd, 0x7e,
+	0x13, 0x6c, 0xd7, 0xef, 0x83, 0x7f, 0x59, 0xbf, 0xc3, 0x3b, 0xd7, 0xef, 0x41, 0xa3, 0x7e, 0x0d,
+	0xf3, 0xd9, 0x42, 0x70, 0xa9, 0x5f, 0xa5, 0xf4, 0xd0, 0x31, 0xef, 0x6d, 0xf2, 0x03, 0x0c, 0x5d,
+	0x39, 0x7c, 0x97, 0x2d, 0x90, 0x9b, 0xcf, 0xfc, 0xcf, 0x16, 0xc3, 0xe3, 0x3b, 0x70, 0xfe, 0xbc,
+	0xb1, 0x31, 0x5e, 0x4b, 0xe4, 0x04, 0xcf, 0xb8, 0xcc, 0x74, 0x45, 0xc9, 0x24, 0x98, 0xee, 0xc4,
+	0x4b, 0xdb, 0x90, 0x21, 0x51, 0xcb, 0xea, 0x4b, 0xad, 0xcd, 0xc4, 0x55, 0xf4, 0xc8, 0x06, 0x34,
+	0x41, 0x33, 0x0d, 0x2d, 0xf0, 0x02, 0x73, 0x56, 0xd1, 0xe3, 0x49, 0x30, 0x0d, 0xe2, 0x15, 0xc4,
+	0x94, 0x9d, 0xb5, 0x9e, 0xb1, 0xe4, 0x8a, 0x5f, 0x5e, 0xd2, 0xff, 0xdb, 0x88, 0x06, 0x66, 0x4e,
+	0xc1, 0x4a, 0xcd, 0xbf, 0x36, 0xdd, 0x32, 0x72, 0x97, 0xf7, 0x36, 0x79, 0x04, 0x87, 0xd2, 0xa9,
+	0xfb, 0xa6, 0xf0, 0xda, 0x8d, 0x2d, 0x77, 0x2d, 0x3c, 0x7c, 0x04, 0xc7, 0x9b, 0x6a, 0xcc, 0x74,
+	0x62, 0x29, 0x0b, 0x45, 0x03, 0xbb, 0xcf, 0xae, 0xc3, 0xef, 0x61, 0xd8, 0xe4, 0xc6, 0xf6, 0xa0,
+	0x44, 0xa6, 0x7d, 0x17, 0xd7, 0x96, 0xc1, 0x4b, 0x91, 0x32, 0xed, 0x3b, 0xb9, 0xb6, 0x0c, 0xee,
+	0x6a, 0xd0, 0xf7, 0xb2, 0xb3, 0xc2, 0xdf, 0x02, 0x78, 0xb8, 0xb5, 0xd4, 0xcd, 0x40, 0xba, 0xc2,
+	0xca, 0x0f, 0xa4, 0x2b, 0xac, 0xc8, 0x6b, 0xd8, 0xb9, 0x66, 0x79, 0x89, 0xf5, 0x2c, 0x7a, 0xf2,
+	0x1f, 0x3b, 0x29, 0x76, 0x59, 0x3e, 0xeb, 0x3c, 0x0d, 0xa2, 0xdf, 0x03, 0xa0, 0xed, 0xbd, 0x5b,
+	0x47, 0xa2, 0x7b, 0x99, 0x3a, 0xcb, 0x97, 0xe9, 0x9f, 0xa9, 0xd3, 0xbd, 0xdb, 0xd4, 0x19, 0x41,
+	0x4f, 0x69, 0x76, 0x91, 0xa3, 0x1f, 0x5f, 0xce, 0x32, 0xf5, 0xee, 0x56, 0xe6, 0x7d, 0xb2, 0xf5,
+	0x5e, 0x9b, 0x11, 0xc2, 0xc9, 0xfa, 0x01, 0xeb, 0x26, 0xf1, 0x23, 0xb5, 0x7d, 0xcc, 0xc7, 0xb0,
+	0xcb, 0xeb, 0x3e, 0xbb, 0x65, 0x6c, 0xfb, 0xb8, 0xb3, 0x3f, 0xbb, 0x70, 0xe0, 0xf3, 0xbf, 0xe6,
+	0x45, 0xa6, 0xb9, 0x24, 0x3f, 0xc2, 0xc1, 0xda, 0xd3, 0x4e, 0xde, 0x5f, 0xe1, 0x7c, 0xf3, 0x0f,
+	0x42, 0x18, 0xdd, 0x14, 0xe2, 0x98, 0x8d, 0xee, 0x91, 0x2f, 0xa0, 0xf7, 0xaa, 0xb8, 0xe6, 0x57,
+	0x48, 0xe8, 0x4a, 0xbc, 0x83, 0x7c, 0xa6, 0x87, 0x1b, 0x3c, 0xcb, 0x04, 0xdf, 0xc2, 0xfe, 0xea,
+	0x3b, 0x46, 0x4e, 0x1a, 0xd5, 0xd0, 0xfa, 0xa9, 0x08, 0xdf, 0xdb, 0xea, 0x5f, 0xa6, 0xfc, 0x09,
+	0x0e, 0xd7, 0xa9, 0x26, 0xd1, 0xed, 0x45, 0x16, 0x7e, 0x70, 0x63, 0xcc, 0x32, 0xfd, 0xcf, 0x30,
+	0xde, 0xa2, 0x24, 0xf9, 0xe8, 0x86, 0x0c, 0x4d, 0xb5, 0xc3, 0x51, 0x4b, 0xca, 0x97, 0xe6, 0x2f,
+	0x2f, 0xba, 0x77, 0xd1, 0xb3, 0xc8, 0x27, 0x7f, 0x0f, 0x00, 0xef, 0x71, 0x83, 0x33, 0x22, 0x0a,
+	0x00, 0x00,
 }
@@ -126,16 +126,22 @@ func TestStackCommands(t *testing.T) {
 		stacks, _ := integration.GetStacks(e)
 		assert.Equal(t, 3, len(stacks))
 
+		// Removing a stack other than the current one leaves the current one selected...
 		e.RunCommand("pulumi", "stack", "rm", "majula", "--yes")
-		stacks, _ = integration.GetStacks(e)
+		stacks, current := integration.GetStacks(e)
 		assert.Equal(t, 2, len(stacks))
 		assert.Contains(t, stacks, "blighttown")
 		assert.Contains(t, stacks, "lothric")
+		if assert.NotNil(t, current) {
+			assert.Equal(t, "lothric", *current)
+		}
 
+		// ...while removing the current one leaves no stack selected.
 		e.RunCommand("pulumi", "stack", "rm", "lothric", "--yes")
-		stacks, _ = integration.GetStacks(e)
+		stacks, current = integration.GetStacks(e)
 		assert.Equal(t, 1, len(stacks))
 		assert.Contains(t, stacks, "blighttown")
+		assert.Nil(t, current)
 
 		e.RunCommand("pulumi", "stack", "rm", "blighttown", "--yes")
 		stacks, _ = integration.GetStacks(e)